@@ -0,0 +1,65 @@
+package main
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"song-library/metadata"
+)
+
+// newMetadataProvider настраивает клиент внешнего API с данными песен по переменным окружения.
+func newMetadataProvider() metadata.Provider {
+	return metadata.NewHTTPProvider(metadata.HTTPConfig{
+		BaseURL:        os.Getenv("API_DOMAIN"),
+		Path:           os.Getenv("API_INFO_PATH"),
+		RequestTimeout: envDuration("API_TIMEOUT", 5*time.Second),
+		MaxAttempts:    envInt("API_MAX_ATTEMPTS", 3),
+		BaseBackoff:    envDuration("API_BACKOFF", 200*time.Millisecond),
+		MaxBackoff:     envDuration("API_MAX_BACKOFF", 5*time.Second),
+		Breaker: metadata.NewBreaker(
+			envInt("API_BREAKER_THRESHOLD", 5),
+			envDuration("API_BREAKER_TIMEOUT", 30*time.Second),
+		),
+	})
+}
+
+// envInt читает целое число из окружения, при отсутствии значения возвращает def.
+func envInt(key string, def int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Fatalf("Некоректное значение %s, %v", key, err)
+	}
+	return n
+}
+
+// envBool читает логическое значение (true или false) из окружения, при отсутствии значения возвращает def.
+func envBool(key string, def bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Fatalf("Некоректное значение %s, %v", key, err)
+	}
+	return b
+}
+
+// envDuration читает длительность (например 5s или 1m30s) из окружения, при отсутствии значения возвращает def.
+func envDuration(key string, def time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatalf("Некоректное значение %s, %v", key, err)
+	}
+	return d
+}
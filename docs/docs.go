@@ -15,48 +15,27 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/songs": {
+        "/albums": {
             "get": {
-                "description": "Получить список всех песен",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Получить список альбомов, отсортированный по дате выхода",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "albums"
                 ],
-                "summary": "Получить все песни",
+                "summary": "Получить альбомы",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Фильтр по группе",
-                        "name": "group",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Фильтр по названию песни",
-                        "name": "song",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Фильтр по ссылке",
-                        "name": "link",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Фильтр по тексту или фрагменту тектса",
-                        "name": "text",
+                        "type": "integer",
+                        "description": "Фильтр по ID исполнителя",
+                        "name": "artist_id",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Поле для сортировки asc для возрастания и desc для убывания",
-                        "name": "sort",
+                        "description": "Фильтр по фрагменту названия",
+                        "name": "title",
                         "in": "query"
                     },
                     {
@@ -80,7 +59,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Song"
+                                "$ref": "#/definitions/models.Album"
                             }
                         }
                     },
@@ -99,7 +78,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Добавить новую песню",
+                "description": "Добавить новый альбом",
                 "consumes": [
                     "application/json"
                 ],
@@ -107,17 +86,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "albums"
                 ],
-                "summary": "Добавить песню",
+                "summary": "Добавить альбом",
                 "parameters": [
                     {
-                        "description": "Данные песни",
-                        "name": "song",
+                        "description": "Данные альбома",
+                        "name": "album",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Song"
+                            "$ref": "#/definitions/models.Album"
                         }
                     }
                 ],
@@ -125,17 +104,23 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Song"
+                            "$ref": "#/definitions/models.Album"
                         }
                     },
                     "400": {
-                        "description": "Песня уже добавлена",
+                        "description": "Неверный формат данных",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Исполнитель не найден",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "500": {
-                        "description": "Ошибка при получении данных от внешнего API",
+                        "description": "Внутренняя ошибка сервера",
                         "schema": {
                             "type": "string"
                         }
@@ -143,9 +128,54 @@ const docTemplate = `{
                 }
             }
         },
-        "/songs/{id}": {
+        "/albums/{id}": {
+            "get": {
+                "description": "Получить альбом по его ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "albums"
+                ],
+                "summary": "Получить альбом",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID альбома",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Album"
+                        }
+                    },
+                    "400": {
+                        "description": "Некоректное значение id",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Альбом не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
             "put": {
-                "description": "Редактировать песню по её ID",
+                "description": "Заменить данные альбома",
                 "consumes": [
                     "application/json"
                 ],
@@ -153,23 +183,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "albums"
                 ],
-                "summary": "Редактировать песню",
+                "summary": "Редактировать альбом",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID песни",
+                        "description": "ID альбома",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Данные песни",
-                        "name": "song",
+                        "description": "Данные альбома",
+                        "name": "album",
                         "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.SongWithDetails"
+                            "$ref": "#/definitions/models.Album"
                         }
                     }
                 ],
@@ -177,17 +208,23 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Song"
+                            "$ref": "#/definitions/models.Album"
                         }
                     },
                     "400": {
-                        "description": "Неверный формат даты. Ожидаемый формат: DD.MM.YYYY",
+                        "description": "Неверный формат данных",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "404": {
-                        "description": "Детали песни не найдены",
+                        "description": "Альбом не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Исполнитель не найден",
                         "schema": {
                             "type": "string"
                         }
@@ -201,21 +238,18 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Удалить песню по её ID",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Удалить альбом вместе со списком треков. Сами песни не удаляются",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "albums"
                 ],
-                "summary": "Удалить песню",
+                "summary": "Удалить альбом",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID песни",
+                        "description": "ID альбома",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -223,19 +257,19 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Песня успешно удалена",
+                        "description": "Альбом удален",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "404": {
-                        "description": "Песня не найдена",
+                        "description": "Альбом не найден",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "500": {
-                        "description": "Ошибка при удалении песни",
+                        "description": "Внутренняя ошибка сервера",
                         "schema": {
                             "type": "string"
                         }
@@ -243,9 +277,57 @@ const docTemplate = `{
                 }
             }
         },
-        "/songs/{id}/text": {
+        "/albums/{id}/tracks": {
             "get": {
-                "description": "Получить текст песни по её ID",
+                "description": "Получить список треков альбома с песнями в порядке дисков и номеров треков",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "albums"
+                ],
+                "summary": "Получить треки альбома",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID альбома",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AlbumTrack"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некоректное значение id",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Альбом не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Поставить песню на позицию в альбоме. Песня, стоявшая на этой позиции, заменяется.\nОдна песня может входить в несколько альбомов",
                 "consumes": [
                     "application/json"
                 ],
@@ -253,47 +335,109 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "albums"
                 ],
-                "summary": "Получить текст песни",
+                "summary": "Добавить трек в альбом",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID песни",
+                        "description": "ID альбома",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Позиция песни",
+                        "name": "track",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AlbumTrackRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AlbumTrack"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат данных",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Альбом не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Песня не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/albums/{id}/tracks/{disc}/{track}": {
+            "delete": {
+                "description": "Убрать песню с позиции в альбоме",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "albums"
+                ],
+                "summary": "Удалить трек из альбома",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID альбома",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "default": 1,
-                        "description": "Номер страницы(пагинация)",
-                        "name": "page",
-                        "in": "query"
+                        "description": "Номер диска",
+                        "name": "disc",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "integer",
-                        "default": 5,
-                        "description": "Лимит куплетов на странице",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Номер трека",
+                        "name": "track",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Текст песни",
+                        "description": "Трек удален",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "400": {
-                        "description": "Неверный формат параметров запроса",
+                        "description": "Некоректное значение параметра пути",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "404": {
-                        "description": "Текст песни отсутствует",
+                        "description": "Трек не найден",
                         "schema": {
                             "type": "string"
                         }
@@ -306,60 +450,4955 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.Song": {
-            "description": "Модель песни",
-            "type": "object",
-            "properties": {
-                "group": {
-                    "description": "Название группы",
-                    "type": "string"
-                },
-                "song": {
-                    "description": "Название песни",
-                    "type": "string"
-                }
-            }
-        },
-        "models.SongDetails": {
-            "description": "Модель дополнительных данных песни",
-            "type": "object",
-            "properties": {
-                "link": {
-                    "description": "Ссылка на песню",
-                    "type": "string",
-                    "example": ""
-                },
-                "releaseDate": {
-                    "description": "Дата выхода песни",
-                    "type": "string",
-                    "example": ""
-                },
-                "text": {
-                    "description": "Текст песни",
-                    "type": "string",
-                    "example": ""
-                }
-            }
         },
-        "models.SongWithDetails": {
-            "description": "Модель песни c дополнительными данными песни",
-            "type": "object",
-            "properties": {
-                "SongDetails": {
-                    "$ref": "#/definitions/models.SongDetails"
-                },
-                "group": {
-                    "type": "string",
-                    "example": ""
+        "/artists": {
+            "get": {
+                "description": "Получить список исполнителей, отсортированный по названию",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artists"
+                ],
+                "summary": "Получить исполнителей",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Фильтр по фрагменту названия",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы(пагинация)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Лимит записей на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Artist"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат параметров запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Добавить нового исполнителя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artists"
+                ],
+                "summary": "Добавить исполнителя",
+                "parameters": [
+                    {
+                        "description": "Данные исполнителя",
+                        "name": "artist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Artist"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Artist"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат данных",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Исполнитель с таким названием уже есть",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/artists/{id}": {
+            "get": {
+                "description": "Получить исполнителя по его ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artists"
+                ],
+                "summary": "Получить исполнителя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID исполнителя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Artist"
+                        }
+                    },
+                    "400": {
+                        "description": "Некоректное значение id",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Исполнитель не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Заменить данные исполнителя. Новое название сразу видно во всех его песнях",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artists"
+                ],
+                "summary": "Редактировать исполнителя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID исполнителя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные исполнителя",
+                        "name": "artist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Artist"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Artist"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат данных",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Исполнитель не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Исполнитель с таким названием уже есть",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удалить исполнителя, у которого нет песен и альбомов",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artists"
+                ],
+                "summary": "Удалить исполнителя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID исполнителя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Исполнитель удален",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Исполнитель не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "У исполнителя есть песни или альбомы",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/artists/{id}/songs": {
+            "get": {
+                "description": "Получить песни исполнителя, отсортированные по дате выхода",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artists"
+                ],
+                "summary": "Получить песни исполнителя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID исполнителя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы(пагинация)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Лимит записей на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Song"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат параметров запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Исполнитель не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists": {
+            "get": {
+                "description": "Получить публичные плейлисты и плейлисты текущего пользователя, начиная с измененных последними.\nАдминистратор получает все плейлисты",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Получить плейлисты",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Фильтр по владельцу",
+                        "name": "owner",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы(пагинация)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Лимит записей на странице",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Текущий пользователь",
+                        "name": "X-User",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Токен администратора",
+                        "name": "X-Admin-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Playlist"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат параметров запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Создать пустой плейлист. Владельцем становится пользователь из заголовка X-User",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Добавить плейлист",
+                "parameters": [
+                    {
+                        "description": "Данные плейлиста",
+                        "name": "playlist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Playlist"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Владелец плейлиста",
+                        "name": "X-User",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Playlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат данных",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists/{id}": {
+            "get": {
+                "description": "Получить плейлист с количеством записей. Приватный плейлист доступен только владельцу и администратору",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Получить плейлист",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID плейлиста",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Текущий пользователь",
+                        "name": "X-User",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Токен администратора",
+                        "name": "X-Admin-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Playlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Некоректное значение id",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Плейлист не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Заменить название, описание и видимость плейлиста. Доступно владельцу и администратору",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Редактировать плейлист",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID плейлиста",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные плейлиста",
+                        "name": "playlist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Playlist"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Текущий пользователь",
+                        "name": "X-User",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Токен администратора",
+                        "name": "X-Admin-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Playlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат данных",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Плейлист не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удалить плейлист вместе с записями. Сами песни не удаляются. Доступно владельцу и администратору",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Удалить плейлист",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID плейлиста",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Текущий пользователь",
+                        "name": "X-User",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Токен администратора",
+                        "name": "X-Admin-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Плейлист удален",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Плейлист не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists/{id}/entries": {
+            "get": {
+                "description": "Получить записи плейлиста с песнями по порядку. У записей песен из корзины поле song отсутствует",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Получить записи плейлиста",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID плейлиста",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Текущий пользователь",
+                        "name": "X-User",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Токен администратора",
+                        "name": "X-Admin-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PlaylistEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некоректное значение id",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Плейлист не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Добавить песню на позицию в плейлисте, следующие записи сдвигаются. Без позиции песня добавляется в конец.\nОдна песня может встречаться в плейлисте несколько раз",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Добавить запись в плейлист",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID плейлиста",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Песня и позиция",
+                        "name": "entry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PlaylistEntryRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Текущий пользователь",
+                        "name": "X-User",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Токен администратора",
+                        "name": "X-Admin-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.PlaylistEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат данных",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Плейлист не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Песня не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists/{id}/entries/{entry}": {
+            "delete": {
+                "description": "Убрать запись из плейлиста, следующие записи сдвигаются",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Удалить запись из плейлиста",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID плейлиста",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID записи",
+                        "name": "entry",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Текущий пользователь",
+                        "name": "X-User",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Токен администратора",
+                        "name": "X-Admin-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Запись удалена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Некоректное значение параметра пути",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Запись не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists/{id}/entries/{entry}/move": {
+            "post": {
+                "description": "Переставить запись на новую позицию, записи между старой и новой позицией сдвигаются",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Переместить запись плейлиста",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID плейлиста",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID записи",
+                        "name": "entry",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новая позиция",
+                        "name": "move",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PlaylistMoveRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Текущий пользователь",
+                        "name": "X-User",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Токен администратора",
+                        "name": "X-Admin-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PlaylistEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат данных",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Запись не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists/{id}/export": {
+            "get": {
+                "description": "Выгрузить плейлист файлом M3U8, XSPF или JSON со ссылками на песни. Песни из корзины не выгружаются,\nпесни без ссылки попадают только в JSON с пустой ссылкой",
+                "produces": [
+                    "audio/x-mpegurl",
+                    "application/xspf+xml",
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Выгрузить плейлист",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID плейлиста",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "m3u8",
+                        "description": "Формат файла: m3u8, xspf или json",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Текущий пользователь",
+                        "name": "X-User",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Токен администратора",
+                        "name": "X-Admin-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Файл плейлиста",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат параметров запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Плейлист не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists/{id}/order": {
+            "put": {
+                "description": "Расставить записи плейлиста в заданном порядке. Список должен содержать ID каждой записи плейлиста ровно один раз",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Упорядочить плейлист",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID плейлиста",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "ID записей в новом порядке",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PlaylistOrderRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Текущий пользователь",
+                        "name": "X-User",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Токен администратора",
+                        "name": "X-Admin-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PlaylistEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат данных",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Плейлист не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Список не совпадает с записями плейлиста",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/setlists": {
+            "get": {
+                "description": "Получить сет-листы без сетов, начиная с измененных последними",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "setlists"
+                ],
+                "summary": "Получить сет-листы",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Фильтр по владельцу",
+                        "name": "owner",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы(пагинация)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Лимит записей на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Setlist"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат параметров запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Создать сет-лист с сетами и бисами. Владельцем становится пользователь из заголовка X-User",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "setlists"
+                ],
+                "summary": "Добавить сет-лист",
+                "parameters": [
+                    {
+                        "description": "Сет-лист",
+                        "name": "setlist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Setlist"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Владелец сет-листа",
+                        "name": "X-User",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Setlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат данных",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Песня не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/setlists/{id}": {
+            "get": {
+                "description": "Получить сет-лист с сетами и песнями. У песен из корзины поле song отсутствует",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "setlists"
+                ],
+                "summary": "Получить сет-лист",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID сет-листа",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Setlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Некоректное значение id",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Сет-лист не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Заменить сет-лист целиком вместе с сетами, сеты и песни получают новые ID. Доступно владельцу и администратору",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "setlists"
+                ],
+                "summary": "Редактировать сет-лист",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID сет-листа",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Сет-лист",
+                        "name": "setlist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Setlist"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Текущий пользователь",
+                        "name": "X-User",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Токен администратора",
+                        "name": "X-Admin-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Setlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат данных",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Сет-лист не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Песня не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удалить сет-лист вместе с сетами. Сами песни не удаляются. Доступно владельцу и администратору",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "setlists"
+                ],
+                "summary": "Удалить сет-лист",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID сет-листа",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Текущий пользователь",
+                        "name": "X-User",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Токен администратора",
+                        "name": "X-Admin-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Сет-лист удален",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Сет-лист не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/setlists/{id}/plan": {
+            "get": {
+                "description": "Рассчитать время каждого сета и общее время, начало каждой песни от начала сета и найти конфликты\nтональностей соседних песен сета: тональности дальше одного шага по квинтовому кругу (Am и C совпадают).\nНезаданные длительность, тональность и темп берутся из листа аккордов песни. Песни из корзины пропускаются",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "setlists"
+                ],
+                "summary": "Рассчитать сет-лист",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID сет-листа",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SetlistPlan"
+                        }
+                    },
+                    "400": {
+                        "description": "Некоректное значение id",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Сет-лист не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/setlists/{id}/print": {
+            "get": {
+                "description": "Получить сет-лист для печати обычным текстом или страницей HTML: песни с номерами, началом от начала сета,\nдлительностью, тональностью, темпом и заметками. «→» — переход без паузы, «!» — конфликт тональностей",
+                "produces": [
+                    "text/plain",
+                    "text/html"
+                ],
+                "tags": [
+                    "setlists"
+                ],
+                "summary": "Распечатать сет-лист",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID сет-листа",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "text",
+                        "description": "Формат: text или html",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Сет-лист для печати",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат параметров запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Сет-лист не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs": {
+            "get": {
+                "description": "Получить список всех песен",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Получить все песни",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Фильтр по группе",
+                        "name": "group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фильтр по названию песни",
+                        "name": "song",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фильтр по ссылке",
+                        "name": "link",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фильтр по тексту или фрагменту тектса",
+                        "name": "text",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фильтр по названию альбома без учета регистра",
+                        "name": "album",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Фильтр по тегам вида namespace:name, параметр повторяется: tag=genre:rock\u0026tag=mood:calm",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "and",
+                        "description": "Сочетание тегов: and — песни со всеми тегами, or — хотя бы с одним",
+                        "name": "tag_mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Поля сортировки через запятую, минус — по убыванию: group, song, release_date, created_at, updated_at, id. Например group,-release_date. По умолчанию release_date",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Вышедшие не раньше даты: DD.MM.YYYY, MM.YYYY или YYYY",
+                        "name": "released_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Вышедшие не позже даты включительно: DD.MM.YYYY, MM.YYYY или YYYY",
+                        "name": "released_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Вышедшие в указанном году",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "exact",
+                        "description": "Режим сравнения group и song: exact или fuzzy (без учета регистра, пробелов и опечаток)",
+                        "name": "match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "default": 0.3,
+                        "description": "Минимальное сходство для match=fuzzy, от 0 до 1",
+                        "name": "threshold",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы(пагинация), вместо него лучше использовать cursor",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Лимит записей на странице, от 1 до 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор next или prev из предыдущего ответа, не поддерживается для match=fuzzy",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Посчитать общее количество подходящих песен",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Посчитать количество подходящих песен с каждым тегом, по пространствам имен",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Включать песни из корзины, только для администратора",
+                        "name": "include_deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Токен администратора",
+                        "name": "X-Admin-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Song"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат параметров запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Добавить новую песню. Если дополнительные данные (SongDetail) не переданы,\nони запрашиваются у внешнего API в фоне, их состояние можно узнать через GET /songs/{id}/enrichment",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Добавить песню",
+                "parameters": [
+                    {
+                        "description": "Данные песни",
+                        "name": "song",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Song"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Автор изменения для истории правок",
+                        "name": "X-User",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Song"
+                        }
+                    },
+                    "400": {
+                        "description": "Исполнитель не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при добавлении песни",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/export": {
+            "get": {
+                "description": "Выгрузить все песни, удовлетворяющие фильтрам, файлом CSV, JSON Lines или XLSX. Фильтры и сортировка те же,\nчто у GET /songs, пагинации нет. Песни передаются потоком по мере чтения из базы данных.\nКолонки: id, group, song, с include=details — releaseDate и link, с include=lyrics — text.\nФайл можно загрузить обратно через POST /songs/import",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Выгрузить песни",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "csv",
+                        "description": "Формат файла: csv, jsonl или xlsx",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "details,lyrics",
+                        "description": "Данные песни через запятую: details — дата выхода и ссылка, lyrics — текст. Пустое значение оставляет только названия",
+                        "name": "include",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фильтр по группе",
+                        "name": "group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фильтр по названию песни",
+                        "name": "song",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фильтр по ссылке",
+                        "name": "link",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фильтр по тексту или фрагменту тектса",
+                        "name": "text",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фильтр по названию альбома без учета регистра",
+                        "name": "album",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Фильтр по тегам вида namespace:name, параметр повторяется: tag=genre:rock\u0026tag=mood:calm",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "and",
+                        "description": "Сочетание тегов: and — песни со всеми тегами, or — хотя бы с одним",
+                        "name": "tag_mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Поля сортировки через запятую, минус — по убыванию: group, song, release_date, created_at, updated_at, id. По умолчанию release_date",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Вышедшие не раньше даты: DD.MM.YYYY, MM.YYYY или YYYY",
+                        "name": "released_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Вышедшие не позже даты включительно: DD.MM.YYYY, MM.YYYY или YYYY",
+                        "name": "released_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Вышедшие в указанном году",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "exact",
+                        "description": "Режим сравнения group и song: exact или fuzzy",
+                        "name": "match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "default": 0.3,
+                        "description": "Минимальное сходство для match=fuzzy, от 0 до 1",
+                        "name": "threshold",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Включать песни из корзины, только для администратора",
+                        "name": "include_deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Токен администратора",
+                        "name": "X-Admin-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Файл с песнями",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат параметров запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/import": {
+            "post": {
+                "description": "Загрузить файл CSV, JSON Lines или XLSX с песнями и запустить задание импорта. Файл передается телом запроса\nили полем file в multipart/form-data. Формат определяется параметром format, типом содержимого или расширением файла.\nВ CSV и XLSX первая строка — заголовок, по умолчанию колонки называются как поля: group, song, text, link, releaseDate;\nдругие названия задаются параметрами columns[поле]=колонка, например columns[group]=Исполнитель. В JSON Lines\nв каждой строке объект песни с теми же ключами, ключи тоже можно переназначить через columns.\nКаждая строка проверяется отдельно, строки с ошибками пропускаются и попадают в отчет GET /songs/import/{id}/errors.\nЗадание выполняется в фоне, его состояние возвращает GET /songs/import/{id}",
+                "consumes": [
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "import"
+                ],
+                "summary": "Импортировать песни из файла",
+                "parameters": [
+                    {
+                        "description": "Файл с песнями",
+                        "name": "file",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Формат файла: csv, jsonl или xlsx",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Только проверить файл, ничего не записывая",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "skip",
+                        "description": "Что делать с уже добавленными песнями: skip — пропустить, update — заменить данные значениями из файла, fail — считать строку ошибкой",
+                        "name": "on_duplicate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "object",
+                        "description": "Названия колонок по полям песни: columns[group]=Исполнитель\u0026columns[song]=Название",
+                        "name": "columns",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": ",",
+                        "description": "Разделитель колонок CSV: один символ или tab",
+                        "name": "delimiter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Автор изменений для истории правок",
+                        "name": "X-User",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportJob"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "Адрес задания импорта"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат параметров запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "Файл слишком большой",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "415": {
+                        "description": "Не удалось определить формат файла",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Файл нельзя импортировать",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/import/{id}": {
+            "get": {
+                "description": "Получить состояние задания импорта: прогресс и количество добавленных, измененных, пропущенных и ошибочных строк.\nЗадания хранятся в памяти сервера сутки после завершения и не переживают перезапуск: задание, созданное\nдо перезапуска, возвращает 410, его нужно запустить заново. Песни, импортированные до перезапуска, остаются в библиотеке",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "import"
+                ],
+                "summary": "Получить задание импорта",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID задания",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportJob"
+                        }
+                    },
+                    "404": {
+                        "description": "Задание импорта не найдено",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "410": {
+                        "description": "Задание импорта создано до перезапуска сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/import/{id}/errors": {
+            "get": {
+                "description": "Скачать ошибки строк задания импорта в порядке строк файла: номер строки, поле, группа, песня и описание ошибки.\nПока задание выполняется, отчет содержит ошибки уже обработанных строк",
+                "produces": [
+                    "text/csv",
+                    "application/json"
+                ],
+                "tags": [
+                    "import"
+                ],
+                "summary": "Получить отчет об ошибках импорта",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID задания",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "csv",
+                        "description": "Формат отчета: csv или json",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ImportRowError"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат параметров запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Задание импорта не найдено",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "410": {
+                        "description": "Задание импорта создано до перезапуска сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/search": {
+            "get": {
+                "description": "Полнотекстовый поиск по текстам песен. Результаты отсортированы по релевантности,\nsnippet — фрагмент HTML: текст песни экранирован, совпадения выделены тегами \u003cb\u003e\u003c/b\u003e.\nЗапрос только из исключений (-слово) находит все песни без этих слов",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Поиск по текстам песен",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Поисковый запрос: слова, \\",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Язык запроса: ru или en, по умолчанию определяется по запросу",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы(пагинация)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Лимит записей на странице, от 1 до 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SearchResult"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат параметров запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/tags": {
+            "post": {
+                "description": "Добавить каждой песне все теги. Новые теги создаются. Если какой-то песни нет или она в корзине,\nтеги не добавляются ни одной песне. В ответе — количество новых привязок тегов",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Добавить теги песням",
+                "parameters": [
+                    {
+                        "description": "Песни и теги",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SongTagsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат данных",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Песня не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Убрать у песен теги. Отсутствующие песни и теги пропускаются. В ответе — количество удаленных привязок тегов",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Убрать теги у песен",
+                "parameters": [
+                    {
+                        "description": "Песни и теги",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SongTagsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат данных",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}": {
+            "get": {
+                "description": "Получить песню по её ID в том же виде, что возвращают POST /songs и PUT /songs/{id}.\nfields оставляет в ответе только перечисленные поля: Id, artistId, group, song, enrichmentStatus, version.\ninclude добавляет связанные данные: details — дополнительные данные (SongDetail), artist — исполнителя,\nalbum — альбомы с номерами дисков и треков. По умолчанию include=details.\nОтвет содержит ETag с версией песни, с заголовком If-None-Match неизмененная песня не передается повторно.\nС include=artist или album ETag не передается: исполнитель и альбомы меняются независимо от версии песни",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Получить песню",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Поля песни через запятую, например song,group",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "details",
+                        "description": "Связанные данные через запятую: details, artist, album",
+                        "name": "include",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag, полученный ранее",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Song"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия песни"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Песня не изменилась",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Некоректное значение id, fields или include",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня не найдена",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Редактировать песню по её ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Редактировать песню",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные песни",
+                        "name": "song",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.SongWithDetails"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Автор изменения для истории правок",
+                        "name": "X-User",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag песни, полученный при чтении; обязателен, если REQUIRE_IF_MATCH=true",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Song"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия песни после изменения"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат даты. Ожидаемый формат: DD.MM.YYYY, MM.YYYY или YYYY",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Песня изменена после получения",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "428": {
+                        "description": "Требуется заголовок If-Match",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Переместить песню в корзину. Песню можно вернуть через POST /songs/{id}/restore,\nпока она не удалена окончательно по истечении срока хранения",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Удалить песню",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Кто удаляет песню",
+                        "name": "X-User",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag песни, полученный при чтении; обязателен, если REQUIRE_IF_MATCH=true",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Песня успешно удалена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Песня изменена после получения",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "428": {
+                        "description": "Требуется заголовок If-Match",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при удалении песни",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Изменить отдельные поля песни. Патч применяется к документу того же вида, что тело PUT /songs/{id}:\n{\"group\", \"song\", \"SongDetails\": {\"text\", \"releaseDate\", \"link\"}}.\nС Content-Type application/merge-patch+json (или application/json) тело — JSON Merge Patch (RFC 7396):\nотсутствующие поля не меняются, null очищает поле. С application/json-patch+json тело — массив операций\nJSON Patch (RFC 6902), например [{\"op\": \"remove\", \"path\": \"/SongDetails/link\"}].\nНазвание песни и группа не могут быть пустыми, ссылка должна быть адресом http или https",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json-patch+json",
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Частично изменить песню",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Изменения песни",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Автор изменения для истории правок",
+                        "name": "X-User",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag песни, полученный при чтении; обязателен, если REQUIRE_IF_MATCH=true",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Song"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия песни после изменения"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат патча",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Не выполнилась проверка операции test",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Песня изменена после получения",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "415": {
+                        "description": "Неподдерживаемый тип содержимого",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Некоректные значения полей",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "428": {
+                        "description": "Требуется заголовок If-Match",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/chords": {
+            "get": {
+                "description": "Получить лист аккордов песни, при необходимости транспонированный",
+                "produces": [
+                    "application/json",
+                    "text/plain"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Получить аккорды",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Сдвиг в полутонах от -11 до 11, например +2",
+                        "name": "transpose",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Запись аккордов: sharp, flat или nashville (ступени относительно {key}). По умолчанию как в документе",
+                        "name": "notation",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "json",
+                        "description": "Формат ответа: json, chordpro или text (аккорды над строками текста)",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ChordSheet"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат параметров запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "У песни нет аккордов",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Загрузить лист аккордов песни в формате ChordPro. При ошибке разбора возвращаются строка и символ",
+                "consumes": [
+                    "text/plain"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Загрузить ChordPro",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Документ ChordPro",
+                        "name": "chordpro",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ChordSheet"
+                        }
+                    },
+                    "400": {
+                        "description": "Некоректный документ ChordPro",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "Документ слишком большой",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удалить лист аккордов песни",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Удалить аккорды",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Аккорды удалены",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Некоректное значение id",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/enrich": {
+            "post": {
+                "description": "Поставить песню в очередь на получение дополнительных данных от внешнего API заново.\nЗаполняются только пустые поля песни. Песню из корзины поставить в очередь нельзя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "enrichment"
+                ],
+                "summary": "Перезапустить получение данных песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/models.EnrichmentJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Некоректное значение id",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/enrichment": {
+            "get": {
+                "description": "Получить состояние фонового получения дополнительных данных песни от внешнего API",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "enrichment"
+                ],
+                "summary": "Состояние получения данных песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.EnrichmentJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Некоректное значение id",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Задание не найдено",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/lrc": {
+            "get": {
+                "description": "Скачать текст песни с метками времени в формате LRC",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Скачать LRC",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Файл LRC",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Некоректное значение id",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "У песни нет текста с метками времени",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Загрузить текст песни в формате LRC или расширенного LRC с метками слов \u003cmm:ss.xx\u003e.\nМетки времени проверяются, строки сохраняются отсортированными по времени. Обычный текст песни не меняется",
+                "consumes": [
+                    "text/plain"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Загрузить LRC",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Файл LRC",
+                        "name": "lrc",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SyncedLyrics"
+                        }
+                    },
+                    "400": {
+                        "description": "Некоректный файл LRC",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "Файл слишком большой",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удалить текст песни с метками времени. Обычный текст песни не меняется",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Удалить LRC",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Метки времени удалены",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Некоректное значение id",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/lyrics": {
+            "get": {
+                "description": "Получить все версии текста песни: оригиналы, переводы и транслитерации",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Получить версии текста",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.LyricVersion"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некоректное значение id",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/lyrics/{lang}/{kind}": {
+            "put": {
+                "description": "Создать или заменить версию текста песни на языке lang. Заголовки частей вида [Chorus]\nиспользуются для сопоставления с оригиналом",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Сохранить версию текста",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Языковой тег BCP 47, например en или ru-Latn",
+                        "name": "lang",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Вид версии: original, translation или transliteration",
+                        "name": "kind",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Текст версии",
+                        "name": "version",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LyricVersionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LyricVersion"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат данных",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удалить версию текста песни на языке lang",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Удалить версию текста",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Языковой тег BCP 47",
+                        "name": "lang",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Вид версии: original, translation или transliteration",
+                        "name": "kind",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Версия текста удалена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат параметров запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Версия текста не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/restore": {
+            "post": {
+                "description": "Вернуть песню из корзины вместе с дополнительными данными, историей правок и версиями текста",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Вернуть песню из корзины",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Song"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия песни"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некоректное значение id",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Песни нет в корзине",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Песня с такой группой и названием уже добавлена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/revisions": {
+            "get": {
+                "description": "Получить правки песни от новых к старым: кто и когда изменил какие поля, старые и новые значения",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "revisions"
+                ],
+                "summary": "Получить историю изменений",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы(пагинация)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Лимит записей на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SongRevision"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат параметров запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/revisions/{rev}": {
+            "get": {
+                "description": "Получить правку песни по номеру вместе с состоянием полей после неё",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "revisions"
+                ],
+                "summary": "Получить правку",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер правки",
+                        "name": "rev",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SongRevision"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат параметров запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Правка не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/revisions/{rev}/diff": {
+            "get": {
+                "description": "Построчно сравнить текст песни после правки from и после правки rev, а также показать другие измененные поля",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "revisions"
+                ],
+                "summary": "Сравнить правки",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер правки",
+                        "name": "rev",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер правки для сравнения, по умолчанию предыдущая",
+                        "name": "from",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.DiffLine"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат параметров запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Правка не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/revisions/{rev}/restore": {
+            "post": {
+                "description": "Вернуть поля песни к состоянию после правки rev в одной транзакции. Откат записывается в историю как новая правка",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "revisions"
+                ],
+                "summary": "Откатить песню к правке",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер правки",
+                        "name": "rev",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Автор отката для истории правок",
+                        "name": "X-User",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Song"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия песни после отката"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат параметров запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Правка не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/tags": {
+            "get": {
+                "description": "Получить теги песни, отсортированные по пространству имен и названию",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Получить теги песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Tag"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некоректное значение id",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/text": {
+            "get": {
+                "description": "Получить текст песни по её ID, разбитый на части: куплеты, припевы, предприпевы, бриджи и концовки.\nПовтор части возвращается ссылкой repeatOf без строк, в поле text повторы раскрыты.\nС параметром lang возвращается версия текста на этом языке, с mode=side-by-side — части оригинала\nи выбранной версии, сопоставленные по порядку и виду",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Получить текст песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы(пагинация)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 5,
+                        "description": "Лимит частей текста на странице",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Языковой тег версии текста, например en или ru-Latn",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Вид версии текста: original, translation или transliteration. По умолчанию в этом порядке",
+                        "name": "kind",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "plain",
+                        "description": "plain или side-by-side (оригинал и версия lang рядом)",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Момент воспроизведения mm:ss.xx: вернуть текущую и следующую строку из LRC вместо частей текста",
+                        "name": "at",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Части текста песни",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.LyricSection"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат параметров запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Нет текста песни на языке lang",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags": {
+            "get": {
+                "description": "Получить теги, которые есть хотя бы у одной песни не из корзины, с количеством песен,\nотсортированные по пространству имен и названию",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Получить теги",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Фильтр по пространству имен: genre, mood, language или свое",
+                        "name": "namespace",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы(пагинация)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Лимит записей на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Tag"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат параметров запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash": {
+            "get": {
+                "description": "Получить песни из корзины, начиная с удаленных последними: когда и кем песня удалена.\nПесни удаляются окончательно, когда пролежат в корзине дольше срока хранения. Только для администратора",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Получить корзину",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Токен администратора",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы(пагинация)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Лимит записей на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Song"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат параметров запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handlers.AlbumTrackRequest": {
+            "description": "Позиция песни в альбоме",
+            "type": "object",
+            "properties": {
+                "disc": {
+                    "description": "Номер диска, по умолчанию 1",
+                    "type": "integer",
+                    "example": 1
+                },
+                "songId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "track": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "handlers.LyricVersionRequest": {
+            "description": "Текст версии песни",
+            "type": "object",
+            "properties": {
+                "text": {
+                    "type": "string",
+                    "example": "[Chorus]\nOoh baby, don't you know I suffer?"
+                }
+            }
+        },
+        "handlers.PlaylistEntryRequest": {
+            "description": "Песня, добавляемая в плейлист",
+            "type": "object",
+            "properties": {
+                "position": {
+                    "description": "Позиция, начиная с 1, 0 — в конец плейлиста",
+                    "type": "integer",
+                    "example": 0
+                },
+                "songId": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "handlers.PlaylistMoveRequest": {
+            "description": "Новая позиция записи плейлиста",
+            "type": "object",
+            "properties": {
+                "position": {
+                    "description": "Позиция, начиная с 1, больше длины плейлиста — в конец",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "handlers.PlaylistOrderRequest": {
+            "description": "Новый порядок записей плейлиста",
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "description": "ID всех записей плейлиста в новом порядке",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        3,
+                        1,
+                        2
+                    ]
+                }
+            }
+        },
+        "handlers.SongTagsRequest": {
+            "description": "Песни и теги для привязки или удаления",
+            "type": "object",
+            "properties": {
+                "songIds": {
+                    "description": "ID песен",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2
+                    ]
+                },
+                "tags": {
+                    "description": "Теги вида namespace:name, без пространства имен — tag:name",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "genre:rock",
+                        "mood:energetic"
+                    ]
+                }
+            }
+        },
+        "models.Album": {
+            "description": "Модель альбома",
+            "type": "object",
+            "properties": {
+                "artistId": {
+                    "description": "ID исполнителя, у сборников может отсутствовать",
+                    "type": "integer",
+                    "example": 1
+                },
+                "releaseDate": {
+                    "description": "Дата выхода: DD.MM.YYYY, MM.YYYY или YYYY",
+                    "type": "string",
+                    "example": "03.07.2006"
+                },
+                "title": {
+                    "description": "Название",
+                    "type": "string",
+                    "example": "Black Holes and Revelations"
+                },
+                "type": {
+                    "description": "Вид релиза",
+                    "enum": [
+                        "lp",
+                        "ep",
+                        "single",
+                        "compilation"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.AlbumType"
+                        }
+                    ],
+                    "example": "lp"
+                }
+            }
+        },
+        "models.AlbumTrack": {
+            "description": "Позиция песни в альбоме",
+            "type": "object",
+            "properties": {
+                "disc": {
+                    "description": "Номер диска, по умолчанию 1",
+                    "type": "integer",
+                    "example": 1
+                },
+                "songId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "track": {
+                    "description": "Номер трека на диске",
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "models.AlbumType": {
+            "type": "string",
+            "enum": [
+                "lp",
+                "ep",
+                "single",
+                "compilation"
+            ],
+            "x-enum-varnames": [
+                "AlbumLP",
+                "AlbumEP",
+                "AlbumSingle",
+                "AlbumCompilation"
+            ]
+        },
+        "models.Artist": {
+            "description": "Модель исполнителя",
+            "type": "object",
+            "properties": {
+                "aliases": {
+                    "description": "Другие написания названия",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "country": {
+                    "description": "Страна",
+                    "type": "string",
+                    "example": "GB"
+                },
+                "formedYear": {
+                    "description": "Год основания",
+                    "type": "integer",
+                    "example": 1994
+                },
+                "members": {
+                    "description": "Участники",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "description": "Название",
+                    "type": "string",
+                    "example": "Muse"
+                }
+            }
+        },
+        "models.ChordDirective": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "key"
+                },
+                "value": {
+                    "type": "string",
+                    "example": "Am"
+                }
+            }
+        },
+        "models.ChordLine": {
+            "description": "Строка листа аккордов",
+            "type": "object",
+            "properties": {
+                "chords": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PlacedChord"
+                    }
+                },
+                "section": {
+                    "description": "Вид части для section_start и section_end",
+                    "type": "string",
+                    "example": "chorus"
+                },
+                "text": {
+                    "description": "Текст строки, комментария или название части",
+                    "type": "string"
+                },
+                "type": {
+                    "enum": [
+                        "lyrics",
+                        "comment",
+                        "section_start",
+                        "section_end",
+                        "chorus",
+                        "tab",
+                        "empty"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ChordLineType"
+                        }
+                    ],
+                    "example": "lyrics"
+                }
+            }
+        },
+        "models.ChordLineType": {
+            "type": "string",
+            "enum": [
+                "lyrics",
+                "comment",
+                "section_start",
+                "section_end",
+                "chorus",
+                "tab",
+                "empty"
+            ],
+            "x-enum-comments": {
+                "ChordLineChorus": "повтор припева {chorus}",
+                "ChordLineComment": "комментарий {comment}",
+                "ChordLineLyrics": "строка текста с аккордами",
+                "ChordLineSectionEnd": "конец части {end_of_chorus}",
+                "ChordLineSectionStart": "начало части {start_of_chorus}",
+                "ChordLineTab": "строка табулатуры, выводится как есть"
+            },
+            "x-enum-varnames": [
+                "ChordLineLyrics",
+                "ChordLineComment",
+                "ChordLineSectionStart",
+                "ChordLineSectionEnd",
+                "ChordLineChorus",
+                "ChordLineTab",
+                "ChordLineEmpty"
+            ]
+        },
+        "models.ChordSheet": {
+            "description": "Лист аккордов",
+            "type": "object",
+            "properties": {
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ChordLine"
+                    }
+                },
+                "meta": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ChordDirective"
+                    }
+                }
+            }
+        },
+        "models.DiffLine": {
+            "description": "Строка построчного сравнения текстов",
+            "type": "object",
+            "properties": {
+                "newLine": {
+                    "description": "Номер строки в новом тексте, начиная с 1",
+                    "type": "integer",
+                    "example": 4
+                },
+                "oldLine": {
+                    "description": "Номер строки в старом тексте, начиная с 1",
+                    "type": "integer",
+                    "example": 3
+                },
+                "op": {
+                    "enum": [
+                        "equal",
+                        "insert",
+                        "delete"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.DiffOp"
+                        }
+                    ],
+                    "example": "insert"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DiffOp": {
+            "type": "string",
+            "enum": [
+                "equal",
+                "insert",
+                "delete"
+            ],
+            "x-enum-varnames": [
+                "DiffEqual",
+                "DiffInsert",
+                "DiffDelete"
+            ]
+        },
+        "models.DuplicatePolicy": {
+            "type": "string",
+            "enum": [
+                "skip",
+                "update",
+                "fail"
+            ],
+            "x-enum-comments": {
+                "DuplicateFail": "считать строку ошибкой",
+                "DuplicateSkip": "оставить песню без изменений",
+                "DuplicateUpdate": "заменить дополнительные данные песни значениями из файла"
+            },
+            "x-enum-varnames": [
+                "DuplicateSkip",
+                "DuplicateUpdate",
+                "DuplicateFail"
+            ]
+        },
+        "models.EnrichmentJob": {
+            "description": "Задание на получение дополнительных данных песни",
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "количество сделанных попыток",
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lastError": {
+                    "description": "текст последней ошибки",
+                    "type": "string"
+                },
+                "lastErrorKind": {
+                    "description": "вид последней ошибки внешнего API",
+                    "type": "string"
+                },
+                "runAt": {
+                    "description": "время, не раньше которого задание будет выполнено",
+                    "type": "string"
+                },
+                "songId": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.JobStatus"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FieldChange": {
+            "description": "Изменение одного поля песни",
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "example": "link"
+                },
+                "new": {
+                    "type": "string",
+                    "example": "https://www.youtube.com/watch?v=Xsp3_a-PMTw"
+                },
+                "old": {
+                    "type": "string",
+                    "example": ""
+                }
+            }
+        },
+        "models.ImportJob": {
+            "description": "Задание на импорт песен из файла",
+            "type": "object",
+            "properties": {
+                "created": {
+                    "description": "добавлено песен (для dryRun — было бы добавлено)",
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "createdBy": {
+                    "type": "string"
+                },
+                "dryRun": {
+                    "description": "только проверить файл, ничего не записывая",
+                    "type": "boolean"
+                },
+                "error": {
+                    "description": "ошибка, из-за которой импорт прерван",
+                    "type": "string"
+                },
+                "errorsTruncated": {
+                    "type": "boolean"
+                },
+                "failed": {
+                    "description": "строк с ошибками, см. отчет об ошибках",
+                    "type": "integer"
+                },
+                "finishedAt": {
+                    "type": "string"
+                },
+                "format": {
+                    "description": "csv, jsonl или xlsx",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "onDuplicate": {
+                    "description": "что делать с уже добавленными песнями",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.DuplicatePolicy"
+                        }
+                    ]
+                },
+                "progress": {
+                    "description": "доля прочитанного файла от 0 до 1",
+                    "type": "number"
+                },
+                "rows": {
+                    "description": "обработано строк",
+                    "type": "integer"
+                },
+                "skipped": {
+                    "description": "пропущено дубликатов и песен без изменений",
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.JobStatus"
+                },
+                "updated": {
+                    "description": "изменено песен",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ImportRowError": {
+            "description": "Ошибка в строке импортируемого файла",
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "поле с ошибкой, пустое для ошибки всей строки",
+                    "type": "string"
+                },
+                "group": {
+                    "description": "группа и название песни из строки, если удалось прочитать",
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "row": {
+                    "description": "номер строки файла, начиная с 1",
+                    "type": "integer"
+                },
+                "song": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ItemPlan": {
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "type": "string",
+                    "example": "3:45"
+                },
+                "itemId": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "keyClash": {
+                    "description": "Тональность конфликтует с предыдущей песней сета",
+                    "type": "boolean"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "number": {
+                    "description": "Номер песни в сет-листе, сквозной для всех сетов",
+                    "type": "integer"
+                },
+                "segue": {
+                    "type": "boolean"
+                },
+                "songId": {
+                    "type": "integer"
+                },
+                "start": {
+                    "description": "Начало от начала сета",
+                    "type": "string",
+                    "example": "12:30"
+                },
+                "tempo": {
+                    "type": "integer"
+                },
+                "title": {
+                    "description": "Исполнитель и название песни",
+                    "type": "string"
+                }
+            }
+        },
+        "models.JobStatus": {
+            "type": "string",
+            "enum": [
+                "queued",
+                "running",
+                "succeeded",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "JobQueued",
+                "JobRunning",
+                "JobSucceeded",
+                "JobFailed"
+            ]
+        },
+        "models.KeyClash": {
+            "type": "object",
+            "properties": {
+                "distance": {
+                    "description": "Шагов по квинтовому кругу между тональностями",
+                    "type": "integer"
+                },
+                "fromItem": {
+                    "type": "integer"
+                },
+                "fromKey": {
+                    "type": "string"
+                },
+                "segue": {
+                    "description": "Песни играются без паузы",
+                    "type": "boolean"
+                },
+                "set": {
+                    "description": "Номер сета, начиная с 1",
+                    "type": "integer"
+                },
+                "toItem": {
+                    "type": "integer"
+                },
+                "toKey": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LRCTag": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LyricKind": {
+            "type": "string",
+            "enum": [
+                "original",
+                "translation",
+                "transliteration"
+            ],
+            "x-enum-varnames": [
+                "LyricOriginal",
+                "LyricTranslation",
+                "LyricTransliteration"
+            ]
+        },
+        "models.LyricSection": {
+            "description": "Часть текста песни",
+            "type": "object",
+            "properties": {
+                "kind": {
+                    "description": "Вид части",
+                    "enum": [
+                        "verse",
+                        "chorus",
+                        "pre-chorus",
+                        "bridge",
+                        "outro"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SectionKind"
+                        }
+                    ],
+                    "example": "chorus"
+                },
+                "label": {
+                    "description": "Заголовок из текста, например Verse 2",
+                    "type": "string",
+                    "example": "Chorus"
+                },
+                "lines": {
+                    "description": "Строки, у повтора отсутствуют",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "position": {
+                    "description": "Номер части, начиная с 1",
+                    "type": "integer",
+                    "example": 2
+                },
+                "repeatOf": {
+                    "description": "Номер части, которая повторяется",
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "models.LyricVersion": {
+            "description": "Версия текста песни на одном языке",
+            "type": "object",
+            "properties": {
+                "kind": {
+                    "description": "Вид версии",
+                    "enum": [
+                        "original",
+                        "translation",
+                        "transliteration"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.LyricKind"
+                        }
+                    ],
+                    "example": "translation"
+                },
+                "language": {
+                    "description": "Языковой тег BCP 47, например en, ru или ru-Latn",
+                    "type": "string",
+                    "example": "en"
+                },
+                "text": {
+                    "type": "string",
+                    "example": "Ooh baby, don't you know I suffer?"
+                }
+            }
+        },
+        "models.PlacedChord": {
+            "description": "Аккорд над позицией в строке текста",
+            "type": "object",
+            "properties": {
+                "chord": {
+                    "type": "string",
+                    "example": "Am7"
+                },
+                "column": {
+                    "description": "Позиция символа в строке текста, начиная с 0",
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "models.Playlist": {
+            "description": "Модель плейлиста",
+            "type": "object",
+            "properties": {
+                "description": {
+                    "description": "Описание",
+                    "type": "string",
+                    "example": ""
+                },
+                "name": {
+                    "description": "Название",
+                    "type": "string",
+                    "example": "В дорогу"
+                },
+                "visibility": {
+                    "description": "Кому виден плейлист",
+                    "enum": [
+                        "private",
+                        "unlisted",
+                        "public"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PlaylistVisibility"
+                        }
+                    ],
+                    "example": "private"
+                }
+            }
+        },
+        "models.PlaylistEntry": {
+            "description": "Запись плейлиста",
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "playlistId": {
+                    "type": "integer"
+                },
+                "position": {
+                    "description": "Позиция в плейлисте, начиная с 1",
+                    "type": "integer",
+                    "example": 1
+                },
+                "song": {
+                    "description": "Песня, отсутствует, если песня в корзине",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Song"
+                        }
+                    ]
+                },
+                "songId": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.PlaylistVisibility": {
+            "type": "string",
+            "enum": [
+                "private",
+                "unlisted",
+                "public"
+            ],
+            "x-enum-comments": {
+                "PlaylistPrivate": "только владельцу",
+                "PlaylistPublic": "всем",
+                "PlaylistUnlisted": "всем, кто знает ID, но не в общем списке"
+            },
+            "x-enum-varnames": [
+                "PlaylistPrivate",
+                "PlaylistUnlisted",
+                "PlaylistPublic"
+            ]
+        },
+        "models.SearchResult": {
+            "description": "Результат поиска по тексту песни",
+            "type": "object",
+            "properties": {
+                "rank": {
+                    "description": "релевантность, чем больше, тем выше в выдаче",
+                    "type": "number"
+                },
+                "snippet": {
+                    "description": "фрагмент текста в HTML: текст экранирован, совпадения выделены тегами \u003cb\u003e\u003c/b\u003e",
+                    "type": "string"
+                },
+                "song": {
+                    "$ref": "#/definitions/models.Song"
+                }
+            }
+        },
+        "models.SectionKind": {
+            "type": "string",
+            "enum": [
+                "verse",
+                "chorus",
+                "pre-chorus",
+                "bridge",
+                "outro"
+            ],
+            "x-enum-varnames": [
+                "SectionVerse",
+                "SectionChorus",
+                "SectionPreChorus",
+                "SectionBridge",
+                "SectionOutro"
+            ]
+        },
+        "models.SetPlan": {
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "description": "Время сета",
+                    "type": "string",
+                    "example": "45:10"
+                },
+                "encore": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ItemPlan"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Setlist": {
+            "description": "Сет-лист",
+            "type": "object",
+            "properties": {
+                "name": {
+                    "description": "Название",
+                    "type": "string",
+                    "example": "Концерт в клубе"
+                },
+                "notes": {
+                    "description": "Заметки для всей группы",
+                    "type": "string",
+                    "example": ""
+                },
+                "sets": {
+                    "description": "Сеты по порядку, бисы после основных сетов, в списке сет-листов отсутствуют",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SetlistSet"
+                    }
+                },
+                "venue": {
+                    "description": "Место",
+                    "type": "string",
+                    "example": "Москва, клуб"
+                }
+            }
+        },
+        "models.SetlistItem": {
+            "description": "Песня в сете",
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "description": "Длительность M:SS или число секунд",
+                    "type": "string",
+                    "example": "3:45"
+                },
+                "key": {
+                    "description": "Тональность",
+                    "type": "string",
+                    "example": "Am"
+                },
+                "notes": {
+                    "description": "Заметки к песне",
+                    "type": "string",
+                    "example": "Вступление — только гитара"
+                },
+                "segue": {
+                    "description": "Следующая песня начинается без паузы",
+                    "type": "boolean",
+                    "example": false
+                },
+                "songId": {
+                    "description": "ID песни из библиотеки",
+                    "type": "integer",
+                    "example": 1
+                },
+                "tempo": {
+                    "description": "Темп, ударов в минуту",
+                    "type": "integer",
+                    "example": 120
+                }
+            }
+        },
+        "models.SetlistPlan": {
+            "description": "Расчет сет-листа",
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "description": "Общее время всех сетов",
+                    "type": "string",
+                    "example": "1:25:30"
+                },
+                "keyClashes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.KeyClash"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "sets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SetPlan"
+                    }
+                },
+                "unknownDurations": {
+                    "description": "Песни без длительности, в общее время не входят",
+                    "type": "integer"
+                },
+                "venue": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SetlistSet": {
+            "description": "Сет или бис",
+            "type": "object",
+            "properties": {
+                "encore": {
+                    "description": "Бис",
+                    "type": "boolean",
+                    "example": false
+                },
+                "items": {
+                    "description": "Песни сета по порядку",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SetlistItem"
+                    }
+                },
+                "name": {
+                    "description": "Название, по умолчанию «Сет N» или «Бис»",
+                    "type": "string",
+                    "example": "Первый сет"
+                }
+            }
+        },
+        "models.Song": {
+            "description": "Модель песни",
+            "type": "object",
+            "properties": {
+                "artistId": {
+                    "description": "ID исполнителя, если не задан, исполнитель ищется по group",
+                    "type": "integer"
+                },
+                "group": {
+                    "description": "Название группы, берется из исполнителя",
+                    "type": "string"
+                },
+                "song": {
+                    "description": "Название песни",
+                    "type": "string"
+                }
+            }
+        },
+        "models.SongDetails": {
+            "description": "Модель дополнительных данных песни",
+            "type": "object",
+            "properties": {
+                "link": {
+                    "description": "Ссылка на песню",
+                    "type": "string",
+                    "example": ""
+                },
+                "releaseDate": {
+                    "description": "Дата выхода песни: DD.MM.YYYY, MM.YYYY или YYYY",
+                    "type": "string",
+                    "example": "16.07.2006"
+                },
+                "text": {
+                    "description": "Текст песни",
+                    "type": "string",
+                    "example": ""
+                }
+            }
+        },
+        "models.SongRevision": {
+            "description": "Запись истории изменений песни",
+            "type": "object",
+            "properties": {
+                "author": {
+                    "description": "Кто внес изменение",
+                    "type": "string",
+                    "example": "anonymous"
+                },
+                "changes": {
+                    "description": "Измененные поля со старыми и новыми значениями",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FieldChange"
+                    }
+                },
+                "createdAt": {
+                    "description": "Когда внесено изменение",
+                    "type": "string"
+                },
+                "restoredFrom": {
+                    "description": "Номер правки, к которой вернули песню",
+                    "type": "integer",
+                    "example": 1
+                },
+                "revision": {
+                    "description": "Номер правки песни, начиная с 1",
+                    "type": "integer",
+                    "example": 3
+                },
+                "snapshot": {
+                    "description": "Состояние полей песни после изменения",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SongSnapshot"
+                        }
+                    ]
+                },
+                "songId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SongSnapshot": {
+            "description": "Состояние полей песни",
+            "type": "object",
+            "properties": {
+                "artistId": {
+                    "description": "Исполнитель, по нему группа восстанавливается после переименования",
+                    "type": "integer",
+                    "example": 1
+                },
+                "group": {
+                    "type": "string",
+                    "example": "Muse"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "releaseDate": {
+                    "type": "string",
+                    "example": "16.07.2006"
+                },
+                "song": {
+                    "type": "string",
+                    "example": "Supermassive Black Hole"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SongWithDetails": {
+            "description": "Модель песни c дополнительными данными песни",
+            "type": "object",
+            "properties": {
+                "SongDetails": {
+                    "$ref": "#/definitions/models.SongDetails"
+                },
+                "group": {
+                    "type": "string",
+                    "example": ""
                 },
                 "song": {
                     "type": "string",
                     "example": ""
                 }
             }
+        },
+        "models.SyncedLyrics": {
+            "type": "object",
+            "properties": {
+                "lines": {
+                    "description": "Строки, отсортированные по времени",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TimedLine"
+                    }
+                },
+                "offsetMs": {
+                    "description": "Сдвиг из тега offset: положительный показывает строки раньше",
+                    "type": "integer"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.LRCTag"
+                    }
+                }
+            }
+        },
+        "models.Tag": {
+            "description": "Тег песни",
+            "type": "object",
+            "properties": {
+                "name": {
+                    "description": "Название тега в пространстве имен",
+                    "type": "string",
+                    "example": "rock"
+                },
+                "namespace": {
+                    "description": "Пространство имен: genre, mood, language или свое",
+                    "type": "string",
+                    "example": "genre"
+                },
+                "songs": {
+                    "description": "Количество песен с тегом",
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "models.TimedLine": {
+            "description": "Строка текста с меткой времени",
+            "type": "object",
+            "properties": {
+                "text": {
+                    "type": "string",
+                    "example": "Ooh baby"
+                },
+                "timeMs": {
+                    "description": "Время начала строки в миллисекундах",
+                    "type": "integer",
+                    "example": 12000
+                },
+                "words": {
+                    "description": "Метки слов, только для расширенного LRC",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TimedWord"
+                    }
+                }
+            }
+        },
+        "models.TimedWord": {
+            "description": "Слово с меткой времени",
+            "type": "object",
+            "properties": {
+                "text": {
+                    "type": "string",
+                    "example": "baby"
+                },
+                "timeMs": {
+                    "description": "Время начала слова в миллисекундах",
+                    "type": "integer",
+                    "example": 12500
+                }
+            }
         }
     }
 }`
@@ -0,0 +1,203 @@
+// Package enrichment в фоне получает дополнительные данные добавленных песен от внешнего API.
+package enrichment
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"song-library/metadata"
+	"song-library/models"
+	"song-library/repository"
+)
+
+// Store — хранилище, из которого очередь берет песни и задания.
+type Store interface {
+	repository.SongRepository
+	repository.EnrichmentRepository
+}
+
+// Config описывает параметры очереди.
+type Config struct {
+	Workers      int           //количество одновременно работающих обработчиков
+	MaxAttempts  int           //сколько раз пробовать получить данные, прежде чем сдаться
+	BaseBackoff  time.Duration //пауза перед первым повтором, дальше удваивается
+	MaxBackoff   time.Duration //максимальная пауза между повторами
+	PollInterval time.Duration //как часто проверять хранилище на готовые задания
+}
+
+// Queue — очередь заданий на получение данных песен. Задания хранятся в Store,
+// поэтому переживают перезапуск сервера.
+type Queue struct {
+	store    Store
+	provider metadata.Provider
+	cfg      Config
+	wake     chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewQueue создает очередь, подставляя значения по умолчанию для незаполненных полей Config.
+func NewQueue(store Store, provider metadata.Provider, cfg Config) *Queue {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 4
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 5
+	}
+	if cfg.BaseBackoff <= 0 {
+		cfg.BaseBackoff = 10 * time.Second
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = 10 * time.Minute
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 5 * time.Second
+	}
+	return &Queue{
+		store:    store,
+		provider: provider,
+		cfg:      cfg,
+		wake:     make(chan struct{}, cfg.Workers),
+	}
+}
+
+// Start возвращает в очередь задания, прерванные прошлой остановкой, и запускает обработчики.
+// Обработчики работают, пока не отменен ctx.
+func (q *Queue) Start(ctx context.Context) error {
+	requeued, err := q.store.RequeueRunningEnrichmentJobs(ctx)
+	if err != nil {
+		return err
+	}
+	if requeued > 0 {
+		log.Printf("Возвращено в очередь прерванных заданий: %d\n", requeued)
+	}
+
+	for i := 0; i < q.cfg.Workers; i++ {
+		q.wg.Add(1)
+		go q.work(ctx)
+	}
+	return nil
+}
+
+// Wait дожидается завершения обработчиков после отмены контекста Start.
+func (q *Queue) Wait() {
+	q.wg.Wait()
+}
+
+// Notify сообщает обработчикам, что появилось новое задание.
+func (q *Queue) Notify() {
+	select {
+	case q.wake <- struct{}{}:
+	default: //обработчики и так уже разбужены
+	}
+}
+
+// Enqueue ставит песню в очередь заново.
+func (q *Queue) Enqueue(ctx context.Context, songID int) (*models.EnrichmentJob, error) {
+	job, err := q.store.EnqueueEnrichment(ctx, songID)
+	if err != nil {
+		return nil, err
+	}
+	q.Notify()
+	return job, nil
+}
+
+// Job возвращает задание песни.
+func (q *Queue) Job(ctx context.Context, songID int) (*models.EnrichmentJob, error) {
+	return q.store.GetEnrichmentJob(ctx, songID)
+}
+
+func (q *Queue) work(ctx context.Context) {
+	defer q.wg.Done()
+
+	ticker := time.NewTicker(q.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		job, err := q.store.ClaimEnrichmentJob(ctx)
+		if err == nil {
+			q.process(ctx, job)
+			continue
+		}
+		if !errors.Is(err, repository.ErrNotFound) && ctx.Err() == nil {
+			log.Printf("Не удалось получить задание из очереди, %v\n", err)
+		}
+
+		select { //ждем новое задание или наступление времени повтора
+		case <-ctx.Done():
+			return
+		case <-q.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+func (q *Queue) process(ctx context.Context, job *models.EnrichmentJob) {
+	song, err := q.store.GetSong(ctx, job.SongId)
+	if err != nil {
+		q.fail(ctx, job, err)
+		return
+	}
+
+	details, err := q.provider.SongInfo(ctx, song.Group, song.Song)
+	if err == nil {
+		err = q.store.CompleteEnrichmentJob(ctx, job.Id, *details)
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+		log.Printf("Не удалось сохранить данные песни %d, %v\n", job.SongId, err)
+		if job.Attempts < q.cfg.MaxAttempts { //данные получим заново, иначе задание останется running до перезапуска
+			q.retry(ctx, job, err, "")
+		} else {
+			q.fail(ctx, job, err)
+		}
+		return
+	}
+	if ctx.Err() != nil { //сервер останавливается, задание будет возвращено в очередь при запуске
+		return
+	}
+
+	var metaErr *metadata.Error
+	if errors.As(err, &metaErr) && metaErr.Retryable() && job.Attempts < q.cfg.MaxAttempts {
+		q.retry(ctx, job, err, string(metaErr.Kind))
+		return
+	}
+	q.fail(ctx, job, err)
+}
+
+// retry возвращает задание в очередь после паузы. Если это не удалось, завершает задание с ошибкой.
+func (q *Queue) retry(ctx context.Context, job *models.EnrichmentJob, cause error, kind string) {
+	runAt := time.Now().Add(q.backoff(job.Attempts))
+	log.Printf("Повтор получения данных песни %d в %v, %v\n", job.SongId, runAt.Format(time.TimeOnly), cause)
+	err := q.store.RetryEnrichmentJob(ctx, job.Id, cause.Error(), kind, runAt)
+	if err == nil || ctx.Err() != nil {
+		return
+	}
+	log.Printf("Не удалось вернуть задание %d в очередь, %v\n", job.Id, err)
+	q.fail(ctx, job, cause)
+}
+
+func (q *Queue) fail(ctx context.Context, job *models.EnrichmentJob, cause error) {
+	kind := ""
+	var metaErr *metadata.Error
+	if errors.As(cause, &metaErr) {
+		kind = string(metaErr.Kind)
+	}
+
+	log.Printf("Не удалось получить данные песни %d, %v\n", job.SongId, cause)
+	if err := q.store.FailEnrichmentJob(ctx, job.Id, cause.Error(), kind); err != nil {
+		//задание останется running, пока сервер не перезапустят
+		log.Printf("Не удалось завершить задание %d, %v\n", job.Id, err)
+	}
+}
+
+// backoff возвращает паузу перед повтором после attempt попыток.
+func (q *Queue) backoff(attempt int) time.Duration {
+	d := q.cfg.BaseBackoff << (attempt - 1)
+	if d <= 0 || d > q.cfg.MaxBackoff {
+		d = q.cfg.MaxBackoff
+	}
+	return d
+}
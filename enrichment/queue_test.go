@@ -0,0 +1,283 @@
+package enrichment
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"song-library/metadata"
+	"song-library/models"
+	"song-library/repository"
+)
+
+// stubProvider возвращает одни и те же данные или ошибку для любой песни.
+type stubProvider struct {
+	details models.SongDetails
+	err     error
+}
+
+func (p stubProvider) SongInfo(ctx context.Context, group, song string) (*models.SongDetails, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	details := p.details
+	return &details, nil
+}
+
+// addPendingSong добавляет в хранилище песню, ожидающую получения данных.
+func addPendingSong(t *testing.T, repo *repository.Memory) *models.Song {
+	t.Helper()
+	song := &models.Song{Group: "Muse", Song: "Hysteria", EnrichmentStatus: models.EnrichmentPending}
+	if err := repo.CreateSong(context.Background(), song); err != nil {
+		t.Fatal(err)
+	}
+	return song
+}
+
+func TestQueueStartRequeuesRunningJobs(t *testing.T) {
+	repo := repository.NewMemory()
+	song := addPendingSong(t, repo)
+
+	//задание было взято обработчиком, но сервер остановился до его завершения
+	if _, err := repo.ClaimEnrichmentJob(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	q := NewQueue(repo, stubProvider{details: models.SongDetails{Text: "It's bugging me"}}, Config{Workers: 2, PollInterval: 5 * time.Millisecond})
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := q.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		cancel()
+		q.Wait()
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		job, err := q.Job(context.Background(), song.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job.Status == models.JobSucceeded {
+			if job.Attempts != 2 {
+				t.Errorf("попыток %d, ожидалось 2", job.Attempts)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("прерванное задание не выполнено после запуска: %+v", job)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	got, err := repo.GetSong(context.Background(), song.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.EnrichmentStatus != models.EnrichmentSucceeded || got.SongDetails.Text != "It's bugging me" {
+		t.Errorf("песня после получения данных = %+v", got)
+	}
+}
+
+func TestQueueProcessFailure(t *testing.T) {
+	const base = time.Second
+	unavailable := &metadata.Error{Kind: metadata.KindUnavailable, StatusCode: 503, Attempts: 3}
+
+	tests := []struct {
+		name      string
+		err       error
+		attempts  int //сколько попыток сделано вместе с текущей
+		wantJob   models.JobStatus
+		wantKind  string
+		wantDelay time.Duration //пауза перед повтором для wantJob == JobQueued
+	}{
+		{name: "первый повтор", err: unavailable, attempts: 1, wantJob: models.JobQueued, wantKind: "unavailable", wantDelay: base},
+		{name: "пауза удваивается", err: &metadata.Error{Kind: metadata.KindTimeout}, attempts: 3, wantJob: models.JobQueued, wantKind: "timeout", wantDelay: 4 * base},
+		{name: "пауза не больше максимальной", err: unavailable, attempts: 4, wantJob: models.JobQueued, wantKind: "unavailable", wantDelay: 5 * base},
+		{name: "попытки кончились", err: unavailable, attempts: 5, wantJob: models.JobFailed, wantKind: "unavailable"},
+		{name: "песня не найдена во внешнем API", err: &metadata.Error{Kind: metadata.KindNotFound, StatusCode: 404}, attempts: 1, wantJob: models.JobFailed, wantKind: "not_found"},
+		{name: "ошибка не внешнего API", err: errors.New("boom"), attempts: 1, wantJob: models.JobFailed, wantKind: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repository.NewMemory()
+			song := addPendingSong(t, repo)
+			q := NewQueue(repo, stubProvider{err: tt.err}, Config{MaxAttempts: 5, BaseBackoff: base, MaxBackoff: 5 * base})
+
+			job, err := repo.ClaimEnrichmentJob(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			job.Attempts = tt.attempts
+
+			before := time.Now()
+			q.process(context.Background(), job)
+			after := time.Now()
+
+			got, err := q.Job(context.Background(), song.Id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Status != tt.wantJob || got.LastErrorKind != tt.wantKind || got.LastError != tt.err.Error() {
+				t.Errorf("задание = %+v, ожидалось состояние %s и вид ошибки %q", got, tt.wantJob, tt.wantKind)
+			}
+			if tt.wantJob == models.JobQueued && (got.RunAt.Before(before.Add(tt.wantDelay)) || got.RunAt.After(after.Add(tt.wantDelay))) {
+				t.Errorf("повтор в %v, ожидалось через %v после %v", got.RunAt, tt.wantDelay, before)
+			}
+
+			wantSong := models.EnrichmentPending
+			if tt.wantJob == models.JobFailed {
+				wantSong = models.EnrichmentFailed
+			}
+			if s, _ := repo.GetSong(context.Background(), song.Id); s.EnrichmentStatus != wantSong {
+				t.Errorf("состояние песни %s, ожидалось %s", s.EnrichmentStatus, wantSong)
+			}
+		})
+	}
+}
+
+func TestQueueProcessFillsEmptyFields(t *testing.T) {
+	released, _ := models.ParseReleaseDate("2003")
+	edited, _ := models.ParseReleaseDate("01.12.2003")
+	found := models.SongDetails{Text: "It's bugging me", Link: "https://example.com/hysteria", ReleaseDate: released}
+
+	tests := []struct {
+		name    string
+		current models.SongDetails
+		want    models.SongDetails
+	}{
+		{name: "все поля пустые", current: models.SongDetails{}, want: found},
+		{
+			name:    "текст изменен пользователем",
+			current: models.SongDetails{Text: "Grating me"},
+			want:    models.SongDetails{Text: "Grating me", Link: found.Link, ReleaseDate: released},
+		},
+		{
+			name:    "все поля заполнены",
+			current: models.SongDetails{Text: "Grating me", Link: "https://example.com/edited", ReleaseDate: edited},
+			want:    models.SongDetails{Text: "Grating me", Link: "https://example.com/edited", ReleaseDate: edited},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repository.NewMemory()
+			song := &models.Song{Group: "Muse", Song: "Hysteria", EnrichmentStatus: models.EnrichmentPending, SongDetails: tt.current}
+			if err := repo.CreateSong(context.Background(), song); err != nil {
+				t.Fatal(err)
+			}
+			q := NewQueue(repo, stubProvider{details: found}, Config{})
+
+			job, err := repo.ClaimEnrichmentJob(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			q.process(context.Background(), job)
+
+			got, err := repo.GetSong(context.Background(), song.Id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d := got.SongDetails
+			if d.Text != tt.want.Text || d.Link != tt.want.Link || d.ReleaseDate != tt.want.ReleaseDate {
+				t.Errorf("данные песни = %+v, ожидалось %+v", d, tt.want)
+			}
+			if got.EnrichmentStatus != models.EnrichmentSucceeded {
+				t.Errorf("состояние песни %s, ожидалось %s", got.EnrichmentStatus, models.EnrichmentSucceeded)
+			}
+		})
+	}
+}
+
+func TestQueueProcessTrashedSong(t *testing.T) {
+	repo := repository.NewMemory()
+	song := addPendingSong(t, repo)
+	q := NewQueue(repo, stubProvider{details: models.SongDetails{Text: "It's bugging me"}}, Config{})
+
+	job, err := repo.ClaimEnrichmentJob(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	//песню переместили в корзину, пока задание выполнялось
+	if err := repo.DeleteSong(context.Background(), song.Id, 0); err != nil {
+		t.Fatal(err)
+	}
+	q.process(context.Background(), job)
+
+	got, err := q.Job(context.Background(), song.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != models.JobFailed {
+		t.Errorf("задание = %+v, ожидалось состояние %s", got, models.JobFailed)
+	}
+	restored, err := repo.RestoreSong(context.Background(), song.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.SongDetails.Text != "" {
+		t.Errorf("данные песни из корзины сохранены: %+v", restored.SongDetails)
+	}
+}
+
+// failingStore не сохраняет полученные данные песен.
+type failingStore struct {
+	*repository.Memory
+}
+
+func (s failingStore) CompleteEnrichmentJob(ctx context.Context, jobID int, details models.SongDetails) error {
+	return errors.New("connection reset")
+}
+
+func TestQueueProcessSaveFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		attempts int
+		wantJob  models.JobStatus
+	}{
+		{name: "задание повторяется", attempts: 1, wantJob: models.JobQueued},
+		{name: "попытки кончились", attempts: 3, wantJob: models.JobFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repository.NewMemory()
+			song := addPendingSong(t, repo)
+			q := NewQueue(failingStore{repo}, stubProvider{details: models.SongDetails{Text: "It's bugging me"}}, Config{MaxAttempts: 3})
+
+			job, err := repo.ClaimEnrichmentJob(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			job.Attempts = tt.attempts
+			q.process(context.Background(), job)
+
+			got, err := q.Job(context.Background(), song.Id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Status != tt.wantJob || got.LastError != "connection reset" {
+				t.Errorf("задание = %+v, ожидалось состояние %s", got, tt.wantJob)
+			}
+		})
+	}
+}
+
+func TestQueueBackoff(t *testing.T) {
+	q := NewQueue(nil, nil, Config{BaseBackoff: 10 * time.Second, MaxBackoff: time.Minute})
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: 10 * time.Second},
+		{attempt: 2, want: 20 * time.Second},
+		{attempt: 3, want: 40 * time.Second},
+		{attempt: 4, want: time.Minute},
+		{attempt: 70, want: time.Minute}, //сдвиг переполняет Duration
+	}
+	for _, tt := range tests {
+		if got := q.backoff(tt.attempt); got != tt.want {
+			t.Errorf("backoff(%d) = %v, ожидалось %v", tt.attempt, got, tt.want)
+		}
+	}
+}
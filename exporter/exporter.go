@@ -0,0 +1,119 @@
+// Package exporter потоком записывает таблицы в форматах CSV, JSON Lines и XLSX:
+// строки пишутся по одной, без накопления всей таблицы в памяти.
+package exporter
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Форматы выгрузки.
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+	FormatXLSX  = "xlsx"
+)
+
+// ContentTypes — типы содержимого форматов выгрузки.
+var ContentTypes = map[string]string{
+	FormatCSV:   "text/csv; charset=utf-8",
+	FormatJSONL: "application/x-ndjson",
+	FormatXLSX:  "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// Writer записывает строки таблицы с колонками, переданными при создании.
+type Writer interface {
+	// Write записывает строку: значения колонок по порядку.
+	Write(values []string) error
+	// Close дописывает окончание файла. Сам io.Writer не закрывается.
+	Close() error
+}
+
+// NewWriter создает Writer формата format с колонками columns. Для CSV и XLSX колонки
+// записываются заголовком, для JSON Lines становятся ключами объектов.
+func NewWriter(format string, w io.Writer, columns []string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, columns)
+	case FormatJSONL:
+		return &jsonlWriter{encoder: json.NewEncoder(w), columns: columns}, nil
+	case FormatXLSX:
+		return newXLSXWriter(w, columns)
+	default:
+		return nil, fmt.Errorf("неподдерживаемый формат %q, допустимы csv, jsonl и xlsx", format)
+	}
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+	rows   int
+}
+
+// csvFlushRows — через сколько строк CSV отправляется клиенту.
+const csvFlushRows = 100
+
+func newCSVWriter(w io.Writer, columns []string) (*csvWriter, error) {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return nil, err
+	}
+	return &csvWriter{writer: writer}, nil
+}
+
+func (w *csvWriter) Write(values []string) error {
+	if err := w.writer.Write(values); err != nil {
+		return err
+	}
+	w.rows++
+	if w.rows%csvFlushRows == 0 {
+		w.writer.Flush()
+		return w.writer.Error()
+	}
+	return nil
+}
+
+func (w *csvWriter) Close() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+type jsonlWriter struct {
+	encoder *json.Encoder
+	columns []string
+}
+
+func (w *jsonlWriter) Write(values []string) error {
+	object := make(orderedObject, len(w.columns))
+	for i, column := range w.columns {
+		object[i] = [2]string{column, values[i]}
+	}
+	return w.encoder.Encode(object)
+}
+
+func (w *jsonlWriter) Close() error {
+	return nil
+}
+
+// orderedObject — JSON объект со строковыми значениями, ключи которого записываются в порядке колонок.
+type orderedObject [][2]string
+
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	buf := []byte{'{'}
+	for i, pair := range o {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		key, err := json.Marshal(pair[0])
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(pair[1])
+		if err != nil {
+			return nil, err
+		}
+		buf = append(append(append(buf, key...), ':'), value...)
+	}
+	return append(buf, '}'), nil
+}
@@ -0,0 +1,199 @@
+package exporter
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCellRef(t *testing.T) {
+	tests := []struct {
+		column, row int
+		want        string
+	}{
+		{0, 1, "A1"},
+		{1, 7, "B7"},
+		{25, 2, "Z2"},
+		{26, 3, "AA3"},
+		{51, 4, "AZ4"},
+		{52, 5, "BA5"},
+		{701, 6, "ZZ6"},
+		{702, 7, "AAA7"},
+	}
+	for _, tt := range tests {
+		if got := cellRef(tt.column, tt.row); got != tt.want {
+			t.Errorf("cellRef(%d, %d) = %s, ожидалось %s", tt.column, tt.row, got, tt.want)
+		}
+	}
+}
+
+func TestOrderedObjectMarshalJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		object orderedObject
+		want   string
+	}{
+		{"пустой", orderedObject{}, `{}`},
+		{"порядок колонок", orderedObject{{"song", "Hysteria"}, {"group", "Muse"}, {"id", "1"}}, `{"song":"Hysteria","group":"Muse","id":"1"}`},
+		{"кавычки и перевод строки", orderedObject{{"text", "It's \"bugging\" me,\nи\tвсе"}}, `{"text":"It's \"bugging\" me,\nи\tвсе"}`},
+		{"HTML", orderedObject{{"a<b>", "x & y"}}, `{"a\u003cb\u003e":"x \u0026 y"}`},
+		{"управляющие символы", orderedObject{{"k", "\x00\\"}}, `{"k":"\u0000\\"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.object)
+			if err != nil {
+				t.Fatalf("MarshalJSON() ошибка: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("MarshalJSON() = %s, ожидалось %s", got, tt.want)
+			}
+			var back map[string]string
+			if err := json.Unmarshal(got, &back); err != nil {
+				t.Fatalf("некоректный JSON %s: %v", got, err)
+			}
+			for _, pair := range tt.object {
+				if back[pair[0]] != pair[1] {
+					t.Errorf("значение %q = %q, ожидалось %q", pair[0], back[pair[0]], pair[1])
+				}
+			}
+		})
+	}
+}
+
+var testRows = [][]string{
+	{"1", "Muse", "It's \"bugging\" me,\n<grating> & me"},
+	{"2", "", "Uprising"},
+}
+
+func writeTable(t *testing.T, format string, columns []string, rows [][]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewWriter(format, &buf, columns)
+	if err != nil {
+		t.Fatalf("NewWriter(%s) ошибка: %v", format, err)
+	}
+	for _, row := range rows {
+		if err := w.Write(row); err != nil {
+			t.Fatalf("Write() ошибка: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() ошибка: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestNewWriterFormat(t *testing.T) {
+	if _, err := NewWriter("pdf", io.Discard, []string{"id"}); err == nil {
+		t.Errorf("NewWriter(pdf) без ошибки")
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	columns := []string{"id", "group", "text"}
+	got, err := csv.NewReader(bytes.NewReader(writeTable(t, FormatCSV, columns, testRows))).ReadAll()
+	if err != nil {
+		t.Fatalf("некоректный CSV: %v", err)
+	}
+	want := append([][]string{columns}, testRows...)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CSV = %q, ожидалось %q", got, want)
+	}
+}
+
+func TestJSONLWriter(t *testing.T) {
+	got := string(writeTable(t, FormatJSONL, []string{"id", "group", "text"}, testRows))
+	want := `{"id":"1","group":"Muse","text":"It's \"bugging\" me,\n\u003cgrating\u003e \u0026 me"}` + "\n" +
+		`{"id":"2","group":"","text":"Uprising"}` + "\n"
+	if got != want {
+		t.Errorf("JSON Lines = %s, ожидалось %s", got, want)
+	}
+}
+
+// sheet — лист XLSX в объеме, который записывает xlsxWriter.
+type sheet struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			R    string `xml:"r,attr"`
+			T    string `xml:"t,attr"`
+			Text string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func TestXLSXWriter(t *testing.T) {
+	columns := make([]string, 28) //колонки за Z: AA и AB
+	for i := range columns {
+		columns[i] = cellRef(i, 0)
+	}
+	row := make([]string, len(columns))
+	row[0], row[26], row[27] = "первая", "<AA> & \"AA\"", "  пробелы\nи перевод строки "
+	data := writeTable(t, FormatXLSX, columns, append(testRows[:1:1], row))
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("XLSX не является архивом zip: %v", err)
+	}
+	var names []string
+	var content []byte
+	for _, f := range archive.File {
+		names = append(names, f.Name)
+		if f.Name != "xl/worksheets/sheet1.xml" {
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			t.Fatalf("не удалось открыть лист: %v", err)
+		}
+		content, err = io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatalf("не удалось прочитать лист: %v", err)
+		}
+	}
+	wantNames := []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/worksheets/sheet1.xml"}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Errorf("части книги = %v, ожидалось %v", names, wantNames)
+	}
+
+	var s sheet
+	if err := xml.Unmarshal(content, &s); err != nil {
+		t.Fatalf("некоректный лист %s: %v", content, err)
+	}
+	if len(s.Rows) != 3 {
+		t.Fatalf("строк = %d, ожидалось 3", len(s.Rows))
+	}
+	cells := make(map[string]string)
+	for i, r := range s.Rows {
+		if r.R != i+1 {
+			t.Errorf("номер строки %d = %d", i, r.R)
+		}
+		for _, c := range r.Cells {
+			if c.T != "inlineStr" {
+				t.Errorf("ячейка %s: тип %q, ожидался inlineStr", c.R, c.T)
+			}
+			cells[c.R] = c.Text
+		}
+	}
+	want := map[string]string{
+		"A2": "1", "B2": "Muse", "C2": testRows[0][2],
+		"A3": "первая", "AA3": row[26], "AB3": row[27],
+	}
+	for i, column := range columns {
+		want[cellRef(i, 1)] = column
+	}
+	if !reflect.DeepEqual(cells, want) {
+		t.Errorf("ячейки = %q, ожидалось %q", cells, want)
+	}
+	if strings.Contains(string(content), `r="B3"`) {
+		t.Errorf("пустая ячейка записана в лист")
+	}
+}
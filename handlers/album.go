@@ -0,0 +1,325 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"song-library/models"
+	"song-library/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AlbumHandler struct {
+	Repo repository.AlbumRepository
+}
+
+func NewAlbumHandler(repo repository.AlbumRepository) *AlbumHandler {
+	return &AlbumHandler{Repo: repo}
+}
+
+// AlbumTrackRequest — позиция песни в альбоме.
+// @Description Позиция песни в альбоме
+type AlbumTrackRequest struct {
+	SongId int `json:"songId" example:"1"`
+	Disc   int `json:"disc" example:"1"` //Номер диска, по умолчанию 1
+	Track  int `json:"track" example:"3"`
+}
+
+// Получить список альбомов
+// @Summary Получить альбомы
+// @Description Получить список альбомов, отсортированный по дате выхода
+// @Tags albums
+// @Produce json
+// @Param artist_id query int false "Фильтр по ID исполнителя"
+// @Param title query string false "Фильтр по фрагменту названия"
+// @Param page query int false "Номер страницы(пагинация)" default(1)
+// @Param limit query int false "Лимит записей на странице" default(10)
+// @Success 200 {array} models.Album
+// @Failure 400 {string} string "Неверный формат параметров запроса"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /albums [get]
+func (h *AlbumHandler) GetAlbums(c *gin.Context) {
+	page, limit, ok := pagination(c, 10)
+	if !ok {
+		return
+	}
+	var artistID int
+	if value := c.Query("artist_id"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректное значение artist_id"})
+			return
+		}
+		artistID = id
+	}
+
+	albums, err := h.Repo.ListAlbums(c.Request.Context(), repository.AlbumFilter{
+		ArtistID: artistID,
+		Title:    c.Query("title"),
+		Offset:   limit * (page - 1),
+		Limit:    limit,
+	})
+	if err != nil {
+		respondRepositoryError(c, err, "Альбомы не найдены")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"page":   page,
+		"limit":  limit,
+		"albums": albums,
+	})
+}
+
+// Получить альбом по ID
+// @Summary Получить альбом
+// @Description Получить альбом по его ID
+// @Tags albums
+// @Produce json
+// @Param id path int true "ID альбома"
+// @Success 200 {object} models.Album
+// @Failure 400 {string} string "Некоректное значение id"
+// @Failure 404 {string} string "Альбом не найден"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /albums/{id} [get]
+func (h *AlbumHandler) GetAlbum(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	album, err := h.Repo.GetAlbum(c.Request.Context(), id)
+	if err != nil {
+		respondRepositoryError(c, err, "Альбом не найден")
+		return
+	}
+
+	c.JSON(http.StatusOK, album)
+}
+
+// Добавить альбом
+// @Summary Добавить альбом
+// @Description Добавить новый альбом
+// @Tags albums
+// @Accept json
+// @Produce json
+// @Param album body models.Album true "Данные альбома"
+// @Success 201 {object} models.Album
+// @Failure 400 {string} string "Неверный формат данных"
+// @Failure 409 {string} string "Исполнитель не найден"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /albums [post]
+func (h *AlbumHandler) AddAlbum(c *gin.Context) {
+	album, ok := bindAlbum(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Repo.CreateAlbum(c.Request.Context(), &album); err != nil {
+		respondAlbumError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, album)
+}
+
+// Редактировать альбом по ID
+// @Summary Редактировать альбом
+// @Description Заменить данные альбома
+// @Tags albums
+// @Accept json
+// @Produce json
+// @Param id path int true "ID альбома"
+// @Param album body models.Album true "Данные альбома"
+// @Success 200 {object} models.Album
+// @Failure 400 {string} string "Неверный формат данных"
+// @Failure 404 {string} string "Альбом не найден"
+// @Failure 409 {string} string "Исполнитель не найден"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /albums/{id} [put]
+func (h *AlbumHandler) EditAlbum(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	album, ok := bindAlbum(c)
+	if !ok {
+		return
+	}
+
+	updated, err := h.Repo.UpdateAlbum(c.Request.Context(), id, album)
+	if err != nil {
+		respondAlbumError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// Удалить альбом по ID
+// @Summary Удалить альбом
+// @Description Удалить альбом вместе со списком треков. Сами песни не удаляются
+// @Tags albums
+// @Produce json
+// @Param id path int true "ID альбома"
+// @Success 200 {string} string "Альбом удален"
+// @Failure 404 {string} string "Альбом не найден"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /albums/{id} [delete]
+func (h *AlbumHandler) DeleteAlbum(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.Repo.DeleteAlbum(c.Request.Context(), id); err != nil {
+		respondRepositoryError(c, err, "Альбом не найден")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Альбом удален"})
+}
+
+// Получить треки альбома
+// @Summary Получить треки альбома
+// @Description Получить список треков альбома с песнями в порядке дисков и номеров треков
+// @Tags albums
+// @Produce json
+// @Param id path int true "ID альбома"
+// @Success 200 {array} models.AlbumTrack
+// @Failure 400 {string} string "Некоректное значение id"
+// @Failure 404 {string} string "Альбом не найден"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /albums/{id}/tracks [get]
+func (h *AlbumHandler) GetAlbumTracks(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	tracks, err := h.Repo.ListAlbumTracks(c.Request.Context(), id)
+	if err != nil {
+		respondRepositoryError(c, err, "Альбом не найден")
+		return
+	}
+	if tracks == nil {
+		tracks = []models.AlbumTrack{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tracks": tracks})
+}
+
+// Поставить песню на позицию в альбоме
+// @Summary Добавить трек в альбом
+// @Description Поставить песню на позицию в альбоме. Песня, стоявшая на этой позиции, заменяется.
+// @Description Одна песня может входить в несколько альбомов
+// @Tags albums
+// @Accept json
+// @Produce json
+// @Param id path int true "ID альбома"
+// @Param track body AlbumTrackRequest true "Позиция песни"
+// @Success 200 {object} models.AlbumTrack
+// @Failure 400 {string} string "Неверный формат данных"
+// @Failure 404 {string} string "Альбом не найден"
+// @Failure 409 {string} string "Песня не найдена"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /albums/{id}/tracks [post]
+func (h *AlbumHandler) AddAlbumTrack(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	var req AlbumTrackRequest
+	if err := c.BindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	if req.Disc == 0 {
+		req.Disc = 1
+	}
+	if req.SongId <= 0 || req.Disc < 0 || req.Track <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректная позиция трека: songId, disc и track должны быть положительными"})
+		return
+	}
+
+	track := models.AlbumTrack{AlbumId: id, DiscNumber: req.Disc, TrackNumber: req.Track, SongId: req.SongId}
+	if err := h.Repo.SetAlbumTrack(c.Request.Context(), track); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Песня не найдена"})
+			return
+		}
+		respondRepositoryError(c, err, "Альбом не найден")
+		return
+	}
+
+	c.JSON(http.StatusOK, track)
+}
+
+// Убрать трек из альбома
+// @Summary Удалить трек из альбома
+// @Description Убрать песню с позиции в альбоме
+// @Tags albums
+// @Produce json
+// @Param id path int true "ID альбома"
+// @Param disc path int true "Номер диска"
+// @Param track path int true "Номер трека"
+// @Success 200 {string} string "Трек удален"
+// @Failure 400 {string} string "Некоректное значение параметра пути"
+// @Failure 404 {string} string "Трек не найден"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /albums/{id}/tracks/{disc}/{track} [delete]
+func (h *AlbumHandler) DeleteAlbumTrack(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	disc, ok := pathID(c, "disc")
+	if !ok {
+		return
+	}
+	track, ok := pathID(c, "track")
+	if !ok {
+		return
+	}
+
+	if err := h.Repo.RemoveAlbumTrack(c.Request.Context(), id, disc, track); err != nil {
+		respondRepositoryError(c, err, "Трек не найден")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Трек удален"})
+}
+
+// bindAlbum разбирает и проверяет тело запроса с данными альбома.
+func bindAlbum(c *gin.Context) (models.Album, bool) {
+	var album models.Album
+	if err := c.BindJSON(&album); err != nil {
+		respondBindError(c, err)
+		return album, false
+	}
+
+	album.Title = strings.TrimSpace(album.Title)
+	if album.Title == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Не задано название альбома"})
+		return album, false
+	}
+	if album.Type == "" {
+		album.Type = models.AlbumLP
+	}
+	if !album.Type.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректное значение type, ожидается lp, ep, single или compilation"})
+		return album, false
+	}
+	return album, true
+}
+
+func respondAlbumError(c *gin.Context, err error) {
+	if errors.Is(err, repository.ErrConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Исполнитель не найден"})
+		return
+	}
+	respondRepositoryError(c, err, "Альбом не найден")
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"song-library/models"
+)
+
+func TestAlbums(t *testing.T) {
+	s := newTestServer(t)
+	hysteria := s.addSong("Muse", "Hysteria", "", "2003")
+	stockholm := s.addSong("Muse", "Stockholm Syndrome", "", "2003")
+
+	var album models.Album
+	s.expect(http.StatusCreated, &album, http.MethodPost, "/albums",
+		`{"title":"Absolution","artistId":`+strconv.Itoa(*hysteria.ArtistId)+`,"releaseDate":"15.09.2003"}`)
+	if album.Id == 0 || album.Type != models.AlbumLP || album.ReleaseDate.String() != "15.09.2003" {
+		t.Errorf("альбом = %+v", album)
+	}
+	path := "/albums/" + strconv.Itoa(album.Id)
+
+	s.expect(http.StatusBadRequest, nil, http.MethodPost, "/albums", `{"title":""}`)
+	s.expect(http.StatusBadRequest, nil, http.MethodPost, "/albums", `{"title":"Live","type":"bootleg"}`)
+	s.expect(http.StatusConflict, nil, http.MethodPost, "/albums", `{"title":"Live","artistId":100}`)
+
+	s.expect(http.StatusOK, nil, http.MethodPost, path+"/tracks", `{"songId":`+strconv.Itoa(stockholm.Id)+`,"track":3}`)
+	s.expect(http.StatusOK, nil, http.MethodPost, path+"/tracks", `{"songId":`+strconv.Itoa(hysteria.Id)+`,"track":1}`)
+	s.expect(http.StatusConflict, nil, http.MethodPost, path+"/tracks", `{"songId":100,"track":2}`)
+	s.expect(http.StatusBadRequest, nil, http.MethodPost, path+"/tracks", `{"songId":1,"track":0}`)
+	s.expect(http.StatusNotFound, nil, http.MethodPost, "/albums/100/tracks", `{"songId":1,"track":1}`)
+
+	var tracks struct {
+		Tracks []models.AlbumTrack `json:"tracks"`
+	}
+	s.expect(http.StatusOK, &tracks, http.MethodGet, path+"/tracks", "")
+	if len(tracks.Tracks) != 2 || tracks.Tracks[0].SongId != hysteria.Id || tracks.Tracks[1].TrackNumber != 3 || tracks.Tracks[1].DiscNumber != 1 {
+		t.Errorf("треки альбома = %+v", tracks.Tracks)
+	}
+
+	var albums struct {
+		Albums []models.Album `json:"albums"`
+	}
+	s.expect(http.StatusOK, &albums, http.MethodGet, "/albums?artist_id="+strconv.Itoa(*hysteria.ArtistId), "")
+	if len(albums.Albums) != 1 || albums.Albums[0].Artist != "Muse" {
+		t.Errorf("альбомы исполнителя = %+v", albums.Albums)
+	}
+	s.expect(http.StatusBadRequest, nil, http.MethodGet, "/albums?artist_id=x", "")
+
+	var updated models.Album
+	s.expect(http.StatusOK, &updated, http.MethodPut, path, `{"title":"Absolution XX","type":"compilation"}`)
+	if updated.Title != "Absolution XX" || updated.Type != models.AlbumCompilation || updated.ArtistId != nil {
+		t.Errorf("альбом после изменения = %+v", updated)
+	}
+
+	s.expect(http.StatusOK, nil, http.MethodDelete, path+"/tracks/1/1", "")
+	s.expect(http.StatusOK, nil, http.MethodDelete, path+"/tracks/1/3", "")
+	s.expect(http.StatusNotFound, nil, http.MethodDelete, path+"/tracks/1/3", "")
+	if rec := s.expect(http.StatusOK, nil, http.MethodGet, path+"/tracks", ""); !strings.Contains(rec.Body.String(), `"tracks":[]`) {
+		t.Errorf("пустой список треков: тело %s", rec.Body.String())
+	}
+	s.expect(http.StatusOK, nil, http.MethodDelete, path, "")
+	s.expect(http.StatusNotFound, nil, http.MethodGet, path, "")
+	s.expect(http.StatusNotFound, nil, http.MethodGet, path+"/tracks", "")
+}
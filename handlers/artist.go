@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"song-library/models"
+	"song-library/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ArtistHandler struct {
+	Repo  repository.ArtistRepository
+	Songs repository.SongRepository
+}
+
+func NewArtistHandler(repo repository.ArtistRepository, songs repository.SongRepository) *ArtistHandler {
+	return &ArtistHandler{Repo: repo, Songs: songs}
+}
+
+// Получить список исполнителей
+// @Summary Получить исполнителей
+// @Description Получить список исполнителей, отсортированный по названию
+// @Tags artists
+// @Produce json
+// @Param name query string false "Фильтр по фрагменту названия"
+// @Param page query int false "Номер страницы(пагинация)" default(1)
+// @Param limit query int false "Лимит записей на странице" default(10)
+// @Success 200 {array} models.Artist
+// @Failure 400 {string} string "Неверный формат параметров запроса"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /artists [get]
+func (h *ArtistHandler) GetArtists(c *gin.Context) {
+	page, limit, ok := pagination(c, 10)
+	if !ok {
+		return
+	}
+
+	artists, err := h.Repo.ListArtists(c.Request.Context(), repository.ArtistFilter{
+		Name:   c.Query("name"),
+		Offset: limit * (page - 1),
+		Limit:  limit,
+	})
+	if err != nil {
+		respondRepositoryError(c, err, "Исполнители не найдены")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"page":    page,
+		"limit":   limit,
+		"artists": artists,
+	})
+}
+
+// Получить исполнителя по ID
+// @Summary Получить исполнителя
+// @Description Получить исполнителя по его ID
+// @Tags artists
+// @Produce json
+// @Param id path int true "ID исполнителя"
+// @Success 200 {object} models.Artist
+// @Failure 400 {string} string "Некоректное значение id"
+// @Failure 404 {string} string "Исполнитель не найден"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /artists/{id} [get]
+func (h *ArtistHandler) GetArtist(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	artist, err := h.Repo.GetArtist(c.Request.Context(), id)
+	if err != nil {
+		respondRepositoryError(c, err, "Исполнитель не найден")
+		return
+	}
+
+	c.JSON(http.StatusOK, artist)
+}
+
+// Добавить исполнителя
+// @Summary Добавить исполнителя
+// @Description Добавить нового исполнителя
+// @Tags artists
+// @Accept json
+// @Produce json
+// @Param artist body models.Artist true "Данные исполнителя"
+// @Success 201 {object} models.Artist
+// @Failure 400 {string} string "Неверный формат данных"
+// @Failure 409 {string} string "Исполнитель с таким названием уже есть"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /artists [post]
+func (h *ArtistHandler) AddArtist(c *gin.Context) {
+	artist, ok := bindArtist(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Repo.CreateArtist(c.Request.Context(), &artist); err != nil {
+		respondArtistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, artist)
+}
+
+// Редактировать исполнителя по ID
+// @Summary Редактировать исполнителя
+// @Description Заменить данные исполнителя. Новое название сразу видно во всех его песнях
+// @Tags artists
+// @Accept json
+// @Produce json
+// @Param id path int true "ID исполнителя"
+// @Param artist body models.Artist true "Данные исполнителя"
+// @Success 200 {object} models.Artist
+// @Failure 400 {string} string "Неверный формат данных"
+// @Failure 404 {string} string "Исполнитель не найден"
+// @Failure 409 {string} string "Исполнитель с таким названием уже есть"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /artists/{id} [put]
+func (h *ArtistHandler) EditArtist(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	artist, ok := bindArtist(c)
+	if !ok {
+		return
+	}
+
+	updated, err := h.Repo.UpdateArtist(c.Request.Context(), id, artist)
+	if err != nil {
+		respondArtistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// Удалить исполнителя по ID
+// @Summary Удалить исполнителя
+// @Description Удалить исполнителя, у которого нет песен и альбомов
+// @Tags artists
+// @Produce json
+// @Param id path int true "ID исполнителя"
+// @Success 200 {string} string "Исполнитель удален"
+// @Failure 404 {string} string "Исполнитель не найден"
+// @Failure 409 {string} string "У исполнителя есть песни или альбомы"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /artists/{id} [delete]
+func (h *ArtistHandler) DeleteArtist(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.Repo.DeleteArtist(c.Request.Context(), id); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": "У исполнителя есть песни или альбомы"})
+			return
+		}
+		respondRepositoryError(c, err, "Исполнитель не найден")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Исполнитель удален"})
+}
+
+// Получить песни исполнителя
+// @Summary Получить песни исполнителя
+// @Description Получить песни исполнителя, отсортированные по дате выхода
+// @Tags artists
+// @Produce json
+// @Param id path int true "ID исполнителя"
+// @Param page query int false "Номер страницы(пагинация)" default(1)
+// @Param limit query int false "Лимит записей на странице" default(10)
+// @Success 200 {array} models.Song
+// @Failure 400 {string} string "Неверный формат параметров запроса"
+// @Failure 404 {string} string "Исполнитель не найден"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /artists/{id}/songs [get]
+func (h *ArtistHandler) GetArtistSongs(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	page, limit, ok := pagination(c, 10)
+	if !ok {
+		return
+	}
+
+	if _, err := h.Repo.GetArtist(c.Request.Context(), id); err != nil {
+		respondRepositoryError(c, err, "Исполнитель не найден")
+		return
+	}
+
+	songs, err := h.Songs.ListSongs(c.Request.Context(), repository.SongFilter{
+		ArtistID: id,
+		Offset:   limit * (page - 1),
+		Limit:    limit,
+	})
+	if err != nil {
+		respondRepositoryError(c, err, "Песни не найдены")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"page":  page,
+		"limit": limit,
+		"songs": songs,
+	})
+}
+
+// bindArtist разбирает и проверяет тело запроса с данными исполнителя.
+func bindArtist(c *gin.Context) (models.Artist, bool) {
+	var artist models.Artist
+	if err := c.BindJSON(&artist); err != nil {
+		respondBindError(c, err)
+		return artist, false
+	}
+
+	artist.Name = strings.Join(strings.Fields(artist.Name), " ")
+	if artist.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Не задано название исполнителя"})
+		return artist, false
+	}
+	if artist.FormedYear != nil && (*artist.FormedYear < 1000 || *artist.FormedYear > time.Now().Year()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректное значение formedYear"})
+		return artist, false
+	}
+	return artist, true
+}
+
+func respondArtistError(c *gin.Context, err error) {
+	if errors.Is(err, repository.ErrConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Исполнитель с таким названием уже есть"})
+		return
+	}
+	respondRepositoryError(c, err, "Исполнитель не найден")
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"song-library/models"
+)
+
+func TestArtists(t *testing.T) {
+	s := newTestServer(t)
+
+	var muse models.Artist
+	s.expect(http.StatusCreated, &muse, http.MethodPost, "/artists",
+		`{"name":"  Muse  ","aliases":["MUSE"],"country":"GB","formedYear":1994,"members":["Matt Bellamy"]}`)
+	if muse.Id == 0 || muse.Name != "Muse" || muse.Country != "GB" {
+		t.Errorf("исполнитель = %+v", muse)
+	}
+	path := "/artists/" + strconv.Itoa(muse.Id)
+
+	tests := []struct {
+		name, body string
+		want       int
+	}{
+		{name: "без названия", body: `{"name":" "}`, want: http.StatusBadRequest},
+		{name: "год в будущем", body: `{"name":"Future","formedYear":3000}`, want: http.StatusBadRequest},
+		{name: "повтор названия", body: `{"name":"Muse"}`, want: http.StatusConflict},
+		{name: "некоректный JSON", body: `{"name":`, want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s.expect(tt.want, nil, http.MethodPost, "/artists", tt.body)
+		})
+	}
+
+	song := s.addSong("MUSE", "Hysteria", "", "2003")
+	if song.ArtistId == nil || *song.ArtistId != muse.Id {
+		t.Errorf("песня по псевдониму привязана к исполнителю %v, ожидался %d", song.ArtistId, muse.Id)
+	}
+	var songs struct {
+		Songs []models.Song `json:"songs"`
+	}
+	s.expect(http.StatusOK, &songs, http.MethodGet, path+"/songs", "")
+	if len(songs.Songs) != 1 || songs.Songs[0].Id != song.Id {
+		t.Errorf("песни исполнителя = %+v", songs.Songs)
+	}
+
+	var updated models.Artist
+	s.expect(http.StatusOK, &updated, http.MethodPut, path, `{"name":"Muse","country":"UK"}`)
+	if updated.Id != muse.Id || updated.Country != "UK" || updated.FormedYear != nil {
+		t.Errorf("исполнитель после изменения = %+v", updated)
+	}
+	s.expect(http.StatusOK, &updated, http.MethodGet, path, "")
+	if updated.Country != "UK" {
+		t.Errorf("GET %s = %+v", path, updated)
+	}
+
+	var list struct {
+		Artists []models.Artist `json:"artists"`
+	}
+	s.expect(http.StatusOK, &list, http.MethodGet, "/artists?name=mus", "")
+	if len(list.Artists) != 1 {
+		t.Errorf("поиск исполнителей = %+v", list.Artists)
+	}
+
+	rec := s.do(http.MethodPost, "/songs", `{"artistId":100,"song":"Uprising"}`)
+	if rec.Code != http.StatusBadRequest || errorMessage(t, rec) != "Исполнитель не найден" {
+		t.Errorf("неизвестный исполнитель: код ответа %d, тело %s", rec.Code, rec.Body.String())
+	}
+
+	//с artistId повтор проверяется по названию исполнителя
+	body := `{"artistId":` + strconv.Itoa(muse.Id) + `,"song":"Uprising"}`
+	var uprising models.Song
+	s.expect(http.StatusOK, &uprising, http.MethodPost, "/songs", body)
+	if uprising.Group != "Muse" {
+		t.Errorf("группа песни по artistId = %q, ожидалось Muse", uprising.Group)
+	}
+	for _, body := range []string{body, `{"artistId":` + strconv.Itoa(muse.Id) + `,"group":"Other","song":"Uprising"}`} {
+		rec := s.do(http.MethodPost, "/songs", body)
+		if rec.Code != http.StatusBadRequest || errorMessage(t, rec) != "Песня уже добавлена" {
+			t.Errorf("повтор песни %s: код ответа %d, тело %s", body, rec.Code, rec.Body.String())
+		}
+	}
+	s.expect(http.StatusOK, nil, http.MethodDelete, songPath(uprising.Id), "")
+
+	s.expect(http.StatusConflict, nil, http.MethodDelete, path, "")
+	s.expect(http.StatusOK, nil, http.MethodDelete, songPath(song.Id), "")
+	s.expect(http.StatusConflict, nil, http.MethodDelete, path, "") //песня в корзине еще может вернуться
+
+	var empty models.Artist
+	s.expect(http.StatusCreated, &empty, http.MethodPost, "/artists", `{"name":"Radiohead"}`)
+	emptyPath := "/artists/" + strconv.Itoa(empty.Id)
+	s.expect(http.StatusOK, nil, http.MethodDelete, emptyPath, "")
+	s.expect(http.StatusNotFound, nil, http.MethodGet, emptyPath, "")
+	s.expect(http.StatusNotFound, nil, http.MethodGet, emptyPath+"/songs", "")
+	s.expect(http.StatusNotFound, nil, http.MethodDelete, emptyPath, "")
+	s.expect(http.StatusBadRequest, nil, http.MethodGet, "/artists/abc", "")
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"song-library/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxChordProSize — наибольший размер загружаемого документа ChordPro.
+const maxChordProSize = 1 << 20
+
+// Загрузить лист аккордов песни
+// @Summary Загрузить ChordPro
+// @Description Загрузить лист аккордов песни в формате ChordPro. При ошибке разбора возвращаются строка и символ
+// @Tags songs
+// @Accept plain
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param chordpro body string true "Документ ChordPro"
+// @Success 200 {object} models.ChordSheet
+// @Failure 400 {string} string "Некоректный документ ChordPro"
+// @Failure 404 {string} string "Песня не найдена"
+// @Failure 413 {string} string "Документ слишком большой"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /songs/{id}/chords [put]
+func (h *SongHandler) UploadChords(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	doc, ok := readTextBody(c, maxChordProSize, "ChordPro")
+	if !ok {
+		return
+	}
+
+	sheet, err := models.ParseChordPro(doc)
+	if err != nil {
+		var proErr *models.ChordProError
+		if errors.As(err, &proErr) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":  "Некоректный документ ChordPro: " + proErr.Error(),
+				"line":   proErr.Line,
+				"column": proErr.Column,
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректный документ ChordPro"})
+		return
+	}
+
+	if err := h.Repo.SetChordPro(c.Request.Context(), id, doc); err != nil {
+		respondRepositoryError(c, err, "Песня не найдена")
+		return
+	}
+
+	c.JSON(http.StatusOK, sheet)
+}
+
+// Получить лист аккордов песни
+// @Summary Получить аккорды
+// @Description Получить лист аккордов песни, при необходимости транспонированный
+// @Tags songs
+// @Produce json
+// @Produce plain
+// @Param id path int true "ID песни"
+// @Param transpose query int false "Сдвиг в полутонах от -11 до 11, например +2" default(0)
+// @Param notation query string false "Запись аккордов: sharp, flat или nashville (ступени относительно {key}). По умолчанию как в документе"
+// @Param format query string false "Формат ответа: json, chordpro или text (аккорды над строками текста)" default(json)
+// @Success 200 {object} models.ChordSheet
+// @Failure 400 {string} string "Неверный формат параметров запроса"
+// @Failure 404 {string} string "Песня не найдена"
+// @Failure 404 {string} string "У песни нет аккордов"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /songs/{id}/chords [get]
+func (h *SongHandler) GetChords(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	// "+" в строке запроса декодируется как пробел, поэтому "+2" приходит как " 2"
+	transpose, err := strconv.Atoi(strings.TrimSpace(c.DefaultQuery("transpose", "0")))
+	if err != nil || transpose < -11 || transpose > 11 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректное значение transpose, ожидается число от -11 до 11"})
+		log.Printf("Некоректное значение transpose, %v\n", err)
+		return
+	}
+	notation := models.Notation(c.Query("notation"))
+	switch notation {
+	case "", models.NotationSharp, models.NotationFlat, models.NotationNashville:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректное значение notation, ожидается sharp, flat или nashville"})
+		return
+	}
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "chordpro" && format != "text" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректное значение format, ожидается json, chordpro или text"})
+		return
+	}
+
+	song, err := h.Repo.GetSong(c.Request.Context(), id)
+	if err != nil {
+		respondRepositoryError(c, err, "Песня не найдена")
+		return
+	}
+	if song.SongDetails.ChordPro == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "У песни нет аккордов"})
+		return
+	}
+
+	sheet, err := models.ParseChordPro(song.SongDetails.ChordPro)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Внутренняя ошибка сервера"})
+		log.Printf("Не удалось разобрать сохраненный документ ChordPro песни %d, %v\n", id, err)
+		return
+	}
+	sheet, err = sheet.Transpose(transpose, notation)
+	if errors.Is(err, models.ErrNoKey) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Для записи nashville в документе должна быть тональность {key}"})
+		return
+	}
+
+	switch format {
+	case "chordpro":
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(sheet.ChordPro()))
+	case "text":
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(sheet.PlainText()))
+	default:
+		c.JSON(http.StatusOK, sheet)
+	}
+}
+
+// Удалить лист аккордов песни
+// @Summary Удалить аккорды
+// @Description Удалить лист аккордов песни
+// @Tags songs
+// @Produce json
+// @Param id path int true "ID песни"
+// @Success 200 {string} string "Аккорды удалены"
+// @Failure 400 {string} string "Некоректное значение id"
+// @Failure 404 {string} string "Песня не найдена"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /songs/{id}/chords [delete]
+func (h *SongHandler) DeleteChords(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.Repo.SetChordPro(c.Request.Context(), id, ""); err != nil {
+		respondRepositoryError(c, err, "Песня не найдена")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Аккорды удалены"})
+}
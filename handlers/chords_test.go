@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"song-library/models"
+)
+
+func TestChords(t *testing.T) {
+	s := newTestServer(t)
+	song := s.addSong("Muse", "Hysteria", "", "2003")
+	path := songPath(song.Id, "chords")
+
+	s.expect(http.StatusNotFound, nil, http.MethodGet, path, "")
+
+	var sheet models.ChordSheet
+	s.expect(http.StatusOK, &sheet, http.MethodPut, path, "{title: Hysteria}\n{key: Am}\n[Am]It's bugging [G]me", "Content-Type", "text/plain")
+	if len(sheet.Meta) != 2 || len(sheet.Lines) != 1 {
+		t.Errorf("лист аккордов = %+v", sheet)
+	}
+
+	tests := []struct {
+		name, query, want string
+	}{
+		{name: "как есть", query: "?format=chordpro", want: "{title: Hysteria}\n{key: Am}\n[Am]It's bugging [G]me\n"},
+		{name: "на тон выше", query: "?format=chordpro&transpose=%2B2", want: "{title: Hysteria}\n{key: Bm}\n[Bm]It's bugging [A]me\n"},
+		{name: "плюс как пробел", query: "?format=chordpro&transpose=+2", want: "{title: Hysteria}\n{key: Bm}\n[Bm]It's bugging [A]me\n"},
+		{name: "бемоли", query: "?format=chordpro&transpose=1&notation=flat", want: "{title: Hysteria}\n{key: Bbm}\n[Bbm]It's bugging [Ab]me\n"},
+		{name: "система Нэшвилла", query: "?format=chordpro&notation=nashville", want: "{title: Hysteria}\n{key: Am}\n[1m]It's bugging [b7]me\n"},
+		{name: "текст", query: "?format=text", want: "Hysteria\nkey: Am\n\nAm           G\nIt's bugging me\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := s.expect(http.StatusOK, nil, http.MethodGet, path+tt.query, "")
+			if got := rec.Body.String(); got != tt.want {
+				t.Errorf("GET %s = %q, ожидалось %q", tt.query, got, tt.want)
+			}
+		})
+	}
+
+	for _, q := range []string{"?transpose=12", "?transpose=x", "?notation=german", "?format=pdf"} {
+		s.expect(http.StatusBadRequest, nil, http.MethodGet, path+q, "")
+	}
+
+	rec := s.expect(http.StatusBadRequest, nil, http.MethodPut, path, "{title: Hysteria\n[Am", "Content-Type", "text/plain")
+	var proErr struct {
+		Line int `json:"line"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &proErr); err != nil || proErr.Line != 1 {
+		t.Errorf("ошибка разбора = %s", rec.Body.String())
+	}
+	s.expect(http.StatusNotFound, nil, http.MethodPut, songPath(100, "chords"), "[Am]x", "Content-Type", "text/plain")
+
+	s.expect(http.StatusOK, nil, http.MethodDelete, path, "")
+	s.expect(http.StatusNotFound, nil, http.MethodGet, path, "")
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"hash/fnv"
+	"net/url"
+	"sort"
+
+	"song-library/repository"
+)
+
+// errCursor возвращается, если курсор поврежден или получен для других параметров запроса.
+var errCursor = errors.New("некоректный курсор")
+
+// pageCursor — содержимое курсора страницы. Клиенту курсор передается непрозрачной строкой.
+type pageCursor struct {
+	repository.Cursor
+	Query uint32 `json:"q"` //хеш параметров фильтра и сортировки, для которых получен курсор
+}
+
+// queryHash возвращает хеш параметров запроса из keys, чтобы курсор нельзя было применить к другому списку.
+// Учитываются все значения повторяющихся параметров без учета их порядка.
+func queryHash(query url.Values, keys ...string) uint32 {
+	sort.Strings(keys)
+	h := fnv.New32a()
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		if len(values) == 0 {
+			values = []string{""}
+		}
+		sort.Strings(values)
+		for _, value := range values {
+			h.Write([]byte(key + "=" + value + "\n"))
+		}
+	}
+	return h.Sum32()
+}
+
+// encodeCursor записывает позицию в списке непрозрачной строкой.
+func encodeCursor(cursor repository.Cursor, hash uint32) string {
+	data, _ := json.Marshal(pageCursor{Cursor: cursor, Query: hash})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor разбирает курсор и проверяет, что он получен для тех же параметров запроса.
+func decodeCursor(value string, hash uint32) (*repository.Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errCursor
+	}
+	var cursor pageCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.Query != hash {
+		return nil, errCursor
+	}
+	return &cursor.Cursor, nil
+}
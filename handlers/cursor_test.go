@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestQueryHash(t *testing.T) {
+	keys := []string{"group", "tag"}
+	base := url.Values{"group": {"Muse"}, "tag": {"rock", "live"}}
+	tests := []struct {
+		name  string
+		query url.Values
+		same  bool
+	}{
+		{name: "те же параметры", query: url.Values{"group": {"Muse"}, "tag": {"rock", "live"}}, same: true},
+		{name: "другой порядок значений", query: url.Values{"group": {"Muse"}, "tag": {"live", "rock"}}, same: true},
+		{name: "параметр не из keys", query: url.Values{"group": {"Muse"}, "tag": {"rock", "live"}, "limit": {"5"}}, same: true},
+		{name: "другое второе значение", query: url.Values{"group": {"Muse"}, "tag": {"rock", "pop"}}},
+		{name: "без второго значения", query: url.Values{"group": {"Muse"}, "tag": {"rock"}}},
+		{name: "лишнее значение", query: url.Values{"group": {"Muse"}, "tag": {"rock", "live", "pop"}}},
+		{name: "без параметра", query: url.Values{"tag": {"rock", "live"}}},
+	}
+	want := queryHash(base, keys...)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := queryHash(tt.query, keys...); (got == want) != tt.same {
+				t.Errorf("queryHash(%v) = %d, для %v = %d, ожидалось совпадение: %v", tt.query, got, base, want, tt.same)
+			}
+		})
+	}
+}
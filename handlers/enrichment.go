@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Получить состояние получения дополнительных данных песни
+// @Summary Состояние получения данных песни
+// @Description Получить состояние фонового получения дополнительных данных песни от внешнего API
+// @Tags enrichment
+// @Produce json
+// @Param id path int true "ID песни"
+// @Success 200 {object} models.EnrichmentJob
+// @Failure 400 {string} string "Некоректное значение id"
+// @Failure 404 {string} string "Задание не найдено"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /songs/{id}/enrichment [get]
+func (h *SongHandler) GetEnrichment(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	job, err := h.Enrichment.Job(c.Request.Context(), id)
+	if err != nil {
+		respondRepositoryError(c, err, "Задание не найдено")
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// Перезапустить получение дополнительных данных песни
+// @Summary Перезапустить получение данных песни
+// @Description Поставить песню в очередь на получение дополнительных данных от внешнего API заново.
+// @Description Заполняются только пустые поля песни. Песню из корзины поставить в очередь нельзя
+// @Tags enrichment
+// @Produce json
+// @Param id path int true "ID песни"
+// @Success 202 {object} models.EnrichmentJob
+// @Failure 400 {string} string "Некоректное значение id"
+// @Failure 404 {string} string "Песня не найдена"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /songs/{id}/enrich [post]
+func (h *SongHandler) Enrich(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	job, err := h.Enrichment.Enqueue(c.Request.Context(), id)
+	if err != nil {
+		respondRepositoryError(c, err, "Песня не найдена")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"song-library/models"
+)
+
+func TestEnrichment(t *testing.T) {
+	s := newTestServer(t)
+	s.provider["Hysteria"] = models.SongDetails{Text: "Enriched text", Link: "https://example.com/song"}
+
+	var song models.Song
+	s.expect(http.StatusOK, &song, http.MethodPost, "/songs", `{"group":"Muse","song":"Hysteria"}`)
+	if song.EnrichmentStatus != models.EnrichmentPending {
+		t.Errorf("состояние новой песни = %s, ожидалось %s", song.EnrichmentStatus, models.EnrichmentPending)
+	}
+
+	var job models.EnrichmentJob
+	s.expect(http.StatusOK, &job, http.MethodGet, songPath(song.Id, "enrichment"), "")
+	if job.SongId != song.Id || job.Status != models.JobQueued {
+		t.Errorf("задание = %+v", job)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := s.queue.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		cancel()
+		s.queue.Wait()
+	})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for job.Status != models.JobSucceeded {
+		if time.Now().After(deadline) {
+			t.Fatalf("задание не выполнено: %+v", job)
+		}
+		time.Sleep(5 * time.Millisecond)
+		s.expect(http.StatusOK, &job, http.MethodGet, songPath(song.Id, "enrichment"), "")
+	}
+
+	var page struct {
+		Songs []models.Song `json:"songs"`
+	}
+	s.expect(http.StatusOK, &page, http.MethodGet, "/songs?song=Hysteria", "")
+	if len(page.Songs) != 1 || page.Songs[0].EnrichmentStatus != models.EnrichmentSucceeded || page.Songs[0].SongDetails.Text != "Enriched text" {
+		t.Errorf("песни после получения данных = %+v", page.Songs)
+	}
+
+	s.expect(http.StatusAccepted, &job, http.MethodPost, songPath(song.Id, "enrich"), "")
+	if job.SongId != song.Id || job.Status == models.JobSucceeded {
+		t.Errorf("перезапущенное задание = %+v", job)
+	}
+	s.expect(http.StatusNotFound, nil, http.MethodPost, songPath(100, "enrich"), "")
+	s.expect(http.StatusNotFound, nil, http.MethodGet, songPath(100, "enrichment"), "")
+	s.expect(http.StatusBadRequest, nil, http.MethodGet, "/songs/abc/enrichment", "")
+}
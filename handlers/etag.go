@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"song-library/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ifMatch проверяет заголовок If-Match перед изменением песни и возвращает версию,
+// которую должно проверить хранилище, или 0, если заголовка нет. Если заголовок обязателен
+// и отсутствует, отвечает 428, если не совпадает с ETag песни — 412.
+func (h *SongHandler) ifMatch(c *gin.Context, id int) (int, bool) {
+	header := c.GetHeader("If-Match")
+	if header == "" {
+		if h.RequireIfMatch {
+			c.JSON(http.StatusPreconditionRequired, gin.H{"error": "Требуется заголовок If-Match с ETag песни"})
+			return 0, false
+		}
+		return 0, true
+	}
+
+	song, err := h.Repo.GetSong(c.Request.Context(), id)
+	if err != nil {
+		respondRepositoryError(c, err, "Песня не найдена")
+		return 0, false
+	}
+	if !etagListMatches(header, song.Version, false) {
+		respondVersionMismatch(c)
+		return 0, false
+	}
+	return song.Version, true
+}
+
+// notModified отвечает 304, если заголовок If-None-Match совпадает с ETag песни.
+func notModified(c *gin.Context, song *models.Song) bool {
+	header := c.GetHeader("If-None-Match")
+	if header == "" || !etagListMatches(header, song.Version, true) {
+		return false
+	}
+	c.Header("ETag", models.VersionETag(song.Version))
+	c.Status(http.StatusNotModified)
+	return true
+}
+
+// etagListMatches сообщает, что список ETag из заголовка содержит ETag версии или *.
+// При слабом сравнении (If-None-Match) префикс W/ не учитывается, при сильном (If-Match) такие ETag не совпадают.
+func etagListMatches(header string, version int, weak bool) bool {
+	etag := models.VersionETag(version)
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if strings.HasPrefix(candidate, "W/") {
+			if !weak {
+				continue
+			}
+			candidate = candidate[2:]
+		}
+		if candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// respondVersionMismatch отвечает 412, если песня изменилась после того, как клиент её получил.
+func respondVersionMismatch(c *gin.Context) {
+	c.JSON(http.StatusPreconditionFailed, gin.H{"error": "Песня изменена после получения, загрузите её заново"})
+}
+
+// setETags заполняет ETag песен в списке.
+func setETags(songs []models.Song) {
+	for i := range songs {
+		songs[i].ETag = models.VersionETag(songs[i].Version)
+	}
+}
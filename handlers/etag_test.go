@@ -0,0 +1,25 @@
+package handlers
+
+import "testing"
+
+func TestETagListMatches(t *testing.T) {
+	tests := []struct {
+		header string
+		weak   bool
+		want   bool
+	}{
+		{header: `"3"`, want: true},
+		{header: `"2"`, want: false},
+		{header: `"1", "3"`, want: true},
+		{header: `*`, want: true},
+		{header: `W/"3"`, want: false},
+		{header: `W/"3"`, weak: true, want: true},
+		{header: `3`, weak: true, want: false},
+		{header: ` "4" ,W/"3" `, weak: true, want: true},
+	}
+	for _, tt := range tests {
+		if got := etagListMatches(tt.header, 3, tt.weak); got != tt.want {
+			t.Errorf("etagListMatches(%q, 3, %v) = %v, ожидалось %v", tt.header, tt.weak, got, tt.want)
+		}
+	}
+}
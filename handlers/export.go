@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"song-library/exporter"
+	"song-library/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exportIncludes — данные песни, которые можно исключить из выгрузки.
+var exportIncludes = []string{"details", "lyrics"}
+
+// Выгрузить песни
+// @Summary Выгрузить песни
+// @Description Выгрузить все песни, удовлетворяющие фильтрам, файлом CSV, JSON Lines или XLSX. Фильтры и сортировка те же,
+// @Description что у GET /songs, пагинации нет. Песни передаются потоком по мере чтения из базы данных.
+// @Description Колонки: id, group, song, с include=details — releaseDate и link, с include=lyrics — text.
+// @Description Файл можно загрузить обратно через POST /songs/import
+// @Tags songs
+// @Produce text/csv,application/x-ndjson,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "Формат файла: csv, jsonl или xlsx" default(csv)
+// @Param include query string false "Данные песни через запятую: details — дата выхода и ссылка, lyrics — текст. Пустое значение оставляет только названия" default(details,lyrics)
+// @Param group query string false "Фильтр по группе"
+// @Param song query string false "Фильтр по названию песни"
+// @Param link query string false "Фильтр по ссылке"
+// @Param text query string false "Фильтр по тексту или фрагменту тектса"
+// @Param album query string false "Фильтр по названию альбома без учета регистра"
+// @Param tag query []string false "Фильтр по тегам вида namespace:name, параметр повторяется: tag=genre:rock&tag=mood:calm" collectionFormat(multi)
+// @Param tag_mode query string false "Сочетание тегов: and — песни со всеми тегами, or — хотя бы с одним" default(and)
+// @Param sort query string false "Поля сортировки через запятую, минус — по убыванию: group, song, release_date, created_at, updated_at, id. По умолчанию release_date"
+// @Param released_from query string false "Вышедшие не раньше даты: DD.MM.YYYY, MM.YYYY или YYYY"
+// @Param released_to query string false "Вышедшие не позже даты включительно: DD.MM.YYYY, MM.YYYY или YYYY"
+// @Param year query int false "Вышедшие в указанном году"
+// @Param match query string false "Режим сравнения group и song: exact или fuzzy" default(exact)
+// @Param threshold query number false "Минимальное сходство для match=fuzzy, от 0 до 1" default(0.3)
+// @Param include_deleted query bool false "Включать песни из корзины, только для администратора" default(false)
+// @Param X-Admin-Token header string false "Токен администратора"
+// @Success 200 {file} file "Файл с песнями"
+// @Failure 400 {string} string "Неверный формат параметров запроса"
+// @Failure 403 {string} string "Недостаточно прав"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /songs/export [get]
+func (h *SongHandler) ExportSongs(c *gin.Context) {
+	format := c.DefaultQuery("format", exporter.FormatCSV)
+	contentType, ok := exporter.ContentTypes[format]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректное значение format, допустимы csv, jsonl и xlsx"})
+		return
+	}
+	include, err := parseList(c.DefaultQuery("include", "details,lyrics"), exportIncludes)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректное значение include: " + err.Error()})
+		return
+	}
+	filter, ok := songFilter(c)
+	if !ok {
+		return
+	}
+
+	columns := []string{"id", "group", "song"}
+	if include["details"] {
+		columns = append(columns, "releaseDate", "link")
+	}
+	if include["lyrics"] {
+		columns = append(columns, "text")
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", `attachment; filename="songs.`+format+`"`)
+	w, err := exporter.NewWriter(format, c.Writer, columns)
+	if err == nil {
+		err = h.Repo.ExportSongs(c.Request.Context(), filter, func(song models.Song) error {
+			values := []string{strconv.Itoa(song.Id), song.Group, song.Song}
+			if include["details"] {
+				values = append(values, song.SongDetails.ReleaseDate.String(), song.SongDetails.Link)
+			}
+			if include["lyrics"] {
+				values = append(values, song.SongDetails.Text)
+			}
+			return w.Write(values)
+		})
+	}
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		if !c.Writer.Written() { //клиент еще ничего не получил, можно ответить ошибкой
+			c.Writer.Header().Del("Content-Type")
+			c.Writer.Header().Del("Content-Disposition")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Не удалось выгрузить песни"})
+		}
+		log.Printf("Не удалось выгрузить песни, %v\n", err)
+	}
+}
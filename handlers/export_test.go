@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"song-library/models"
+	"song-library/repository"
+)
+
+func TestExportSongs(t *testing.T) {
+	s := newTestServer(t)
+	hysteria := s.addSong("Muse", "Hysteria", "It's bugging me,\n\"grating\" me", "2003")
+	uprising := s.addSong("Muse", "Uprising", "", "07.09.2009")
+	s.addSong("Radiohead", "Creep", "", "1992")
+
+	rec := s.expect(http.StatusOK, nil, http.MethodGet, "/songs/export?group=Muse&sort=song", "")
+	if ct := rec.Header().Get("Content-Type"); ct != "text/csv; charset=utf-8" {
+		t.Errorf("Content-Type = %s", ct)
+	}
+	if cd := rec.Header().Get("Content-Disposition"); cd != `attachment; filename="songs.csv"` {
+		t.Errorf("Content-Disposition = %s", cd)
+	}
+	rows, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(rec.Body.String(), "\ufeff"))).ReadAll()
+	if err != nil {
+		t.Fatalf("некоректный CSV %q: %v", rec.Body.String(), err)
+	}
+	want := [][]string{
+		{"id", "group", "song", "releaseDate", "link", "text"},
+		{strconv.Itoa(hysteria.Id), "Muse", "Hysteria", "2003", "", "It's bugging me,\n\"grating\" me"},
+		{strconv.Itoa(uprising.Id), "Muse", "Uprising", "07.09.2009", "", ""},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("CSV = %q, ожидалось %q", rows, want)
+	}
+
+	rec = s.expect(http.StatusOK, nil, http.MethodGet, "/songs/export?format=jsonl&include=&sort=-id", "")
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(rec.Body)
+	for scanner.Scan() {
+		var line map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("некоректная строка JSON Lines %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) != 3 || lines[0]["song"] != "Creep" || len(lines[0]) != 3 {
+		t.Errorf("JSON Lines без данных песен = %v", lines)
+	}
+
+	rec = s.expect(http.StatusOK, nil, http.MethodGet, "/songs/export?format=xlsx", "")
+	if !strings.HasPrefix(rec.Body.String(), "PK") {
+		t.Errorf("XLSX не является архивом zip")
+	}
+
+	for _, q := range []string{"format=pdf", "include=audio", "sort=title", "include_deleted=true"} {
+		rec := s.do(http.MethodGet, "/songs/export?"+q, "")
+		if rec.Code != http.StatusBadRequest && rec.Code != http.StatusForbidden {
+			t.Errorf("GET /songs/export?%s: код ответа %d, ожидалась ошибка", q, rec.Code)
+		}
+	}
+}
+
+// failingExport — хранилище, в котором выгрузка песен завершается ошибкой.
+type failingExport struct {
+	*repository.Memory
+}
+
+func (failingExport) ExportSongs(context.Context, repository.SongFilter, func(models.Song) error) error {
+	return errors.New("соединение с базой данных потеряно")
+}
+
+func TestExportSongsError(t *testing.T) {
+	s := newTestServer(t)
+	s.songs.Repo = failingExport{s.repo}
+
+	for _, format := range []string{"csv", "jsonl", "xlsx"} {
+		rec := s.expect(http.StatusInternalServerError, nil, http.MethodGet, "/songs/export?format="+format, "")
+		if ct := rec.Header().Get("Content-Type"); ct != "application/json; charset=utf-8" {
+			t.Errorf("%s: Content-Type ошибки = %s", format, ct)
+		}
+		if cd := rec.Header().Get("Content-Disposition"); cd != "" {
+			t.Errorf("%s: Content-Disposition ошибки = %s", format, cd)
+		}
+	}
+}
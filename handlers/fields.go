@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"song-library/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// songFields — поля песни, которые можно выбрать параметром fields.
+var songFields = []string{"Id", "artistId", "group", "song", "enrichmentStatus", "version"}
+
+// songIncludes — связанные данные, которые можно добавить параметром include.
+var songIncludes = []string{"details", "artist", "album"}
+
+// parseList разбирает список значений через запятую, допустимы только значения из allowed.
+// Пустая строка дает пустой список.
+func parseList(value string, allowed []string) (map[string]bool, error) {
+	result := map[string]bool{}
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		known := false
+		for _, name := range allowed {
+			known = known || item == name
+		}
+		if !known {
+			return nil, fmt.Errorf("неизвестное значение %q, допустимы %s", item, strings.Join(allowed, ", "))
+		}
+		result[item] = true
+	}
+	return result, nil
+}
+
+// songView возвращает песню в виде JSON объекта только с выбранными полями и связанными данными.
+// Пустой fields оставляет все поля.
+func (h *SongHandler) songView(ctx context.Context, song *models.Song, fields, include map[string]bool) (interface{}, error) {
+	if len(fields) == 0 && len(include) == 1 && include["details"] { //ответ по умолчанию совпадает с ответом POST и PUT
+		return song, nil
+	}
+
+	data, err := json.Marshal(song)
+	if err != nil {
+		return nil, err
+	}
+	var view map[string]interface{}
+	if err := json.Unmarshal(data, &view); err != nil {
+		return nil, err
+	}
+
+	if len(fields) > 0 {
+		for key := range view {
+			if !fields[key] && key != "SongDetail" {
+				delete(view, key)
+			}
+		}
+	}
+	if !include["details"] {
+		delete(view, "SongDetail")
+	}
+
+	if include["artist"] && song.ArtistId != nil {
+		artist, err := h.Artists.GetArtist(ctx, *song.ArtistId)
+		if err != nil {
+			return nil, err
+		}
+		view["artist"] = artist
+	}
+	if include["album"] {
+		tracks, err := h.Albums.ListSongTracks(ctx, song.Id)
+		if err != nil {
+			return nil, err
+		}
+		albums := make([]gin.H, len(tracks))
+		for i, track := range tracks {
+			albums[i] = gin.H{"album": track.Album, "disc": track.DiscNumber, "track": track.TrackNumber}
+		}
+		view["albums"] = albums
+	}
+	return view, nil
+}
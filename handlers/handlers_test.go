@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"song-library/enrichment"
+	"song-library/importer"
+	"song-library/metadata"
+	"song-library/models"
+	"song-library/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// testAdminToken — токен администратора тестового сервера.
+const testAdminToken = "admin-token"
+
+// testServer — API поверх хранилища в памяти с маршрутами из Register.
+type testServer struct {
+	t        *testing.T
+	repo     *repository.Memory
+	provider stubProvider
+	queue    *enrichment.Queue
+	importer *importer.Importer
+	songs    *SongHandler
+	router   *gin.Engine
+}
+
+// stubProvider возвращает данные песен по названию, для неизвестной песни — ошибку not_found.
+type stubProvider map[string]interface{}
+
+func (p stubProvider) SongInfo(ctx context.Context, group, song string) (*models.SongDetails, error) {
+	switch v := p[song].(type) {
+	case models.SongDetails:
+		return &v, nil
+	case error:
+		return nil, v
+	}
+	return nil, &metadata.Error{Kind: metadata.KindNotFound, StatusCode: http.StatusNotFound, Attempts: 1}
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	s := &testServer{t: t, repo: repository.NewMemory(), provider: stubProvider{}}
+	s.queue = enrichment.NewQueue(s.repo, s.provider, enrichment.Config{Workers: 1, PollInterval: 10 * time.Millisecond})
+	s.importer = importer.NewImporter(s.repo, s.queue.Notify, importer.Config{})
+	s.songs = NewSongHandler(s.repo, s.repo, s.repo, s.repo, s.repo, s.queue)
+
+	s.router = gin.New()
+	Register(s.router, s.repo, s.songs, s.importer, testAdminToken)
+	return s
+}
+
+// do выполняет запрос. headers — пары заголовок, значение; тело без Content-Type передается как JSON.
+func (s *testServer) do(method, path, body string, headers ...string) *httptest.ResponseRecorder {
+	s.t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	return rec
+}
+
+// expect выполняет запрос, проверяет код ответа и разбирает JSON ответа в v, если v не nil.
+func (s *testServer) expect(status int, v interface{}, method, path, body string, headers ...string) *httptest.ResponseRecorder {
+	s.t.Helper()
+	rec := s.do(method, path, body, headers...)
+	if rec.Code != status {
+		s.t.Fatalf("%s %s: код ответа %d, ожидался %d, тело %s", method, path, rec.Code, status, rec.Body.String())
+	}
+	if v != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+			s.t.Fatalf("%s %s: некоректный JSON ответа %s: %v", method, path, rec.Body.String(), err)
+		}
+	}
+	return rec
+}
+
+// addSong добавляет песню через POST /songs.
+func (s *testServer) addSong(group, song, text, releaseDate string) models.Song {
+	s.t.Helper()
+	body, _ := json.Marshal(map[string]interface{}{
+		"group": group,
+		"song":  song,
+		"SongDetail": map[string]string{
+			"text":        text,
+			"releaseDate": releaseDate,
+		},
+	})
+	var created models.Song
+	s.expect(http.StatusOK, &created, http.MethodPost, "/songs", string(body))
+	return created
+}
+
+// songPath возвращает путь песни с необязательным продолжением, например songPath(1, "text").
+func songPath(id int, rest ...string) string {
+	return strings.Join(append([]string{"/songs", strconv.Itoa(id)}, rest...), "/")
+}
+
+// errorMessage возвращает поле error из JSON ответа.
+func errorMessage(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var body struct {
+		Error string `json:"error"`
+	}
+	data, _ := io.ReadAll(rec.Body)
+	if err := json.Unmarshal(data, &body); err != nil {
+		t.Fatalf("некоректный JSON ответа %s: %v", data, err)
+	}
+	return body.Error
+}
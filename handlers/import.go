@@ -0,0 +1,282 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"song-library/importer"
+	"song-library/models"
+	"song-library/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportSize — максимальный размер импортируемого файла.
+const maxImportSize = 64 << 20
+
+// importTypes — форматы импорта по типу содержимого.
+var importTypes = map[string]string{
+	"text/csv":             importer.FormatCSV,
+	"application/csv":      importer.FormatCSV,
+	"application/x-ndjson": importer.FormatJSONL,
+	"application/jsonl":    importer.FormatJSONL,
+	"application/x-jsonl":  importer.FormatJSONL,
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": importer.FormatXLSX,
+}
+
+// importExtensions — форматы импорта по расширению имени файла.
+var importExtensions = map[string]string{
+	".csv":    importer.FormatCSV,
+	".jsonl":  importer.FormatJSONL,
+	".ndjson": importer.FormatJSONL,
+	".xlsx":   importer.FormatXLSX,
+}
+
+type ImportHandler struct {
+	Importer *importer.Importer
+}
+
+func NewImportHandler(im *importer.Importer) *ImportHandler {
+	return &ImportHandler{Importer: im}
+}
+
+// Импортировать песни из файла
+// @Summary Импортировать песни из файла
+// @Description Загрузить файл CSV, JSON Lines или XLSX с песнями и запустить задание импорта. Файл передается телом запроса
+// @Description или полем file в multipart/form-data. Формат определяется параметром format, типом содержимого или расширением файла.
+// @Description В CSV и XLSX первая строка — заголовок, по умолчанию колонки называются как поля: group, song, text, link, releaseDate;
+// @Description другие названия задаются параметрами columns[поле]=колонка, например columns[group]=Исполнитель. В JSON Lines
+// @Description в каждой строке объект песни с теми же ключами, ключи тоже можно переназначить через columns.
+// @Description Каждая строка проверяется отдельно, строки с ошибками пропускаются и попадают в отчет GET /songs/import/{id}/errors.
+// @Description Задание выполняется в фоне, его состояние возвращает GET /songs/import/{id}
+// @Tags import
+// @Accept text/csv,application/x-ndjson,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,multipart/form-data
+// @Produce json
+// @Param file body string true "Файл с песнями"
+// @Param format query string false "Формат файла: csv, jsonl или xlsx"
+// @Param dry_run query bool false "Только проверить файл, ничего не записывая" default(false)
+// @Param on_duplicate query string false "Что делать с уже добавленными песнями: skip — пропустить, update — заменить данные значениями из файла, fail — считать строку ошибкой" default(skip)
+// @Param columns query object false "Названия колонок по полям песни: columns[group]=Исполнитель&columns[song]=Название"
+// @Param delimiter query string false "Разделитель колонок CSV: один символ или tab" default(,)
+// @Param X-User header string false "Автор изменений для истории правок"
+// @Success 202 {object} models.ImportJob
+// @Header 202 {string} Location "Адрес задания импорта"
+// @Failure 400 {string} string "Неверный формат параметров запроса"
+// @Failure 413 {string} string "Файл слишком большой"
+// @Failure 415 {string} string "Не удалось определить формат файла"
+// @Failure 422 {string} string "Файл нельзя импортировать"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /songs/import [post]
+func (h *ImportHandler) ImportSongs(c *gin.Context) {
+	opts := importer.Options{
+		Format:      strings.ToLower(c.Query("format")),
+		OnDuplicate: models.DuplicatePolicy(c.DefaultQuery("on_duplicate", string(models.DuplicateSkip))),
+		Columns:     c.QueryMap("columns"),
+	}
+	switch opts.OnDuplicate {
+	case models.DuplicateSkip, models.DuplicateUpdate, models.DuplicateFail:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректное значение on_duplicate, допустимы skip, update и fail"})
+		return
+	}
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректное значение dry_run"})
+		return
+	}
+	opts.DryRun = dryRun
+	switch delimiter := c.Query("delimiter"); {
+	case delimiter == "":
+	case delimiter == "tab":
+		opts.Delimiter = '\t'
+	case utf8.RuneCountInString(delimiter) == 1:
+		opts.Delimiter, _ = utf8.DecodeRuneInString(delimiter)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректное значение delimiter, ожидается один символ или tab"})
+		return
+	}
+
+	file, size, name, contentType, ok := spoolUpload(c)
+	if !ok {
+		return
+	}
+	if opts.Format == "" {
+		opts.Format = importFormat(contentType, name)
+	}
+	if opts.Format == "" {
+		file.Close()
+		os.Remove(file.Name())
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "Не удалось определить формат файла, укажите format: csv, jsonl или xlsx"})
+		return
+	}
+
+	job, err := h.Importer.Submit(repository.ActorFrom(c.Request.Context()), file, size, opts)
+	var formatErr *importer.FormatError
+	if errors.As(err, &formatErr) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Файл нельзя импортировать: " + formatErr.Msg})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Не удалось запустить импорт"})
+		log.Printf("Не удалось запустить импорт, %v\n", err)
+		return
+	}
+
+	c.Header("Location", "/songs/import/"+job.Id)
+	c.JSON(http.StatusAccepted, job)
+}
+
+// spoolUpload сохраняет загруженный файл во временный файл и возвращает его вместе с размером,
+// именем и типом содержимого. При ошибке отвечает клиенту.
+func spoolUpload(c *gin.Context) (file *os.File, size int64, name, contentType string, ok bool) {
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+	var upload io.Reader = body
+	contentType = c.ContentType()
+	if mediaType, _, err := mime.ParseMediaType(c.GetHeader("Content-Type")); err == nil && mediaType == "multipart/form-data" {
+		c.Request.Body = body
+		reader, err := c.Request.MultipartReader()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректное тело multipart/form-data"})
+			return nil, 0, "", "", false
+		}
+		for upload = nil; upload == nil; {
+			part, err := reader.NextPart()
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Нет поля file с файлом"})
+				return nil, 0, "", "", false
+			}
+			if part.FormName() == "file" {
+				upload, name, contentType = part, part.FileName(), part.Header.Get("Content-Type")
+			}
+		}
+	}
+
+	file, err := os.CreateTemp("", "song-import-*")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Не удалось сохранить файл"})
+		log.Printf("Не удалось создать временный файл импорта, %v\n", err)
+		return nil, 0, "", "", false
+	}
+	size, err = io.Copy(file, upload)
+	if err == nil {
+		_, err = file.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Файл слишком большой"})
+			return nil, 0, "", "", false
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Не удалось прочитать файл"})
+		log.Printf("Не удалось прочитать импортируемый файл, %v\n", err)
+		return nil, 0, "", "", false
+	}
+	if size == 0 {
+		file.Close()
+		os.Remove(file.Name())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Файл пустой"})
+		return nil, 0, "", "", false
+	}
+	return file, size, name, contentType, true
+}
+
+// importFormat определяет формат файла по типу содержимого или расширению имени.
+func importFormat(contentType, name string) string {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		if format, ok := importTypes[mediaType]; ok {
+			return format
+		}
+	}
+	return importExtensions[strings.ToLower(filepath.Ext(name))]
+}
+
+// Получить задание импорта
+// @Summary Получить задание импорта
+// @Description Получить состояние задания импорта: прогресс и количество добавленных, измененных, пропущенных и ошибочных строк.
+// @Description Задания хранятся в памяти сервера сутки после завершения и не переживают перезапуск: задание, созданное
+// @Description до перезапуска, возвращает 410, его нужно запустить заново. Песни, импортированные до перезапуска, остаются в библиотеке
+// @Tags import
+// @Produce json
+// @Param id path string true "ID задания"
+// @Success 200 {object} models.ImportJob
+// @Failure 404 {string} string "Задание импорта не найдено"
+// @Failure 410 {string} string "Задание импорта создано до перезапуска сервера"
+// @Router /songs/import/{id} [get]
+func (h *ImportHandler) GetImport(c *gin.Context) {
+	job, err := h.Importer.Job(c.Param("id"))
+	if err != nil {
+		respondImportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// Получить отчет об ошибках импорта
+// @Summary Получить отчет об ошибках импорта
+// @Description Скачать ошибки строк задания импорта в порядке строк файла: номер строки, поле, группа, песня и описание ошибки.
+// @Description Пока задание выполняется, отчет содержит ошибки уже обработанных строк
+// @Tags import
+// @Produce text/csv,json
+// @Param id path string true "ID задания"
+// @Param format query string false "Формат отчета: csv или json" default(csv)
+// @Success 200 {array} models.ImportRowError
+// @Failure 400 {string} string "Неверный формат параметров запроса"
+// @Failure 404 {string} string "Задание импорта не найдено"
+// @Failure 410 {string} string "Задание импорта создано до перезапуска сервера"
+// @Router /songs/import/{id}/errors [get]
+func (h *ImportHandler) GetImportErrors(c *gin.Context) {
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "json" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректное значение format, допустимы csv и json"})
+		return
+	}
+	id := c.Param("id")
+	job, err := h.Importer.Job(id)
+	if err != nil {
+		respondImportError(c, err)
+		return
+	}
+	rowErrors, err := h.Importer.Errors(id)
+	if err != nil {
+		respondImportError(c, err)
+		return
+	}
+
+	if format == "json" {
+		c.JSON(http.StatusOK, gin.H{"errors": rowErrors, "truncated": job.ErrorsTruncated})
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="import-`+id+`-errors.csv"`)
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Status(http.StatusOK)
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"row", "field", "group", "song", "message"})
+	for _, rowErr := range rowErrors {
+		w.Write([]string{strconv.Itoa(rowErr.Row), rowErr.Field, rowErr.Group, rowErr.Song, rowErr.Message})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		log.Printf("Не удалось отправить отчет об ошибках импорта %s, %v\n", id, err)
+	}
+}
+
+// respondImportError отвечает клиенту на ошибку получения задания импорта.
+func respondImportError(c *gin.Context, err error) {
+	if errors.Is(err, importer.ErrJobLost) {
+		c.JSON(http.StatusGone, gin.H{"error": "Задание импорта создано до перезапуска сервера, его состояние и отчет не сохранились. Запустите импорт заново"})
+		return
+	}
+	respondRepositoryError(c, err, "Задание импорта не найдено")
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"song-library/models"
+)
+
+// waitImport дожидается завершения задания импорта через GET /songs/import/{id}.
+func (s *testServer) waitImport(location string) models.ImportJob {
+	s.t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		var job models.ImportJob
+		s.expect(http.StatusOK, &job, http.MethodGet, location, "")
+		if job.FinishedAt != nil {
+			return job
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	s.t.Fatalf("задание %s не завершилось", location)
+	return models.ImportJob{}
+}
+
+func TestImportSongs(t *testing.T) {
+	s := newTestServer(t)
+	s.addSong("Muse", "Hysteria", "old", "")
+
+	data := "Исполнитель;Название;text\nMuse;Hysteria;new\nMuse;Uprising;Paranoia is in bloom\n;Nameless;\n"
+	rec := s.expect(http.StatusAccepted, nil, http.MethodPost, "/songs/import?delimiter=%3B&on_duplicate=update&columns[group]=Исполнитель&columns[song]=Название",
+		data, "Content-Type", "text/csv", UserHeader, "importer")
+	location := rec.Header().Get("Location")
+	if !strings.HasPrefix(location, "/songs/import/") {
+		t.Fatalf("Location = %q", location)
+	}
+
+	job := s.waitImport(location)
+	if job.Status != models.JobSucceeded || job.Created != 1 || job.Updated != 1 || job.Failed != 1 || job.CreatedBy != "importer" || job.Format != "csv" {
+		t.Errorf("задание = %+v", job)
+	}
+	var songs struct {
+		Songs []models.Song `json:"songs"`
+	}
+	s.expect(http.StatusOK, &songs, http.MethodGet, "/songs?sort=song", "")
+	if len(songs.Songs) != 2 || songs.Songs[0].SongDetails.Text != "new" || songs.Songs[1].Song != "Uprising" {
+		t.Errorf("песни после импорта = %+v", songs.Songs)
+	}
+
+	var report struct {
+		Errors    []models.ImportRowError `json:"errors"`
+		Truncated bool                    `json:"truncated"`
+	}
+	s.expect(http.StatusOK, &report, http.MethodGet, location+"/errors?format=json", "")
+	if len(report.Errors) != 1 || report.Errors[0].Row != 4 || report.Errors[0].Field != "group" {
+		t.Errorf("отчет об ошибках = %+v", report)
+	}
+	rec = s.expect(http.StatusOK, nil, http.MethodGet, location+"/errors", "")
+	rows, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil || len(rows) != 2 || rows[1][0] != "4" || rows[1][1] != "group" || rows[1][3] != "Nameless" {
+		t.Errorf("отчет CSV = %q, ошибка %v", rows, err)
+	}
+}
+
+func TestImportSongsMultipart(t *testing.T) {
+	s := newTestServer(t)
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	form.WriteField("comment", "ignored")
+	part, _ := form.CreateFormFile("file", "songs.jsonl")
+	part.Write([]byte(`{"group":"Muse","song":"Hysteria","SongDetail":{"releaseDate":2003}}` + "\n"))
+	form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/songs/import?dry_run=true", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("код ответа %d, тело %s", rec.Code, rec.Body.String())
+	}
+
+	job := s.waitImport(rec.Header().Get("Location"))
+	if job.Format != "jsonl" || !job.DryRun || job.Created != 1 {
+		t.Errorf("задание = %+v", job)
+	}
+	var songs struct {
+		Songs []models.Song `json:"songs"`
+	}
+	s.expect(http.StatusOK, &songs, http.MethodGet, "/songs", "")
+	if len(songs.Songs) != 0 {
+		t.Errorf("проверка без записи добавила песни: %+v", songs.Songs)
+	}
+}
+
+func TestImportSongsErrors(t *testing.T) {
+	s := newTestServer(t)
+
+	tests := []struct {
+		name, query, body, contentType string
+		want                           int
+	}{
+		{name: "неизвестный формат", query: "", body: "group,song\n", contentType: "text/plain", want: http.StatusUnsupportedMediaType},
+		{name: "пустой файл", query: "?format=csv", body: "", contentType: "text/csv", want: http.StatusBadRequest},
+		{name: "нет колонок", query: "", body: "title\nHysteria\n", contentType: "text/csv", want: http.StatusUnprocessableEntity},
+		{name: "не XLSX", query: "?format=xlsx", body: "group,song\n", contentType: "text/csv", want: http.StatusUnprocessableEntity},
+		{name: "неизвестное on_duplicate", query: "?on_duplicate=merge", body: "group,song\n", contentType: "text/csv", want: http.StatusBadRequest},
+		{name: "некоректный delimiter", query: "?delimiter=%3B%3B", body: "group,song\n", contentType: "text/csv", want: http.StatusBadRequest},
+		{name: "некоректный dry_run", query: "?dry_run=maybe", body: "group,song\n", contentType: "text/csv", want: http.StatusBadRequest},
+		{name: "multipart без файла", query: "", body: "--x--\r\n", contentType: "multipart/form-data; boundary=x", want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s.expect(tt.want, nil, http.MethodPost, "/songs/import"+tt.query, tt.body, "Content-Type", tt.contentType)
+		})
+	}
+
+	s.expect(http.StatusNotFound, nil, http.MethodGet, "/songs/import/missing", "")
+	s.expect(http.StatusNotFound, nil, http.MethodGet, "/songs/import/missing/errors", "")
+	s.expect(http.StatusBadRequest, nil, http.MethodGet, "/songs/import/missing/errors?format=xml", "")
+	s.expect(http.StatusGone, nil, http.MethodGet, "/songs/import/0-1", "") //задание прошлого запуска сервера
+	s.expect(http.StatusGone, nil, http.MethodGet, "/songs/import/0-1/errors", "")
+}
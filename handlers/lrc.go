@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"song-library/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxLRCSize — наибольший размер загружаемого файла LRC.
+const maxLRCSize = 1 << 20
+
+// Загрузить текст песни с метками времени
+// @Summary Загрузить LRC
+// @Description Загрузить текст песни в формате LRC или расширенного LRC с метками слов <mm:ss.xx>.
+// @Description Метки времени проверяются, строки сохраняются отсортированными по времени. Обычный текст песни не меняется
+// @Tags songs
+// @Accept plain
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param lrc body string true "Файл LRC"
+// @Success 200 {object} models.SyncedLyrics
+// @Failure 400 {string} string "Некоректный файл LRC"
+// @Failure 404 {string} string "Песня не найдена"
+// @Failure 413 {string} string "Файл слишком большой"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /songs/{id}/lrc [put]
+func (h *SongHandler) UploadLRC(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	data, ok := readTextBody(c, maxLRCSize, "LRC")
+	if !ok {
+		return
+	}
+
+	synced, err := models.ParseLRC(data)
+	if err != nil {
+		var lrcErr *models.LRCError
+		if errors.As(err, &lrcErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректный файл LRC: " + lrcErr.Error(), "line": lrcErr.Line})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректный файл LRC"})
+		return
+	}
+
+	if err := h.Repo.SetSyncedLyrics(c.Request.Context(), id, synced); err != nil {
+		respondRepositoryError(c, err, "Песня не найдена")
+		return
+	}
+
+	c.JSON(http.StatusOK, synced)
+}
+
+// Скачать текст песни с метками времени
+// @Summary Скачать LRC
+// @Description Скачать текст песни с метками времени в формате LRC
+// @Tags songs
+// @Produce plain
+// @Param id path int true "ID песни"
+// @Success 200 {string} string "Файл LRC"
+// @Failure 400 {string} string "Некоректное значение id"
+// @Failure 404 {string} string "Песня не найдена"
+// @Failure 404 {string} string "У песни нет текста с метками времени"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /songs/{id}/lrc [get]
+func (h *SongHandler) DownloadLRC(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	song, err := h.Repo.GetSong(c.Request.Context(), id)
+	if err != nil {
+		respondRepositoryError(c, err, "Песня не найдена")
+		return
+	}
+	if song.SongDetails.Synced.IsZero() {
+		c.JSON(http.StatusNotFound, gin.H{"error": "У песни нет текста с метками времени"})
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="song-`+strconv.Itoa(id)+`.lrc"`)
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(song.SongDetails.Synced.String()))
+}
+
+// Удалить метки времени текста песни
+// @Summary Удалить LRC
+// @Description Удалить текст песни с метками времени. Обычный текст песни не меняется
+// @Tags songs
+// @Produce json
+// @Param id path int true "ID песни"
+// @Success 200 {string} string "Метки времени удалены"
+// @Failure 400 {string} string "Некоректное значение id"
+// @Failure 404 {string} string "Песня не найдена"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /songs/{id}/lrc [delete]
+func (h *SongHandler) DeleteLRC(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.Repo.SetSyncedLyrics(c.Request.Context(), id, models.SyncedLyrics{}); err != nil {
+		respondRepositoryError(c, err, "Песня не найдена")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Метки времени удалены"})
+}
+
+// readTextBody читает текстовое тело запроса не больше limit байт, при ошибке отвечает клиенту.
+func readTextBody(c *gin.Context, limit int64, format string) (string, bool) {
+	data, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, limit))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Файл " + format + " слишком большой"})
+			return "", false
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Не удалось прочитать файл " + format})
+		log.Printf("Не удалось прочитать файл %s, %v\n", format, err)
+		return "", false
+	}
+	return string(data), true
+}
+
+// respondLineAt отвечает строкой, которая звучит в момент at, и следующей строкой.
+func respondLineAt(c *gin.Context, song *models.Song, at string) {
+	position, err := models.ParseTimestamp(at)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректное значение at, ожидается mm:ss.xx"})
+		log.Printf("Некоректное значение at, %v\n", err)
+		return
+	}
+	if song.SongDetails.Synced.IsZero() {
+		c.JSON(http.StatusNotFound, gin.H{"error": "У песни нет текста с метками времени"})
+		return
+	}
+
+	current, next := song.SongDetails.Synced.At(position)
+	c.JSON(http.StatusOK, gin.H{
+		"at":      position,
+		"current": current,
+		"next":    next,
+	})
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"song-library/models"
+)
+
+func TestLRC(t *testing.T) {
+	s := newTestServer(t)
+	song := s.addSong("Muse", "Hysteria", "", "2003")
+	path := songPath(song.Id, "lrc")
+
+	s.expect(http.StatusNotFound, nil, http.MethodGet, path, "")
+
+	var synced models.SyncedLyrics
+	s.expect(http.StatusOK, &synced, http.MethodPut, path, "[ar:Muse]\n[00:12.00]It's bugging me\n[00:05.50]Grating me\n", "Content-Type", "text/plain")
+	if len(synced.Lines) != 2 || synced.Lines[0].TimeMs != 5500 || synced.Lines[1].Text != "It's bugging me" {
+		t.Errorf("строки = %+v", synced.Lines)
+	}
+
+	rec := s.expect(http.StatusOK, nil, http.MethodGet, path, "")
+	if want := "[ar:Muse]\n[00:05.50]Grating me\n[00:12.00]It's bugging me\n"; rec.Body.String() != want {
+		t.Errorf("LRC = %q, ожидалось %q", rec.Body.String(), want)
+	}
+	if cd := rec.Header().Get("Content-Disposition"); cd != `attachment; filename="song-1.lrc"` {
+		t.Errorf("Content-Disposition = %s", cd)
+	}
+
+	var line struct {
+		At      int               `json:"at"`
+		Current *models.TimedLine `json:"current"`
+		Next    *models.TimedLine `json:"next"`
+	}
+	s.expect(http.StatusOK, &line, http.MethodGet, songPath(song.Id, "text")+"?at=00:06.00", "")
+	if line.At != 6000 || line.Current == nil || line.Current.Text != "Grating me" || line.Next == nil || line.Next.TimeMs != 12000 {
+		t.Errorf("строка в 00:06 = %+v", line)
+	}
+	s.expect(http.StatusBadRequest, nil, http.MethodGet, songPath(song.Id, "text")+"?at=6s", "")
+
+	rec = s.expect(http.StatusBadRequest, nil, http.MethodPut, path, "[00:01.00]ok\n[00:61.00]bad\n", "Content-Type", "text/plain")
+	var lrcErr struct {
+		Line int `json:"line"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &lrcErr); err != nil || lrcErr.Line != 2 {
+		t.Errorf("ошибка разбора = %s", rec.Body.String())
+	}
+	s.expect(http.StatusNotFound, nil, http.MethodPut, songPath(100, "lrc"), "[00:01.00]x", "Content-Type", "text/plain")
+
+	s.expect(http.StatusOK, nil, http.MethodDelete, path, "")
+	s.expect(http.StatusNotFound, nil, http.MethodGet, path, "")
+	s.expect(http.StatusNotFound, nil, http.MethodGet, songPath(song.Id, "text")+"?at=00:06.00", "")
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"net/http"
+
+	"song-library/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LyricVersionRequest — текст версии песни.
+// @Description Текст версии песни
+type LyricVersionRequest struct {
+	Text string `json:"text" example:"[Chorus]\nOoh baby, don't you know I suffer?"`
+}
+
+// Получить версии текста песни
+// @Summary Получить версии текста
+// @Description Получить все версии текста песни: оригиналы, переводы и транслитерации
+// @Tags songs
+// @Produce json
+// @Param id path int true "ID песни"
+// @Success 200 {array} models.LyricVersion
+// @Failure 400 {string} string "Некоректное значение id"
+// @Failure 404 {string} string "Песня не найдена"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /songs/{id}/lyrics [get]
+func (h *SongHandler) GetLyricVersions(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	versions, err := h.Lyrics.ListLyricVersions(c.Request.Context(), id)
+	if err != nil {
+		respondRepositoryError(c, err, "Песня не найдена")
+		return
+	}
+	if versions == nil {
+		versions = []models.LyricVersion{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"versions": versions})
+}
+
+// Сохранить версию текста песни
+// @Summary Сохранить версию текста
+// @Description Создать или заменить версию текста песни на языке lang. Заголовки частей вида [Chorus]
+// @Description используются для сопоставления с оригиналом
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param lang path string true "Языковой тег BCP 47, например en или ru-Latn"
+// @Param kind path string true "Вид версии: original, translation или transliteration"
+// @Param version body LyricVersionRequest true "Текст версии"
+// @Success 200 {object} models.LyricVersion
+// @Failure 400 {string} string "Неверный формат данных"
+// @Failure 404 {string} string "Песня не найдена"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /songs/{id}/lyrics/{lang}/{kind} [put]
+func (h *SongHandler) SaveLyricVersion(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	lang, kind, ok := lyricVersionKey(c)
+	if !ok {
+		return
+	}
+	var req LyricVersionRequest
+	if err := c.BindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	if req.Text == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Не задан текст версии"})
+		return
+	}
+
+	version := models.LyricVersion{SongId: id, Language: lang, Kind: kind, Text: req.Text}
+	if err := h.Lyrics.SaveLyricVersion(c.Request.Context(), &version); err != nil {
+		respondRepositoryError(c, err, "Песня не найдена")
+		return
+	}
+
+	c.JSON(http.StatusOK, version)
+}
+
+// Удалить версию текста песни
+// @Summary Удалить версию текста
+// @Description Удалить версию текста песни на языке lang
+// @Tags songs
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param lang path string true "Языковой тег BCP 47"
+// @Param kind path string true "Вид версии: original, translation или transliteration"
+// @Success 200 {string} string "Версия текста удалена"
+// @Failure 400 {string} string "Неверный формат параметров запроса"
+// @Failure 404 {string} string "Версия текста не найдена"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /songs/{id}/lyrics/{lang}/{kind} [delete]
+func (h *SongHandler) DeleteLyricVersion(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	lang, kind, ok := lyricVersionKey(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Lyrics.DeleteLyricVersion(c.Request.Context(), id, lang, kind); err != nil {
+		respondRepositoryError(c, err, "Версия текста не найдена")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Версия текста удалена"})
+}
+
+// lyricVersionKey разбирает язык и вид версии из пути, при ошибке отвечает клиенту 400.
+func lyricVersionKey(c *gin.Context) (string, models.LyricKind, bool) {
+	lang, ok := models.NormalizeLanguageTag(c.Param("lang"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректное значение lang, ожидается языковой тег, например en или ru-Latn"})
+		return "", "", false
+	}
+	kind := models.LyricKind(c.Param("kind"))
+	if !kind.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректное значение kind, ожидается original, translation или transliteration"})
+		return "", "", false
+	}
+	return lang, kind, true
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"song-library/models"
+)
+
+func TestLyricVersions(t *testing.T) {
+	s := newTestServer(t)
+	song := s.addSong("Muse", "Hysteria", "[Verse]\nIt's bugging me\n\n[Chorus]\nCause I want it now", "2003")
+	path := songPath(song.Id, "lyrics")
+
+	if rec := s.expect(http.StatusOK, nil, http.MethodGet, path, ""); !strings.Contains(rec.Body.String(), `"versions":[]`) {
+		t.Errorf("пустой список версий: тело %s", rec.Body.String())
+	}
+
+	var version models.LyricVersion
+	s.expect(http.StatusOK, &version, http.MethodPut, path+"/RU/translation", `{"text":"[Chorus]\nПотому что я хочу этого сейчас"}`)
+	if version.Language != "ru" || version.Kind != models.LyricTranslation {
+		t.Errorf("версия = %+v", version)
+	}
+	s.expect(http.StatusOK, nil, http.MethodPut, path+"/ru-latn/transliteration", `{"text":"Potomu chto"}`)
+
+	tests := []struct {
+		name, path, body string
+		want             int
+	}{
+		{name: "некоректный язык", path: path + "/russian!/translation", body: `{"text":"x"}`, want: http.StatusBadRequest},
+		{name: "некоректный вид", path: path + "/ru/cover", body: `{"text":"x"}`, want: http.StatusBadRequest},
+		{name: "пустой текст", path: path + "/ru/translation", body: `{"text":""}`, want: http.StatusBadRequest},
+		{name: "неизвестная песня", path: songPath(100, "lyrics") + "/ru/translation", body: `{"text":"x"}`, want: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s.expect(tt.want, nil, http.MethodPut, tt.path, tt.body)
+		})
+	}
+
+	var list struct {
+		Versions []models.LyricVersion `json:"versions"`
+	}
+	s.expect(http.StatusOK, &list, http.MethodGet, path, "")
+	if len(list.Versions) != 2 {
+		t.Errorf("версии = %+v", list.Versions)
+	}
+
+	var text struct {
+		Language string   `json:"language"`
+		Kind     string   `json:"kind"`
+		Text     []string `json:"text"`
+	}
+	s.expect(http.StatusOK, &text, http.MethodGet, songPath(song.Id, "text")+"?lang=ru", "")
+	if text.Language != "ru" || text.Kind != "translation" || len(text.Text) != 1 {
+		t.Errorf("текст на русском = %+v", text)
+	}
+	s.expect(http.StatusOK, &text, http.MethodGet, songPath(song.Id, "text")+"?lang=ru&kind=transliteration", "")
+	if text.Language != "ru-Latn" || text.Text[0] != "Potomu chto" {
+		t.Errorf("транслитерация = %+v", text)
+	}
+
+	var sideBySide struct {
+		Rows  []models.AlignedSection `json:"rows"`
+		Total int                     `json:"total"`
+	}
+	s.expect(http.StatusOK, &sideBySide, http.MethodGet, songPath(song.Id, "text")+"?lang=ru&kind=translation&mode=side-by-side", "")
+	if sideBySide.Total != 2 || sideBySide.Rows[0].Translation != nil || sideBySide.Rows[1].Translation == nil || sideBySide.Rows[1].Original == nil {
+		t.Errorf("перевод рядом с оригиналом = %+v", sideBySide)
+	}
+
+	s.expect(http.StatusOK, nil, http.MethodDelete, path+"/ru/translation", "")
+	s.expect(http.StatusNotFound, nil, http.MethodDelete, path+"/ru/translation", "")
+	s.expect(http.StatusOK, &list, http.MethodGet, path, "")
+	if len(list.Versions) != 1 || list.Versions[0].Kind != models.LyricTransliteration {
+		t.Errorf("версии после удаления = %+v", list.Versions)
+	}
+}
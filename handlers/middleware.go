@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"song-library/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserHeader — заголовок с именем пользователя, от которого выполняется запрос.
+const UserHeader = "X-User"
+
+// Actor передает имя пользователя из заголовка X-User в контекст запроса,
+// чтобы хранилище записывало изменения в историю от его имени.
+func Actor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if user := strings.TrimSpace(c.GetHeader(UserHeader)); user != "" {
+			c.Request = c.Request.WithContext(repository.WithActor(c.Request.Context(), user))
+		}
+		c.Next()
+	}
+}
+
+// AdminHeader — заголовок с токеном администратора.
+const AdminHeader = "X-Admin-Token"
+
+// adminKey — ключ контекста gin с признаком администратора.
+const adminKey = "admin"
+
+// Admin отмечает запрос как запрос администратора, если заголовок X-Admin-Token совпадает с token.
+// Пустой token отключает права администратора.
+func Admin(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		given := c.GetHeader(AdminHeader)
+		if token != "" && subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1 {
+			c.Set(adminKey, true)
+		}
+		c.Next()
+	}
+}
+
+// isAdmin сообщает, что запрос выполняет администратор.
+func isAdmin(c *gin.Context) bool {
+	return c.GetBool(adminKey)
+}
+
+// requireAdmin отвечает 403, если запрос выполняет не администратор.
+func requireAdmin(c *gin.Context) bool {
+	if isAdmin(c) {
+		return true
+	}
+	c.JSON(http.StatusForbidden, gin.H{"error": "Недостаточно прав"})
+	return false
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// pathID разбирает числовой параметр пути, при ошибке отвечает клиенту 400.
+func pathID(c *gin.Context, name string) (int, bool) {
+	id, err := strconv.Atoi(c.Param(name))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Некоректное значение " + name,
+		})
+		log.Printf("Некоректное значение %s, %v\n", name, err)
+		return 0, false
+	}
+	return id, true
+}
+
+// maxPageLimit — максимальное количество записей на странице.
+const maxPageLimit = 100
+
+// pagination разбирает параметры page и limit, при ошибке отвечает клиенту 400.
+func pagination(c *gin.Context, defaultLimit int) (page, limit int, ok bool) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Некоректное значение page",
+		})
+		log.Printf("Некоректное значение page, %v", err)
+		return 0, 0, false
+	}
+	limit, err = strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultLimit)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Некоректное значение limit",
+		})
+		log.Printf("Некоректное значение limit, %v", err)
+		return 0, 0, false
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultLimit
+	}
+	if limit > maxPageLimit {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Некоректное значение limit, ожидается не больше " + strconv.Itoa(maxPageLimit),
+		})
+		return 0, 0, false
+	}
+	return page, limit, true
+}
@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strings"
+
+	"song-library/models"
+	"song-library/patch"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxPatchSize — максимальный размер тела запроса PATCH.
+const maxPatchSize = 1 << 20
+
+// Частично изменить песню
+// @Summary Частично изменить песню
+// @Description Изменить отдельные поля песни. Патч применяется к документу того же вида, что тело PUT /songs/{id}:
+// @Description {"group", "song", "SongDetails": {"text", "releaseDate", "link"}}.
+// @Description С Content-Type application/merge-patch+json (или application/json) тело — JSON Merge Patch (RFC 7396):
+// @Description отсутствующие поля не меняются, null очищает поле. С application/json-patch+json тело — массив операций
+// @Description JSON Patch (RFC 6902), например [{"op": "remove", "path": "/SongDetails/link"}].
+// @Description Название песни и группа не могут быть пустыми, ссылка должна быть адресом http или https
+// @Tags songs
+// @Accept application/merge-patch+json,application/json-patch+json,json
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param patch body object true "Изменения песни"
+// @Param X-User header string false "Автор изменения для истории правок"
+// @Param If-Match header string false "ETag песни, полученный при чтении; обязателен, если REQUIRE_IF_MATCH=true"
+// @Success 200 {object} models.Song
+// @Header 200 {string} ETag "Версия песни после изменения"
+// @Failure 400 {string} string "Неверный формат патча"
+// @Failure 404 {string} string "Песня не найдена"
+// @Failure 409 {string} string "Не выполнилась проверка операции test"
+// @Failure 412 {string} string "Песня изменена после получения"
+// @Failure 415 {string} string "Неподдерживаемый тип содержимого"
+// @Failure 422 {object} map[string]string "Некоректные значения полей"
+// @Failure 428 {string} string "Требуется заголовок If-Match"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /songs/{id} [patch]
+func (h *SongHandler) PatchSong(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	contentType, _, err := mime.ParseMediaType(c.ContentType())
+	if err != nil || contentType != patch.MergePatchType && contentType != patch.JSONPatchType && contentType != "application/json" {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{
+			"error": "Неподдерживаемый тип содержимого, ожидается " + patch.MergePatchType + " или " + patch.JSONPatchType,
+		})
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxPatchSize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Патч слишком большой"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Не удалось прочитать патч"})
+		log.Printf("Не удалось прочитать патч, %v\n", err)
+		return
+	}
+
+	ifMatch := c.GetHeader("If-Match")
+	if ifMatch == "" && h.RequireIfMatch {
+		c.JSON(http.StatusPreconditionRequired, gin.H{"error": "Требуется заголовок If-Match с ETag песни"})
+		return
+	}
+
+	song, err := h.Repo.GetSong(c.Request.Context(), id)
+	if err != nil {
+		respondRepositoryError(c, err, "Песня не найдена")
+		return
+	}
+	if ifMatch != "" && !etagListMatches(ifMatch, song.Version, false) {
+		respondVersionMismatch(c)
+		return
+	}
+
+	doc := songDocument(song)
+	var patched interface{}
+	if contentType == patch.JSONPatchType {
+		var ops []patch.Operation
+		if ops, err = patch.ParseOperations(body); err == nil {
+			patched, err = patch.Apply(doc, ops)
+		}
+	} else {
+		var mergePatch interface{}
+		if err = json.Unmarshal(body, &mergePatch); err == nil {
+			patched = patch.Merge(doc, mergePatch)
+		} else {
+			err = &patch.Error{Op: -1, Msg: "тело запроса не является JSON"}
+		}
+	}
+	var testErr *patch.TestFailedError
+	if errors.As(err, &testErr) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат патча: " + err.Error()})
+		log.Printf("Неверный формат патча, %v\n", err)
+		return
+	}
+
+	update, fieldErrors := patchedUpdate(song, patched)
+	if len(fieldErrors) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":  "Некоректные значения полей",
+			"fields": fieldErrors,
+		})
+		return
+	}
+	if update == (models.SongUpdate{}) { //патч ничего не меняет, правка не записывается
+		c.Header("ETag", models.VersionETag(song.Version))
+		c.JSON(http.StatusOK, song)
+		return
+	}
+	if ifMatch != "" {
+		update.IfVersion = song.Version //песня не должна измениться между чтением и записью
+	}
+
+	song, err = h.Repo.UpdateSong(c.Request.Context(), id, update)
+	if err != nil {
+		respondRepositoryError(c, err, "Песня не найдена")
+		return
+	}
+	c.Header("ETag", models.VersionETag(song.Version))
+
+	c.JSON(http.StatusOK, song)
+}
+
+// songDocument возвращает песню в виде JSON документа, к которому применяется патч.
+func songDocument(song *models.Song) interface{} {
+	return map[string]interface{}{
+		"group": song.Group,
+		"song":  song.Song,
+		"SongDetails": map[string]interface{}{
+			"text":        song.SongDetails.Text,
+			"releaseDate": song.SongDetails.ReleaseDate.String(),
+			"link":        song.SongDetails.Link,
+		},
+	}
+}
+
+// patchedUpdate проверяет документ после патча и возвращает изменения только тех полей,
+// которые отличаются от текущих. Отсутствующее поле и null очищают значение. Ошибки возвращаются по путям полей.
+func patchedUpdate(song *models.Song, patched interface{}) (models.SongUpdate, map[string]string) {
+	var update models.SongUpdate
+	fieldErrors := map[string]string{}
+
+	root, ok := patched.(map[string]interface{})
+	if !ok {
+		fieldErrors["/"] = "ожидается объект"
+		return update, fieldErrors
+	}
+	details, ok := root["SongDetails"].(map[string]interface{})
+	if !ok {
+		if value, present := root["SongDetails"]; present && value != nil {
+			fieldErrors["/SongDetails"] = "ожидается объект"
+		}
+		details = map[string]interface{}{}
+	}
+	checkUnknown(root, "", fieldErrors, "group", "song", "SongDetails")
+	checkUnknown(details, "/SongDetails", fieldErrors, "text", "releaseDate", "link")
+
+	if group, ok := stringField(root, "group", "", fieldErrors); ok {
+		if strings.TrimSpace(group) == "" {
+			fieldErrors["/group"] = "группа не может быть пустой"
+		} else if group != song.Group {
+			update.Group = &group
+		}
+	}
+	if name, ok := stringField(root, "song", "", fieldErrors); ok {
+		if strings.TrimSpace(name) == "" {
+			fieldErrors["/song"] = "название песни не может быть пустым"
+		} else if name != song.Song {
+			update.Song = &name
+		}
+	}
+	if text, ok := stringField(details, "text", "/SongDetails", fieldErrors); ok && text != song.SongDetails.Text {
+		update.Text = &text
+	}
+	if link, ok := stringField(details, "link", "/SongDetails", fieldErrors); ok && link != song.SongDetails.Link {
+		if err := models.ValidateLink(link); err != nil {
+			fieldErrors["/SongDetails/link"] = err.Error()
+		} else {
+			update.Link = &link
+		}
+	}
+	if value, ok := stringField(details, "releaseDate", "/SongDetails", fieldErrors); ok {
+		releaseDate, err := models.ParseReleaseDate(value)
+		if err != nil {
+			fieldErrors["/SongDetails/releaseDate"] = err.Error()
+		} else if releaseDate.String() != song.SongDetails.ReleaseDate.String() {
+			update.ReleaseDate = &releaseDate
+		}
+	}
+	return update, fieldErrors
+}
+
+// stringField возвращает строковое поле объекта, отсутствующее поле или null дают пустую строку.
+func stringField(object map[string]interface{}, key, prefix string, fieldErrors map[string]string) (string, bool) {
+	switch value := object[key].(type) {
+	case nil:
+		return "", true
+	case string:
+		return value, true
+	default:
+		fieldErrors[prefix+"/"+key] = "ожидается строка"
+		return "", false
+	}
+}
+
+// checkUnknown отмечает поля объекта, которых нет в allowed.
+func checkUnknown(object map[string]interface{}, prefix string, fieldErrors map[string]string, allowed ...string) {
+	for key := range object {
+		known := false
+		for _, name := range allowed {
+			known = known || key == name
+		}
+		if !known {
+			fieldErrors[prefix+"/"+key] = "неизвестное поле"
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"testing"
+
+	"song-library/models"
+	"song-library/patch"
+)
+
+func TestPatchSong(t *testing.T) {
+	s := newTestServer(t)
+	song := s.addSong("Muse", "Hysteria", "It's bugging me", "2003")
+	path := songPath(song.Id)
+
+	var patched models.Song
+	rec := s.expect(http.StatusOK, &patched, http.MethodPatch, path,
+		`{"SongDetails":{"link":"https://example.com/hysteria","text":null}}`, "Content-Type", patch.MergePatchType)
+	if patched.SongDetails.Link != "https://example.com/hysteria" || patched.SongDetails.Text != "" || patched.Song != "Hysteria" {
+		t.Errorf("песня после merge patch = %+v", patched)
+	}
+	s.expect(http.StatusOK, &patched, http.MethodPatch, path,
+		`[{"op":"test","path":"/song","value":"Hysteria"},{"op":"replace","path":"/SongDetails/releaseDate","value":"15.09.2003"},{"op":"copy","from":"/song","path":"/SongDetails/text"}]`,
+		"Content-Type", patch.JSONPatchType, "If-Match", rec.Header().Get("ETag"))
+	if patched.SongDetails.ReleaseDate.String() != "15.09.2003" || patched.SongDetails.Text != "Hysteria" {
+		t.Errorf("песня после JSON Patch = %+v", patched)
+	}
+
+	//патч без изменений не записывает правку
+	rec = s.expect(http.StatusOK, &patched, http.MethodPatch, path, `{"song":"Hysteria"}`, "Content-Type", "application/json")
+	if got := rec.Header().Get("ETag"); got != models.VersionETag(patched.Version) || patched.Version != 3 {
+		t.Errorf("ETag = %s, версия %d", got, patched.Version)
+	}
+	var revisions struct {
+		Revisions []models.SongRevision `json:"revisions"`
+	}
+	s.expect(http.StatusOK, &revisions, http.MethodGet, songPath(song.Id, "revisions"), "")
+	if len(revisions.Revisions) != 3 {
+		t.Errorf("правок %d, ожидалось 3", len(revisions.Revisions))
+	}
+
+	tests := []struct {
+		name, body, contentType string
+		headers                 []string
+		want                    int
+	}{
+		{name: "тип содержимого", body: `{}`, contentType: "text/plain", want: http.StatusUnsupportedMediaType},
+		{name: "не JSON", body: `{`, contentType: patch.MergePatchType, want: http.StatusBadRequest},
+		{name: "неизвестная операция", body: `[{"op":"rename","path":"/song"}]`, contentType: patch.JSONPatchType, want: http.StatusBadRequest},
+		{name: "путь не найден", body: `[{"op":"remove","path":"/SongDetails/lyrics"}]`, contentType: patch.JSONPatchType, want: http.StatusBadRequest},
+		{name: "test не прошел", body: `[{"op":"test","path":"/group","value":"Radiohead"}]`, contentType: patch.JSONPatchType, want: http.StatusConflict},
+		{name: "устаревший ETag", body: `{"song":"x"}`, contentType: patch.MergePatchType, headers: []string{"If-Match", `"1"`}, want: http.StatusPreconditionFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s.expect(tt.want, nil, http.MethodPatch, path, tt.body, append([]string{"Content-Type", tt.contentType}, tt.headers...)...)
+		})
+	}
+
+	rec = s.expect(http.StatusUnprocessableEntity, nil, http.MethodPatch, path,
+		`{"group":" ","song":1,"album":"Absolution","SongDetails":{"releaseDate":"15/09/2003","link":"ftp://x"}}`, "Content-Type", patch.MergePatchType)
+	var fieldErrors struct {
+		Fields map[string]string `json:"fields"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &fieldErrors); err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for p := range fieldErrors.Fields {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	want := []string{"/SongDetails/link", "/SongDetails/releaseDate", "/album", "/group", "/song"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("ошибки полей = %v, ожидались %v", fieldErrors.Fields, want)
+	}
+
+	s.expect(http.StatusNotFound, nil, http.MethodPatch, songPath(100), `{}`, "Content-Type", patch.MergePatchType)
+}
@@ -0,0 +1,520 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"song-library/models"
+	"song-library/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PlaylistHandler struct {
+	Repo repository.PlaylistRepository
+}
+
+func NewPlaylistHandler(repo repository.PlaylistRepository) *PlaylistHandler {
+	return &PlaylistHandler{Repo: repo}
+}
+
+// PlaylistEntryRequest — песня, добавляемая в плейлист.
+// @Description Песня, добавляемая в плейлист
+type PlaylistEntryRequest struct {
+	SongId   int `json:"songId" example:"1"`
+	Position int `json:"position" example:"0"` //Позиция, начиная с 1, 0 — в конец плейлиста
+}
+
+// PlaylistMoveRequest — новая позиция записи плейлиста.
+// @Description Новая позиция записи плейлиста
+type PlaylistMoveRequest struct {
+	Position int `json:"position" example:"1"` //Позиция, начиная с 1, больше длины плейлиста — в конец
+}
+
+// PlaylistOrderRequest — новый порядок записей плейлиста.
+// @Description Новый порядок записей плейлиста
+type PlaylistOrderRequest struct {
+	Entries []int `json:"entries" example:"3,1,2"` //ID всех записей плейлиста в новом порядке
+}
+
+// PlaylistExportEntry — запись плейлиста в выгрузке JSON.
+type PlaylistExportEntry struct {
+	Position int    `json:"position"`
+	SongId   int    `json:"songId"`
+	Group    string `json:"group"`
+	Song     string `json:"song"`
+	Link     string `json:"link"`
+}
+
+// Получить список плейлистов
+// @Summary Получить плейлисты
+// @Description Получить публичные плейлисты и плейлисты текущего пользователя, начиная с измененных последними.
+// @Description Администратор получает все плейлисты
+// @Tags playlists
+// @Produce json
+// @Param owner query string false "Фильтр по владельцу"
+// @Param page query int false "Номер страницы(пагинация)" default(1)
+// @Param limit query int false "Лимит записей на странице" default(10)
+// @Param X-User header string false "Текущий пользователь"
+// @Param X-Admin-Token header string false "Токен администратора"
+// @Success 200 {array} models.Playlist
+// @Failure 400 {string} string "Неверный формат параметров запроса"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /playlists [get]
+func (h *PlaylistHandler) GetPlaylists(c *gin.Context) {
+	page, limit, ok := pagination(c, 10)
+	if !ok {
+		return
+	}
+
+	playlists, err := h.Repo.ListPlaylists(c.Request.Context(), repository.PlaylistFilter{
+		Owner:  c.Query("owner"),
+		Viewer: repository.ActorFrom(c.Request.Context()),
+		All:    isAdmin(c),
+		Offset: limit * (page - 1),
+		Limit:  limit,
+	})
+	if err != nil {
+		respondRepositoryError(c, err, "Плейлисты не найдены")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"page":      page,
+		"limit":     limit,
+		"playlists": playlists,
+	})
+}
+
+// Получить плейлист по ID
+// @Summary Получить плейлист
+// @Description Получить плейлист с количеством записей. Приватный плейлист доступен только владельцу и администратору
+// @Tags playlists
+// @Produce json
+// @Param id path int true "ID плейлиста"
+// @Param X-User header string false "Текущий пользователь"
+// @Param X-Admin-Token header string false "Токен администратора"
+// @Success 200 {object} models.Playlist
+// @Failure 400 {string} string "Некоректное значение id"
+// @Failure 404 {string} string "Плейлист не найден"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /playlists/{id} [get]
+func (h *PlaylistHandler) GetPlaylist(c *gin.Context) {
+	playlist, ok := h.playlist(c, false)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, playlist)
+}
+
+// Добавить плейлист
+// @Summary Добавить плейлист
+// @Description Создать пустой плейлист. Владельцем становится пользователь из заголовка X-User
+// @Tags playlists
+// @Accept json
+// @Produce json
+// @Param playlist body models.Playlist true "Данные плейлиста"
+// @Param X-User header string false "Владелец плейлиста"
+// @Success 201 {object} models.Playlist
+// @Failure 400 {string} string "Неверный формат данных"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /playlists [post]
+func (h *PlaylistHandler) AddPlaylist(c *gin.Context) {
+	playlist, ok := bindPlaylist(c)
+	if !ok {
+		return
+	}
+	playlist.Owner = repository.ActorFrom(c.Request.Context())
+
+	if err := h.Repo.CreatePlaylist(c.Request.Context(), &playlist); err != nil {
+		respondRepositoryError(c, err, "Плейлист не найден")
+		return
+	}
+
+	c.JSON(http.StatusCreated, playlist)
+}
+
+// Редактировать плейлист по ID
+// @Summary Редактировать плейлист
+// @Description Заменить название, описание и видимость плейлиста. Доступно владельцу и администратору
+// @Tags playlists
+// @Accept json
+// @Produce json
+// @Param id path int true "ID плейлиста"
+// @Param playlist body models.Playlist true "Данные плейлиста"
+// @Param X-User header string false "Текущий пользователь"
+// @Param X-Admin-Token header string false "Токен администратора"
+// @Success 200 {object} models.Playlist
+// @Failure 400 {string} string "Неверный формат данных"
+// @Failure 403 {string} string "Недостаточно прав"
+// @Failure 404 {string} string "Плейлист не найден"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /playlists/{id} [put]
+func (h *PlaylistHandler) EditPlaylist(c *gin.Context) {
+	existing, ok := h.playlist(c, true)
+	if !ok {
+		return
+	}
+	playlist, ok := bindPlaylist(c)
+	if !ok {
+		return
+	}
+
+	updated, err := h.Repo.UpdatePlaylist(c.Request.Context(), existing.Id, playlist)
+	if err != nil {
+		respondRepositoryError(c, err, "Плейлист не найден")
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// Удалить плейлист по ID
+// @Summary Удалить плейлист
+// @Description Удалить плейлист вместе с записями. Сами песни не удаляются. Доступно владельцу и администратору
+// @Tags playlists
+// @Produce json
+// @Param id path int true "ID плейлиста"
+// @Param X-User header string false "Текущий пользователь"
+// @Param X-Admin-Token header string false "Токен администратора"
+// @Success 200 {string} string "Плейлист удален"
+// @Failure 403 {string} string "Недостаточно прав"
+// @Failure 404 {string} string "Плейлист не найден"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /playlists/{id} [delete]
+func (h *PlaylistHandler) DeletePlaylist(c *gin.Context) {
+	playlist, ok := h.playlist(c, true)
+	if !ok {
+		return
+	}
+
+	if err := h.Repo.DeletePlaylist(c.Request.Context(), playlist.Id); err != nil {
+		respondRepositoryError(c, err, "Плейлист не найден")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Плейлист удален"})
+}
+
+// Получить записи плейлиста
+// @Summary Получить записи плейлиста
+// @Description Получить записи плейлиста с песнями по порядку. У записей песен из корзины поле song отсутствует
+// @Tags playlists
+// @Produce json
+// @Param id path int true "ID плейлиста"
+// @Param X-User header string false "Текущий пользователь"
+// @Param X-Admin-Token header string false "Токен администратора"
+// @Success 200 {array} models.PlaylistEntry
+// @Failure 400 {string} string "Некоректное значение id"
+// @Failure 404 {string} string "Плейлист не найден"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /playlists/{id}/entries [get]
+func (h *PlaylistHandler) GetPlaylistEntries(c *gin.Context) {
+	playlist, ok := h.playlist(c, false)
+	if !ok {
+		return
+	}
+	entries, ok := h.entries(c, playlist.Id)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"entries": entries})
+}
+
+// Добавить песню в плейлист
+// @Summary Добавить запись в плейлист
+// @Description Добавить песню на позицию в плейлисте, следующие записи сдвигаются. Без позиции песня добавляется в конец.
+// @Description Одна песня может встречаться в плейлисте несколько раз
+// @Tags playlists
+// @Accept json
+// @Produce json
+// @Param id path int true "ID плейлиста"
+// @Param entry body PlaylistEntryRequest true "Песня и позиция"
+// @Param X-User header string false "Текущий пользователь"
+// @Param X-Admin-Token header string false "Токен администратора"
+// @Success 201 {object} models.PlaylistEntry
+// @Failure 400 {string} string "Неверный формат данных"
+// @Failure 403 {string} string "Недостаточно прав"
+// @Failure 404 {string} string "Плейлист не найден"
+// @Failure 409 {string} string "Песня не найдена"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /playlists/{id}/entries [post]
+func (h *PlaylistHandler) AddPlaylistEntry(c *gin.Context) {
+	playlist, ok := h.playlist(c, true)
+	if !ok {
+		return
+	}
+	var req PlaylistEntryRequest
+	if err := c.BindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	if req.SongId <= 0 || req.Position < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректная запись: songId должен быть положительным, position — не меньше 0"})
+		return
+	}
+
+	entry := models.PlaylistEntry{PlaylistId: playlist.Id, SongId: req.SongId, Position: req.Position}
+	if err := h.Repo.AddPlaylistEntry(c.Request.Context(), &entry); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Песня не найдена"})
+			return
+		}
+		respondRepositoryError(c, err, "Плейлист не найден")
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// Убрать запись из плейлиста
+// @Summary Удалить запись из плейлиста
+// @Description Убрать запись из плейлиста, следующие записи сдвигаются
+// @Tags playlists
+// @Produce json
+// @Param id path int true "ID плейлиста"
+// @Param entry path int true "ID записи"
+// @Param X-User header string false "Текущий пользователь"
+// @Param X-Admin-Token header string false "Токен администратора"
+// @Success 200 {string} string "Запись удалена"
+// @Failure 400 {string} string "Некоректное значение параметра пути"
+// @Failure 403 {string} string "Недостаточно прав"
+// @Failure 404 {string} string "Запись не найдена"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /playlists/{id}/entries/{entry} [delete]
+func (h *PlaylistHandler) DeletePlaylistEntry(c *gin.Context) {
+	playlist, ok := h.playlist(c, true)
+	if !ok {
+		return
+	}
+	entryID, ok := pathID(c, "entry")
+	if !ok {
+		return
+	}
+
+	if err := h.Repo.RemovePlaylistEntry(c.Request.Context(), playlist.Id, entryID); err != nil {
+		respondRepositoryError(c, err, "Запись не найдена")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Запись удалена"})
+}
+
+// Переставить запись плейлиста
+// @Summary Переместить запись плейлиста
+// @Description Переставить запись на новую позицию, записи между старой и новой позицией сдвигаются
+// @Tags playlists
+// @Accept json
+// @Produce json
+// @Param id path int true "ID плейлиста"
+// @Param entry path int true "ID записи"
+// @Param move body PlaylistMoveRequest true "Новая позиция"
+// @Param X-User header string false "Текущий пользователь"
+// @Param X-Admin-Token header string false "Токен администратора"
+// @Success 200 {array} models.PlaylistEntry
+// @Failure 400 {string} string "Неверный формат данных"
+// @Failure 403 {string} string "Недостаточно прав"
+// @Failure 404 {string} string "Запись не найдена"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /playlists/{id}/entries/{entry}/move [post]
+func (h *PlaylistHandler) MovePlaylistEntry(c *gin.Context) {
+	playlist, ok := h.playlist(c, true)
+	if !ok {
+		return
+	}
+	entryID, ok := pathID(c, "entry")
+	if !ok {
+		return
+	}
+	var req PlaylistMoveRequest
+	if err := c.BindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	if req.Position <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректная позиция: position должен быть положительным"})
+		return
+	}
+
+	entries, err := h.Repo.MovePlaylistEntry(c.Request.Context(), playlist.Id, entryID, req.Position)
+	if err != nil {
+		respondRepositoryError(c, err, "Запись не найдена")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"entries": entries})
+}
+
+// Задать порядок записей плейлиста
+// @Summary Упорядочить плейлист
+// @Description Расставить записи плейлиста в заданном порядке. Список должен содержать ID каждой записи плейлиста ровно один раз
+// @Tags playlists
+// @Accept json
+// @Produce json
+// @Param id path int true "ID плейлиста"
+// @Param order body PlaylistOrderRequest true "ID записей в новом порядке"
+// @Param X-User header string false "Текущий пользователь"
+// @Param X-Admin-Token header string false "Токен администратора"
+// @Success 200 {array} models.PlaylistEntry
+// @Failure 400 {string} string "Неверный формат данных"
+// @Failure 403 {string} string "Недостаточно прав"
+// @Failure 404 {string} string "Плейлист не найден"
+// @Failure 409 {string} string "Список не совпадает с записями плейлиста"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /playlists/{id}/order [put]
+func (h *PlaylistHandler) ReorderPlaylist(c *gin.Context) {
+	playlist, ok := h.playlist(c, true)
+	if !ok {
+		return
+	}
+	var req PlaylistOrderRequest
+	if err := c.BindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	entries, err := h.Repo.ReorderPlaylist(c.Request.Context(), playlist.Id, req.Entries)
+	if err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Список должен содержать ID каждой записи плейлиста ровно один раз"})
+			return
+		}
+		respondRepositoryError(c, err, "Плейлист не найден")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"entries": entries})
+}
+
+// Выгрузить плейлист
+// @Summary Выгрузить плейлист
+// @Description Выгрузить плейлист файлом M3U8, XSPF или JSON со ссылками на песни. Песни из корзины не выгружаются,
+// @Description песни без ссылки попадают только в JSON с пустой ссылкой
+// @Tags playlists
+// @Produce audio/x-mpegurl,application/xspf+xml,json
+// @Param id path int true "ID плейлиста"
+// @Param format query string false "Формат файла: m3u8, xspf или json" default(m3u8)
+// @Param X-User header string false "Текущий пользователь"
+// @Param X-Admin-Token header string false "Токен администратора"
+// @Success 200 {file} file "Файл плейлиста"
+// @Failure 400 {string} string "Неверный формат параметров запроса"
+// @Failure 404 {string} string "Плейлист не найден"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /playlists/{id}/export [get]
+func (h *PlaylistHandler) ExportPlaylist(c *gin.Context) {
+	format := c.DefaultQuery("format", "m3u8")
+	if format != "m3u8" && format != "xspf" && format != "json" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректное значение format, допустимы m3u8, xspf и json"})
+		return
+	}
+	playlist, ok := h.playlist(c, false)
+	if !ok {
+		return
+	}
+	entries, ok := h.entries(c, playlist.Id)
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="playlist-`+strconv.Itoa(playlist.Id)+`.`+format+`"`)
+	switch format {
+	case "m3u8":
+		c.Data(http.StatusOK, "audio/x-mpegurl; charset=utf-8", []byte(models.PlaylistM3U8(*playlist, entries)))
+	case "xspf":
+		data, err := models.PlaylistXSPF(*playlist, entries)
+		if err != nil {
+			c.Writer.Header().Del("Content-Disposition")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Не удалось выгрузить плейлист"})
+			log.Printf("Не удалось выгрузить плейлист, %v\n", err)
+			return
+		}
+		c.Data(http.StatusOK, "application/xspf+xml", data)
+	case "json":
+		exported := make([]PlaylistExportEntry, 0, len(entries))
+		for _, entry := range entries {
+			if entry.Song == nil {
+				continue
+			}
+			exported = append(exported, PlaylistExportEntry{
+				Position: entry.Position,
+				SongId:   entry.SongId,
+				Group:    entry.Song.Group,
+				Song:     entry.Song.Song,
+				Link:     entry.Song.SongDetails.Link,
+			})
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"name":        playlist.Name,
+			"description": playlist.Description,
+			"owner":       playlist.Owner,
+			"entries":     exported,
+		})
+	}
+}
+
+// playlist возвращает плейлист из пути запроса, проверяя права текущего пользователя:
+// смотреть приватный плейлист, а для edit — изменять любой плейлист могут только владелец и администратор.
+// Чужой приватный плейлист отвечает 404, чтобы не раскрывать его существование.
+func (h *PlaylistHandler) playlist(c *gin.Context, edit bool) (*models.Playlist, bool) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return nil, false
+	}
+	playlist, err := h.Repo.GetPlaylist(c.Request.Context(), id)
+	if err != nil {
+		respondRepositoryError(c, err, "Плейлист не найден")
+		return nil, false
+	}
+
+	owner := isAdmin(c) || playlist.Owner == repository.ActorFrom(c.Request.Context())
+	if !owner && playlist.Visibility == models.PlaylistPrivate {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Плейлист не найден"})
+		return nil, false
+	}
+	if edit && !owner {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Недостаточно прав"})
+		return nil, false
+	}
+	return playlist, true
+}
+
+// entries возвращает записи плейлиста, при ошибке отвечает клиенту.
+func (h *PlaylistHandler) entries(c *gin.Context, playlistID int) ([]models.PlaylistEntry, bool) {
+	entries, err := h.Repo.ListPlaylistEntries(c.Request.Context(), playlistID)
+	if err != nil {
+		respondRepositoryError(c, err, "Плейлист не найден")
+		return nil, false
+	}
+	if entries == nil {
+		entries = []models.PlaylistEntry{}
+	}
+	return entries, true
+}
+
+// bindPlaylist разбирает и проверяет тело запроса с данными плейлиста.
+func bindPlaylist(c *gin.Context) (models.Playlist, bool) {
+	var playlist models.Playlist
+	if err := c.BindJSON(&playlist); err != nil {
+		respondBindError(c, err)
+		return playlist, false
+	}
+
+	playlist.Name = strings.TrimSpace(playlist.Name)
+	if playlist.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Не задано название плейлиста"})
+		return playlist, false
+	}
+	if playlist.Visibility == "" {
+		playlist.Visibility = models.PlaylistPrivate
+	}
+	if !playlist.Visibility.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректное значение visibility, ожидается private, unlisted или public"})
+		return playlist, false
+	}
+	return playlist, true
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"song-library/models"
+)
+
+// entryIDs возвращает ID записей плейлиста по порядку, проверяя, что позиции идут подряд с 1.
+func entryIDs(t *testing.T, entries []models.PlaylistEntry) []int {
+	t.Helper()
+	ids := make([]int, len(entries))
+	for i, entry := range entries {
+		if entry.Position != i+1 {
+			t.Errorf("запись %d на позиции %d, ожидалась %d", entry.Id, entry.Position, i+1)
+		}
+		ids[i] = entry.Id
+	}
+	return ids
+}
+
+func TestPlaylists(t *testing.T) {
+	s := newTestServer(t)
+	hysteria := s.addSong("Muse", "Hysteria", "", "2003")
+	uprising := s.addSong("Muse", "Uprising", "", "2009")
+	alice := []string{UserHeader, "alice"}
+	bob := []string{UserHeader, "bob"}
+
+	var playlist models.Playlist
+	s.expect(http.StatusCreated, &playlist, http.MethodPost, "/playlists", `{"name":"В дорогу"}`, alice...)
+	if playlist.Owner != "alice" || playlist.Visibility != models.PlaylistPrivate {
+		t.Errorf("плейлист = %+v", playlist)
+	}
+	path := "/playlists/" + strconv.Itoa(playlist.Id)
+	s.expect(http.StatusBadRequest, nil, http.MethodPost, "/playlists", `{"name":"x","visibility":"friends"}`, alice...)
+
+	//Чужой приватный плейлист не виден, администратору виден
+	s.expect(http.StatusNotFound, nil, http.MethodGet, path, "", bob...)
+	s.expect(http.StatusOK, nil, http.MethodGet, path, "", AdminHeader, testAdminToken)
+
+	var entries []int
+	for _, songID := range []int{hysteria.Id, uprising.Id, hysteria.Id} {
+		var entry models.PlaylistEntry
+		s.expect(http.StatusCreated, &entry, http.MethodPost, path+"/entries", `{"songId":`+strconv.Itoa(songID)+`}`, alice...)
+		entries = append(entries, entry.Id)
+	}
+	var first models.PlaylistEntry
+	s.expect(http.StatusCreated, &first, http.MethodPost, path+"/entries", `{"songId":`+strconv.Itoa(uprising.Id)+`,"position":1}`, alice...)
+	entries = append([]int{first.Id}, entries...)
+	s.expect(http.StatusConflict, nil, http.MethodPost, path+"/entries", `{"songId":100}`, alice...)
+	s.expect(http.StatusBadRequest, nil, http.MethodPost, path+"/entries", `{"songId":1,"position":-1}`, alice...)
+
+	var list struct {
+		Entries []models.PlaylistEntry `json:"entries"`
+	}
+	s.expect(http.StatusOK, &list, http.MethodGet, path+"/entries", "", alice...)
+	if got := entryIDs(t, list.Entries); !equalInts(got, entries) {
+		t.Errorf("записи = %v, ожидалось %v", got, entries)
+	}
+
+	s.expect(http.StatusOK, &list, http.MethodPost, path+"/entries/"+strconv.Itoa(entries[0])+"/move", `{"position":10}`, alice...)
+	want := []int{entries[1], entries[2], entries[3], entries[0]}
+	if got := entryIDs(t, list.Entries); !equalInts(got, want) {
+		t.Errorf("записи после перемещения = %v, ожидалось %v", got, want)
+	}
+
+	reversed := []int{want[3], want[2], want[1], want[0]}
+	s.expect(http.StatusOK, &list, http.MethodPut, path+"/order", `{"entries":`+intsJSON(reversed)+`}`, alice...)
+	if got := entryIDs(t, list.Entries); !equalInts(got, reversed) {
+		t.Errorf("записи после смены порядка = %v, ожидалось %v", got, reversed)
+	}
+	s.expect(http.StatusConflict, nil, http.MethodPut, path+"/order", `{"entries":`+intsJSON(reversed[:3])+`}`, alice...)
+
+	s.expect(http.StatusOK, nil, http.MethodDelete, path+"/entries/"+strconv.Itoa(reversed[0]), "", alice...)
+	s.expect(http.StatusNotFound, nil, http.MethodDelete, path+"/entries/"+strconv.Itoa(reversed[0]), "", alice...)
+
+	//Открытый плейлист виден всем, но менять его может только владелец
+	s.expect(http.StatusOK, nil, http.MethodPut, path, `{"name":"В дорогу","visibility":"public"}`, alice...)
+	s.expect(http.StatusOK, nil, http.MethodGet, path, "", bob...)
+	s.expect(http.StatusForbidden, nil, http.MethodPost, path+"/entries", `{"songId":1}`, bob...)
+	s.expect(http.StatusForbidden, nil, http.MethodDelete, path, "", bob...)
+
+	var playlists struct {
+		Playlists []models.Playlist `json:"playlists"`
+	}
+	s.expect(http.StatusOK, &playlists, http.MethodGet, "/playlists?owner=alice", "", bob...)
+	if len(playlists.Playlists) != 1 || playlists.Playlists[0].EntryCount != 3 {
+		t.Errorf("плейлисты = %+v", playlists.Playlists)
+	}
+
+	rec := s.expect(http.StatusOK, nil, http.MethodGet, path+"/export", "", bob...)
+	if ct := rec.Header().Get("Content-Type"); ct != "audio/x-mpegurl; charset=utf-8" || !strings.HasPrefix(rec.Body.String(), "#EXTM3U\n#PLAYLIST:В дорогу\n") {
+		t.Errorf("M3U8 = %s %q", ct, rec.Body.String())
+	}
+	rec = s.expect(http.StatusOK, nil, http.MethodGet, path+"/export?format=xspf", "", bob...)
+	if !strings.Contains(rec.Body.String(), "<title>В дорогу</title>") {
+		t.Errorf("XSPF = %s", rec.Body.String())
+	}
+	var exported struct {
+		Entries []PlaylistExportEntry `json:"entries"`
+	}
+	s.expect(http.StatusOK, &exported, http.MethodGet, path+"/export?format=json", "", bob...)
+	if len(exported.Entries) != 3 || exported.Entries[1].Song != "Uprising" || exported.Entries[0].Position != 1 {
+		t.Errorf("выгрузка JSON = %+v", exported.Entries)
+	}
+	s.expect(http.StatusBadRequest, nil, http.MethodGet, path+"/export?format=pls", "", bob...)
+
+	s.expect(http.StatusOK, nil, http.MethodDelete, path, "", alice...)
+	s.expect(http.StatusNotFound, nil, http.MethodGet, path, "", alice...)
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func intsJSON(ids []int) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(id)
+	}
+	return "[" + strings.Join(parts, ",") + "]"
+}
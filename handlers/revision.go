@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"song-library/models"
+	"song-library/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RevisionHandler struct {
+	Repo repository.RevisionRepository
+}
+
+func NewRevisionHandler(repo repository.RevisionRepository) *RevisionHandler {
+	return &RevisionHandler{Repo: repo}
+}
+
+// Получить историю изменений песни
+// @Summary Получить историю изменений
+// @Description Получить правки песни от новых к старым: кто и когда изменил какие поля, старые и новые значения
+// @Tags revisions
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param page query int false "Номер страницы(пагинация)" default(1)
+// @Param limit query int false "Лимит записей на странице" default(10)
+// @Success 200 {array} models.SongRevision
+// @Failure 400 {string} string "Неверный формат параметров запроса"
+// @Failure 404 {string} string "Песня не найдена"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /songs/{id}/revisions [get]
+func (h *RevisionHandler) GetRevisions(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	page, limit, ok := pagination(c, 10)
+	if !ok {
+		return
+	}
+
+	revisions, err := h.Repo.ListRevisions(c.Request.Context(), id, limit*(page-1), limit)
+	if err != nil {
+		respondRepositoryError(c, err, "Песня не найдена")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"page":      page,
+		"limit":     limit,
+		"revisions": revisions,
+	})
+}
+
+// Получить правку песни
+// @Summary Получить правку
+// @Description Получить правку песни по номеру вместе с состоянием полей после неё
+// @Tags revisions
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param rev path int true "Номер правки"
+// @Success 200 {object} models.SongRevision
+// @Failure 400 {string} string "Неверный формат параметров запроса"
+// @Failure 404 {string} string "Правка не найдена"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /songs/{id}/revisions/{rev} [get]
+func (h *RevisionHandler) GetRevision(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	rev, ok := pathID(c, "rev")
+	if !ok {
+		return
+	}
+
+	revision, err := h.Repo.GetRevision(c.Request.Context(), id, rev)
+	if err != nil {
+		respondRepositoryError(c, err, "Правка не найдена")
+		return
+	}
+
+	c.JSON(http.StatusOK, revision)
+}
+
+// Сравнить текст песни в двух правках
+// @Summary Сравнить правки
+// @Description Построчно сравнить текст песни после правки from и после правки rev, а также показать другие измененные поля
+// @Tags revisions
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param rev path int true "Номер правки"
+// @Param from query int false "Номер правки для сравнения, по умолчанию предыдущая"
+// @Success 200 {array} models.DiffLine
+// @Failure 400 {string} string "Неверный формат параметров запроса"
+// @Failure 404 {string} string "Правка не найдена"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /songs/{id}/revisions/{rev}/diff [get]
+func (h *RevisionHandler) DiffRevisions(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	rev, ok := pathID(c, "rev")
+	if !ok {
+		return
+	}
+	from, err := strconv.Atoi(c.DefaultQuery("from", strconv.Itoa(rev-1)))
+	if err != nil || from < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректное значение from"})
+		return
+	}
+
+	to, err := h.Repo.GetRevision(c.Request.Context(), id, rev)
+	if err != nil {
+		respondRepositoryError(c, err, "Правка не найдена")
+		return
+	}
+	var old models.SongSnapshot //правка 0 — пустая песня до создания
+	if from > 0 {
+		fromRevision, err := h.Repo.GetRevision(c.Request.Context(), id, from)
+		if err != nil {
+			respondRepositoryError(c, err, "Правка не найдена")
+			return
+		}
+		old = fromRevision.Snapshot
+	}
+
+	changes := models.FieldChanges{}
+	for _, change := range models.DiffSnapshots(old, to.Snapshot) {
+		if change.Field != "text" { //текст сравнивается построчно
+			changes = append(changes, change)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"from":    from,
+		"to":      rev,
+		"changes": changes,
+		"text":    models.DiffLines(old.Text, to.Snapshot.Text),
+	})
+}
+
+// Вернуть песню к правке
+// @Summary Откатить песню к правке
+// @Description Вернуть поля песни к состоянию после правки rev в одной транзакции. Откат записывается в историю как новая правка
+// @Tags revisions
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param rev path int true "Номер правки"
+// @Param X-User header string false "Автор отката для истории правок"
+// @Success 200 {object} models.Song
+// @Header 200 {string} ETag "Версия песни после отката"
+// @Failure 400 {string} string "Неверный формат параметров запроса"
+// @Failure 404 {string} string "Правка не найдена"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /songs/{id}/revisions/{rev}/restore [post]
+func (h *RevisionHandler) RestoreRevision(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	rev, ok := pathID(c, "rev")
+	if !ok {
+		return
+	}
+
+	song, err := h.Repo.RestoreRevision(c.Request.Context(), id, rev)
+	if err != nil {
+		respondRepositoryError(c, err, "Правка не найдена")
+		return
+	}
+
+	c.Header("ETag", models.VersionETag(song.Version))
+	c.JSON(http.StatusOK, song)
+}
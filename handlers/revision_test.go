@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"song-library/models"
+)
+
+func TestRevisions(t *testing.T) {
+	s := newTestServer(t)
+	song := s.addSong("Muse", "Hysteria", "It's bugging me\ngrating me", "2003")
+	s.expect(http.StatusOK, nil, http.MethodPut, songPath(song.Id),
+		`{"SongDetails":{"text":"It's bugging me\ntwisting me","link":"https://example.com/hysteria"}}`, UserHeader, "alice")
+
+	var list struct {
+		Revisions []models.SongRevision `json:"revisions"`
+	}
+	s.expect(http.StatusOK, &list, http.MethodGet, songPath(song.Id, "revisions"), "")
+	if len(list.Revisions) != 2 || list.Revisions[0].Revision != 2 || list.Revisions[0].Author != "alice" {
+		t.Fatalf("история = %+v", list.Revisions)
+	}
+	wantChanges := models.FieldChanges{
+		{Field: "text", Old: "It's bugging me\ngrating me", New: "It's bugging me\ntwisting me"},
+		{Field: "link", Old: "", New: "https://example.com/hysteria"},
+	}
+	if !reflect.DeepEqual(list.Revisions[0].Changes, wantChanges) {
+		t.Errorf("изменения = %+v, ожидалось %+v", list.Revisions[0].Changes, wantChanges)
+	}
+
+	var first models.SongRevision
+	s.expect(http.StatusOK, &first, http.MethodGet, songPath(song.Id, "revisions", "1"), "")
+	if first.Snapshot.Text != "It's bugging me\ngrating me" || first.Snapshot.ReleaseDate != "2003" {
+		t.Errorf("правка 1 = %+v", first)
+	}
+	s.expect(http.StatusNotFound, nil, http.MethodGet, songPath(song.Id, "revisions", "3"), "")
+
+	var diff struct {
+		From    int                 `json:"from"`
+		To      int                 `json:"to"`
+		Changes models.FieldChanges `json:"changes"`
+		Text    []models.DiffLine   `json:"text"`
+	}
+	s.expect(http.StatusOK, &diff, http.MethodGet, songPath(song.Id, "revisions", "2", "diff"), "")
+	wantText := []models.DiffLine{
+		{Op: models.DiffEqual, Text: "It's bugging me", OldLine: 1, NewLine: 1},
+		{Op: models.DiffDelete, Text: "grating me", OldLine: 2},
+		{Op: models.DiffInsert, Text: "twisting me", NewLine: 2},
+	}
+	if diff.From != 1 || diff.To != 2 || !reflect.DeepEqual(diff.Changes, wantChanges[1:]) || !reflect.DeepEqual(diff.Text, wantText) {
+		t.Errorf("сравнение = %+v", diff)
+	}
+	s.expect(http.StatusOK, &diff, http.MethodGet, songPath(song.Id, "revisions", "1", "diff"), "")
+	if diff.From != 0 || len(diff.Changes) != 3 || len(diff.Text) != 2 || diff.Text[0].Op != models.DiffInsert {
+		t.Errorf("сравнение с пустой песней = %+v", diff)
+	}
+	s.expect(http.StatusBadRequest, nil, http.MethodGet, songPath(song.Id, "revisions", "2", "diff?from=x"), "")
+
+	var restored models.Song
+	rec := s.expect(http.StatusOK, &restored, http.MethodPost, songPath(song.Id, "revisions", "1", "restore"), "")
+	if restored.SongDetails.Text != "It's bugging me\ngrating me" || restored.SongDetails.Link != "" {
+		t.Errorf("песня после возврата к правке = %+v", restored)
+	}
+	if etag := rec.Header().Get("ETag"); etag != `"3"` {
+		t.Errorf("ETag = %s, ожидалось \"3\"", etag)
+	}
+	s.expect(http.StatusOK, &list, http.MethodGet, songPath(song.Id, "revisions"), "")
+	if len(list.Revisions) != 3 || list.Revisions[0].RestoredFrom == nil || *list.Revisions[0].RestoredFrom != 1 {
+		t.Errorf("история после возврата = %+v", list.Revisions)
+	}
+	s.expect(http.StatusNotFound, nil, http.MethodPost, songPath(song.Id, "revisions", "10", "restore"), "")
+
+	//история песни в корзине недоступна
+	s.expect(http.StatusOK, nil, http.MethodDelete, songPath(song.Id), "")
+	s.expect(http.StatusNotFound, nil, http.MethodGet, songPath(song.Id, "revisions"), "")
+	s.expect(http.StatusNotFound, nil, http.MethodGet, songPath(song.Id, "revisions", "1"), "")
+	s.expect(http.StatusNotFound, nil, http.MethodGet, songPath(song.Id, "revisions", "2", "diff"), "")
+	s.expect(http.StatusNotFound, nil, http.MethodPost, songPath(song.Id, "revisions", "1", "restore"), "")
+}
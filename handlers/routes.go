@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"song-library/importer"
+	"song-library/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Register регистрирует промежуточные обработчики и маршруты API. Обработчик песен создается
+// вызывающим, чтобы он мог настроить его поля, например RequireIfMatch.
+func Register(r *gin.Engine, repo repository.Store, songs *SongHandler, songImporter *importer.Importer, adminToken string) {
+	artists := NewArtistHandler(repo, repo)
+	albums := NewAlbumHandler(repo)
+	playlists := NewPlaylistHandler(repo)
+	setlists := NewSetlistHandler(repo)
+	tags := NewTagHandler(repo)
+	revisions := NewRevisionHandler(repo)
+	imports := NewImportHandler(songImporter)
+
+	r.Use(Actor())           //автор изменений для истории правок
+	r.Use(Admin(adminToken)) //права администратора
+
+	r.GET("/songs", songs.GetSongs)
+
+	r.GET("/songs/search", songs.SearchSongs)
+
+	r.GET("/songs/export", songs.ExportSongs)
+
+	r.POST("/songs/import", imports.ImportSongs)
+
+	r.POST("/songs/tags", tags.TagSongs)
+
+	r.DELETE("/songs/tags", tags.UntagSongs)
+
+	r.GET("/songs/import/:id", imports.GetImport)
+
+	r.GET("/songs/import/:id/errors", imports.GetImportErrors)
+
+	r.GET("/songs/:id/text", songs.GetSongText)
+
+	r.PUT("/songs/:id/lrc", songs.UploadLRC)
+
+	r.GET("/songs/:id/lrc", songs.DownloadLRC)
+
+	r.DELETE("/songs/:id/lrc", songs.DeleteLRC)
+
+	r.PUT("/songs/:id/chords", songs.UploadChords)
+
+	r.GET("/songs/:id/chords", songs.GetChords)
+
+	r.DELETE("/songs/:id/chords", songs.DeleteChords)
+
+	r.GET("/songs/:id/tags", tags.GetSongTags)
+
+	r.GET("/songs/:id/lyrics", songs.GetLyricVersions)
+
+	r.PUT("/songs/:id/lyrics/:lang/:kind", songs.SaveLyricVersion)
+
+	r.DELETE("/songs/:id/lyrics/:lang/:kind", songs.DeleteLyricVersion)
+
+	r.GET("/songs/:id/revisions", revisions.GetRevisions)
+
+	r.GET("/songs/:id/revisions/:rev", revisions.GetRevision)
+
+	r.GET("/songs/:id/revisions/:rev/diff", revisions.DiffRevisions)
+
+	r.POST("/songs/:id/revisions/:rev/restore", revisions.RestoreRevision)
+
+	r.GET("/songs/:id", songs.GetSong)
+
+	r.PUT("/songs/:id", songs.EditSong)
+
+	r.PATCH("/songs/:id", songs.PatchSong)
+
+	r.DELETE("/songs/:id", songs.DeleteSong)
+
+	r.POST("/songs/:id/restore", songs.RestoreSong)
+
+	r.GET("/trash", songs.GetTrash)
+
+	r.POST("/songs", songs.AddSong)
+
+	r.GET("/songs/:id/enrichment", songs.GetEnrichment)
+
+	r.POST("/songs/:id/enrich", songs.Enrich)
+
+	r.GET("/artists", artists.GetArtists)
+
+	r.GET("/artists/:id", artists.GetArtist)
+
+	r.POST("/artists", artists.AddArtist)
+
+	r.PUT("/artists/:id", artists.EditArtist)
+
+	r.DELETE("/artists/:id", artists.DeleteArtist)
+
+	r.GET("/artists/:id/songs", artists.GetArtistSongs)
+
+	r.GET("/albums", albums.GetAlbums)
+
+	r.GET("/albums/:id", albums.GetAlbum)
+
+	r.POST("/albums", albums.AddAlbum)
+
+	r.PUT("/albums/:id", albums.EditAlbum)
+
+	r.DELETE("/albums/:id", albums.DeleteAlbum)
+
+	r.GET("/albums/:id/tracks", albums.GetAlbumTracks)
+
+	r.POST("/albums/:id/tracks", albums.AddAlbumTrack)
+
+	r.DELETE("/albums/:id/tracks/:disc/:track", albums.DeleteAlbumTrack)
+
+	r.GET("/playlists", playlists.GetPlaylists)
+
+	r.GET("/playlists/:id", playlists.GetPlaylist)
+
+	r.POST("/playlists", playlists.AddPlaylist)
+
+	r.PUT("/playlists/:id", playlists.EditPlaylist)
+
+	r.DELETE("/playlists/:id", playlists.DeletePlaylist)
+
+	r.GET("/playlists/:id/entries", playlists.GetPlaylistEntries)
+
+	r.POST("/playlists/:id/entries", playlists.AddPlaylistEntry)
+
+	r.DELETE("/playlists/:id/entries/:entry", playlists.DeletePlaylistEntry)
+
+	r.POST("/playlists/:id/entries/:entry/move", playlists.MovePlaylistEntry)
+
+	r.PUT("/playlists/:id/order", playlists.ReorderPlaylist)
+
+	r.GET("/playlists/:id/export", playlists.ExportPlaylist)
+
+	r.GET("/tags", tags.GetTags)
+
+	r.GET("/setlists", setlists.GetSetlists)
+
+	r.GET("/setlists/:id", setlists.GetSetlist)
+
+	r.POST("/setlists", setlists.AddSetlist)
+
+	r.PUT("/setlists/:id", setlists.EditSetlist)
+
+	r.DELETE("/setlists/:id", setlists.DeleteSetlist)
+
+	r.GET("/setlists/:id/plan", setlists.GetSetlistPlan)
+
+	r.GET("/setlists/:id/print", setlists.PrintSetlist)
+}
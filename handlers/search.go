@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"song-library/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// searchLanguages сопоставляет значения параметра lang конфигурациям полнотекстового поиска.
+var searchLanguages = map[string]string{
+	"ru":      "russian",
+	"russian": "russian",
+	"en":      "english",
+	"english": "english",
+}
+
+// Поиск песен по тексту
+// @Summary Поиск по текстам песен
+// @Description Полнотекстовый поиск по текстам песен. Результаты отсортированы по релевантности,
+// @Description snippet — фрагмент HTML: текст песни экранирован, совпадения выделены тегами <b></b>.
+// @Description Запрос только из исключений (-слово) находит все песни без этих слов
+// @Tags songs
+// @Produce json
+// @Param q query string true "Поисковый запрос: слова, \"фраза\", -исключение, or"
+// @Param lang query string false "Язык запроса: ru или en, по умолчанию определяется по запросу"
+// @Param page query int false "Номер страницы(пагинация)" default(1)
+// @Param limit query int false "Лимит записей на странице, от 1 до 100" default(10)
+// @Success 200 {array} models.SearchResult
+// @Failure 400 {string} string "Неверный формат параметров запроса"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /songs/search [get]
+func (h *SongHandler) SearchSongs(c *gin.Context) {
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Не задан поисковый запрос q"})
+		return
+	}
+
+	lang := c.Query("lang")
+	language, ok := searchLanguages[strings.ToLower(lang)]
+	if lang == "" {
+		language, ok = detectLanguage(q), true
+	}
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректное значение lang, ожидается ru или en"})
+		return
+	}
+
+	page, limit, ok := pagination(c, 10)
+	if !ok {
+		return
+	}
+
+	results, err := h.Repo.SearchSongs(c.Request.Context(), repository.SearchQuery{
+		Query:    q,
+		Language: language,
+		Offset:   limit * (page - 1),
+		Limit:    limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Не удалось выполнить поиск"})
+		log.Printf("Не удалось выполнить поиск, %v\n", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"page":    page,
+		"limit":   limit,
+		"lang":    language,
+		"results": results,
+	})
+}
+
+// detectLanguage выбирает русский язык, если в запросе есть кириллица, иначе английский.
+func detectLanguage(q string) string {
+	for _, r := range q {
+		if unicode.Is(unicode.Cyrillic, r) {
+			return "russian"
+		}
+	}
+	return "english"
+}
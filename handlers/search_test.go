@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	"song-library/models"
+)
+
+func TestSearchSongs(t *testing.T) {
+	s := newTestServer(t)
+	s.addSong("Muse", "Hysteria", "It's bugging me, grating me <and> twisting me around", "01.12.2003")
+	s.addSong("Muse", "Uprising", "Paranoia is in bloom, the PR transmissions will resume", "07.09.2009")
+	s.addSong("Кино", "Группа крови", "Теплое место, но улицы ждут отпечатков наших ног", "01.01.1988")
+
+	type response struct {
+		Lang    string                `json:"lang"`
+		Results []models.SearchResult `json:"results"`
+	}
+	tests := []struct {
+		query    string
+		wantLang string
+		want     []string
+	}{
+		{query: "q=bugging", wantLang: "english", want: []string{"Hysteria"}},
+		{query: "q=paranoia+or+twisting", wantLang: "english", want: []string{"Hysteria", "Uprising"}},
+		{query: "q=улицы", wantLang: "russian", want: []string{"Группа крови"}},
+		{query: "q=bloom&lang=ru", wantLang: "russian", want: []string{"Uprising"}},
+		{query: "q=nothing", wantLang: "english", want: []string{}},
+		{query: "q=-paranoia", wantLang: "english", want: []string{"Hysteria", "Группа крови"}},
+	}
+	for _, tt := range tests {
+		var got response
+		s.expect(http.StatusOK, &got, http.MethodGet, "/songs/search?"+tt.query, "")
+		titles := []string{}
+		for _, result := range got.Results {
+			titles = append(titles, result.Song.Song)
+		}
+		sort.Strings(titles)
+		if got.Lang != tt.wantLang || strings.Join(titles, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("поиск %s = %v на %s, ожидалось %v на %s", tt.query, titles, got.Lang, tt.want, tt.wantLang)
+		}
+	}
+
+	var got response
+	s.expect(http.StatusOK, &got, http.MethodGet, "/songs/search?q=grating", "")
+	if snippet := got.Results[0].Snippet; !strings.Contains(snippet, "<b>grating</b>") || !strings.Contains(snippet, "&lt;and&gt;") {
+		t.Errorf("фрагмент = %q, ожидалось выделение совпадения и экранированный текст", snippet)
+	}
+
+	for _, q := range []string{"q=", "q=x&lang=de", "q=x&page=x", "q=x&limit=101"} {
+		s.expect(http.StatusBadRequest, nil, http.MethodGet, "/songs/search?"+q, "")
+	}
+}
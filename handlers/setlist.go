@@ -0,0 +1,303 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"song-library/models"
+	"song-library/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SetlistHandler struct {
+	Repo repository.SetlistRepository
+}
+
+func NewSetlistHandler(repo repository.SetlistRepository) *SetlistHandler {
+	return &SetlistHandler{Repo: repo}
+}
+
+// maxTempo — наибольший допустимый темп песни, ударов в минуту.
+const maxTempo = 400
+
+// Получить список сет-листов
+// @Summary Получить сет-листы
+// @Description Получить сет-листы без сетов, начиная с измененных последними
+// @Tags setlists
+// @Produce json
+// @Param owner query string false "Фильтр по владельцу"
+// @Param page query int false "Номер страницы(пагинация)" default(1)
+// @Param limit query int false "Лимит записей на странице" default(10)
+// @Success 200 {array} models.Setlist
+// @Failure 400 {string} string "Неверный формат параметров запроса"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /setlists [get]
+func (h *SetlistHandler) GetSetlists(c *gin.Context) {
+	page, limit, ok := pagination(c, 10)
+	if !ok {
+		return
+	}
+
+	setlists, err := h.Repo.ListSetlists(c.Request.Context(), repository.SetlistFilter{
+		Owner:  c.Query("owner"),
+		Offset: limit * (page - 1),
+		Limit:  limit,
+	})
+	if err != nil {
+		respondRepositoryError(c, err, "Сет-листы не найдены")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"page":     page,
+		"limit":    limit,
+		"setlists": setlists,
+	})
+}
+
+// Получить сет-лист по ID
+// @Summary Получить сет-лист
+// @Description Получить сет-лист с сетами и песнями. У песен из корзины поле song отсутствует
+// @Tags setlists
+// @Produce json
+// @Param id path int true "ID сет-листа"
+// @Success 200 {object} models.Setlist
+// @Failure 400 {string} string "Некоректное значение id"
+// @Failure 404 {string} string "Сет-лист не найден"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /setlists/{id} [get]
+func (h *SetlistHandler) GetSetlist(c *gin.Context) {
+	setlist, ok := h.setlist(c, false)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, setlist)
+}
+
+// Добавить сет-лист
+// @Summary Добавить сет-лист
+// @Description Создать сет-лист с сетами и бисами. Владельцем становится пользователь из заголовка X-User
+// @Tags setlists
+// @Accept json
+// @Produce json
+// @Param setlist body models.Setlist true "Сет-лист"
+// @Param X-User header string false "Владелец сет-листа"
+// @Success 201 {object} models.Setlist
+// @Failure 400 {string} string "Неверный формат данных"
+// @Failure 409 {string} string "Песня не найдена"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /setlists [post]
+func (h *SetlistHandler) AddSetlist(c *gin.Context) {
+	setlist, ok := bindSetlist(c)
+	if !ok {
+		return
+	}
+	setlist.Owner = repository.ActorFrom(c.Request.Context())
+
+	if err := h.Repo.CreateSetlist(c.Request.Context(), &setlist); err != nil {
+		respondSetlistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, setlist)
+}
+
+// Редактировать сет-лист по ID
+// @Summary Редактировать сет-лист
+// @Description Заменить сет-лист целиком вместе с сетами, сеты и песни получают новые ID. Доступно владельцу и администратору
+// @Tags setlists
+// @Accept json
+// @Produce json
+// @Param id path int true "ID сет-листа"
+// @Param setlist body models.Setlist true "Сет-лист"
+// @Param X-User header string false "Текущий пользователь"
+// @Param X-Admin-Token header string false "Токен администратора"
+// @Success 200 {object} models.Setlist
+// @Failure 400 {string} string "Неверный формат данных"
+// @Failure 403 {string} string "Недостаточно прав"
+// @Failure 404 {string} string "Сет-лист не найден"
+// @Failure 409 {string} string "Песня не найдена"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /setlists/{id} [put]
+func (h *SetlistHandler) EditSetlist(c *gin.Context) {
+	existing, ok := h.setlist(c, true)
+	if !ok {
+		return
+	}
+	setlist, ok := bindSetlist(c)
+	if !ok {
+		return
+	}
+
+	updated, err := h.Repo.UpdateSetlist(c.Request.Context(), existing.Id, setlist)
+	if err != nil {
+		respondSetlistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// Удалить сет-лист по ID
+// @Summary Удалить сет-лист
+// @Description Удалить сет-лист вместе с сетами. Сами песни не удаляются. Доступно владельцу и администратору
+// @Tags setlists
+// @Produce json
+// @Param id path int true "ID сет-листа"
+// @Param X-User header string false "Текущий пользователь"
+// @Param X-Admin-Token header string false "Токен администратора"
+// @Success 200 {string} string "Сет-лист удален"
+// @Failure 403 {string} string "Недостаточно прав"
+// @Failure 404 {string} string "Сет-лист не найден"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /setlists/{id} [delete]
+func (h *SetlistHandler) DeleteSetlist(c *gin.Context) {
+	setlist, ok := h.setlist(c, true)
+	if !ok {
+		return
+	}
+
+	if err := h.Repo.DeleteSetlist(c.Request.Context(), setlist.Id); err != nil {
+		respondRepositoryError(c, err, "Сет-лист не найден")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Сет-лист удален"})
+}
+
+// Рассчитать сет-лист
+// @Summary Рассчитать сет-лист
+// @Description Рассчитать время каждого сета и общее время, начало каждой песни от начала сета и найти конфликты
+// @Description тональностей соседних песен сета: тональности дальше одного шага по квинтовому кругу (Am и C совпадают).
+// @Description Незаданные длительность, тональность и темп берутся из листа аккордов песни. Песни из корзины пропускаются
+// @Tags setlists
+// @Produce json
+// @Param id path int true "ID сет-листа"
+// @Success 200 {object} models.SetlistPlan
+// @Failure 400 {string} string "Некоректное значение id"
+// @Failure 404 {string} string "Сет-лист не найден"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /setlists/{id}/plan [get]
+func (h *SetlistHandler) GetSetlistPlan(c *gin.Context) {
+	setlist, ok := h.setlist(c, false)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PlanSetlist(*setlist))
+}
+
+// Распечатать сет-лист
+// @Summary Распечатать сет-лист
+// @Description Получить сет-лист для печати обычным текстом или страницей HTML: песни с номерами, началом от начала сета,
+// @Description длительностью, тональностью, темпом и заметками. «→» — переход без паузы, «!» — конфликт тональностей
+// @Tags setlists
+// @Produce plain,html
+// @Param id path int true "ID сет-листа"
+// @Param format query string false "Формат: text или html" default(text)
+// @Success 200 {string} string "Сет-лист для печати"
+// @Failure 400 {string} string "Неверный формат параметров запроса"
+// @Failure 404 {string} string "Сет-лист не найден"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /setlists/{id}/print [get]
+func (h *SetlistHandler) PrintSetlist(c *gin.Context) {
+	format := c.DefaultQuery("format", "text")
+	if format != "text" && format != "html" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректное значение format, допустимы text и html"})
+		return
+	}
+	setlist, ok := h.setlist(c, false)
+	if !ok {
+		return
+	}
+
+	plan := models.PlanSetlist(*setlist)
+	if format == "text" {
+		c.String(http.StatusOK, plan.Text())
+		return
+	}
+	page, err := plan.HTML()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Не удалось подготовить сет-лист для печати"})
+		log.Printf("Не удалось подготовить сет-лист для печати, %v\n", err)
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", page)
+}
+
+// setlist возвращает сет-лист из пути запроса. Для edit проверяет, что его изменяет владелец или администратор.
+func (h *SetlistHandler) setlist(c *gin.Context, edit bool) (*models.Setlist, bool) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return nil, false
+	}
+	setlist, err := h.Repo.GetSetlist(c.Request.Context(), id)
+	if err != nil {
+		respondRepositoryError(c, err, "Сет-лист не найден")
+		return nil, false
+	}
+	if edit && !isAdmin(c) && setlist.Owner != repository.ActorFrom(c.Request.Context()) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Недостаточно прав"})
+		return nil, false
+	}
+	return setlist, true
+}
+
+// bindSetlist разбирает и проверяет тело запроса с сет-листом. Тональности записываются единообразно.
+func bindSetlist(c *gin.Context) (models.Setlist, bool) {
+	var setlist models.Setlist
+	if err := c.BindJSON(&setlist); err != nil {
+		respondBindError(c, err)
+		return setlist, false
+	}
+
+	setlist.Name = strings.TrimSpace(setlist.Name)
+	if setlist.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Не задано название сет-листа"})
+		return setlist, false
+	}
+	for i := range setlist.Sets {
+		set := &setlist.Sets[i]
+		set.Name = strings.TrimSpace(set.Name)
+		if set.Items == nil {
+			set.Items = []models.SetlistItem{}
+		}
+		for j := range set.Items {
+			item := &set.Items[j]
+			where := fmt.Sprintf("сет %d, песня %d", i+1, j+1)
+			if item.SongId <= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": where + ": songId должен быть положительным"})
+				return setlist, false
+			}
+			if item.Key = strings.TrimSpace(item.Key); item.Key != "" {
+				key, ok := models.ParseChord(item.Key)
+				if !ok {
+					c.JSON(http.StatusBadRequest, gin.H{"error": where + ": некоректная тональность " + item.Key})
+					return setlist, false
+				}
+				item.Key = key.String()
+			}
+			if item.Tempo < 0 || item.Tempo > maxTempo {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%s: темп должен быть от 0 до %d", where, maxTempo)})
+				return setlist, false
+			}
+		}
+	}
+	if setlist.Sets == nil {
+		setlist.Sets = []models.SetlistSet{}
+	}
+	return setlist, true
+}
+
+func respondSetlistError(c *gin.Context, err error) {
+	if errors.Is(err, repository.ErrConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Песня не найдена"})
+		return
+	}
+	respondRepositoryError(c, err, "Сет-лист не найден")
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"song-library/models"
+)
+
+func TestSetlists(t *testing.T) {
+	s := newTestServer(t)
+	hysteria := s.addSong("Muse", "Hysteria", "", "2003")
+	uprising := s.addSong("Muse", "Uprising", "", "2009")
+	s.expect(http.StatusOK, nil, http.MethodPut, songPath(uprising.Id, "chords"), "{key: F#m}\n{duration: 5:03}\n{tempo: 130}\n[F#m]Paranoia",
+		"Content-Type", "text/plain")
+	alice := []string{UserHeader, "alice"}
+
+	body := `{"name":"Концерт","venue":"Клуб","sets":[
+		{"items":[
+			{"songId":` + strconv.Itoa(hysteria.Id) + `,"duration":"3:47","key":" Am ","segue":true},
+			{"songId":` + strconv.Itoa(uprising.Id) + `,"notes":"Свет погасить"}
+		]},
+		{"encore":true,"items":[{"songId":` + strconv.Itoa(hysteria.Id) + `,"duration":227}]}
+	]}`
+	var setlist models.Setlist
+	s.expect(http.StatusCreated, &setlist, http.MethodPost, "/setlists", body, alice...)
+	if setlist.Owner != "alice" || len(setlist.Sets) != 2 || setlist.Sets[0].Items[0].Key != "Am" {
+		t.Errorf("сет-лист = %+v", setlist)
+	}
+	path := "/setlists/" + strconv.Itoa(setlist.Id)
+
+	tests := []struct {
+		name, body string
+		want       int
+	}{
+		{name: "без названия", body: `{"name":""}`, want: http.StatusBadRequest},
+		{name: "нет songId", body: `{"name":"x","sets":[{"items":[{"key":"Am"}]}]}`, want: http.StatusBadRequest},
+		{name: "некоректная тональность", body: `{"name":"x","sets":[{"items":[{"songId":1,"key":"H"}]}]}`, want: http.StatusBadRequest},
+		{name: "темп", body: `{"name":"x","sets":[{"items":[{"songId":1,"tempo":500}]}]}`, want: http.StatusBadRequest},
+		{name: "длительность", body: `{"name":"x","sets":[{"items":[{"songId":1,"duration":"3:4"}]}]}`, want: http.StatusBadRequest},
+		{name: "неизвестная песня", body: `{"name":"x","sets":[{"items":[{"songId":100}]}]}`, want: http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s.expect(tt.want, nil, http.MethodPost, "/setlists", tt.body, alice...)
+		})
+	}
+
+	var plan models.SetlistPlan
+	s.expect(http.StatusOK, &plan, http.MethodGet, path+"/plan", "")
+	if plan.Duration != 3*60+47+5*60+3+227 || plan.Unknown != 0 || len(plan.Sets) != 2 {
+		t.Fatalf("расчет = %+v", plan)
+	}
+	second := plan.Sets[0].Items[1]
+	if second.Key != "F#m" || second.Tempo != 130 || second.Start != 3*60+47 || !second.Clash || second.Number != 2 {
+		t.Errorf("песня из листа аккордов = %+v", second)
+	}
+	if plan.Sets[0].Name != "Сет 1" || plan.Sets[1].Name != "Бис" || plan.Sets[1].Items[0].Number != 3 {
+		t.Errorf("сеты = %+v", plan.Sets)
+	}
+	if len(plan.Clashes) != 1 || plan.Clashes[0].FromKey != "Am" || !plan.Clashes[0].Segue {
+		t.Errorf("конфликты тональностей = %+v", plan.Clashes)
+	}
+
+	rec := s.expect(http.StatusOK, nil, http.MethodGet, path+"/print", "")
+	if text := rec.Body.String(); !strings.HasPrefix(text, "Концерт\nКлуб\n\nСет 1 — 8:50\n") || !strings.Contains(text, "!  2.    3:47  Muse - Uprising  [5:03, F#m, 130 BPM]\n") {
+		t.Errorf("печать текстом = %q", text)
+	}
+	rec = s.expect(http.StatusOK, nil, http.MethodGet, path+"/print?format=html", "")
+	if ct := rec.Header().Get("Content-Type"); ct != "text/html; charset=utf-8" || !strings.Contains(rec.Body.String(), "<title>Концерт</title>") {
+		t.Errorf("печать HTML = %s", ct)
+	}
+	s.expect(http.StatusBadRequest, nil, http.MethodGet, path+"/print?format=pdf", "")
+
+	s.expect(http.StatusForbidden, nil, http.MethodPut, path, `{"name":"Чужой"}`, UserHeader, "bob")
+	var updated models.Setlist
+	s.expect(http.StatusOK, &updated, http.MethodPut, path, `{"name":"Репетиция"}`, AdminHeader, testAdminToken)
+	if updated.Name != "Репетиция" || len(updated.Sets) != 0 || updated.Owner != "alice" {
+		t.Errorf("сет-лист после изменения = %+v", updated)
+	}
+
+	var list struct {
+		Setlists []models.Setlist `json:"setlists"`
+	}
+	s.expect(http.StatusOK, &list, http.MethodGet, "/setlists?owner=alice", "")
+	if len(list.Setlists) != 1 {
+		t.Errorf("сет-листы = %+v", list.Setlists)
+	}
+
+	s.expect(http.StatusForbidden, nil, http.MethodDelete, path, "", UserHeader, "bob")
+	s.expect(http.StatusOK, nil, http.MethodDelete, path, "", alice...)
+	s.expect(http.StatusNotFound, nil, http.MethodGet, path, "")
+}
@@ -1,30 +1,36 @@
 package handlers
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
-	"net/url"
-	"os"
 	"strconv"
 	"strings"
 	"time"
 
 	_ "song-library/docs"
+	"song-library/enrichment"
 	"song-library/models"
+	"song-library/repository"
 
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 )
 
 type SongHandler struct {
-	DB *gorm.DB
+	Repo       repository.SongRepository
+	Lyrics     repository.LyricsRepository
+	Artists    repository.ArtistRepository
+	Albums     repository.AlbumRepository
+	Tags       repository.TagRepository
+	Enrichment *enrichment.Queue
+
+	RequireIfMatch bool //изменение и удаление песни без заголовка If-Match отклоняются
 }
 
-func NewSongHandler(db *gorm.DB) *SongHandler {
-	return &SongHandler{DB: db}
+func NewSongHandler(repo repository.SongRepository, lyrics repository.LyricsRepository, artists repository.ArtistRepository,
+	albums repository.AlbumRepository, tags repository.TagRepository, queue *enrichment.Queue) *SongHandler {
+	return &SongHandler{Repo: repo, Lyrics: lyrics, Artists: artists, Albums: albums, Tags: tags, Enrichment: queue}
 }
 
 // Получить все песни
@@ -37,11 +43,25 @@ func NewSongHandler(db *gorm.DB) *SongHandler {
 // @Param song query string false "Фильтр по названию песни"
 // @Param link query string false "Фильтр по ссылке"
 // @Param text query string false "Фильтр по тексту или фрагменту тектса"
-// @Param sort query string false "Поле для сортировки asc для возрастания и desc для убывания"
-// @Param page query int false "Номер страницы(пагинация)" default(1)
-// @Param limit query int false "Лимит записей на странице" default(10)
+// @Param album query string false "Фильтр по названию альбома без учета регистра"
+// @Param tag query []string false "Фильтр по тегам вида namespace:name, параметр повторяется: tag=genre:rock&tag=mood:calm" collectionFormat(multi)
+// @Param tag_mode query string false "Сочетание тегов: and — песни со всеми тегами, or — хотя бы с одним" default(and)
+// @Param sort query string false "Поля сортировки через запятую, минус — по убыванию: group, song, release_date, created_at, updated_at, id. Например group,-release_date. По умолчанию release_date"
+// @Param released_from query string false "Вышедшие не раньше даты: DD.MM.YYYY, MM.YYYY или YYYY"
+// @Param released_to query string false "Вышедшие не позже даты включительно: DD.MM.YYYY, MM.YYYY или YYYY"
+// @Param year query int false "Вышедшие в указанном году"
+// @Param match query string false "Режим сравнения group и song: exact или fuzzy (без учета регистра, пробелов и опечаток)" default(exact)
+// @Param threshold query number false "Минимальное сходство для match=fuzzy, от 0 до 1" default(0.3)
+// @Param page query int false "Номер страницы(пагинация), вместо него лучше использовать cursor" default(1)
+// @Param limit query int false "Лимит записей на странице, от 1 до 100" default(10)
+// @Param cursor query string false "Курсор next или prev из предыдущего ответа, не поддерживается для match=fuzzy"
+// @Param total query bool false "Посчитать общее количество подходящих песен" default(false)
+// @Param facets query bool false "Посчитать количество подходящих песен с каждым тегом, по пространствам имен" default(false)
+// @Param include_deleted query bool false "Включать песни из корзины, только для администратора" default(false)
+// @Param X-Admin-Token header string false "Токен администратора"
 // @Success 200 {array} models.Song
 // @Failure 400 {string} string "Неверный формат параметров запроса"
+// @Failure 403 {string} string "Недостаточно прав"
 // @Failure 500 {string} string "Внутренняя ошибка сервера"
 // @Router /songs [get]
 func (h *SongHandler) GetSongs(c *gin.Context) {
@@ -55,85 +75,231 @@ func (h *SongHandler) GetSongs(c *gin.Context) {
 		return
 	}
 	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10")) //Количество песен на странице (по умолчанию 10)
-	if err != nil {
+	if err != nil || limit < 1 || limit > maxPageLimit {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Некоректное значение limit",
+			"error": "Некоректное значение limit, ожидается число от 1 до " + strconv.Itoa(maxPageLimit),
 		})
-		log.Printf("Некоректное значение limit, %v", err)
+		log.Printf("Некоректное значение limit %q", c.Query("limit"))
+		return
+	}
+	withTotal, err := strconv.ParseBool(c.DefaultQuery("total", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректное значение total"})
+		return
+	}
+	withFacets, err := strconv.ParseBool(c.DefaultQuery("facets", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректное значение facets"})
 		return
 	}
 
 	if page < 1 {
 		page = 1
 	}
-	if limit < 0 {
-		limit = 0
-	}
 
 	offset := limit * (page - 1)
 
-	group := c.Query("group") //фильтр по группе
-	song := c.Query("song")   //фмльтр по песне
-	link := c.Query("link")   //фильтр по ссылке
-	text := c.Query("text")   //фильтр по тексту
+	hash := queryHash(c.Request.URL.Query(), "group", "song", "link", "text", "album", "sort", "match", "threshold",
+		"released_from", "released_to", "year", "include_deleted", "tag", "tag_mode")
+	var cursor *repository.Cursor
+	if value := c.Query("cursor"); value != "" {
+		if page > 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Параметры cursor и page нельзя использовать вместе"})
+			return
+		}
+		if cursor, err = decodeCursor(value, hash); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректный курсор, он поврежден или получен для других параметров запроса"})
+			return
+		}
+	}
 
-	text = strings.ReplaceAll(text, `\n`, "\n") // чтобы коректно находились записи в бд
+	filter, ok := songFilter(c)
+	if !ok {
+		return
+	}
+	filter.Offset = offset
+	filter.Limit = limit + 1 //лишняя песня показывает, что список продолжается
+	filter.Cursor = cursor
 
-	query := h.DB.Model(&models.Song{}).
-		Joins("JOIN song_details ON song_details.song_id = songs.id").
-		Preload("SongDetails")
+	songs, err := h.Repo.ListSongs(c.Request.Context(), filter)
+	if errors.Is(err, repository.ErrFuzzyCursor) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Курсор не поддерживается для match=fuzzy, используйте page"})
+		return
+	}
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректный курсор, он поврежден или получен для других параметров запроса"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Не удалось получить список песен",
+		})
+		log.Printf("Не удалось получить список песен, %v\n", err)
+		return
+	}
 
-	if group != "" {
-		query = query.Where(`"group" = ?`, group) // Экранирую "group"
+	backward := cursor != nil && cursor.Backward
+	more := len(songs) > limit
+	if more && backward {
+		songs = songs[1:]
+	} else if more {
+		songs = songs[:limit]
 	}
-	if song != "" {
-		query = query.Where("song = ?", song)
+	setETags(songs)
+
+	response := gin.H{
+		"page":  page,
+		"limit": limit,
+		"sort":  c.Query("sort"),
+		"songs": songs,
+		"next":  nil,
+		"prev":  nil,
+	}
+	if !filter.Fuzzy {
+		hasNext := more || backward //страница перед курсором заканчивается у курсора
+		hasPrev := more && backward || !backward && (cursor != nil || offset > 0)
+		if len(songs) > 0 {
+			if hasNext {
+				response["next"] = encodeCursor(repository.SongCursor(songs[len(songs)-1], filter), hash)
+			}
+			if hasPrev {
+				prev := repository.SongCursor(songs[0], filter)
+				prev.Backward = true
+				response["prev"] = encodeCursor(prev, hash)
+			}
+		} else if cursor != nil { //за курсором ничего нет, но вернуться к нему можно
+			back := *cursor
+			back.Backward = !cursor.Backward
+			if backward {
+				response["next"] = encodeCursor(back, hash)
+			} else {
+				response["prev"] = encodeCursor(back, hash)
+			}
+		}
 	}
-	if link != "" {
-		query = query.Where("link = ?", link)
+	if withTotal {
+		total, err := h.Repo.CountSongs(c.Request.Context(), filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Не удалось посчитать песни"})
+			log.Printf("Не удалось посчитать песни, %v\n", err)
+			return
+		}
+		response["total"] = total
 	}
-	if text != "" {
-		query = query.Where("text LIKE ?", "%"+text+"%") //LIKE для частичного совподения чтобы искать не по всему тексту а по фрагменту
+	if withFacets {
+		tags, err := h.Tags.TagFacets(c.Request.Context(), filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Не удалось посчитать теги"})
+			log.Printf("Не удалось посчитать теги, %v\n", err)
+			return
+		}
+		response["facets"] = groupFacets(tags)
 	}
 
-	sortOrder := c.Query("sort")
+	c.JSON(http.StatusOK, response)
+}
 
-	if sortOrder == "asc" {
-		query = query.Order("TO_DATE(release_date, 'DD.MM.YYYY') ASC") //сортировка по возростанию
-	} else if sortOrder == "desc" {
-		query = query.Order("TO_DATE(release_date, 'DD.MM.YYYY') DESC") //сортировка по убыванию
-	} else {
-		query = query.Order("TO_DATE(release_date, 'DD.MM.YYYY') ASC") //по умолчанию сортировка по возростанию
+// Получить песню по ID
+// @Summary Получить песню
+// @Description Получить песню по её ID в том же виде, что возвращают POST /songs и PUT /songs/{id}.
+// @Description fields оставляет в ответе только перечисленные поля: Id, artistId, group, song, enrichmentStatus, version.
+// @Description include добавляет связанные данные: details — дополнительные данные (SongDetail), artist — исполнителя,
+// @Description album — альбомы с номерами дисков и треков. По умолчанию include=details.
+// @Description Ответ содержит ETag с версией песни, с заголовком If-None-Match неизмененная песня не передается повторно.
+// @Description С include=artist или album ETag не передается: исполнитель и альбомы меняются независимо от версии песни
+// @Tags songs
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param fields query string false "Поля песни через запятую, например song,group"
+// @Param include query string false "Связанные данные через запятую: details, artist, album" default(details)
+// @Param If-None-Match header string false "ETag, полученный ранее"
+// @Success 200 {object} models.Song
+// @Header 200 {string} ETag "Версия песни"
+// @Success 304 {string} string "Песня не изменилась"
+// @Failure 400 {string} string "Некоректное значение id, fields или include"
+// @Failure 404 {object} map[string]interface{} "Песня не найдена"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /songs/{id} [get]
+func (h *SongHandler) GetSong(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	fields, err := parseList(c.Query("fields"), songFields)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректное значение fields: " + err.Error()})
+		return
+	}
+	include, err := parseList(c.DefaultQuery("include", "details"), songIncludes)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректное значение include: " + err.Error()})
+		return
+	}
+
+	song, err := h.Repo.GetSong(c.Request.Context(), id)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":    "Песня не найдена",
+			"code":     "not_found",
+			"resource": "song",
+			"id":       id,
+		})
+		return
+	}
+	if err != nil {
+		respondRepositoryError(c, err, "Песня не найдена")
+		return
 	}
 
-	var songs []models.Song
-	query.Offset(offset).Limit(limit).Find(&songs)
+	versioned := !include["artist"] && !include["album"] //связанные данные не входят в версию песни
+	if versioned && notModified(c, song) {
+		return
+	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"page":  page,
-		"limit": limit,
-		"sort":  sortOrder,
-		"songs": songs,
-	})
+	view, err := h.songView(c.Request.Context(), song, fields, include)
+	if err != nil {
+		respondRepositoryError(c, err, "Песня не найдена")
+		return
+	}
+	if versioned {
+		c.Header("ETag", models.VersionETag(song.Version))
+	}
+	c.JSON(http.StatusOK, view)
 }
 
 // Получить текст песни по ID
 // @Summary Получить текст песни
-// @Description Получить текст песни по её ID
+// @Description Получить текст песни по её ID, разбитый на части: куплеты, припевы, предприпевы, бриджи и концовки.
+// @Description Повтор части возвращается ссылкой repeatOf без строк, в поле text повторы раскрыты.
+// @Description С параметром lang возвращается версия текста на этом языке, с mode=side-by-side — части оригинала
+// @Description и выбранной версии, сопоставленные по порядку и виду
 // @Tags songs
 // @Accept json
 // @Produce json
 // @Param id path int true "ID песни"
 // @Param page query int false "Номер страницы(пагинация)" default(1)
-// @Param limit query int false "Лимит куплетов на странице" default(5)
-// @Success 200 {string} string "Текст песни"
+// @Param limit query int false "Лимит частей текста на странице" default(5)
+// @Param lang query string false "Языковой тег версии текста, например en или ru-Latn"
+// @Param kind query string false "Вид версии текста: original, translation или transliteration. По умолчанию в этом порядке"
+// @Param mode query string false "plain или side-by-side (оригинал и версия lang рядом)" default(plain)
+// @Param at query string false "Момент воспроизведения mm:ss.xx: вернуть текущую и следующую строку из LRC вместо частей текста"
+// @Success 200 {array} models.LyricSection "Части текста песни"
 // @Failure 400 {string} string "Неверный формат параметров запроса"
 // @Failure 404 {string} string "Песня не найдена"
 // @Failure 404 {string} string "Текст песни отсутствует"
+// @Failure 404 {string} string "У песни нет текста с метками времени"
+// @Failure 404 {string} string "Нет текста песни на языке lang"
 // @Failure 500 {string} string "Внутренняя ошибка сервера"
 // @Router /songs/{id}/text [get]
 func (h *SongHandler) GetSongText(c *gin.Context) {
-	songId := c.Param("id")
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Некоректное значение id",
+		})
+		log.Printf("Некоректное значение id, %v\n", err)
+		return
+	}
 
 	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
 	if err != nil {
@@ -159,37 +325,118 @@ func (h *SongHandler) GetSongText(c *gin.Context) {
 		limit = 10
 	}
 
-	var songDetails models.SongDetails
+	lang := c.Query("lang")
+	if lang != "" {
+		normalized, ok := models.NormalizeLanguageTag(lang)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректное значение lang, ожидается языковой тег, например en или ru-Latn"})
+			return
+		}
+		lang = normalized
+	}
+	kind := models.LyricKind(c.Query("kind"))
+	if kind != "" && !kind.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректное значение kind, ожидается original, translation или transliteration"})
+		return
+	}
+	mode := c.DefaultQuery("mode", "plain")
+	if mode != "plain" && mode != "side-by-side" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректное значение mode, ожидается plain или side-by-side"})
+		return
+	}
+	if mode == "side-by-side" && lang == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Для mode=side-by-side нужен параметр lang"})
+		return
+	}
 
-	if h.DB.First(&songDetails, songId).Error != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Песня не найдена",
-		})
-		log.Println(err)
+	song, err := h.Repo.GetSong(c.Request.Context(), id)
+	if err != nil {
+		respondRepositoryError(c, err, "Песня не найдена")
 		return
 	}
 
-	if songDetails.Text == "" {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Текст песни отсутствует"})
+	if at := c.Query("at"); at != "" { //режим караоке: строка в момент воспроизведения
+		respondLineAt(c, song, at)
 		return
 	}
 
-	verses := strings.Split(songDetails.Text, "\n\n")
+	songDetails := song.SongDetails
+	original := songDetails.Sections
+	if original == nil { //песня сохранена до разбиения текста на части
+		original = models.ParseLyrics(songDetails.Text)
+	}
+
+	if lang == "" {
+		if songDetails.Text == "" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Текст песни отсутствует"})
+			return
+		}
+		respondSections(c, original, page, limit, gin.H{})
+		return
+	}
 
-	totalVerses := len(verses)
-	start := (page - 1) * limit
-	end := start + limit
+	versions, err := h.Lyrics.ListLyricVersions(c.Request.Context(), id)
+	if err != nil {
+		respondRepositoryError(c, err, "Песня не найдена")
+		return
+	}
+	version, ok := models.PickLyricVersion(versions, lang, kind)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Нет текста песни на языке " + lang})
+		return
+	}
+	response := gin.H{"language": version.Language, "kind": version.Kind}
+
+	if mode == "plain" {
+		respondSections(c, version.Sections, page, limit, response)
+		return
+	}
 
-	if start > totalVerses {
-		start = totalVerses
+	if version.Kind == models.LyricOriginal {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Для mode=side-by-side выберите перевод или транслитерацию"})
+		return
 	}
-	if end > totalVerses {
-		end = totalVerses
+	if stored, ok := models.PickLyricVersion(versions, "", models.LyricOriginal); ok {
+		original = stored.Sections
+	} else if songDetails.Text == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Текст песни отсутствует"})
+		return
 	}
 
-	paginationVerses := verses[start:end]
+	rows := models.AlignLyrics(original, version.Sections)
+	start, end := pageBounds(len(rows), page, limit)
+	response["rows"] = rows[start:end]
+	response["total"] = len(rows)
+	c.JSON(http.StatusOK, response)
+}
+
+// respondSections отвечает страницей частей текста, дополняя response.
+func respondSections(c *gin.Context, sections models.Lyrics, page, limit int, response gin.H) {
+	start, end := pageBounds(len(sections), page, limit)
+	paginationSections := sections[start:end]
+	text := make([]string, len(paginationSections))
+	for i, section := range paginationSections {
+		text[i] = sections.SectionText(section)
+	}
 
-	c.JSON(http.StatusOK, gin.H{"text": paginationVerses})
+	response["text"] = text
+	response["sections"] = paginationSections
+	response["total"] = len(sections)
+	c.JSON(http.StatusOK, response)
+}
+
+// pageBounds возвращает границы страницы page по limit элементов из total.
+func pageBounds(total, page, limit int) (start, end int) {
+	start = (page - 1) * limit
+	end = start + limit
+
+	if start > total {
+		start = total
+	}
+	if end > total {
+		end = total
+	}
+	return start, end
 }
 
 // Редактировать песню по ID
@@ -200,11 +447,15 @@ func (h *SongHandler) GetSongText(c *gin.Context) {
 // @Produce json
 // @Param id path int true "ID песни"
 // @Param song body models.SongWithDetails false "Данные песни"
+// @Param X-User header string false "Автор изменения для истории правок"
+// @Param If-Match header string false "ETag песни, полученный при чтении; обязателен, если REQUIRE_IF_MATCH=true"
 // @Success 200 {object} models.Song
+// @Header 200 {string} ETag "Версия песни после изменения"
 // @Failure 400 {string} string "Неверный формат данных"
-// @Failure 400 {string} string "Неверный формат даты. Ожидаемый формат: DD.MM.YYYY"
+// @Failure 400 {string} string "Неверный формат даты. Ожидаемый формат: DD.MM.YYYY, MM.YYYY или YYYY"
 // @Failure 404 {string} string "Песня не найдена"
-// @Failure 404 {string} string "Детали песни не найдены"
+// @Failure 412 {string} string "Песня изменена после получения"
+// @Failure 428 {string} string "Требуется заголовок If-Match"
 // @Failure 500 {string} string "Внутренняя ошибка сервера"
 // @Router /songs/{id} [put]
 func (h *SongHandler) EditSong(c *gin.Context) {
@@ -219,175 +470,81 @@ func (h *SongHandler) EditSong(c *gin.Context) {
 
 	var songWithDetails models.SongWithDetails //использую специальную структуру для получения тела запроса
 
-	if err := c.BindJSON(&songWithDetails); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат данных"})
-		log.Println(err)
+	if err := c.BindJSON(&songWithDetails); err != nil { //дата выхода проверяется при разборе тела запроса
+		respondBindError(c, err)
 		return
 	}
 
-	if songWithDetails.SongDetails.ReleaseDate != "" {
-		_, err = time.Parse("02.01.2006", songWithDetails.SongDetails.ReleaseDate) //проверяем коректность введеной даты
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат даты. Ожидаемый формат: DD.MM.YYYY"})
-			log.Printf("Неверный формат даты, %v\n", err)
-			return
-		}
-	}
-
-	log.Printf("Тело запроса: %+v\n", songWithDetails)
-
-	var song models.Song
-	var songDetails models.SongDetails
-
-	if err := h.DB.First(&song, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Песня не найдена"})
-		log.Printf("Детали песни не найдены, %v", err)
-		return
-	}
-
-	if err := h.DB.First(&songDetails, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Детали песни не найдены"})
-		log.Printf("Детали песни не найдены, %v", err)
-		return
-	}
-
-	song = models.Song{
-		Id:    id,
-		Song:  songWithDetails.Song,
-		Group: songWithDetails.Group,
-	}
-
-	songDetails = models.SongDetails{
-		SongId:      id,
-		Text:        songWithDetails.SongDetails.Text,
-		Link:        songWithDetails.SongDetails.Link,
-		ReleaseDate: songWithDetails.SongDetails.ReleaseDate,
-	}
-
-	tx := h.DB.Begin()
-	if tx.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Не удалось обновить данные песни"})
-		log.Printf("Детали песни не найдены, %v\n", tx.Error.Error())
-		return
-	}
-
-	if err := tx.Model(&song).Where("id = ?", id).Updates(&song).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Не удалось обновить данные песни"})
-		log.Printf("Детали песни не найдены, %v\n", err)
-		return
-	}
-
-	if err := tx.Model(&songDetails).Where("song_id = ?", id).Updates(&songDetails).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Не удалось обновить детали песни"})
-		log.Printf("Детали песни не найдены, %v\n", err)
-		return
-	}
-
-	if err := tx.Commit().Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		log.Printf("Детали песни не найдены, %v\n", err)
+	version, ok := h.ifMatch(c, id)
+	if !ok {
 		return
 	}
+	update := songUpdate(songWithDetails)
+	update.IfVersion = version
 
-	if err := h.DB.First(&song, id).Error; err != nil { //для вывода обновленных данных
-		c.JSON(http.StatusNotFound, gin.H{"error": "Песня не найдена"})
-		log.Printf("Детали песни не найдены, %v\n", err)
-		return
-	}
-
-	if err := h.DB.First(&songDetails, id).Error; err != nil { //для вывода обновленных данных
-		c.JSON(http.StatusNotFound, gin.H{"error": "Детали песни не найдены"})
-		log.Printf("Детали песни не найдены, %v\n", err)
+	song, err := h.Repo.UpdateSong(c.Request.Context(), id, update)
+	if err != nil {
+		respondRepositoryError(c, err, "Песня не найдена")
 		return
 	}
-
-	songWithDetails = models.SongWithDetails{
-		Song:  song.Song,
-		Group: song.Group,
-		SongDetails: models.SongDetails{
-			SongId:      songDetails.SongId,
-			Text:        songDetails.Text,
-			Link:        songDetails.Link,
-			ReleaseDate: songDetails.ReleaseDate,
-		},
-	}
-
-	c.JSON(http.StatusOK, songWithDetails)
+	c.Header("ETag", models.VersionETag(song.Version))
+	c.JSON(http.StatusOK, song)
 }
 
 // Удалить песню по ID
 // @Summary Удалить песню
-// @Description Удалить песню по её ID
+// @Description Переместить песню в корзину. Песню можно вернуть через POST /songs/{id}/restore,
+// @Description пока она не удалена окончательно по истечении срока хранения
 // @Tags songs
 // @Accept json
 // @Produce json
 // @Param id path int true "ID песни"
+// @Param X-User header string false "Кто удаляет песню"
+// @Param If-Match header string false "ETag песни, полученный при чтении; обязателен, если REQUIRE_IF_MATCH=true"
 // @Success 200 {string} string "Песня успешно удалена"
 // @Failure 404 {string} string "Песня не найдена"
+// @Failure 412 {string} string "Песня изменена после получения"
+// @Failure 428 {string} string "Требуется заголовок If-Match"
 // @Failure 500 {string} string "Ошибка при удалении песни"
 // @Router /songs/{id} [delete]
 func (h *SongHandler) DeleteSong(c *gin.Context) {
-	id := c.Param("id")
-	var song models.Song
-	var songDetails models.SongDetails
-
-	if err := h.DB.First(&songDetails, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Песня не найдена"})
-		log.Printf("Песня не найдена, %v\n", err)
-		return
-	}
-
-	tx := h.DB.Begin() //Начало транзакции
-	if tx.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Не удалось удалить данные песни",
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Некоректное значение id",
 		})
-		log.Printf("Не удалось удалить данные песни, %v\n", tx.Error)
+		log.Printf("Некоректное значение id, %v\n", err)
 		return
 	}
 
-	if tx.Delete(&songDetails, id).Error != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Не удалось удалить данные песни",
-		})
-		log.Printf("Не удалось удалить данные песни, %v\n", tx.Error)
+	version, ok := h.ifMatch(c, id)
+	if !ok {
 		return
 	}
 
-	if tx.Delete(&song, id).Error != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Не удалось удалить данные песни",
-		})
-		log.Printf("Не удалось удалить данные песни, %v\n", tx.Error)
+	if err := h.Repo.DeleteSong(c.Request.Context(), id, version); err != nil { //песня перемещается в корзину
+		respondRepositoryError(c, err, "Песня не найдена")
 		return
 	}
 
-	if tx.Commit().Error != nil { //подтверждение транзакции
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Не удалось удалить данные песни",
-		})
-		log.Printf("Не удалось удалить данные песни, %v\n", tx.Error)
-		return
-	}
-	c.JSON(http.StatusOK, gin.H{"message": "Песня удалена"})
+	c.JSON(http.StatusOK, gin.H{"message": "Песня перемещена в корзину"})
 }
 
 // Добавить новую песню
 // @Summary Добавить песню
-// @Description Добавить новую песню
+// @Description Добавить новую песню. Если дополнительные данные (SongDetail) не переданы,
+// @Description они запрашиваются у внешнего API в фоне, их состояние можно узнать через GET /songs/{id}/enrichment
 // @Tags songs
 // @Accept json
 // @Produce json
 // @Param song body models.Song true "Данные песни"
+// @Param X-User header string false "Автор изменения для истории правок"
 // @Success 201 {object} models.Song
 // @Failure 400 {string} string "Неверный формат данных"
-// @Failure 500 {string} string "Ошибка при добавлении песни"
-// @Failure 500 {string} string "Ошибка при получении данных от внешнего API"
+// @Failure 400 {string} string "Неверный формат даты. Ожидаемый формат: DD.MM.YYYY, MM.YYYY или YYYY"
 // @Failure 400 {string} string "Песня уже добавлена"
+// @Failure 400 {string} string "Исполнитель не найден"
+// @Failure 500 {string} string "Ошибка при добавлении песни"
 // @Router /songs [post]
 func (h *SongHandler) AddSong(c *gin.Context) {
 
@@ -395,98 +552,228 @@ func (h *SongHandler) AddSong(c *gin.Context) {
 
 	err := c.BindJSON(&song)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат данных"})
-		log.Printf("Неверный формат данных, %v\n", err)
+		respondBindError(c, err)
 		return
 	}
 
-	if h.DB.First(&song).Error == nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Песня уже добавлена"})
-		log.Println("Песня уже добавлена")
+	song.Group = strings.TrimSpace(song.Group)
+	song.Song = strings.TrimSpace(song.Song)
+	if song.Group == "" && song.ArtistId == nil { //с artistId группа берется из исполнителя
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Группа не может быть пустой"})
 		return
 	}
-
-	songDetails, err := GetSongInfo(song.Song, song.Group) //получаем доп данные песни
-	if err != nil {
-		log.Println(err)
+	if song.Song == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Название песни не может быть пустым"})
+		return
 	}
 
-	tx := h.DB.Begin() //начало транзакции, чтобы данные добавлялись в обе таблицы атомарно
-	if tx.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Не удалось добавить песню",
-		})
-		log.Printf("Не удалось добавить данные песни, %v\n", tx.Error)
-		return
+	if song.ArtistId != nil { //группа берется из исполнителя до проверки повтора
+		artist, err := h.Artists.GetArtist(c.Request.Context(), *song.ArtistId)
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Исполнитель не найден"})
+			return
+		} else if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Не удалось добавить песню"})
+			log.Printf("Не удалось получить исполнителя песни, %v\n", err)
+			return
+		}
+		song.Group = artist.Name
 	}
 
-	if tx.Create(&song).Error != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Не удалось добавить песню",
-		})
-		log.Printf("Не удалось добавить данные песни, %v\n", tx.Error)
+	if _, err := h.Repo.FindSong(c.Request.Context(), song.Group, song.Song); err == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Песня уже добавлена"})
+		log.Println("Песня уже добавлена")
 		return
 	}
 
-	songDetails.SongId = song.Id
-
-	if tx.Create(&songDetails).Error != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Не удалось добавить песню",
-		})
-		log.Printf("Не удалось добавить данные песни, %v\n", tx.Error)
-		return
+	details := song.SongDetails
+	if details.Text == "" && details.Link == "" && details.ReleaseDate.IsZero() {
+		song.EnrichmentStatus = models.EnrichmentPending //доп данные песни будут получены в фоне
+	} else {
+		song.EnrichmentStatus = models.EnrichmentSucceeded //доп данные переданы клиентом
 	}
 
-	if tx.Commit().Error != nil { //подтверждение транзакции
+	if err := h.Repo.CreateSong(c.Request.Context(), &song); errors.Is(err, repository.ErrConflict) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Исполнитель не найден"})
+		return
+	} else if errors.Is(err, repository.ErrEmptyArtist) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Группа не может быть пустой"})
+		return
+	} else if err != nil { //песня и задание в очереди добавляются атомарно
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Не удалось добавить песню",
 		})
-		log.Printf("Не удалось добавить данные песни, %v\n", tx.Error)
+		log.Printf("Не удалось добавить данные песни, %v\n", err)
 		return
 	}
 
-	song.SongDetails = *songDetails
+	h.Enrichment.Notify()
 
+	c.Header("ETag", models.VersionETag(song.Version))
 	c.JSON(http.StatusOK, song)
 }
 
-func GetSongInfo(song string, group string) (*models.SongDetails, error) { //отправляет запрос на сторонее API для получения информации о песне
-
-	var songDetails models.SongDetails
+// songFilter разбирает фильтры и сортировку списка песен без пагинации, при ошибке отвечает клиенту.
+func songFilter(c *gin.Context) (filter repository.SongFilter, ok bool) {
+	match := c.DefaultQuery("match", "exact")
+	if match != "exact" && match != "fuzzy" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Некоректное значение match, ожидается exact или fuzzy",
+		})
+		return filter, false
+	}
+	threshold, err := strconv.ParseFloat(c.DefaultQuery("threshold", "0.3"), 64) //порог сходства для нечеткого поиска
+	if err != nil || threshold < 0 || threshold > 1 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Некоректное значение threshold, ожидается число от 0 до 1",
+		})
+		return filter, false
+	}
 
-	url := fmt.Sprintf("%s/info?song=%s&group=%s", os.Getenv("API_DOMAIN"), url.QueryEscape(song), url.QueryEscape(song))
+	releasedFrom, releasedBefore, err := releaseRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		log.Printf("Некоректный фильтр по дате выхода, %v\n", err)
+		return filter, false
+	}
 
-	resp, err := http.Get(url)
+	includeDeleted, err := strconv.ParseBool(c.DefaultQuery("include_deleted", "false"))
 	if err != nil {
-		return nil, fmt.Errorf("ошибка при получении ответа от сервера: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректное значение include_deleted"})
+		return filter, false
+	}
+	if includeDeleted && !requireAdmin(c) {
+		return filter, false
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("ошибка при получении данных от API: статус %d", resp.StatusCode)
+	sortKeys, err := repository.ParseSort(c.Query("sort"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректное значение sort: " + err.Error()})
+		return filter, false
 	}
 
-	log.Println("Получен ответ от API", url)
+	tagMode := c.DefaultQuery("tag_mode", "and")
+	if tagMode != "and" && tagMode != "or" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Некоректное значение tag_mode, ожидается and или or"})
+		return filter, false
+	}
+	var tags []models.Tag
+	for _, value := range c.QueryArray("tag") {
+		tag, err := models.ParseTag(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return filter, false
+		}
+		tags = append(tags, tag)
+	}
+
+	filter = repository.SongFilter{
+		Group:     c.Query("group"), //фильтр по группе
+		Song:      c.Query("song"),  //фильтр по песне
+		Link:      c.Query("link"),  //фильтр по ссылке
+		Text:      c.Query("text"),  //фильтр по тексту
+		Album:     c.Query("album"), //фильтр по альбому
+		Sort:      sortKeys,
+		Fuzzy:     match == "fuzzy",
+		Threshold: threshold,
+		Tags:      tags,
+		AnyTag:    tagMode == "or",
+
+		ReleasedFrom:   releasedFrom,
+		ReleasedBefore: releasedBefore,
+		IncludeDeleted: includeDeleted,
+	}
+	return filter, true
+}
 
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("ошибка при получении тела ответа от сервера: %v", err)
+// releaseRange разбирает фильтры released_from, released_to и year в полуинтервал дат [from, before).
+// Частичная дата в released_to включает весь период: released_to=1997 означает до конца 1997 года.
+func releaseRange(c *gin.Context) (from, before *time.Time, err error) {
+	later := func(t time.Time) { //сужает начало интервала
+		if from == nil || t.After(*from) {
+			from = &t
+		}
+	}
+	earlier := func(t time.Time) { //сужает конец интервала
+		if before == nil || t.Before(*before) {
+			before = &t
+		}
 	}
 
-	if len(data) == 0 { //на случай если в API не будет доп данных о песне, мы их просто не добавляем
-		songDetails = models.SongDetails{Link: "", Text: "", ReleaseDate: ""}
-		return &songDetails, fmt.Errorf("дополнительные данные песни не найдены")
+	if value := c.Query("released_from"); value != "" {
+		date, err := models.ParseReleaseDate(value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Некоректное значение released_from: %v", err)
+		}
+		later(date.Start())
 	}
+	if value := c.Query("released_to"); value != "" {
+		date, err := models.ParseReleaseDate(value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Некоректное значение released_to: %v", err)
+		}
+		earlier(date.End())
+	}
+	if value := c.Query("year"); value != "" {
+		year, err := strconv.Atoi(value)
+		if err != nil || year < 1 || year > 9999 {
+			return nil, nil, fmt.Errorf("Некоректное значение year")
+		}
+		start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		later(start)
+		earlier(start.AddDate(1, 0, 0))
+	}
+	return from, before, nil
+}
 
-	log.Printf("Получен тело ответа от API %v\n%v", url, string(data))
+// songUpdate переводит тело запроса EditSong в изменения песни, пустые поля не изменяются.
+func songUpdate(songWithDetails models.SongWithDetails) models.SongUpdate {
+	var update models.SongUpdate
+	if songWithDetails.Group != "" {
+		update.Group = &songWithDetails.Group
+	}
+	if songWithDetails.Song != "" {
+		update.Song = &songWithDetails.Song
+	}
+	if songWithDetails.SongDetails.Text != "" {
+		update.Text = &songWithDetails.SongDetails.Text
+	}
+	if songWithDetails.SongDetails.Link != "" {
+		update.Link = &songWithDetails.SongDetails.Link
+	}
+	if !songWithDetails.SongDetails.ReleaseDate.IsZero() {
+		update.ReleaseDate = &songWithDetails.SongDetails.ReleaseDate
+	}
+	return update
+}
 
-	err = json.Unmarshal(data, &songDetails)
-	if err != nil {
-		return nil, fmt.Errorf("ошибка при анмаршалинге ответа от сервера: %v", err)
+// respondBindError отвечает клиенту на некоректное тело запроса.
+func respondBindError(c *gin.Context, err error) {
+	var dateErr *models.DateFormatError
+	if errors.As(err, &dateErr) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат даты. Ожидаемый формат: DD.MM.YYYY, MM.YYYY или YYYY"})
+		log.Printf("Неверный формат даты, %v\n", err)
+		return
 	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат данных"})
+	log.Printf("Неверный формат данных, %v\n", err)
+}
 
-	return &songDetails, nil
+// respondRepositoryError отвечает клиенту в зависимости от ошибки хранилища.
+func respondRepositoryError(c *gin.Context, err error, notFound string) {
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": notFound})
+		log.Printf("%v, %v\n", notFound, err)
+		return
+	}
+	if errors.Is(err, repository.ErrVersionMismatch) {
+		respondVersionMismatch(c)
+		return
+	}
+	if errors.Is(err, repository.ErrEmptyArtist) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Группа не может быть пустой"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Внутренняя ошибка сервера"})
+	log.Printf("Ошибка хранилища, %v\n", err)
 }
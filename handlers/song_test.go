@@ -0,0 +1,299 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"song-library/models"
+)
+
+func TestAddSong(t *testing.T) {
+	s := newTestServer(t)
+
+	song := s.addSong("Muse", "Hysteria", "It's bugging me", "01.12.2003")
+	if song.Id != 1 || song.SongDetails.SongId != 1 || song.SongDetails.Text != "It's bugging me" {
+		t.Errorf("песня = %+v", song)
+	}
+
+	tests := []struct {
+		name, body, want string
+	}{
+		{name: "повтор песни", body: `{"group":"Muse","song":"Hysteria"}`, want: "Песня уже добавлена"},
+		{name: "повтор с пробелами", body: `{"group":" Muse ","song":" Hysteria "}`, want: "Песня уже добавлена"},
+		{name: "пустая группа", body: `{"group":"  ","song":"Uprising"}`, want: "Группа не может быть пустой"},
+		{name: "пустое название", body: `{"group":"Muse","song":""}`, want: "Название песни не может быть пустым"},
+		{name: "неизвестный исполнитель", body: `{"artistId":100,"song":"Uprising"}`, want: "Исполнитель не найден"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := s.do(http.MethodPost, "/songs", tt.body)
+			if rec.Code != http.StatusBadRequest || errorMessage(t, rec) != tt.want {
+				t.Errorf("код ответа %d, тело %s, ожидалось 400 %q", rec.Code, rec.Body.String(), tt.want)
+			}
+		})
+	}
+	s.expect(http.StatusBadRequest, nil, http.MethodPost, "/songs", `{"group":`)
+	var artists struct {
+		Artists []models.Artist `json:"artists"`
+	}
+	s.expect(http.StatusOK, &artists, http.MethodGet, "/artists", "")
+	if len(artists.Artists) != 1 || artists.Artists[0].Name != "Muse" {
+		t.Errorf("исполнители = %+v, ожидался только Muse", artists.Artists)
+	}
+
+	if song.EnrichmentStatus != models.EnrichmentSucceeded {
+		t.Errorf("состояние песни с переданными данными = %s, ожидалось %s", song.EnrichmentStatus, models.EnrichmentSucceeded)
+	}
+	var pending models.Song
+	s.expect(http.StatusOK, &pending, http.MethodPost, "/songs", `{"group":"Muse","song":"Uprising"}`)
+	if pending.EnrichmentStatus != models.EnrichmentPending {
+		t.Errorf("состояние песни без данных = %s, ожидалось %s", pending.EnrichmentStatus, models.EnrichmentPending)
+	}
+
+	rec := s.do(http.MethodPost, "/songs", `{"group":"Muse","song":"Starlight","SongDetail":{"releaseDate":"2006-13"}}`)
+	if rec.Code != http.StatusBadRequest || errorMessage(t, rec) != "Неверный формат даты. Ожидаемый формат: DD.MM.YYYY, MM.YYYY или YYYY" {
+		t.Errorf("некоректная дата: код ответа %d, тело %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestGetSongs(t *testing.T) {
+	s := newTestServer(t)
+	s.addSong("Muse", "Hysteria", "It's bugging me\ngrating me", "01.12.2003")
+	s.addSong("Muse", "Starlight", "Far away", "04.09.2006")
+	s.addSong("Radiohead", "Creep", "", "09.1992")
+
+	tests := []struct {
+		name, query string
+		want        []string
+	}{
+		{name: "по дате выхода", query: "", want: []string{"Creep", "Hysteria", "Starlight"}},
+		{name: "по убыванию даты", query: "?sort=desc", want: []string{"Starlight", "Hysteria", "Creep"}},
+		{name: "по группе", query: "?group=Muse", want: []string{"Hysteria", "Starlight"}},
+		{name: "по убыванию названия", query: "?group=Muse&sort=-song", want: []string{"Starlight", "Hysteria"}},
+		{name: "по нескольким полям", query: "?sort=group,-release_date", want: []string{"Starlight", "Hysteria", "Creep"}},
+		{name: "по тексту с переводом строки", query: `?text=me\ngrating`, want: []string{"Hysteria"}},
+		{name: "вторая страница", query: "?limit=2&page=2", want: []string{"Starlight"}},
+		{name: "ничего не найдено", query: "?song=Uprising", want: []string{}},
+		{name: "за год", query: "?year=2003", want: []string{"Hysteria"}},
+		{name: "с месяца по год включительно", query: "?released_from=09.1992&released_to=2003", want: []string{"Creep", "Hysteria"}},
+		{name: "с даты", query: "?released_from=2004-01-01", want: []string{"Starlight"}},
+		{name: "нечеткий поиск по группе", query: "?match=fuzzy&group=radio+head", want: []string{"Creep"}},
+		{name: "нечеткий поиск с опечаткой", query: "?match=fuzzy&song=histeria", want: []string{"Hysteria"}},
+		{name: "высокий порог сходства", query: "?match=fuzzy&group=radio+head&threshold=0.95", want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var page struct {
+				Songs []models.Song `json:"songs"`
+			}
+			s.expect(http.StatusOK, &page, http.MethodGet, "/songs"+tt.query, "")
+			got := []string{}
+			for _, song := range page.Songs {
+				got = append(got, song.Song)
+			}
+			if !equalStrings(got, tt.want) {
+				t.Errorf("GET /songs%s = %v, ожидалось %v", tt.query, got, tt.want)
+			}
+		})
+	}
+
+	s.expect(http.StatusBadRequest, nil, http.MethodGet, "/songs?page=x", "")
+	s.expect(http.StatusBadRequest, nil, http.MethodGet, "/songs?limit=x", "")
+	s.expect(http.StatusBadRequest, nil, http.MethodGet, "/songs?match=regex", "")
+	s.expect(http.StatusBadRequest, nil, http.MethodGet, "/songs?sort=title", "")
+	s.expect(http.StatusBadRequest, nil, http.MethodGet, "/songs?sort=song,-song", "")
+	s.expect(http.StatusBadRequest, nil, http.MethodGet, "/songs?year=x", "")
+	s.expect(http.StatusBadRequest, nil, http.MethodGet, "/songs?released_from=31.02.2003", "")
+	s.expect(http.StatusBadRequest, nil, http.MethodGet, "/songs?match=fuzzy&threshold=2", "")
+}
+
+func TestGetSongsCursor(t *testing.T) {
+	s := newTestServer(t)
+	for _, song := range []struct{ title, date string }{{"e", "2005"}, {"c", "2003"}, {"a", "2001"}, {"d", "2004"}, {"b", "2002"}} {
+		s.addSong("Muse", song.title, "", song.date)
+	}
+
+	type page struct {
+		Songs []models.Song `json:"songs"`
+		Next  *string       `json:"next"`
+		Prev  *string       `json:"prev"`
+		Total int           `json:"total"`
+	}
+	query := url.Values{"sort": {"desc"}, "limit": {"2"}, "total": {"true"}}
+
+	var titles []string
+	var last page
+	for cursor := ""; ; {
+		if cursor != "" {
+			query.Set("cursor", cursor)
+		}
+		var p page
+		s.expect(http.StatusOK, &p, http.MethodGet, "/songs?"+query.Encode(), "")
+		for _, song := range p.Songs {
+			titles = append(titles, song.Song)
+		}
+		last = p
+		if p.Next == nil {
+			break
+		}
+		cursor = *p.Next
+	}
+	if !equalStrings(titles, []string{"e", "d", "c", "b", "a"}) || last.Total != 5 {
+		t.Errorf("песни по курсорам = %v, всего %d", titles, last.Total)
+	}
+
+	var prev page
+	query.Set("cursor", *last.Prev)
+	s.expect(http.StatusOK, &prev, http.MethodGet, "/songs?"+query.Encode(), "")
+	if len(prev.Songs) != 2 || prev.Songs[0].Song != "c" || prev.Songs[1].Song != "b" {
+		t.Errorf("страница перед последней = %+v", prev.Songs)
+	}
+
+	//после удаления последней песни страница по курсору пуста, список выводится как [], а не null
+	query.Set("cursor", *prev.Next)
+	s.expect(http.StatusOK, nil, http.MethodDelete, songPath(last.Songs[0].Id), "")
+	if rec := s.expect(http.StatusOK, nil, http.MethodGet, "/songs?"+query.Encode(), ""); !strings.Contains(rec.Body.String(), `"songs":[]`) {
+		t.Errorf("пустая страница по курсору: тело %s", rec.Body.String())
+	}
+
+	query.Set("sort", "asc")
+	rec := s.expect(http.StatusBadRequest, nil, http.MethodGet, "/songs?"+query.Encode(), "")
+	if msg := errorMessage(t, rec); msg != "Некоректный курсор, он поврежден или получен для других параметров запроса" {
+		t.Errorf("курсор для другой сортировки: %s", msg)
+	}
+
+	for _, q := range []string{"limit=101", "limit=0", "page=2&cursor=x", "cursor=x", "total=maybe"} {
+		s.expect(http.StatusBadRequest, nil, http.MethodGet, "/songs?"+q, "")
+	}
+}
+
+func TestEditSong(t *testing.T) {
+	s := newTestServer(t)
+	song := s.addSong("Muse", "Hysteria", "old", "")
+
+	var edited models.Song
+	rec := s.expect(http.StatusOK, &edited, http.MethodPut, songPath(song.Id), `{"SongDetails":{"text":"new","releaseDate":"01.12.2003"}}`, "If-Match", `"1"`)
+	if edited.Song != "Hysteria" || edited.Version != 2 || edited.SongDetails.Text != "new" || edited.SongDetails.ReleaseDate.String() != "01.12.2003" {
+		t.Errorf("песня после изменения = %+v", edited)
+	}
+	if etag := rec.Header().Get("ETag"); etag != `"2"` {
+		t.Errorf("ETag = %s, ожидалось \"2\"", etag)
+	}
+
+	s.expect(http.StatusPreconditionFailed, nil, http.MethodPut, songPath(song.Id), `{"SongDetails":{"text":"stale"}}`, "If-Match", `"1"`)
+	s.expect(http.StatusPreconditionFailed, nil, http.MethodDelete, songPath(song.Id), "", "If-Match", `W/"2"`)
+	s.expect(http.StatusOK, nil, http.MethodPut, songPath(song.Id), `{"SongDetails":{"text":"any"}}`, "If-Match", `"5", *`)
+
+	s.expect(http.StatusBadRequest, nil, http.MethodPut, songPath(song.Id), `{"SongDetails":{"releaseDate":"2003-13"}}`)
+	s.expect(http.StatusBadRequest, nil, http.MethodPut, "/songs/abc", `{}`)
+	s.expect(http.StatusNotFound, nil, http.MethodPut, songPath(100), `{"song":"x"}`)
+}
+
+func TestGetSong(t *testing.T) {
+	s := newTestServer(t)
+	song := s.addSong("Muse", "Hysteria", "It's bugging me", "2003")
+
+	var got models.Song
+	rec := s.expect(http.StatusOK, &got, http.MethodGet, songPath(song.Id), "")
+	if got.Song != "Hysteria" || got.Group != "Muse" || got.SongDetails.Text != "It's bugging me" || got.Version != 1 {
+		t.Errorf("песня = %+v", got)
+	}
+	etag := rec.Header().Get("ETag")
+	if etag != `"1"` {
+		t.Errorf("ETag = %s, ожидалось \"1\"", etag)
+	}
+	s.expect(http.StatusNotModified, nil, http.MethodGet, songPath(song.Id), "", "If-None-Match", "W/"+etag)
+	s.expect(http.StatusOK, nil, http.MethodGet, songPath(song.Id), "", "If-None-Match", `"2"`)
+
+	var view map[string]interface{}
+	s.expect(http.StatusOK, &view, http.MethodGet, songPath(song.Id)+"?fields=song,version&include=", "")
+	if len(view) != 2 || view["song"] != "Hysteria" || view["version"] != float64(1) {
+		t.Errorf("fields=song,version: ответ %v", view)
+	}
+
+	var album models.Album
+	s.expect(http.StatusCreated, &album, http.MethodPost, "/albums", `{"title":"Absolution"}`)
+	s.expect(http.StatusOK, nil, http.MethodPost, "/albums/"+strconv.Itoa(album.Id)+"/tracks", `{"songId":`+strconv.Itoa(song.Id)+`,"track":3}`)
+	var related struct {
+		Song   string        `json:"song"`
+		Artist models.Artist `json:"artist"`
+		Albums []struct {
+			Album models.Album `json:"album"`
+			Track int          `json:"track"`
+		} `json:"albums"`
+		Details *models.SongDetails `json:"SongDetail"`
+	}
+	rec = s.expect(http.StatusOK, &related, http.MethodGet, songPath(song.Id)+"?fields=song&include=artist,album", "")
+	if related.Song != "Hysteria" || related.Artist.Name != "Muse" || len(related.Albums) != 1 || related.Albums[0].Album.Title != "Absolution" ||
+		related.Albums[0].Track != 3 || related.Details != nil {
+		t.Errorf("песня со связанными данными = %+v", related)
+	}
+	if rec.Header().Get("ETag") != "" {
+		t.Errorf("ETag с include=artist,album = %s, ожидалось без ETag", rec.Header().Get("ETag"))
+	}
+
+	var notFound map[string]interface{}
+	s.expect(http.StatusNotFound, &notFound, http.MethodGet, songPath(100), "")
+	if notFound["code"] != "not_found" || notFound["id"] != float64(100) {
+		t.Errorf("ответ для несуществующей песни %v", notFound)
+	}
+	s.expect(http.StatusBadRequest, nil, http.MethodGet, "/songs/abc", "")
+	s.expect(http.StatusBadRequest, nil, http.MethodGet, songPath(song.Id)+"?fields=text", "")
+	s.expect(http.StatusBadRequest, nil, http.MethodGet, songPath(song.Id)+"?include=lyrics", "")
+}
+
+func TestRequireIfMatch(t *testing.T) {
+	s := newTestServer(t)
+	song := s.addSong("Muse", "Hysteria", "", "")
+	s.songs.RequireIfMatch = true
+
+	s.expect(http.StatusPreconditionRequired, nil, http.MethodPut, songPath(song.Id), `{"song":"x"}`)
+	s.expect(http.StatusPreconditionRequired, nil, http.MethodDelete, songPath(song.Id), "")
+	s.expect(http.StatusOK, nil, http.MethodDelete, songPath(song.Id), "", "If-Match", `"1"`)
+	s.expect(http.StatusNotFound, nil, http.MethodGet, songPath(song.Id), "")
+}
+
+func TestDeleteSong(t *testing.T) {
+	s := newTestServer(t)
+	song := s.addSong("Muse", "Hysteria", "", "")
+
+	s.expect(http.StatusOK, nil, http.MethodDelete, songPath(song.Id), "")
+	s.expect(http.StatusNotFound, nil, http.MethodDelete, songPath(song.Id), "")
+	s.expect(http.StatusNotFound, nil, http.MethodGet, songPath(song.Id, "text"), "")
+}
+
+func TestGetSongText(t *testing.T) {
+	s := newTestServer(t)
+	song := s.addSong("Muse", "Hysteria", "It's bugging me\n\n[Chorus]\nCause I want it now\n\nGrating me\n\n[Chorus]", "")
+
+	var text struct {
+		Text     []string              `json:"text"`
+		Sections []models.LyricSection `json:"sections"`
+		Total    int                   `json:"total"`
+	}
+	s.expect(http.StatusOK, &text, http.MethodGet, songPath(song.Id, "text")+"?page=2&limit=2", "")
+	if text.Total != 4 || len(text.Sections) != 2 || text.Sections[1].RepeatOf != 2 || text.Text[1] != "[Chorus]\nCause I want it now" {
+		t.Errorf("вторая страница текста = %+v", text)
+	}
+
+	empty := s.addSong("Muse", "Untitled", "", "")
+	s.expect(http.StatusNotFound, nil, http.MethodGet, songPath(empty.Id, "text"), "")
+	s.expect(http.StatusBadRequest, nil, http.MethodGet, songPath(song.Id, "text")+"?page=x", "")
+	s.expect(http.StatusBadRequest, nil, http.MethodGet, songPath(song.Id, "text")+"?mode=side-by-side", "")
+	s.expect(http.StatusNotFound, nil, http.MethodGet, songPath(song.Id, "text")+"?lang=en", "")
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"song-library/models"
+	"song-library/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TagHandler struct {
+	Repo repository.TagRepository
+}
+
+func NewTagHandler(repo repository.TagRepository) *TagHandler {
+	return &TagHandler{Repo: repo}
+}
+
+// maxTagBatch — наибольшее количество песен и тегов в одном запросе на привязку тегов.
+const maxTagBatch = 1000
+
+// SongTagsRequest — песни и теги для привязки или удаления.
+// @Description Песни и теги для привязки или удаления
+type SongTagsRequest struct {
+	SongIds []int    `json:"songIds" example:"1,2"`                    //ID песен
+	Tags    []string `json:"tags" example:"genre:rock,mood:energetic"` //Теги вида namespace:name, без пространства имен — tag:name
+}
+
+// Получить список тегов
+// @Summary Получить теги
+// @Description Получить теги, которые есть хотя бы у одной песни не из корзины, с количеством песен,
+// @Description отсортированные по пространству имен и названию
+// @Tags tags
+// @Produce json
+// @Param namespace query string false "Фильтр по пространству имен: genre, mood, language или свое"
+// @Param page query int false "Номер страницы(пагинация)" default(1)
+// @Param limit query int false "Лимит записей на странице" default(50)
+// @Success 200 {array} models.Tag
+// @Failure 400 {string} string "Неверный формат параметров запроса"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /tags [get]
+func (h *TagHandler) GetTags(c *gin.Context) {
+	page, limit, ok := pagination(c, 50)
+	if !ok {
+		return
+	}
+
+	tags, err := h.Repo.ListTags(c.Request.Context(), repository.TagFilter{
+		Namespace: c.Query("namespace"),
+		Offset:    limit * (page - 1),
+		Limit:     limit,
+	})
+	if err != nil {
+		respondRepositoryError(c, err, "Теги не найдены")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"page":  page,
+		"limit": limit,
+		"tags":  tags,
+	})
+}
+
+// Получить теги песни
+// @Summary Получить теги песни
+// @Description Получить теги песни, отсортированные по пространству имен и названию
+// @Tags tags
+// @Produce json
+// @Param id path int true "ID песни"
+// @Success 200 {array} models.Tag
+// @Failure 400 {string} string "Некоректное значение id"
+// @Failure 404 {string} string "Песня не найдена"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /songs/{id}/tags [get]
+func (h *TagHandler) GetSongTags(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	tags, err := h.Repo.ListSongTags(c.Request.Context(), id)
+	if err != nil {
+		respondRepositoryError(c, err, "Песня не найдена")
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+// Добавить теги песням
+// @Summary Добавить теги песням
+// @Description Добавить каждой песне все теги. Новые теги создаются. Если какой-то песни нет или она в корзине,
+// @Description теги не добавляются ни одной песне. В ответе — количество новых привязок тегов
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param request body SongTagsRequest true "Песни и теги"
+// @Success 200 {object} map[string]int
+// @Failure 400 {string} string "Неверный формат данных"
+// @Failure 409 {string} string "Песня не найдена"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /songs/tags [post]
+func (h *TagHandler) TagSongs(c *gin.Context) {
+	songIDs, tags, ok := bindSongTags(c)
+	if !ok {
+		return
+	}
+
+	tagged, err := h.Repo.TagSongs(c.Request.Context(), songIDs, tags)
+	if errors.Is(err, repository.ErrConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Песня не найдена"})
+		return
+	}
+	if err != nil {
+		respondRepositoryError(c, err, "Песня не найдена")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tagged": tagged})
+}
+
+// Убрать теги у песен
+// @Summary Убрать теги у песен
+// @Description Убрать у песен теги. Отсутствующие песни и теги пропускаются. В ответе — количество удаленных привязок тегов
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param request body SongTagsRequest true "Песни и теги"
+// @Success 200 {object} map[string]int
+// @Failure 400 {string} string "Неверный формат данных"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /songs/tags [delete]
+func (h *TagHandler) UntagSongs(c *gin.Context) {
+	songIDs, tags, ok := bindSongTags(c)
+	if !ok {
+		return
+	}
+
+	untagged, err := h.Repo.UntagSongs(c.Request.Context(), songIDs, tags)
+	if err != nil {
+		respondRepositoryError(c, err, "Песня не найдена")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"untagged": untagged})
+}
+
+// bindSongTags разбирает и проверяет тело запроса с песнями и тегами. Повторы тегов убираются.
+func bindSongTags(c *gin.Context) ([]int, []models.Tag, bool) {
+	var request SongTagsRequest
+	if err := c.BindJSON(&request); err != nil {
+		respondBindError(c, err)
+		return nil, nil, false
+	}
+
+	if len(request.SongIds) == 0 || len(request.Tags) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Не заданы songIds или tags"})
+		return nil, nil, false
+	}
+	if len(request.SongIds) > maxTagBatch || len(request.Tags) > maxTagBatch {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Не больше %d песен и %d тегов в одном запросе", maxTagBatch, maxTagBatch)})
+		return nil, nil, false
+	}
+	for _, id := range request.SongIds {
+		if id <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "songIds должны быть положительными"})
+			return nil, nil, false
+		}
+	}
+
+	seen := make(map[models.Tag]bool, len(request.Tags))
+	tags := make([]models.Tag, 0, len(request.Tags))
+	for _, value := range request.Tags {
+		tag, err := models.ParseTag(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return nil, nil, false
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return request.SongIds, tags, true
+}
+
+// groupFacets группирует количество песен с тегами по пространствам имен, сохраняя порядок тегов.
+func groupFacets(tags []models.Tag) map[string][]models.TagFacet {
+	facets := make(map[string][]models.TagFacet)
+	for _, tag := range tags {
+		facets[tag.Namespace] = append(facets[tag.Namespace], models.TagFacet{
+			Tag:   tag.String(),
+			Name:  tag.Name,
+			Count: tag.Songs,
+		})
+	}
+	return facets
+}
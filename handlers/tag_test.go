@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"song-library/models"
+)
+
+func TestTags(t *testing.T) {
+	s := newTestServer(t)
+	hysteria := s.addSong("Muse", "Hysteria", "", "2003")
+	uprising := s.addSong("Muse", "Uprising", "", "2009")
+	s.addSong("Radiohead", "Creep", "", "1992")
+	both := `[` + strconv.Itoa(hysteria.Id) + `,` + strconv.Itoa(uprising.Id) + `]`
+
+	var result struct {
+		Tagged   int `json:"tagged"`
+		Untagged int `json:"untagged"`
+	}
+	s.expect(http.StatusOK, &result, http.MethodPost, "/songs/tags", `{"songIds":`+both+`,"tags":["Genre:Rock","genre:rock","language:EN"]}`)
+	if result.Tagged != 4 {
+		t.Errorf("tagged = %d, ожидалось 4", result.Tagged)
+	}
+	s.expect(http.StatusOK, &result, http.MethodPost, "/songs/tags", `{"songIds":[`+strconv.Itoa(hysteria.Id)+`],"tags":["genre:rock","mood:  Angry "]}`)
+	if result.Tagged != 1 {
+		t.Errorf("повторная привязка: tagged = %d, ожидалось 1", result.Tagged)
+	}
+
+	tests := []struct {
+		name, body string
+		want       int
+	}{
+		{name: "без тегов", body: `{"songIds":[1],"tags":[]}`, want: http.StatusBadRequest},
+		{name: "отрицательный ID", body: `{"songIds":[-1],"tags":["rock"]}`, want: http.StatusBadRequest},
+		{name: "некоректное пространство имен", body: `{"songIds":[1],"tags":["жанр:рок"]}`, want: http.StatusBadRequest},
+		{name: "некоректный язык", body: `{"songIds":[1],"tags":["language:english!"]}`, want: http.StatusBadRequest},
+		{name: "неизвестная песня", body: `{"songIds":[1,100],"tags":["rock"]}`, want: http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s.expect(tt.want, nil, http.MethodPost, "/songs/tags", tt.body)
+		})
+	}
+
+	var songTags []models.Tag
+	s.expect(http.StatusOK, &songTags, http.MethodGet, songPath(hysteria.Id, "tags"), "")
+	want := []models.Tag{{Namespace: "genre", Name: "rock"}, {Namespace: "language", Name: "en"}, {Namespace: "mood", Name: "angry"}}
+	if !reflect.DeepEqual(songTags, want) {
+		t.Errorf("теги песни = %+v, ожидалось %+v", songTags, want)
+	}
+	s.expect(http.StatusNotFound, nil, http.MethodGet, songPath(100, "tags"), "")
+
+	var songs struct {
+		Songs  []models.Song                `json:"songs"`
+		Facets map[string][]models.TagFacet `json:"facets"`
+	}
+	s.expect(http.StatusOK, &songs, http.MethodGet, "/songs?tag=genre:rock&tag=mood:angry&facets=true", "")
+	if len(songs.Songs) != 1 || songs.Songs[0].Id != hysteria.Id {
+		t.Errorf("песни со всеми тегами = %+v", songs.Songs)
+	}
+	if facets := songs.Facets["genre"]; len(facets) != 1 || facets[0].Tag != "genre:rock" || facets[0].Count != 1 {
+		t.Errorf("фасеты = %+v", songs.Facets)
+	}
+	s.expect(http.StatusOK, &songs, http.MethodGet, "/songs?tag=mood:angry&tag=language:en&tag_mode=or", "")
+	if len(songs.Songs) != 2 {
+		t.Errorf("песни хотя бы с одним тегом = %+v", songs.Songs)
+	}
+	s.expect(http.StatusBadRequest, nil, http.MethodGet, "/songs?tag=rock&tag_mode=xor", "")
+
+	s.expect(http.StatusOK, &result, http.MethodDelete, "/songs/tags", `{"songIds":`+both+`,"tags":["genre:rock"]}`)
+	if result.Untagged != 2 {
+		t.Errorf("untagged = %d, ожидалось 2", result.Untagged)
+	}
+
+	var tags struct {
+		Tags []models.Tag `json:"tags"`
+	}
+	s.expect(http.StatusOK, &tags, http.MethodGet, "/tags?namespace=language", "")
+	if len(tags.Tags) != 1 || tags.Tags[0].Name != "en" || tags.Tags[0].Songs != 2 {
+		t.Errorf("теги = %+v", tags.Tags)
+	}
+}
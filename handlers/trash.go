@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"song-library/models"
+	"song-library/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Вернуть песню из корзины
+// @Summary Вернуть песню из корзины
+// @Description Вернуть песню из корзины вместе с дополнительными данными, историей правок и версиями текста
+// @Tags trash
+// @Produce json
+// @Param id path int true "ID песни"
+// @Success 200 {object} models.Song
+// @Header 200 {string} ETag "Версия песни"
+// @Failure 400 {string} string "Некоректное значение id"
+// @Failure 404 {string} string "Песни нет в корзине"
+// @Failure 409 {string} string "Песня с такой группой и названием уже добавлена"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /songs/{id}/restore [post]
+func (h *SongHandler) RestoreSong(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	song, err := h.Repo.RestoreSong(c.Request.Context(), id)
+	if errors.Is(err, repository.ErrConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Песня с такой группой и названием уже добавлена"})
+		return
+	}
+	if err != nil {
+		respondRepositoryError(c, err, "Песни нет в корзине")
+		return
+	}
+
+	c.Header("ETag", models.VersionETag(song.Version))
+	c.JSON(http.StatusOK, song)
+}
+
+// Получить корзину
+// @Summary Получить корзину
+// @Description Получить песни из корзины, начиная с удаленных последними: когда и кем песня удалена.
+// @Description Песни удаляются окончательно, когда пролежат в корзине дольше срока хранения. Только для администратора
+// @Tags trash
+// @Produce json
+// @Param X-Admin-Token header string true "Токен администратора"
+// @Param page query int false "Номер страницы(пагинация)" default(1)
+// @Param limit query int false "Лимит записей на странице" default(10)
+// @Success 200 {array} models.Song
+// @Failure 400 {string} string "Неверный формат параметров запроса"
+// @Failure 403 {string} string "Недостаточно прав"
+// @Failure 500 {string} string "Внутренняя ошибка сервера"
+// @Router /trash [get]
+func (h *SongHandler) GetTrash(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+	page, limit, ok := pagination(c, 10)
+	if !ok {
+		return
+	}
+
+	songs, err := h.Repo.ListDeletedSongs(c.Request.Context(), limit*(page-1), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Не удалось получить корзину"})
+		log.Printf("Не удалось получить корзину, %v\n", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"page":  page,
+		"limit": limit,
+		"songs": songs,
+	})
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"song-library/models"
+)
+
+func TestTrash(t *testing.T) {
+	s := newTestServer(t)
+	song := s.addSong("Muse", "Hysteria", "It's bugging me", "2003")
+	s.addSong("Muse", "Uprising", "", "2009")
+
+	s.expect(http.StatusOK, nil, http.MethodDelete, songPath(song.Id), "", UserHeader, "alice")
+	s.expect(http.StatusNotFound, nil, http.MethodGet, songPath(song.Id), "")
+	s.expect(http.StatusNotFound, nil, http.MethodDelete, songPath(song.Id), "")
+
+	var page struct {
+		Songs []models.Song `json:"songs"`
+	}
+	s.expect(http.StatusOK, &page, http.MethodGet, "/songs", "")
+	if len(page.Songs) != 1 || page.Songs[0].Song != "Uprising" {
+		t.Errorf("список песен = %+v, ожидалась только Uprising", page.Songs)
+	}
+	s.expect(http.StatusForbidden, nil, http.MethodGet, "/songs?include_deleted=true", "")
+	s.expect(http.StatusOK, &page, http.MethodGet, "/songs?include_deleted=true", "", AdminHeader, testAdminToken)
+	if len(page.Songs) != 2 {
+		t.Errorf("список песен с корзиной = %+v", page.Songs)
+	}
+
+	s.expect(http.StatusForbidden, nil, http.MethodGet, "/trash", "")
+	s.expect(http.StatusForbidden, nil, http.MethodGet, "/trash", "", AdminHeader, "wrong")
+	s.expect(http.StatusOK, &page, http.MethodGet, "/trash", "", AdminHeader, testAdminToken)
+	if len(page.Songs) != 1 || page.Songs[0].DeletedBy != "alice" || page.Songs[0].DeletedAt == nil {
+		t.Errorf("корзина = %+v", page.Songs)
+	}
+
+	var restored models.Song
+	rec := s.expect(http.StatusOK, &restored, http.MethodPost, songPath(song.Id, "restore"), "")
+	if restored.DeletedAt != nil || restored.SongDetails.Text != "It's bugging me" {
+		t.Errorf("песня из корзины = %+v", restored)
+	}
+	if etag := rec.Header().Get("ETag"); etag != models.VersionETag(restored.Version) {
+		t.Errorf("ETag = %s, ожидалась версия %d", etag, restored.Version)
+	}
+	s.expect(http.StatusNotFound, nil, http.MethodPost, songPath(song.Id, "restore"), "")
+	s.expect(http.StatusOK, nil, http.MethodGet, songPath(song.Id), "")
+
+	//пока песня в корзине, такую же добавили заново
+	s.expect(http.StatusOK, nil, http.MethodDelete, songPath(song.Id), "")
+	again := s.addSong("Muse", "Hysteria", "", "2003")
+	rec = s.expect(http.StatusConflict, nil, http.MethodPost, songPath(song.Id, "restore"), "")
+	if msg := errorMessage(t, rec); msg != "Песня с такой группой и названием уже добавлена" {
+		t.Errorf("возврат повтора из корзины: %s", msg)
+	}
+	s.expect(http.StatusOK, nil, http.MethodDelete, songPath(again.Id), "")
+	s.expect(http.StatusOK, nil, http.MethodPost, songPath(song.Id, "restore"), "")
+	s.expect(http.StatusConflict, nil, http.MethodPost, songPath(again.Id, "restore"), "")
+}
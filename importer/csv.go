@@ -0,0 +1,64 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// csvSource читает CSV с заголовком в первой строке.
+type csvSource struct {
+	reader  *csv.Reader
+	counter *countingReader
+	index   map[string]int
+}
+
+func newCSVSource(file *os.File, size int64, opts Options) (*csvSource, error) {
+	counter := &countingReader{r: file, total: size}
+	reader := csv.NewReader(counter)
+	reader.Comma = opts.Delimiter
+	if reader.Comma == 0 {
+		reader.Comma = ','
+	}
+	reader.FieldsPerRecord = -1 //в строках может не хватать пустых ячеек в конце
+	reader.LazyQuotes = true
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, &FormatError{Msg: "файл пустой, ожидается заголовок с названиями колонок"}
+	}
+	if err != nil {
+		return nil, &FormatError{Msg: fmt.Sprintf("не удалось прочитать заголовок CSV: %v", err)}
+	}
+	index, err := headerIndex(header, opts.Columns)
+	if err != nil {
+		return nil, err
+	}
+	return &csvSource{reader: reader, counter: counter, index: index}, nil
+}
+
+func (s *csvSource) next() (record, error) {
+	for {
+		cells, err := s.reader.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return record{}, io.EOF
+			}
+			return record{}, &FormatError{Msg: fmt.Sprintf("не удалось разобрать CSV: %v", err)}
+		}
+		if emptyRow(cells) {
+			continue
+		}
+		line, _ := s.reader.FieldPos(0)
+		return tableRecord(line, cells, s.index), nil
+	}
+}
+
+func (s *csvSource) progress() float64 {
+	return s.counter.progress()
+}
+
+func (s *csvSource) close() {}
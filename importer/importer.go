@@ -0,0 +1,406 @@
+// Package importer в фоне импортирует песни из файлов CSV, JSON Lines и XLSX. Файл читается потоком,
+// каждая строка проверяется отдельно, ошибки строк собираются в отчет задания.
+package importer
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"song-library/models"
+	"song-library/repository"
+)
+
+// Форматы импортируемых файлов.
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+	FormatXLSX  = "xlsx"
+)
+
+// Store — хранилище, в которое импортируются песни, см. repository.SongRepository.
+type Store interface {
+	FindSong(ctx context.Context, group, song string) (*models.Song, error)
+	CreateSong(ctx context.Context, song *models.Song) error
+	UpdateSong(ctx context.Context, id int, update models.SongUpdate) (*models.Song, error)
+}
+
+// Options описывает параметры импорта файла.
+type Options struct {
+	Format      string
+	DryRun      bool
+	OnDuplicate models.DuplicatePolicy
+	Columns     map[string]string //название колонки (ключа для JSON Lines) по полю песни, см. Fields
+	Delimiter   rune              //разделитель CSV, по умолчанию запятая
+}
+
+// Config описывает параметры импорта.
+type Config struct {
+	Retention time.Duration //сколько хранить завершенные задания и их отчеты
+	MaxErrors int           //сколько ошибок строк хранить в отчете задания
+}
+
+// ErrJobLost возвращается для задания, созданного до перезапуска сервера: его состояние и отчет не сохранились.
+var ErrJobLost = errors.New("задание импорта создано до перезапуска сервера")
+
+// Importer выполняет задания импорта. Задания и отчеты хранятся в памяти процесса
+// и пропадают при перезапуске сервера. ID задания начинается с ID запуска сервера,
+// поэтому задание прошлого запуска отличается от несуществующего, см. ErrJobLost.
+type Importer struct {
+	store  Store
+	notify func() //будит очередь получения данных песен
+	cfg    Config
+	ctx    context.Context
+	wg     sync.WaitGroup
+	boot   string //ID запуска сервера, префикс ID заданий
+
+	mu   sync.Mutex
+	jobs map[string]*job
+}
+
+type job struct {
+	models.ImportJob
+	errors []models.ImportRowError
+}
+
+// NewImporter создает импорт, подставляя значения по умолчанию для незаполненных полей Config.
+// notify вызывается после добавления песен, данные которых нужно получить в фоне.
+func NewImporter(store Store, notify func(), cfg Config) *Importer {
+	if cfg.Retention <= 0 {
+		cfg.Retention = 24 * time.Hour
+	}
+	if cfg.MaxErrors <= 0 {
+		cfg.MaxErrors = 10000
+	}
+	boot := strconv.FormatInt(time.Now().UnixNano(), 36) //у каждого запуска сервера свое время
+	return &Importer{store: store, notify: notify, cfg: cfg, ctx: context.Background(), boot: boot, jobs: map[string]*job{}}
+}
+
+// Start задает контекст, в котором выполняются задания: после его отмены задания прерываются.
+func (im *Importer) Start(ctx context.Context) {
+	im.ctx = ctx
+}
+
+// Wait дожидается завершения заданий после отмены контекста Start.
+func (im *Importer) Wait() {
+	im.wg.Wait()
+}
+
+// Submit проверяет параметры и заголовок файла и запускает задание импорта от имени actor.
+// Задание забирает файл: закрывает и удаляет его по завершении, в том числе при ошибке Submit.
+// Если файл нельзя импортировать, возвращает *FormatError.
+func (im *Importer) Submit(actor string, file *os.File, size int64, opts Options) (*models.ImportJob, error) {
+	if opts.OnDuplicate == "" {
+		opts.OnDuplicate = models.DuplicateSkip
+	}
+	switch opts.OnDuplicate {
+	case models.DuplicateSkip, models.DuplicateUpdate, models.DuplicateFail:
+	default:
+		removeFile(file)
+		return nil, &FormatError{Msg: "неизвестное значение on_duplicate, допустимы skip, update и fail"}
+	}
+	src, err := openSource(file, size, opts.Format, opts)
+	if err != nil {
+		removeFile(file)
+		return nil, err
+	}
+
+	id, err := newJobID()
+	if err != nil {
+		src.close()
+		removeFile(file)
+		return nil, err
+	}
+	id = im.boot + "-" + id
+	j := &job{ImportJob: models.ImportJob{
+		Id:          id,
+		Status:      models.JobQueued,
+		Format:      opts.Format,
+		DryRun:      opts.DryRun,
+		OnDuplicate: opts.OnDuplicate,
+		CreatedBy:   actor,
+		CreatedAt:   time.Now(),
+	}}
+
+	im.mu.Lock()
+	im.prune()
+	im.jobs[id] = j
+	snapshot := j.ImportJob
+	im.mu.Unlock()
+
+	im.wg.Add(1)
+	go func() {
+		defer im.wg.Done()
+		defer removeFile(file)
+		defer src.close()
+		im.run(repository.WithActor(im.ctx, actor), j, src, opts)
+	}()
+	return &snapshot, nil
+}
+
+// Job возвращает состояние задания. Если задание создано до перезапуска сервера, возвращает ErrJobLost,
+// если задания нет или оно удалено по сроку хранения — ErrNotFound.
+func (im *Importer) Job(id string) (*models.ImportJob, error) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+	j, err := im.lookup(id)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := j.ImportJob
+	return &snapshot, nil
+}
+
+// Errors возвращает ошибки строк задания в порядке строк файла. Ошибки те же, что у Job.
+func (im *Importer) Errors(id string) ([]models.ImportRowError, error) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+	j, err := im.lookup(id)
+	if err != nil {
+		return nil, err
+	}
+	return append([]models.ImportRowError{}, j.errors...), nil
+}
+
+// lookup возвращает задание по ID, вызывается под mu.
+func (im *Importer) lookup(id string) (*job, error) {
+	if j, ok := im.jobs[id]; ok {
+		return j, nil
+	}
+	if boot, _, ok := strings.Cut(id, "-"); ok && boot != im.boot {
+		return nil, ErrJobLost
+	}
+	return nil, repository.ErrNotFound
+}
+
+// prune удаляет завершенные задания старше срока хранения, вызывается под mu.
+func (im *Importer) prune() {
+	for id, j := range im.jobs {
+		if j.FinishedAt != nil && time.Since(*j.FinishedAt) > im.cfg.Retention {
+			delete(im.jobs, id)
+		}
+	}
+}
+
+func (im *Importer) run(ctx context.Context, j *job, src source, opts Options) {
+	im.update(j, func(j *job) { j.Status = models.JobRunning })
+	seen := map[string]*models.Song{} //песни из файла для проверки дубликатов без записи
+
+	var failure error
+	for {
+		if ctx.Err() != nil {
+			failure = errors.New("импорт прерван остановкой сервера")
+			break
+		}
+		rec, err := src.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var rowErr *rowError
+		if errors.As(err, &rowErr) {
+			im.update(j, func(j *job) {
+				j.Rows++
+				j.Progress = src.progress()
+				j.Failed++
+				im.addErrors(j, models.ImportRowError{Row: rowErr.line, Field: rowErr.field, Message: rowErr.msg})
+			})
+			continue
+		}
+		if err != nil {
+			failure = err
+			break
+		}
+
+		outcome, rowErrors := im.importRow(ctx, rec, opts, seen)
+		im.update(j, func(j *job) {
+			j.Rows++
+			j.Progress = src.progress()
+			switch outcome {
+			case created:
+				j.Created++
+			case updated:
+				j.Updated++
+			case skipped:
+				j.Skipped++
+			default:
+				j.Failed++
+				im.addErrors(j, rowErrors...)
+			}
+		})
+	}
+
+	im.update(j, func(j *job) {
+		now := time.Now()
+		j.FinishedAt = &now
+		if failure != nil {
+			j.Status = models.JobFailed
+			j.Error = failure.Error()
+			return
+		}
+		j.Status = models.JobSucceeded
+		j.Progress = 1
+	})
+	if failure != nil {
+		log.Printf("Импорт %s прерван, %v\n", j.Id, failure)
+	}
+}
+
+// outcome — результат импорта строки.
+type outcome int
+
+const (
+	failed outcome = iota
+	created
+	updated
+	skipped
+)
+
+// importRow проверяет строку и добавляет или изменяет песню, если это не проверка без записи.
+func (im *Importer) importRow(ctx context.Context, rec record, opts Options, seen map[string]*models.Song) (outcome, []models.ImportRowError) {
+	song, rowErrors := validate(rec)
+	if len(rowErrors) > 0 {
+		return failed, rowErrors
+	}
+	rowFailure := func(msg string) []models.ImportRowError {
+		return []models.ImportRowError{{Row: rec.line, Group: song.Group, Song: song.Song, Message: msg}}
+	}
+
+	key := strings.ToLower(song.Group) + "\x00" + song.Song //название песни сравнивается точно, как в FindSong
+	existing, err := im.store.FindSong(ctx, song.Group, song.Song)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return failed, rowFailure("не удалось проверить песню в библиотеке")
+	}
+	if existing == nil && opts.DryRun { //в проверке без записи песни из файла не появляются в библиотеке
+		existing = seen[key]
+	}
+	if opts.DryRun {
+		seen[key] = &song
+	}
+
+	if existing == nil {
+		details := song.SongDetails
+		if details.Text == "" && details.Link == "" && details.ReleaseDate.IsZero() {
+			song.EnrichmentStatus = models.EnrichmentPending //доп данные песни будут получены в фоне
+		} else {
+			song.EnrichmentStatus = models.EnrichmentSucceeded
+		}
+		if opts.DryRun {
+			return created, nil
+		}
+		if err := im.store.CreateSong(ctx, &song); err != nil {
+			log.Printf("Не удалось импортировать песню из строки %d, %v\n", rec.line, err)
+			return failed, rowFailure("не удалось добавить песню")
+		}
+		if song.EnrichmentStatus == models.EnrichmentPending {
+			im.notify()
+		}
+		return created, nil
+	}
+
+	switch opts.OnDuplicate {
+	case models.DuplicateFail:
+		return failed, rowFailure("песня уже добавлена")
+	case models.DuplicateUpdate:
+		update := changes(existing, song, rec)
+		if update == (models.SongUpdate{}) {
+			return skipped, nil
+		}
+		if opts.DryRun || existing.Id == 0 {
+			return updated, nil
+		}
+		if _, err := im.store.UpdateSong(ctx, existing.Id, update); err != nil {
+			log.Printf("Не удалось изменить песню %d из строки %d, %v\n", existing.Id, rec.line, err)
+			return failed, rowFailure("не удалось изменить песню")
+		}
+		return updated, nil
+	default:
+		return skipped, nil
+	}
+}
+
+// validate проверяет значения строки и собирает из них песню. Ошибки возвращаются по полям.
+func validate(rec record) (models.Song, []models.ImportRowError) {
+	var song models.Song
+	var rowErrors []models.ImportRowError
+	song.Group = strings.TrimSpace(rec.values["group"])
+	song.Song = strings.TrimSpace(rec.values["song"])
+	fieldError := func(field, msg string) {
+		rowErrors = append(rowErrors, models.ImportRowError{Row: rec.line, Group: song.Group, Song: song.Song, Field: field, Message: msg})
+	}
+
+	if song.Group == "" {
+		fieldError("group", "группа не может быть пустой")
+	}
+	if song.Song == "" {
+		fieldError("song", "название песни не может быть пустым")
+	}
+	song.SongDetails.Text = rec.values["text"]
+	song.SongDetails.Link = strings.TrimSpace(rec.values["link"])
+	if err := models.ValidateLink(song.SongDetails.Link); err != nil {
+		fieldError("link", err.Error())
+	}
+	if value := strings.TrimSpace(rec.values["releaseDate"]); value != "" {
+		releaseDate, err := models.ParseReleaseDate(value)
+		if err != nil {
+			fieldError("releaseDate", err.Error())
+		}
+		song.SongDetails.ReleaseDate = releaseDate
+	}
+	return song, rowErrors
+}
+
+// changes возвращает изменения дополнительных данных песни из строки. Меняются только поля,
+// колонки которых есть в файле и значения которых отличаются от текущих.
+func changes(existing *models.Song, song models.Song, rec record) models.SongUpdate {
+	var update models.SongUpdate
+	details := song.SongDetails
+	if _, ok := rec.values["text"]; ok && details.Text != existing.SongDetails.Text {
+		update.Text = &details.Text
+	}
+	if _, ok := rec.values["link"]; ok && details.Link != existing.SongDetails.Link {
+		update.Link = &details.Link
+	}
+	if _, ok := rec.values["releaseDate"]; ok && details.ReleaseDate.String() != existing.SongDetails.ReleaseDate.String() {
+		update.ReleaseDate = &details.ReleaseDate
+	}
+	return update
+}
+
+// update изменяет задание под mu.
+func (im *Importer) update(j *job, change func(j *job)) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+	change(j)
+}
+
+// addErrors добавляет ошибки строки в отчет, пока не заполнен MaxErrors, вызывается под mu.
+func (im *Importer) addErrors(j *job, rowErrors ...models.ImportRowError) {
+	for _, rowErr := range rowErrors {
+		if len(j.errors) >= im.cfg.MaxErrors {
+			j.ErrorsTruncated = true
+			return
+		}
+		j.errors = append(j.errors, rowErr)
+	}
+}
+
+func newJobID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func removeFile(file *os.File) {
+	file.Close()
+	os.Remove(file.Name())
+}
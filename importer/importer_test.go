@@ -0,0 +1,181 @@
+package importer
+
+import (
+	"context"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"song-library/models"
+	"song-library/repository"
+)
+
+// waitJob дожидается завершения задания импорта.
+func waitJob(t *testing.T, im *Importer, id string) *models.ImportJob {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		job, err := im.Job(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job.FinishedAt != nil {
+			return job
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("задание %s не завершилось", id)
+	return nil
+}
+
+func TestImporter(t *testing.T) {
+	data := []byte("group,song,text,releaseDate\n" +
+		"Muse,Hysteria,new text,2003\n" +
+		"Muse,Uprising,,\n" +
+		",Nameless,,\n" +
+		"Muse,Bad date,,99.99.2003\n" +
+		"muse,Uprising,,\n")
+
+	tests := []struct {
+		name         string
+		opts         Options
+		want         models.ImportJob
+		wantSongs    int
+		wantNotify   int32
+		wantHysteria string
+	}{
+		{
+			name:         "обновление дубликатов",
+			opts:         Options{Format: FormatCSV, OnDuplicate: models.DuplicateUpdate},
+			want:         models.ImportJob{Rows: 5, Created: 1, Updated: 1, Skipped: 1, Failed: 2},
+			wantSongs:    2,
+			wantNotify:   1,
+			wantHysteria: "new text",
+		},
+		{
+			name:         "пропуск дубликатов",
+			opts:         Options{Format: FormatCSV},
+			want:         models.ImportJob{Rows: 5, Created: 1, Skipped: 2, Failed: 2},
+			wantSongs:    2,
+			wantNotify:   1,
+			wantHysteria: "old text",
+		},
+		{
+			name:         "дубликаты — ошибка",
+			opts:         Options{Format: FormatCSV, OnDuplicate: models.DuplicateFail},
+			want:         models.ImportJob{Rows: 5, Created: 1, Failed: 4},
+			wantSongs:    2,
+			wantNotify:   1,
+			wantHysteria: "old text",
+		},
+		{
+			name:         "проверка без записи",
+			opts:         Options{Format: FormatCSV, DryRun: true, OnDuplicate: models.DuplicateUpdate},
+			want:         models.ImportJob{Rows: 5, Created: 1, Updated: 1, Skipped: 1, Failed: 2},
+			wantSongs:    1,
+			wantHysteria: "old text",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := repository.NewMemory()
+			existing := models.Song{Group: "Muse", Song: "Hysteria", SongDetails: models.SongDetails{Text: "old text"}}
+			if err := repo.CreateSong(ctx, &existing); err != nil {
+				t.Fatal(err)
+			}
+
+			var notified atomic.Int32
+			im := NewImporter(repo, func() { notified.Add(1) }, Config{})
+			file, size := tempFile(t, data)
+			job, err := im.Submit("tester", file, size, tt.opts)
+			if err != nil {
+				t.Fatalf("Submit() ошибка = %v", err)
+			}
+			job = waitJob(t, im, job.Id)
+
+			if job.Status != models.JobSucceeded || job.Progress != 1 || job.CreatedBy != "tester" {
+				t.Errorf("задание = %+v, ожидалось успешное завершение", job)
+			}
+			got := models.ImportJob{Rows: job.Rows, Created: job.Created, Updated: job.Updated, Skipped: job.Skipped, Failed: job.Failed}
+			if got != tt.want {
+				t.Errorf("счетчики = %+v, ожидалось %+v", got, tt.want)
+			}
+			if n := notified.Load(); n != tt.wantNotify {
+				t.Errorf("очередь получения данных разбужена %d раз, ожидалось %d", n, tt.wantNotify)
+			}
+
+			count, err := repo.CountSongs(ctx, repository.SongFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if count != tt.wantSongs {
+				t.Errorf("песен в библиотеке = %d, ожидалось %d", count, tt.wantSongs)
+			}
+			hysteria, err := repo.FindSong(ctx, "Muse", "Hysteria")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hysteria.SongDetails.Text != tt.wantHysteria {
+				t.Errorf("текст Hysteria = %q, ожидалось %q", hysteria.SongDetails.Text, tt.wantHysteria)
+			}
+
+			rowErrors, err := im.Errors(job.Id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			byRow := map[int]models.ImportRowError{}
+			for _, rowErr := range rowErrors {
+				byRow[rowErr.Row] = rowErr
+			}
+			if len(rowErrors) != tt.want.Failed || byRow[4] != (models.ImportRowError{Row: 4, Song: "Nameless", Field: "group", Message: "группа не может быть пустой"}) ||
+				byRow[5].Field != "releaseDate" {
+				t.Errorf("ошибки строк = %+v", rowErrors)
+			}
+		})
+	}
+}
+
+func TestImporterSubmitErrors(t *testing.T) {
+	im := NewImporter(repository.NewMemory(), func() {}, Config{})
+
+	file, size := tempFile(t, []byte("group,song\n"))
+	if _, err := im.Submit("tester", file, size, Options{Format: FormatCSV, OnDuplicate: "merge"}); err == nil {
+		t.Error("Submit() с неизвестным on_duplicate не вернул ошибку")
+	}
+	file, size = tempFile(t, []byte("title\n"))
+	if _, err := im.Submit("tester", file, size, Options{Format: FormatCSV}); err == nil {
+		t.Error("Submit() без колонок group и song не вернул ошибку")
+	}
+	if _, err := im.Job("missing"); err != repository.ErrNotFound {
+		t.Errorf("Job() неизвестного задания ошибка = %v, ожидалась %v", err, repository.ErrNotFound)
+	}
+	if _, err := im.Errors(im.boot + "-missing"); err != repository.ErrNotFound {
+		t.Errorf("Errors() неизвестного задания ошибка = %v, ожидалась %v", err, repository.ErrNotFound)
+	}
+	for _, id := range []string{"0-1", "lz0q3x2k-abcdef"} {
+		if _, err := im.Job(id); err != ErrJobLost {
+			t.Errorf("Job(%s) задания прошлого запуска ошибка = %v, ожидалась %v", id, err, ErrJobLost)
+		}
+		if _, err := im.Errors(id); err != ErrJobLost {
+			t.Errorf("Errors(%s) задания прошлого запуска ошибка = %v, ожидалась %v", id, err, ErrJobLost)
+		}
+	}
+}
+
+func TestImporterMaxErrors(t *testing.T) {
+	im := NewImporter(repository.NewMemory(), func() {}, Config{MaxErrors: 1})
+	file, size := tempFile(t, []byte("group,song\n,a\n,b\n"))
+	job, err := im.Submit("tester", file, size, Options{Format: FormatCSV})
+	if err != nil {
+		t.Fatal(err)
+	}
+	job = waitJob(t, im, job.Id)
+
+	rowErrors, _ := im.Errors(job.Id)
+	if job.Failed != 2 || !job.ErrorsTruncated || !reflect.DeepEqual(rowErrors, []models.ImportRowError{{Row: 2, Song: "a", Field: "group", Message: "группа не может быть пустой"}}) {
+		t.Errorf("задание = %+v, ошибки строк = %+v", job, rowErrors)
+	}
+}
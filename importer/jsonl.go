@@ -0,0 +1,95 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strings"
+)
+
+// jsonlSource читает JSON Lines: по объекту песни в строке. Поля дополнительных данных
+// можно передать как в API, во вложенном объекте SongDetail или SongDetails.
+type jsonlSource struct {
+	reader  *bufio.Reader
+	counter *countingReader
+	keys    map[string]string //поле песни по ключу объекта в нижнем регистре
+	line    int
+}
+
+func newJSONLSource(file *os.File, size int64, opts Options) (*jsonlSource, error) {
+	names, err := columnNames(opts.Columns)
+	if err != nil {
+		return nil, err
+	}
+	keys := make(map[string]string, len(names))
+	for field, name := range names {
+		keys[strings.ToLower(name)] = field
+	}
+	counter := &countingReader{r: file, total: size}
+	return &jsonlSource{reader: bufio.NewReaderSize(counter, 64<<10), counter: counter, keys: keys}, nil
+}
+
+func (s *jsonlSource) next() (record, error) {
+	for {
+		data, err := s.reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return record{}, err
+		}
+		if len(data) == 0 && err != nil {
+			return record{}, io.EOF
+		}
+		s.line++
+		data = bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\ufeff")))
+		if len(data) == 0 {
+			continue
+		}
+		return s.parse(data)
+	}
+}
+
+func (s *jsonlSource) parse(data []byte) (record, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var object map[string]interface{}
+	if err := decoder.Decode(&object); err != nil || object == nil || decoder.More() {
+		return record{}, &rowError{line: s.line, msg: "ожидается JSON объект"}
+	}
+	for _, key := range []string{"SongDetail", "SongDetails"} {
+		if details, ok := object[key].(map[string]interface{}); ok {
+			delete(object, key)
+			for name, value := range details {
+				if _, present := object[name]; !present {
+					object[name] = value
+				}
+			}
+		}
+	}
+
+	values := map[string]string{}
+	for key, value := range object {
+		field, ok := s.keys[strings.ToLower(key)]
+		if !ok {
+			continue //остальные ключи, например ID из выгрузки, не импортируются
+		}
+		switch v := value.(type) {
+		case nil:
+			values[field] = ""
+		case string:
+			values[field] = v
+		case json.Number: //год выхода числом
+			values[field] = v.String()
+		default:
+			return record{}, &rowError{line: s.line, field: field, msg: "ожидается строка"}
+		}
+	}
+	return record{line: s.line, values: values}, nil
+}
+
+func (s *jsonlSource) progress() float64 {
+	return s.counter.progress()
+}
+
+func (s *jsonlSource) close() {}
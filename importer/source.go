@@ -0,0 +1,158 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// Fields — поля песни, которые можно импортировать, в порядке колонок файла по умолчанию.
+var Fields = []string{"group", "song", "text", "link", "releaseDate"}
+
+// record — строка файла: значения полей песни по названиям из Fields.
+// Поля, для которых в файле нет колонки или ключа, отсутствуют в values.
+type record struct {
+	line   int
+	values map[string]string
+}
+
+// source последовательно читает строки файла.
+type source interface {
+	// next возвращает следующую строку, io.EOF в конце файла. Ошибка *rowError относится
+	// только к этой строке, после неё чтение можно продолжить. Остальные ошибки прерывают импорт.
+	next() (record, error)
+	// progress возвращает долю прочитанного файла от 0 до 1.
+	progress() float64
+	// close освобождает ресурсы, сам файл закрывает вызывающий.
+	close()
+}
+
+// rowError — строку файла не удалось разобрать.
+type rowError struct {
+	line  int
+	field string //поле с ошибкой, пустое для ошибки всей строки
+	msg   string
+}
+
+func (e *rowError) Error() string {
+	if e.field != "" {
+		return fmt.Sprintf("строка %d, поле %s: %s", e.line, e.field, e.msg)
+	}
+	return fmt.Sprintf("строка %d: %s", e.line, e.msg)
+}
+
+// FormatError возвращается, если файл нельзя импортировать: неверные параметры, нет нужных колонок, файл поврежден.
+type FormatError struct {
+	Msg string
+}
+
+func (e *FormatError) Error() string {
+	return e.Msg
+}
+
+// columnNames возвращает названия колонок или ключей файла для полей песни:
+// по умолчанию название совпадает с полем, columns заменяет его для отдельных полей.
+func columnNames(columns map[string]string) (map[string]string, error) {
+	names := make(map[string]string, len(Fields))
+	for _, field := range Fields {
+		names[field] = field
+	}
+	for field, name := range columns {
+		if _, ok := names[field]; !ok {
+			return nil, &FormatError{Msg: fmt.Sprintf("неизвестное поле %q в соответствии колонок, допустимы %s", field, strings.Join(Fields, ", "))}
+		}
+		if strings.TrimSpace(name) == "" {
+			return nil, &FormatError{Msg: fmt.Sprintf("пустое название колонки для поля %q", field)}
+		}
+		names[field] = name
+	}
+	return names, nil
+}
+
+// headerIndex находит в заголовке таблицы колонки полей песни без учета регистра и пробелов по краям.
+// Колонки group и song обязательны, колонки остальных полей могут отсутствовать.
+func headerIndex(header []string, columns map[string]string) (map[string]int, error) {
+	names, err := columnNames(columns)
+	if err != nil {
+		return nil, err
+	}
+	positions := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, ok := positions[name]; !ok {
+			positions[name] = i
+		}
+	}
+
+	index := map[string]int{}
+	for _, field := range Fields {
+		i, ok := positions[strings.ToLower(strings.TrimSpace(names[field]))]
+		if !ok {
+			if field == "group" || field == "song" {
+				return nil, &FormatError{Msg: fmt.Sprintf("в заголовке нет колонки %q для поля %s", names[field], field)}
+			}
+			continue
+		}
+		index[field] = i
+	}
+	return index, nil
+}
+
+// tableRecord собирает строку таблицы по позициям колонок из headerIndex. Ячейки за концом строки пустые.
+func tableRecord(line int, cells []string, index map[string]int) record {
+	values := make(map[string]string, len(index))
+	for field, i := range index {
+		if i < len(cells) {
+			values[field] = cells[i]
+		} else {
+			values[field] = ""
+		}
+	}
+	return record{line: line, values: values}
+}
+
+// emptyRow сообщает, что все ячейки строки таблицы пустые.
+func emptyRow(cells []string) bool {
+	for _, cell := range cells {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// countingReader считает прочитанные байты для оценки прогресса.
+type countingReader struct {
+	r     io.Reader
+	read  atomic.Int64
+	total int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.read.Add(int64(n))
+	return n, err
+}
+
+func (r *countingReader) progress() float64 {
+	if r.total <= 0 {
+		return 0
+	}
+	return min(float64(r.read.Load())/float64(r.total), 1)
+}
+
+// openSource открывает файл в формате format для чтения строк.
+func openSource(file *os.File, size int64, format string, opts Options) (source, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVSource(file, size, opts)
+	case FormatJSONL:
+		return newJSONLSource(file, size, opts)
+	case FormatXLSX:
+		return newXLSXSource(file, size, opts)
+	default:
+		return nil, &FormatError{Msg: fmt.Sprintf("неподдерживаемый формат %q, допустимы csv, jsonl и xlsx", format)}
+	}
+}
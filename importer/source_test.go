@@ -0,0 +1,234 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// tempFile записывает data во временный файл, как обработчик загрузки.
+func tempFile(t *testing.T, data []byte) (*os.File, int64) {
+	t.Helper()
+	file, err := os.Create(filepath.Join(t.TempDir(), "import"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { file.Close() })
+	return file, int64(len(data))
+}
+
+// readSource читает все строки файла: записи и ошибки строк по порядку.
+func readSource(t *testing.T, data []byte, opts Options) ([]record, error) {
+	t.Helper()
+	file, size := tempFile(t, data)
+	src, err := openSource(file, size, opts.Format, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer src.close()
+
+	var records []record
+	for {
+		rec, err := src.next()
+		if errors.Is(err, io.EOF) {
+			if p := src.progress(); p != 1 {
+				t.Errorf("progress() в конце файла = %v, ожидалось 1", p)
+			}
+			return records, nil
+		}
+		var rowErr *rowError
+		if errors.As(err, &rowErr) {
+			records = append(records, record{line: rowErr.line, values: map[string]string{"error": rowErr.field}})
+			continue
+		}
+		if err != nil {
+			return records, err
+		}
+		records = append(records, rec)
+	}
+}
+
+func TestCSVSource(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		opts Options
+		want []record
+	}{
+		{
+			name: "заголовок с BOM и в другом регистре",
+			data: "\ufeffSong, GROUP ,releaseDate,extra\nHysteria,Muse,2003,x\n\n,,,\n\"Time Is\nRunning Out\",Muse\n",
+			want: []record{
+				{line: 2, values: map[string]string{"group": "Muse", "song": "Hysteria", "releaseDate": "2003"}},
+				{line: 5, values: map[string]string{"group": "Muse", "song": "Time Is\nRunning Out", "releaseDate": ""}},
+			},
+		},
+		{
+			name: "свои названия колонок и разделитель",
+			data: "Исполнитель;Название;Текст\nМумий Тролль;Утекай;\"Утекай; в подворотне\"\n",
+			opts: Options{Delimiter: ';', Columns: map[string]string{"group": "исполнитель", "song": "Название", "text": "Текст"}},
+			want: []record{
+				{line: 2, values: map[string]string{"group": "Мумий Тролль", "song": "Утекай", "text": "Утекай; в подворотне"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Format = FormatCSV
+			got, err := readSource(t, []byte(tt.data), tt.opts)
+			if err != nil {
+				t.Fatalf("ошибка = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("строки = %+v, ожидалось %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSourceFormatErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		opts Options
+	}{
+		{name: "неизвестный формат", data: []byte("group,song\n"), opts: Options{Format: "txt"}},
+		{name: "пустой CSV", data: nil, opts: Options{Format: FormatCSV}},
+		{name: "нет колонки song", data: []byte("group,title\n"), opts: Options{Format: FormatCSV}},
+		{name: "неизвестное поле колонки", data: []byte("group,song\n"), opts: Options{Format: FormatCSV, Columns: map[string]string{"album": "Album"}}},
+		{name: "пустое название колонки", data: []byte(`{}`), opts: Options{Format: FormatJSONL, Columns: map[string]string{"song": " "}}},
+		{name: "не XLSX", data: []byte("group,song\n"), opts: Options{Format: FormatXLSX}},
+		{name: "пустой лист", data: xlsxBook(t, nil, `<sheetData/>`), opts: Options{Format: FormatXLSX}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readSource(t, tt.data, tt.opts)
+			var formatErr *FormatError
+			if !errors.As(err, &formatErr) {
+				t.Errorf("ошибка = %v, ожидалась *FormatError", err)
+			}
+		})
+	}
+}
+
+func TestJSONLSource(t *testing.T) {
+	data := "\ufeff{\"group\":\"Muse\",\"song\":\"Hysteria\",\"id\":7,\"SongDetail\":{\"text\":\"It's bugging me\",\"releaseDate\":2003}}\n" +
+		"\n" +
+		"not json\n" +
+		"{\"Group\":\"Muse\",\"song\":\"Uprising\",\"link\":null,\"SongDetails\":{\"song\":\"ignored\"}}\n" +
+		"{\"group\":\"Muse\",\"song\":[\"Starlight\"]}\n" +
+		"[1,2]\r\n" +
+		"{\"group\":\"Muse\",\"song\":\"Starlight\"}"
+
+	want := []record{
+		{line: 1, values: map[string]string{"group": "Muse", "song": "Hysteria", "text": "It's bugging me", "releaseDate": "2003"}},
+		{line: 3, values: map[string]string{"error": ""}},
+		{line: 4, values: map[string]string{"group": "Muse", "song": "Uprising", "link": ""}},
+		{line: 5, values: map[string]string{"error": "song"}},
+		{line: 6, values: map[string]string{"error": ""}},
+		{line: 7, values: map[string]string{"group": "Muse", "song": "Starlight"}},
+	}
+	got, err := readSource(t, []byte(data), Options{Format: FormatJSONL})
+	if err != nil {
+		t.Fatalf("ошибка = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("строки = %+v, ожидалось %+v", got, want)
+	}
+
+	got, err = readSource(t, []byte(`{"artist":"Muse","group":"x","song":"Hysteria"}`), Options{Format: FormatJSONL, Columns: map[string]string{"group": "Artist"}})
+	if err != nil {
+		t.Fatalf("ошибка = %v", err)
+	}
+	if want := []record{{line: 1, values: map[string]string{"group": "Muse", "song": "Hysteria"}}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("строки со своими ключами = %+v, ожидалось %+v", got, want)
+	}
+}
+
+// xlsxBook собирает минимальную книгу XLSX с общими строками shared и данными листа sheetData.
+func xlsxBook(t *testing.T, shared []string, sheetData string, workbookPr ...string) []byte {
+	t.Helper()
+	var sharedXML bytes.Buffer
+	sharedXML.WriteString(`<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	for _, s := range shared {
+		sharedXML.WriteString("<si><t>" + s + "</t></si>")
+	}
+	sharedXML.WriteString(`</sst>`)
+
+	parts := map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			append(workbookPr, "")[0] + `<sheets><sheet name="Songs" sheetId="1" r:id="rId3"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/songs.xml"/></Relationships>`,
+		"xl/sharedStrings.xml":    sharedXML.String(),
+		"xl/worksheets/songs.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` + sheetData + `</worksheet>`,
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for name, content := range parts {
+		w, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.WriteString(w, content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestXLSXSource(t *testing.T) {
+	shared := []string{"Group", "Song", "releaseDate", "Muse", "Hysteria"}
+	sheet := `<sheetData>` +
+		`<row r="2"><c r="B2" t="s"><v>0</v></c><c r="C2" t="s"><v>1</v></c><c r="D2" t="s"><v>2</v></c></row>` +
+		`<row r="3"><c r="B3" t="s"><v>3</v></c><c r="C3" t="s"><v>4</v></c><c r="D3"><v>38741</v></c></row>` +
+		`<row r="4"><c r="B4" t="inlineStr"><is><t>Muse</t></is></c><c r="C4" t="inlineStr"><is><r><t>Time Is </t></r><r><t>Running Out</t></r></is></c><c r="D4" t="n"><v>2003</v></c></row>` +
+		`<row r="5"><c r="B5" t="str"><v> </v></c></row>` +
+		`<row r="6"><c r="C6" t="str"><v>Uprising</v></c><c r="D6" t="str"><v>07.09.2009</v></c></row>` +
+		`<row r="7"><c r="B7" t="s"><v>3</v></c><c r="C7" t="str"><v>Starlight</v></c></row>` +
+		`</sheetData>`
+
+	want := []record{
+		{line: 3, values: map[string]string{"group": "Muse", "song": "Hysteria", "releaseDate": "24.01.2006"}},
+		{line: 4, values: map[string]string{"group": "Muse", "song": "Time Is Running Out", "releaseDate": "2003"}},
+		{line: 6, values: map[string]string{"group": "", "song": "Uprising", "releaseDate": "07.09.2009"}},
+		{line: 7, values: map[string]string{"group": "Muse", "song": "Starlight", "releaseDate": ""}},
+	}
+	got, err := readSource(t, xlsxBook(t, shared, sheet), Options{Format: FormatXLSX})
+	if err != nil {
+		t.Fatalf("ошибка = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("строки = %+v, ожидалось %+v", got, want)
+	}
+
+	got, err = readSource(t, xlsxBook(t, shared, sheet, `<workbookPr date1904="1"/>`), Options{Format: FormatXLSX})
+	if err != nil {
+		t.Fatalf("ошибка = %v", err)
+	}
+	if date := got[0].values["releaseDate"]; date != "25.01.2010" {
+		t.Errorf("дата в книге с эпохой 1904 = %s, ожидалось 25.01.2010", date)
+	}
+
+	_, err = readSource(t, xlsxBook(t, shared, `<sheetData><row><c t="s"><v>10</v></c></row></sheetData>`), Options{Format: FormatXLSX})
+	var formatErr *FormatError
+	if !errors.As(err, &formatErr) {
+		t.Errorf("ошибка для ссылки на несуществующую строку = %v, ожидалась *FormatError", err)
+	}
+}
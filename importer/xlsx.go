@@ -0,0 +1,331 @@
+package importer
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// xlsxSource читает первый лист книги Excel с заголовком в первой непустой строке.
+// Лист разбирается потоком, в памяти хранится только таблица общих строк книги.
+type xlsxSource struct {
+	sheet    io.ReadCloser
+	decoder  *xml.Decoder
+	counter  *countingReader
+	shared   []string
+	date1904 bool
+	index    map[string]int
+}
+
+// xlsxCell — значение ячейки и признак того, что оно числовое.
+type xlsxCell struct {
+	value   string
+	numeric bool
+}
+
+type xlsxWorkbook struct {
+	Properties struct {
+		Date1904 string `xml:"date1904,attr"`
+	} `xml:"workbookPr"`
+	Sheets []struct {
+		RelationID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		Id     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxInlineString struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (s xlsxInlineString) String() string {
+	text := s.Text
+	for _, run := range s.Runs {
+		text += run.Text
+	}
+	return text
+}
+
+func newXLSXSource(file *os.File, size int64, opts Options) (*xlsxSource, error) {
+	archive, err := zip.NewReader(file, size)
+	if err != nil {
+		return nil, &FormatError{Msg: "файл не является книгой XLSX"}
+	}
+	files := map[string]*zip.File{}
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+
+	s := &xlsxSource{}
+	sheetName := "xl/worksheets/sheet1.xml"
+	var workbook xlsxWorkbook
+	if err := decodeXLSXPart(files, "xl/workbook.xml", &workbook); err == nil && len(workbook.Sheets) > 0 {
+		s.date1904 = workbook.Properties.Date1904 == "1" || workbook.Properties.Date1904 == "true"
+		var rels xlsxRelationships
+		if err := decodeXLSXPart(files, "xl/_rels/workbook.xml.rels", &rels); err == nil {
+			for _, rel := range rels.Relationships {
+				if rel.Id == workbook.Sheets[0].RelationID {
+					if strings.HasPrefix(rel.Target, "/") {
+						sheetName = strings.TrimPrefix(rel.Target, "/")
+					} else {
+						sheetName = path.Join("xl", rel.Target)
+					}
+				}
+			}
+		}
+	}
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if s.shared, err = readSharedStrings(f); err != nil {
+			return nil, &FormatError{Msg: fmt.Sprintf("не удалось прочитать строки книги XLSX: %v", err)}
+		}
+	}
+
+	sheet, ok := files[sheetName]
+	if !ok {
+		return nil, &FormatError{Msg: "в книге XLSX нет листов"}
+	}
+	if s.sheet, err = sheet.Open(); err != nil {
+		return nil, &FormatError{Msg: fmt.Sprintf("не удалось открыть лист книги XLSX: %v", err)}
+	}
+	s.counter = &countingReader{r: s.sheet, total: int64(sheet.UncompressedSize64)}
+	s.decoder = xml.NewDecoder(s.counter)
+
+	for {
+		_, cells, err := s.nextRow()
+		if errors.Is(err, io.EOF) {
+			s.sheet.Close()
+			return nil, &FormatError{Msg: "лист пустой, ожидается заголовок с названиями колонок"}
+		}
+		if err != nil {
+			s.sheet.Close()
+			return nil, err
+		}
+		header := make([]string, len(cells))
+		for i, cell := range cells {
+			header[i] = cell.value
+		}
+		if emptyRow(header) {
+			continue
+		}
+		if s.index, err = headerIndex(header, opts.Columns); err != nil {
+			s.sheet.Close()
+			return nil, err
+		}
+		return s, nil
+	}
+}
+
+func (s *xlsxSource) next() (record, error) {
+	for {
+		line, cells, err := s.nextRow()
+		if err != nil {
+			return record{}, err
+		}
+		values := make([]string, len(cells))
+		for i, cell := range cells {
+			values[i] = cell.value
+		}
+		if emptyRow(values) {
+			continue
+		}
+		if i, ok := s.index["releaseDate"]; ok && i < len(cells) && cells[i].numeric {
+			values[i] = s.serialDate(cells[i].value)
+		}
+		return tableRecord(line, values, s.index), nil
+	}
+}
+
+func (s *xlsxSource) progress() float64 {
+	return s.counter.progress()
+}
+
+func (s *xlsxSource) close() {
+	s.sheet.Close()
+}
+
+// nextRow читает следующую строку листа: номер строки и ячейки по номерам колонок.
+func (s *xlsxSource) nextRow() (int, []xlsxCell, error) {
+	line := 0
+	var cells []xlsxCell
+	column, kind := 0, ""
+	var cell xlsxCell
+	inRow := false
+
+	for {
+		token, err := s.decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return 0, nil, io.EOF
+		}
+		if err != nil {
+			return 0, nil, &FormatError{Msg: fmt.Sprintf("не удалось разобрать лист книги XLSX: %v", err)}
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "row":
+				inRow, cells, column = true, nil, 0
+				line++
+				if r, err := strconv.Atoi(xmlAttr(t, "r")); err == nil {
+					line = r
+				}
+			case "c":
+				if !inRow {
+					continue
+				}
+				kind, cell = xmlAttr(t, "t"), xlsxCell{}
+				if ref := xmlAttr(t, "r"); ref != "" {
+					column = max(columnIndex(ref), 0)
+				}
+			case "v":
+				var value string
+				if err := s.decoder.DecodeElement(&value, &t); err != nil {
+					return 0, nil, &FormatError{Msg: fmt.Sprintf("не удалось разобрать лист книги XLSX: %v", err)}
+				}
+				switch kind {
+				case "s":
+					i, err := strconv.Atoi(value)
+					if err != nil || i < 0 || i >= len(s.shared) {
+						return 0, nil, &FormatError{Msg: fmt.Sprintf("строка %d листа ссылается на несуществующую строку книги", line)}
+					}
+					cell.value = s.shared[i]
+				case "", "n":
+					cell = xlsxCell{value: value, numeric: true}
+				default:
+					cell.value = value
+				}
+			case "is":
+				var value xlsxInlineString
+				if err := s.decoder.DecodeElement(&value, &t); err != nil {
+					return 0, nil, &FormatError{Msg: fmt.Sprintf("не удалось разобрать лист книги XLSX: %v", err)}
+				}
+				cell.value = value.String()
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "c":
+				if !inRow {
+					continue
+				}
+				for len(cells) <= column {
+					cells = append(cells, xlsxCell{})
+				}
+				cells[column] = cell
+				column++
+			case "row":
+				return line, cells, nil
+			}
+		}
+	}
+}
+
+// serialDate переводит дату Excel (число дней от начала эпохи книги) в формат DD.MM.YYYY.
+// Числа меньше 10000 остаются как есть: это год выхода, а не дата до 1927 года.
+func (s *xlsxSource) serialDate(value string) string {
+	serial, err := strconv.ParseFloat(value, 64)
+	if err != nil || serial < 10000 {
+		return value
+	}
+	epoch := time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC)
+	if s.date1904 {
+		epoch = time.Date(1904, time.January, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return epoch.AddDate(0, 0, int(math.Floor(serial))).Format("02.01.2006")
+}
+
+// readSharedStrings читает таблицу общих строк книги. Текст фонетических подсказок (rPh) пропускается.
+func readSharedStrings(f *zip.File) ([]string, error) {
+	r, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var shared []string
+	var text strings.Builder
+	decoder := xml.NewDecoder(r)
+	phonetic, inText := 0, false
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return shared, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "si":
+				text.Reset()
+			case "rPh":
+				phonetic++
+			case "t":
+				inText = phonetic == 0
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "si":
+				shared = append(shared, text.String())
+			case "rPh":
+				phonetic--
+			case "t":
+				inText = false
+			}
+		case xml.CharData:
+			if inText {
+				text.Write(t)
+			}
+		}
+	}
+}
+
+func decodeXLSXPart(files map[string]*zip.File, name string, v interface{}) error {
+	f, ok := files[name]
+	if !ok {
+		return fmt.Errorf("нет части %s", name)
+	}
+	r, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	return xml.NewDecoder(r).Decode(v)
+}
+
+func xmlAttr(element xml.StartElement, name string) string {
+	for _, attr := range element.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// columnIndex возвращает номер колонки, начиная с 0, по адресу ячейки, например 1 для B7.
+func columnIndex(ref string) int {
+	column := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		column = column*26 + int(r-'A'+1)
+	}
+	return column - 1
+}
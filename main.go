@@ -1,12 +1,21 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
 	_ "song-library/docs"
+	"song-library/enrichment"
 	"song-library/handlers"
-	"song-library/models"
+	"song-library/importer"
+	"song-library/repository"
+	"song-library/trash"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -30,6 +39,79 @@ func main() {
 		log.Fatal("Не удалось загрузить файл конфигурации")
 	}
 
+	if len(os.Args) > 1 && os.Args[1] == "migrate" { //подкоманда для управления схемой базы данных
+		runMigrate(os.Args[2:])
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var repo repository.Store
+	if os.Getenv("STORAGE") == "memory" { //хранилище в памяти для локальной демонстрации
+		log.Println("Используется хранилище в памяти")
+		repo = repository.NewMemory()
+	} else {
+		db := connectDB()
+		checkSchema(db)
+		repo = repository.NewPostgres(db)
+	}
+
+	queue := enrichment.NewQueue(repo, newMetadataProvider(), enrichment.Config{
+		Workers:     envInt("ENRICHMENT_WORKERS", 4),
+		MaxAttempts: envInt("ENRICHMENT_MAX_ATTEMPTS", 5),
+		BaseBackoff: envDuration("ENRICHMENT_BACKOFF", 10*time.Second),
+	})
+	if err := queue.Start(ctx); err != nil {
+		log.Fatalf("Не удалось запустить очередь получения данных песен, %v", err)
+	}
+
+	purger := trash.NewPurger(repo, trash.Config{
+		Retention: envDuration("TRASH_RETENTION", 30*24*time.Hour),
+		Interval:  envDuration("TRASH_PURGE_INTERVAL", time.Hour),
+	})
+	purger.Start(ctx)
+
+	songImporter := importer.NewImporter(repo, queue.Notify, importer.Config{
+		Retention: envDuration("IMPORT_RETENTION", 24*time.Hour),
+		MaxErrors: envInt("IMPORT_MAX_ERRORS", 10000),
+	})
+	songImporter.Start(ctx)
+
+	songHandler := handlers.NewSongHandler(repo, repo, repo, repo, repo, queue)
+	songHandler.RequireIfMatch = envBool("REQUIRE_IF_MATCH", false)
+
+	r := gin.Default()
+	handlers.Register(r, repo, songHandler, songImporter, os.Getenv("ADMIN_TOKEN"))
+
+	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	serve(ctx, r)
+	queue.Wait()
+	purger.Wait()
+	songImporter.Wait()
+}
+
+// serve обслуживает запросы, пока не отменен ctx, после чего дожидается завершения текущих запросов.
+func serve(ctx context.Context, handler http.Handler) {
+	srv := &http.Server{Addr: ":8080", Handler: handler}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Не удалось корректно остановить сервер, %v\n", err)
+		}
+	}()
+
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalf("Не удалось запустить сервер, %v", err)
+	}
+}
+
+// connectDB подключается к PostgreSQL по параметрам из окружения.
+func connectDB() *gorm.DB {
 	host := os.Getenv("DB_HOST")
 	port := os.Getenv("DB_PORT")
 	user := os.Getenv("DB_USER")
@@ -43,33 +125,10 @@ func main() {
 		database,
 		password)
 
-	db, err := gorm.Open(postgres.Open(connStr), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(connStr), &gorm.Config{TranslateError: true}) //ошибки ограничений переводятся в gorm.ErrDuplicatedKey и т.п.
 	if err != nil {
 		log.Fatal("Не удалось подключиться к базе данных", err)
 	}
 
-	db = db.Debug()
-
-	err = db.AutoMigrate(&models.Song{}, &models.SongDetails{}) //делаем миграцию(создаем таблицы в базе данных)
-	if err != nil {
-		log.Printf("Не удалось совершить миграцию, %v\n", err)
-	}
-
-	songHandler := handlers.NewSongHandler(db)
-
-	r := gin.Default()
-
-	r.GET("/songs", songHandler.GetSongs)
-
-	r.GET("/songs/:id/text", songHandler.GetSongText)
-
-	r.PUT("/songs/:id", songHandler.EditSong)
-
-	r.DELETE("/songs/:id", songHandler.DeleteSong)
-
-	r.POST("/songs", songHandler.AddSong)
-
-	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-
-	r.Run(":8080")
+	return db.Debug()
 }
@@ -0,0 +1,90 @@
+package metadata
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen возвращается, пока предохранитель разомкнут и запросы к источнику не выполняются.
+var ErrCircuitOpen = errors.New("предохранитель разомкнут, внешний источник временно недоступен")
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// Breaker — предохранитель, который перестает обращаться к источнику после серии отказов
+// и через OpenTimeout пропускает одну пробную попытку.
+type Breaker struct {
+	FailureThreshold int           //сколько отказов подряд размыкают предохранитель
+	OpenTimeout      time.Duration //сколько предохранитель остается разомкнутым
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+	now      func() time.Time
+}
+
+// NewBreaker создает замкнутый предохранитель.
+func NewBreaker(failureThreshold int, openTimeout time.Duration) *Breaker {
+	return &Breaker{
+		FailureThreshold: failureThreshold,
+		OpenTimeout:      openTimeout,
+		now:              time.Now,
+	}
+}
+
+// Allow возвращает ErrCircuitOpen, если запрос выполнять нельзя.
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if b.now().Sub(b.openedAt) < b.OpenTimeout {
+			return ErrCircuitOpen
+		}
+		b.state = breakerHalfOpen //пропускаем одну пробную попытку
+		return nil
+	case breakerHalfOpen:
+		return ErrCircuitOpen //пробная попытка еще выполняется
+	}
+	return nil
+}
+
+// Success отмечает успешный запрос и замыкает предохранитель.
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = breakerClosed
+	b.failures = 0
+}
+
+// Failure отмечает отказ источника.
+func (b *Breaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.FailureThreshold {
+		b.state = breakerOpen
+		b.openedAt = b.now()
+	}
+}
+
+// Cancel отмечает запрос, прерванный вызывающей стороной: исход неизвестен,
+// поэтому следующая попытка снова будет пробной.
+func (b *Breaker) Cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerHalfOpen {
+		b.state = breakerOpen
+	}
+}
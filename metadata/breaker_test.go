@@ -0,0 +1,78 @@
+package metadata
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeClock — часы, которые идут только по команде теста.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+// newTestBreaker создает предохранитель на поддельных часах.
+func newTestBreaker(threshold int, timeout time.Duration) (*Breaker, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	b := NewBreaker(threshold, timeout)
+	b.now = clock.Now
+	return b, clock
+}
+
+func TestBreaker(t *testing.T) {
+	type step struct {
+		do      string        //allow, success, failure, cancel или wait
+		wait    time.Duration //для wait
+		wantErr bool          //для allow: ожидается ErrCircuitOpen
+	}
+	allow := step{do: "allow"}
+	deny := step{do: "allow", wantErr: true}
+	success := step{do: "success"}
+	failure := step{do: "failure"}
+	cancel := step{do: "cancel"}
+	wait := func(d time.Duration) step { return step{do: "wait", wait: d} }
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{name: "отказы меньше порога", steps: []step{allow, failure, allow, failure, allow}},
+		{name: "успех сбрасывает счетчик отказов", steps: []step{failure, failure, success, failure, failure, allow}},
+		{name: "порог отказов размыкает", steps: []step{failure, failure, failure, deny, wait(59 * time.Second), deny}},
+		{name: "после таймаута одна пробная попытка", steps: []step{failure, failure, failure, wait(time.Minute), allow, deny, deny}},
+		{name: "удачная пробная попытка замыкает", steps: []step{failure, failure, failure, wait(time.Minute), allow, success, allow, allow}},
+		{name: "неудачная пробная попытка снова размыкает", steps: []step{
+			failure, failure, failure, wait(time.Minute), allow, failure, deny, wait(59 * time.Second), deny, wait(time.Second), allow,
+		}},
+		{name: "отмена пробной попытки оставляет следующую пробной", steps: []step{
+			failure, failure, failure, wait(time.Minute), allow, cancel, allow, deny, success, allow,
+		}},
+		{name: "отмена в замкнутом состоянии ничего не меняет", steps: []step{failure, failure, cancel, allow, failure, deny}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, clock := newTestBreaker(3, time.Minute)
+			for i, s := range tt.steps {
+				switch s.do {
+				case "allow":
+					err := b.Allow()
+					if s.wantErr && !errors.Is(err, ErrCircuitOpen) || !s.wantErr && err != nil {
+						t.Fatalf("шаг %d: Allow() = %v, ожидалась ошибка: %v", i, err, s.wantErr)
+					}
+				case "success":
+					b.Success()
+				case "failure":
+					b.Failure()
+				case "cancel":
+					b.Cancel()
+				case "wait":
+					clock.Advance(s.wait)
+				}
+			}
+		})
+	}
+}
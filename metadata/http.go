@@ -0,0 +1,175 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"song-library/models"
+)
+
+// HTTPConfig описывает подключение к внешнему API с данными песен.
+type HTTPConfig struct {
+	BaseURL        string        //адрес API, например http://localhost:8081
+	Path           string        //путь метода получения данных, по умолчанию /info
+	RequestTimeout time.Duration //ограничение времени одной попытки
+	MaxAttempts    int           //максимальное количество попыток
+	BaseBackoff    time.Duration //пауза перед второй попыткой, дальше удваивается
+	MaxBackoff     time.Duration //максимальная пауза между попытками
+	Client         *http.Client
+	Breaker        *Breaker
+}
+
+// HTTPProvider получает данные песни из внешнего HTTP API.
+type HTTPProvider struct {
+	cfg HTTPConfig
+}
+
+// NewHTTPProvider создает HTTPProvider, подставляя значения по умолчанию для незаполненных полей.
+func NewHTTPProvider(cfg HTTPConfig) *HTTPProvider {
+	if cfg.Path == "" {
+		cfg.Path = "/info"
+	}
+	if cfg.RequestTimeout <= 0 {
+		cfg.RequestTimeout = 5 * time.Second
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 3
+	}
+	if cfg.BaseBackoff <= 0 {
+		cfg.BaseBackoff = 200 * time.Millisecond
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = 5 * time.Second
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{}
+	}
+	if cfg.Breaker == nil {
+		cfg.Breaker = NewBreaker(5, 30*time.Second)
+	}
+	return &HTTPProvider{cfg: cfg}
+}
+
+// SongInfo запрашивает данные песни, повторяя запрос с экспоненциальной паузой
+// при ошибках сети, таймаутах и ответах 5xx.
+func (p *HTTPProvider) SongInfo(ctx context.Context, group, song string) (*models.SongDetails, error) {
+	query := url.Values{}
+	query.Set("group", group)
+	query.Set("song", song)
+	endpoint := strings.TrimRight(p.cfg.BaseURL, "/") + p.cfg.Path + "?" + query.Encode()
+
+	var lastErr *Error
+	for attempt := 1; attempt <= p.cfg.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			if err := sleep(ctx, p.backoff(attempt-1)); err != nil {
+				break
+			}
+		}
+
+		if err := p.cfg.Breaker.Allow(); err != nil {
+			return nil, &Error{Kind: KindUnavailable, Attempts: attempt - 1, Err: err}
+		}
+
+		details, err := p.fetch(ctx, endpoint)
+		if err == nil {
+			p.cfg.Breaker.Success()
+			return details, nil
+		}
+
+		err.Attempts = attempt
+		lastErr = err
+		if ctx.Err() != nil { //запрос отменен вызывающей стороной, источник тут ни при чем
+			p.cfg.Breaker.Cancel()
+			break
+		}
+		if !err.Retryable() {
+			p.cfg.Breaker.Success() //источник ответил, значит он работает
+			return nil, err
+		}
+		p.cfg.Breaker.Failure()
+		log.Printf("Попытка %d получения данных песни не удалась, %v\n", attempt, err)
+	}
+
+	if lastErr == nil {
+		lastErr = &Error{Kind: KindTimeout, Err: ctx.Err()}
+	}
+	return nil, lastErr
+}
+
+// fetch выполняет одну попытку запроса с собственным ограничением времени.
+func (p *HTTPProvider) fetch(ctx context.Context, endpoint string) (*models.SongDetails, *Error) {
+	ctx, cancel := context.WithTimeout(ctx, p.cfg.RequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, &Error{Kind: KindRejected, Err: err}
+	}
+
+	resp, err := p.cfg.Client.Do(req)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, &Error{Kind: KindTimeout, Err: err}
+		}
+		return nil, &Error{Kind: KindUnavailable, Err: err}
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, &Error{Kind: KindTimeout, StatusCode: resp.StatusCode, Err: err}
+		}
+		return nil, &Error{Kind: KindUnavailable, StatusCode: resp.StatusCode, Err: err}
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusNoContent:
+		return nil, &Error{Kind: KindNotFound, StatusCode: resp.StatusCode}
+	case resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusGatewayTimeout:
+		return nil, &Error{Kind: KindTimeout, StatusCode: resp.StatusCode}
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return nil, &Error{Kind: KindUnavailable, StatusCode: resp.StatusCode}
+	case resp.StatusCode != http.StatusOK:
+		return nil, &Error{Kind: KindRejected, StatusCode: resp.StatusCode, Err: fmt.Errorf("%s", strings.TrimSpace(string(data)))}
+	}
+
+	if len(strings.TrimSpace(string(data))) == 0 { //на случай если в API не будет доп данных о песне
+		return nil, &Error{Kind: KindNotFound, StatusCode: resp.StatusCode}
+	}
+
+	var details models.SongDetails
+	if err := json.Unmarshal(data, &details); err != nil {
+		return nil, &Error{Kind: KindBadResponse, StatusCode: resp.StatusCode, Err: err}
+	}
+	return &details, nil
+}
+
+// backoff возвращает паузу перед повтором с номером retry (начиная с 1) со случайным разбросом.
+func (p *HTTPProvider) backoff(retry int) time.Duration {
+	d := p.cfg.BaseBackoff << (retry - 1)
+	if d <= 0 || d > p.cfg.MaxBackoff {
+		d = p.cfg.MaxBackoff
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,216 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"song-library/models"
+)
+
+// scriptedAPI отвечает на запросы по очереди заданными ответами, последний ответ повторяется.
+type scriptedAPI struct {
+	mu        sync.Mutex
+	responses []response
+	requests  []*http.Request
+}
+
+type response struct {
+	status int
+	body   string
+	delay  time.Duration //ответ задерживается, пока запрос не отменят, но не дольше delay
+}
+
+func (a *scriptedAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	a.requests = append(a.requests, r)
+	resp := a.responses[0]
+	if len(a.responses) > 1 {
+		a.responses = a.responses[1:]
+	}
+	a.mu.Unlock()
+
+	if resp.delay > 0 {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(resp.delay):
+		}
+	}
+	w.WriteHeader(resp.status)
+	w.Write([]byte(resp.body))
+}
+
+func (a *scriptedAPI) calls() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.requests)
+}
+
+// newTestProvider запускает API с ответами responses и создает провайдер с короткими паузами.
+func newTestProvider(t *testing.T, breaker *Breaker, responses ...response) (*HTTPProvider, *scriptedAPI) {
+	t.Helper()
+	api := &scriptedAPI{responses: responses}
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+	if breaker == nil {
+		breaker = NewBreaker(100, time.Minute)
+	}
+	return NewHTTPProvider(HTTPConfig{
+		BaseURL:        server.URL + "/",
+		RequestTimeout: 50 * time.Millisecond,
+		MaxAttempts:    3,
+		BaseBackoff:    time.Millisecond,
+		MaxBackoff:     2 * time.Millisecond,
+		Breaker:        breaker,
+	}), api
+}
+
+const hysteriaJSON = `{"releaseDate":"01.12.2003","text":"It's bugging me","link":"https://example.com/hysteria"}`
+
+func TestHTTPProviderSongInfo(t *testing.T) {
+	ok := response{status: http.StatusOK, body: hysteriaJSON}
+	released, _ := models.ParseReleaseDate("01.12.2003")
+	hysteria := &models.SongDetails{ReleaseDate: released, Text: "It's bugging me", Link: "https://example.com/hysteria"}
+
+	tests := []struct {
+		name      string
+		responses []response
+		want      *models.SongDetails
+		wantErr   *Error //Err не сравнивается
+		wantCalls int
+	}{
+		{name: "успех", responses: []response{ok}, want: hysteria, wantCalls: 1},
+		{name: "повтор после 500", responses: []response{{status: 500}, {status: 502}, ok}, want: hysteria, wantCalls: 3},
+		{name: "повтор после 429", responses: []response{{status: 429}, ok}, want: hysteria, wantCalls: 2},
+		{name: "повтор после 504", responses: []response{{status: 504}, ok}, want: hysteria, wantCalls: 2},
+		{name: "попытки кончились", responses: []response{{status: 503}},
+			wantErr: &Error{Kind: KindUnavailable, StatusCode: 503, Attempts: 3}, wantCalls: 3},
+		{name: "таймаут каждой попытки", responses: []response{{status: 200, body: hysteriaJSON, delay: time.Second}},
+			wantErr: &Error{Kind: KindTimeout, Attempts: 3}, wantCalls: 3},
+		{name: "408 — таймаут источника", responses: []response{{status: 408}},
+			wantErr: &Error{Kind: KindTimeout, StatusCode: 408, Attempts: 3}, wantCalls: 3},
+		{name: "404 без повтора", responses: []response{{status: 404}, ok},
+			wantErr: &Error{Kind: KindNotFound, StatusCode: 404, Attempts: 1}, wantCalls: 1},
+		{name: "204 — нет данных", responses: []response{{status: 204}},
+			wantErr: &Error{Kind: KindNotFound, StatusCode: 204, Attempts: 1}, wantCalls: 1},
+		{name: "пустое тело", responses: []response{{status: 200, body: " \n"}},
+			wantErr: &Error{Kind: KindNotFound, StatusCode: 200, Attempts: 1}, wantCalls: 1},
+		{name: "400 без повтора", responses: []response{{status: 400, body: "bad group"}, ok},
+			wantErr: &Error{Kind: KindRejected, StatusCode: 400, Attempts: 1}, wantCalls: 1},
+		{name: "некорректный JSON", responses: []response{{status: 200, body: "{"}, ok},
+			wantErr: &Error{Kind: KindBadResponse, StatusCode: 200, Attempts: 1}, wantCalls: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, api := newTestProvider(t, nil, tt.responses...)
+			got, err := p.SongInfo(context.Background(), "Muse", "Hysteria")
+
+			if tt.wantErr == nil {
+				if err != nil || !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("SongInfo() = %+v, %v, ожидалось %+v", got, err, tt.want)
+				}
+			} else {
+				var metaErr *Error
+				if !errors.As(err, &metaErr) {
+					t.Fatalf("SongInfo() вернул ошибку %v, ожидалась *Error", err)
+				}
+				if metaErr.Kind != tt.wantErr.Kind || metaErr.StatusCode != tt.wantErr.StatusCode || metaErr.Attempts != tt.wantErr.Attempts {
+					t.Errorf("SongInfo() вернул %+v, ожидалось %+v", metaErr, tt.wantErr)
+				}
+			}
+			if calls := api.calls(); calls != tt.wantCalls {
+				t.Errorf("запросов к API %d, ожидалось %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestHTTPProviderRequest(t *testing.T) {
+	p, api := newTestProvider(t, nil, response{status: http.StatusOK, body: hysteriaJSON})
+	if _, err := p.SongInfo(context.Background(), "Guns N' Roses", "Sweet Child O'Mine & more"); err != nil {
+		t.Fatal(err)
+	}
+	req := api.requests[0]
+	if req.URL.Path != "/info" || req.URL.Query().Get("group") != "Guns N' Roses" || req.URL.Query().Get("song") != "Sweet Child O'Mine & more" {
+		t.Errorf("запрос %s", req.URL)
+	}
+}
+
+func TestHTTPProviderBreaker(t *testing.T) {
+	breaker, clock := newTestBreaker(2, time.Minute)
+	p, api := newTestProvider(t, breaker, response{status: 500}, response{status: 500}, response{status: http.StatusOK, body: hysteriaJSON})
+
+	//две неудачные попытки размыкают предохранитель, третья не выполняется
+	_, err := p.SongInfo(context.Background(), "Muse", "Hysteria")
+	var metaErr *Error
+	if !errors.As(err, &metaErr) || metaErr.Kind != KindUnavailable || metaErr.Attempts != 2 || !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("SongInfo() = %v, ожидался разомкнутый предохранитель после 2 попыток", err)
+	}
+	if _, err := p.SongInfo(context.Background(), "Muse", "Hysteria"); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("пока предохранитель разомкнут: %v", err)
+	}
+	if calls := api.calls(); calls != 2 {
+		t.Errorf("запросов к API %d, ожидалось 2", calls)
+	}
+
+	clock.Advance(time.Minute)
+	if _, err := p.SongInfo(context.Background(), "Muse", "Hysteria"); err != nil {
+		t.Errorf("пробная попытка: %v", err)
+	}
+	if err := breaker.Allow(); err != nil {
+		t.Errorf("после удачной пробной попытки предохранитель разомкнут: %v", err)
+	}
+}
+
+func TestHTTPProviderNonRetryableKeepsBreakerClosed(t *testing.T) {
+	breaker, _ := newTestBreaker(1, time.Minute)
+	p, _ := newTestProvider(t, breaker, response{status: http.StatusNotFound})
+	for i := 0; i < 3; i++ {
+		if _, err := p.SongInfo(context.Background(), "Muse", "Hysteria"); errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("запрос %d: ответ 404 разомкнул предохранитель", i+1)
+		}
+	}
+}
+
+func TestHTTPProviderCancel(t *testing.T) {
+	breaker, _ := newTestBreaker(1, time.Minute)
+	p, _ := newTestProvider(t, breaker, response{status: http.StatusOK, body: hysteriaJSON, delay: time.Second})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := p.SongInfo(ctx, "Muse", "Hysteria")
+	var metaErr *Error
+	if !errors.As(err, &metaErr) || metaErr.Kind != KindTimeout || metaErr.Attempts != 1 {
+		t.Fatalf("SongInfo() = %v, ожидался таймаут после 1 попытки", err)
+	}
+	if err := breaker.Allow(); err != nil {
+		t.Errorf("отмена запроса вызывающей стороной разомкнула предохранитель: %v", err)
+	}
+}
+
+func TestHTTPProviderBackoff(t *testing.T) {
+	p := NewHTTPProvider(HTTPConfig{BaseBackoff: 100 * time.Millisecond, MaxBackoff: time.Second})
+	tests := []struct {
+		retry    int
+		min, max time.Duration
+	}{
+		{retry: 1, min: 50 * time.Millisecond, max: 100 * time.Millisecond},
+		{retry: 2, min: 100 * time.Millisecond, max: 200 * time.Millisecond},
+		{retry: 4, min: 400 * time.Millisecond, max: 800 * time.Millisecond},
+		{retry: 5, min: 500 * time.Millisecond, max: time.Second},
+		{retry: 70, min: 500 * time.Millisecond, max: time.Second}, //сдвиг переполняет Duration
+	}
+	for _, tt := range tests {
+		for i := 0; i < 20; i++ {
+			if d := p.backoff(tt.retry); d < tt.min || d > tt.max {
+				t.Errorf("backoff(%d) = %v, ожидалось от %v до %v", tt.retry, d, tt.min, tt.max)
+			}
+		}
+	}
+}
@@ -0,0 +1,57 @@
+// Package metadata получает дополнительные данные песен (текст, ссылку, дату выхода) из внешних источников.
+package metadata
+
+import (
+	"context"
+	"fmt"
+
+	"song-library/models"
+)
+
+// Provider получает дополнительные данные песни из внешнего источника.
+type Provider interface {
+	SongInfo(ctx context.Context, group, song string) (*models.SongDetails, error)
+}
+
+// ErrorKind классифицирует ошибки получения данных.
+type ErrorKind string
+
+const (
+	KindNotFound    ErrorKind = "not_found"    //у источника нет данных о песне
+	KindRejected    ErrorKind = "rejected"     //источник отклонил запрос (4xx)
+	KindUnavailable ErrorKind = "unavailable"  //источник недоступен: ошибка сети, 5xx или разомкнут предохранитель
+	KindTimeout     ErrorKind = "timeout"      //источник не ответил вовремя
+	KindBadResponse ErrorKind = "bad_response" //ответ источника не удалось разобрать
+)
+
+// Error — структурированная ошибка получения данных от внешнего источника.
+type Error struct {
+	Kind       ErrorKind
+	StatusCode int //HTTP статус ответа источника, если он был получен
+	Attempts   int //количество сделанных попыток
+	Err        error
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("ошибка получения данных песни (%s", e.Kind)
+	if e.StatusCode != 0 {
+		msg += fmt.Sprintf(", статус %d", e.StatusCode)
+	}
+	if e.Attempts > 1 {
+		msg += fmt.Sprintf(", попыток %d", e.Attempts)
+	}
+	msg += ")"
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Retryable сообщает, имеет ли смысл повторить запрос.
+func (e *Error) Retryable() bool {
+	return e.Kind == KindUnavailable || e.Kind == KindTimeout
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"song-library/migrations"
+
+	"gorm.io/gorm"
+)
+
+// runMigrate выполняет подкоманду migrate up|down|status.
+func runMigrate(args []string) {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Использование: song-library migrate up|down|status")
+		os.Exit(2)
+	}
+
+	migrator := newMigrator(connectDB())
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		done, err := migrator.Up(ctx)
+		for _, m := range done {
+			fmt.Printf("Применена миграция %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatalf("Не удалось применить миграции, %v", err)
+		}
+		if len(done) == 0 {
+			fmt.Println("Схема базы данных актуальна")
+		}
+	case "down":
+		undone, err := migrator.Down(ctx)
+		if err != nil {
+			log.Fatalf("Не удалось откатить миграцию, %v", err)
+		}
+		if undone == nil {
+			fmt.Println("Нет примененных миграций")
+			return
+		}
+		fmt.Printf("Откачена миграция %04d_%s\n", undone.Version, undone.Name)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.Fatalf("Не удалось получить состояние миграций, %v", err)
+		}
+		for _, status := range statuses {
+			applied := "не применена"
+			if status.AppliedAt != nil {
+				applied = "применена " + status.AppliedAt.Format("02.01.2006 15:04:05")
+			}
+			fmt.Printf("%04d_%s\t%s\n", status.Version, status.Name, applied)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Неизвестная команда migrate %q, ожидается up, down или status\n", args[0])
+		os.Exit(2)
+	}
+}
+
+// checkSchema не дает запустить сервер, если схема базы данных отстает от кода.
+func checkSchema(db *gorm.DB) {
+	pending, err := newMigrator(db).Pending(context.Background())
+	if err != nil {
+		log.Fatalf("Не удалось проверить схему базы данных, %v", err)
+	}
+	if len(pending) > 0 {
+		for _, m := range pending {
+			log.Printf("Не применена миграция %04d_%s\n", m.Version, m.Name)
+		}
+		log.Fatal("Схема базы данных устарела, выполните migrate up")
+	}
+}
+
+func newMigrator(db *gorm.DB) *migrations.Migrator {
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatalf("Не удалось получить подключение к базе данных, %v", err)
+	}
+	migrator, err := migrations.NewMigrator(sqlDB)
+	if err != nil {
+		log.Fatalf("Некоректный набор миграций, %v", err)
+	}
+	return migrator
+}
@@ -0,0 +1,54 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"song-library/models"
+)
+
+// Миграция 0009 добавляет тексту песни разбиение на части и заполняет его для уже сохраненных песен.
+func init() {
+	register(Migration{
+		Version: 9,
+		Name:    "lyrics_sections",
+		Up:      lyricsSectionsUp,
+		Down:    sqlStep(`ALTER TABLE song_details DROP COLUMN sections`),
+	})
+}
+
+func lyricsSectionsUp(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.ExecContext(ctx, `ALTER TABLE song_details ADD COLUMN sections jsonb NOT NULL DEFAULT '[]'`); err != nil {
+		return err
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT song_id, text FROM song_details WHERE text <> ''`)
+	if err != nil {
+		return err
+	}
+	texts := map[int]string{}
+	for rows.Next() {
+		var id int
+		var text string
+		if err := rows.Scan(&id, &text); err != nil {
+			rows.Close()
+			return err
+		}
+		texts[id] = text
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for id, text := range texts {
+		sections, err := models.ParseLyrics(text).Value()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE song_details SET sections = $1 WHERE song_id = $2`, sections, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
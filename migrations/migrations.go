@@ -0,0 +1,285 @@
+// Package migrations содержит упорядоченный набор миграций схемы базы данных
+// и средства для их применения и отката.
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+//go:embed sql/*.sql
+var sqlFiles embed.FS
+
+// lockID — ключ advisory-блокировки, чтобы миграции не выполнялись одновременно из нескольких процессов.
+const lockID = 7318240581
+
+// Step выполняет один шаг миграции внутри транзакции.
+type Step func(ctx context.Context, tx *sql.Tx) error
+
+// Migration описывает одну версию схемы.
+type Migration struct {
+	Version int
+	Name    string
+	Up      Step
+	Down    Step
+}
+
+// Status описывает состояние одной миграции в базе данных.
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// goMigrations — миграции с шагами на Go, например для переноса данных, который нельзя выразить в SQL.
+// Регистрируются через register в init файлов NNNN_name.go.
+var goMigrations []Migration
+
+func register(m Migration) {
+	goMigrations = append(goMigrations, m)
+}
+
+// All возвращает все миграции, отсортированные по версии.
+func All() ([]Migration, error) {
+	byVersion := map[int]Migration{}
+
+	files, err := fs.ReadDir(sqlFiles, "sql")
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		match := fileName.FindStringSubmatch(file.Name())
+		if match == nil {
+			return nil, fmt.Errorf("некоректное имя файла миграции %q", file.Name())
+		}
+		version, _ := strconv.Atoi(match[1])
+
+		data, err := sqlFiles.ReadFile(path.Join("sql", file.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m := byVersion[version]
+		if m.Name != "" && m.Name != match[2] {
+			return nil, fmt.Errorf("у миграции %d несколько имен: %q и %q", version, m.Name, match[2])
+		}
+		m.Version = version
+		m.Name = match[2]
+		if match[3] == "up" {
+			m.Up = sqlStep(string(data))
+		} else {
+			m.Down = sqlStep(string(data))
+		}
+		byVersion[version] = m
+	}
+
+	for _, m := range goMigrations {
+		if existing, ok := byVersion[m.Version]; ok {
+			return nil, fmt.Errorf("миграция %d задана и SQL файлами (%q), и на Go (%q)", m.Version, existing.Name, m.Name)
+		}
+		byVersion[m.Version] = m
+	}
+
+	all := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == nil || m.Down == nil {
+			return nil, fmt.Errorf("у миграции %d_%s нет шага up или down", m.Version, m.Name)
+		}
+		all = append(all, m)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Version < all[j].Version })
+	return all, nil
+}
+
+func sqlStep(query string) Step {
+	return func(ctx context.Context, tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, query)
+		return err
+	}
+}
+
+// Migrator применяет и откатывает миграции, записывая примененные версии в таблицу schema_migrations.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// NewMigrator создает Migrator для всех миграций пакета.
+func NewMigrator(db *sql.DB) (*Migrator, error) {
+	all, err := All()
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: all}, nil
+}
+
+// Status возвращает состояние всех известных миграций.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	if err := m.ensureTable(ctx, m.db); err != nil {
+		return nil, err
+	}
+	applied, err := m.applied(ctx, m.db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Migration: migration}
+		if at, ok := applied[migration.Version]; ok {
+			status.AppliedAt = &at
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Pending возвращает миграции, которые еще не применены.
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var pending []Migration
+	for _, status := range statuses {
+		if status.AppliedAt == nil {
+			pending = append(pending, status.Migration)
+		}
+	}
+	return pending, nil
+}
+
+// Up применяет все непримененные миграции по порядку, каждую в своей транзакции.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			err := m.inTx(ctx, conn, func(tx *sql.Tx) error {
+				if err := migration.Up(ctx, tx); err != nil {
+					return err
+				}
+				_, err := tx.ExecContext(ctx,
+					"INSERT INTO schema_migrations (version, name) VALUES ($1, $2)",
+					migration.Version, migration.Name)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("миграция %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down откатывает последнюю примененную миграцию. Если примененных миграций нет, возвращает nil.
+func (m *Migrator) Down(ctx context.Context) (*Migration, error) {
+	var undone *Migration
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			err := m.inTx(ctx, conn, func(tx *sql.Tx) error {
+				if err := migration.Down(ctx, tx); err != nil {
+					return err
+				}
+				_, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = $1", migration.Version)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("откат миграции %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			undone = &migration
+			return nil
+		}
+		return nil
+	})
+	return undone, err
+}
+
+type execQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+func (m *Migrator) ensureTable(ctx context.Context, db execQuerier) error {
+	_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version bigint PRIMARY KEY,
+		name text NOT NULL,
+		applied_at timestamptz NOT NULL DEFAULT now()
+	)`)
+	return err
+}
+
+func (m *Migrator) applied(ctx context.Context, db execQuerier) (map[int]time.Time, error) {
+	rows, err := db.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]time.Time{}
+	for rows.Next() {
+		var version int
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		applied[version] = at
+	}
+	return applied, rows.Err()
+}
+
+// locked выполняет fn на отдельном соединении под advisory-блокировкой.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockID); err != nil {
+		return fmt.Errorf("не удалось получить блокировку миграций: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockID)
+
+	if err := m.ensureTable(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+func (m *Migrator) inTx(ctx context.Context, conn *sql.Conn, fn func(tx *sql.Tx) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,40 @@
+package migrations
+
+import "testing"
+
+func TestAll(t *testing.T) {
+	all, err := All()
+	if err != nil {
+		t.Fatalf("All() вернул ошибку: %v", err)
+	}
+	if len(all) == 0 {
+		t.Fatal("All() не вернул ни одной миграции")
+	}
+	for i, m := range all {
+		if m.Version != i+1 {
+			t.Errorf("миграция %d_%s на месте %d, версии должны идти подряд с 1", m.Version, m.Name, i+1)
+		}
+		if m.Up == nil || m.Down == nil {
+			t.Errorf("у миграции %d_%s нет шага up или down", m.Version, m.Name)
+		}
+	}
+}
+
+func TestFileName(t *testing.T) {
+	tests := []struct {
+		name string
+		ok   bool
+	}{
+		{name: "0001_create_songs.up.sql", ok: true},
+		{name: "0012_add_index.down.sql", ok: true},
+		{name: "0001_create songs.up.sql", ok: false},
+		{name: "create_songs.up.sql", ok: false},
+		{name: "0001_create_songs.sql", ok: false},
+		{name: "0001_create_songs.up.sql.bak", ok: false},
+	}
+	for _, tt := range tests {
+		if got := fileName.MatchString(tt.name); got != tt.ok {
+			t.Errorf("fileName.MatchString(%q) = %v, ожидалось %v", tt.name, got, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+-- Таблицы совпадают с теми, что раньше создавал AutoMigrate,
+-- поэтому миграция безопасно применяется к уже существующей базе.
+CREATE TABLE IF NOT EXISTS songs (
+    id bigserial PRIMARY KEY,
+    "group" text,
+    song text
+);
+
+CREATE TABLE IF NOT EXISTS song_details (
+    song_id bigint,
+    text text,
+    release_date text,
+    link text
+);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS song_details_link_idx;
+DROP INDEX IF EXISTS songs_group_song_idx;
+
+ALTER TABLE song_details DROP CONSTRAINT IF EXISTS song_details_song_id_fkey;
+ALTER TABLE song_details DROP CONSTRAINT IF EXISTS song_details_pkey;
+ALTER TABLE song_details ALTER COLUMN song_id DROP NOT NULL;
@@ -0,0 +1,14 @@
+-- Удаляем данные, которые нарушили бы ограничения: детали без песни и дубли деталей.
+DELETE FROM song_details WHERE song_id IS NULL OR song_id NOT IN (SELECT id FROM songs);
+DELETE FROM song_details a USING song_details b
+WHERE a.song_id = b.song_id AND a.ctid > b.ctid;
+
+-- Внешний ключ, который мог создать AutoMigrate, заменяем ключом с каскадным удалением.
+ALTER TABLE song_details DROP CONSTRAINT IF EXISTS fk_songs_song_details;
+ALTER TABLE song_details ALTER COLUMN song_id SET NOT NULL;
+ALTER TABLE song_details ADD CONSTRAINT song_details_pkey PRIMARY KEY (song_id);
+ALTER TABLE song_details ADD CONSTRAINT song_details_song_id_fkey
+    FOREIGN KEY (song_id) REFERENCES songs (id) ON DELETE CASCADE;
+
+CREATE INDEX songs_group_song_idx ON songs ("group", song);
+CREATE INDEX song_details_link_idx ON song_details (link);
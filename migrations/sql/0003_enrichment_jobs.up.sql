@@ -0,0 +1,16 @@
+-- У существующих песен дополнительные данные уже получены при добавлении.
+ALTER TABLE songs ADD COLUMN enrichment_status text NOT NULL DEFAULT 'succeeded';
+
+CREATE TABLE enrichment_jobs (
+    id bigserial PRIMARY KEY,
+    song_id bigint NOT NULL UNIQUE REFERENCES songs (id) ON DELETE CASCADE,
+    status text NOT NULL DEFAULT 'queued',
+    attempts integer NOT NULL DEFAULT 0,
+    last_error text NOT NULL DEFAULT '',
+    last_error_kind text NOT NULL DEFAULT '',
+    run_at timestamptz NOT NULL DEFAULT now(),
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX enrichment_jobs_queued_idx ON enrichment_jobs (run_at) WHERE status = 'queued';
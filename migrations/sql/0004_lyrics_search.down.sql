@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS song_details_search_idx;
+ALTER TABLE song_details DROP COLUMN IF EXISTS search_vector;
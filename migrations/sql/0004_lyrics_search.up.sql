@@ -0,0 +1,7 @@
+-- Текст песни индексируется сразу для русского и английского языков.
+ALTER TABLE song_details ADD COLUMN search_vector tsvector
+    GENERATED ALWAYS AS (
+        to_tsvector('russian', coalesce(text, '')) || to_tsvector('english', coalesce(text, ''))
+    ) STORED;
+
+CREATE INDEX song_details_search_idx ON song_details USING GIN (search_vector);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS songs_song_trgm_idx;
+DROP INDEX IF EXISTS songs_group_trgm_idx;
+DROP FUNCTION IF EXISTS normalize_name(text);
+-- Расширение pg_trgm оставляем: им могут пользоваться другие схемы.
@@ -0,0 +1,9 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Нормализация названий для нечеткого поиска: без учета регистра и лишних пробелов.
+CREATE FUNCTION normalize_name(name text) RETURNS text
+    LANGUAGE sql IMMUTABLE PARALLEL SAFE
+    AS $$ SELECT lower(btrim(regexp_replace(coalesce(name, ''), '\s+', ' ', 'g'))) $$;
+
+CREATE INDEX songs_group_trgm_idx ON songs USING GIN (normalize_name("group") gin_trgm_ops);
+CREATE INDEX songs_song_trgm_idx ON songs USING GIN (normalize_name(song) gin_trgm_ops);
@@ -0,0 +1,15 @@
+DROP INDEX IF EXISTS song_details_released_on_idx;
+
+ALTER TABLE song_details RENAME COLUMN release_date_legacy TO release_date;
+
+UPDATE song_details
+SET release_date = CASE release_precision
+        WHEN 'year' THEN to_char(released_on, 'YYYY')
+        WHEN 'month' THEN to_char(released_on, 'MM.YYYY')
+        ELSE to_char(released_on, 'DD.MM.YYYY')
+    END
+WHERE released_on IS NOT NULL;
+
+ALTER TABLE song_details
+    DROP COLUMN release_precision,
+    DROP COLUMN released_on;
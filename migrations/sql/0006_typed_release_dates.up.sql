@@ -0,0 +1,42 @@
+ALTER TABLE song_details
+    ADD COLUMN released_on date,
+    ADD COLUMN release_precision text
+        CONSTRAINT song_details_release_precision_check CHECK (release_precision IN ('year', 'month', 'day'));
+
+-- Разбирает строковую дату в одном из поддерживаемых форматов.
+-- Некоректные даты (например 31.02.2006) дают NULL, а не ошибку миграции.
+CREATE FUNCTION migrate_release_date(value text, OUT released_on date, OUT release_precision text)
+LANGUAGE plpgsql IMMUTABLE AS $$
+BEGIN
+    value := btrim(coalesce(value, ''));
+    IF value ~ '^\d{1,2}\.\d{1,2}\.\d{4}$' THEN
+        released_on := to_date(value, 'DD.MM.YYYY');
+        release_precision := 'day';
+    ELSIF value ~ '^\d{4}-\d{1,2}-\d{1,2}$' THEN
+        released_on := to_date(value, 'YYYY-MM-DD');
+        release_precision := 'day';
+    ELSIF value ~ '^\d{1,2}\.\d{4}$' THEN
+        released_on := to_date(value, 'MM.YYYY');
+        release_precision := 'month';
+    ELSIF value ~ '^\d{4}-\d{1,2}$' THEN
+        released_on := to_date(value, 'YYYY-MM');
+        release_precision := 'month';
+    ELSIF value ~ '^\d{4}$' THEN
+        released_on := to_date(value, 'YYYY');
+        release_precision := 'year';
+    END IF;
+EXCEPTION WHEN others THEN
+    released_on := NULL;
+    release_precision := NULL;
+END
+$$;
+
+UPDATE song_details
+SET (released_on, release_precision) = (SELECT * FROM migrate_release_date(release_date));
+
+DROP FUNCTION migrate_release_date(text);
+
+-- Исходные строки сохраняем, чтобы вручную разобрать те, что не удалось преобразовать.
+ALTER TABLE song_details RENAME COLUMN release_date TO release_date_legacy;
+
+CREATE INDEX song_details_released_on_idx ON song_details (released_on);
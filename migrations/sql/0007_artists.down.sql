@@ -0,0 +1,13 @@
+ALTER TABLE songs ADD COLUMN "group" text;
+
+UPDATE songs SET "group" = artists.name
+FROM artists
+WHERE artists.id = songs.artist_id;
+
+DROP INDEX IF EXISTS songs_artist_id_song_idx;
+ALTER TABLE songs DROP COLUMN artist_id;
+
+CREATE INDEX songs_group_song_idx ON songs ("group", song);
+CREATE INDEX songs_group_trgm_idx ON songs USING GIN (normalize_name("group") gin_trgm_ops);
+
+DROP TABLE artists;
@@ -0,0 +1,35 @@
+CREATE TABLE artists (
+    id bigserial PRIMARY KEY,
+    name text NOT NULL,
+    aliases jsonb NOT NULL DEFAULT '[]',
+    country text NOT NULL DEFAULT '',
+    formed_year integer,
+    members jsonb NOT NULL DEFAULT '[]'
+);
+
+-- Названия, отличающиеся только регистром и пробелами, считаются одним исполнителем.
+CREATE UNIQUE INDEX artists_name_key ON artists (normalize_name(name));
+CREATE INDEX artists_name_trgm_idx ON artists USING GIN (normalize_name(name) gin_trgm_ops);
+
+-- Для каждой группы написаний выбираем самое частое как название исполнителя.
+INSERT INTO artists (name)
+SELECT DISTINCT ON (normalized) spelling
+FROM (
+    SELECT normalize_name("group") AS normalized, btrim("group") AS spelling, count(*) AS uses
+    FROM songs
+    WHERE normalize_name("group") <> ''
+    GROUP BY 1, 2
+) spellings
+ORDER BY normalized, uses DESC, spelling;
+
+ALTER TABLE songs ADD COLUMN artist_id bigint REFERENCES artists (id) ON DELETE RESTRICT;
+
+UPDATE songs SET artist_id = artists.id
+FROM artists
+WHERE normalize_name(artists.name) = normalize_name(songs."group");
+
+DROP INDEX IF EXISTS songs_group_trgm_idx;
+DROP INDEX IF EXISTS songs_group_song_idx;
+ALTER TABLE songs DROP COLUMN "group";
+
+CREATE INDEX songs_artist_id_song_idx ON songs (artist_id, song);
@@ -0,0 +1,21 @@
+CREATE TABLE albums (
+    id bigserial PRIMARY KEY,
+    title text NOT NULL,
+    artist_id bigint REFERENCES artists (id) ON DELETE RESTRICT,
+    released_on date,
+    release_precision text CONSTRAINT albums_release_precision_check CHECK (release_precision IN ('year', 'month', 'day')),
+    type text NOT NULL DEFAULT 'lp' CONSTRAINT albums_type_check CHECK (type IN ('lp', 'ep', 'single', 'compilation'))
+);
+
+CREATE INDEX albums_artist_id_idx ON albums (artist_id);
+CREATE INDEX albums_title_idx ON albums (normalize_name(title));
+
+CREATE TABLE album_tracks (
+    album_id bigint NOT NULL REFERENCES albums (id) ON DELETE CASCADE,
+    disc_number integer NOT NULL DEFAULT 1 CHECK (disc_number > 0),
+    track_number integer NOT NULL CHECK (track_number > 0),
+    song_id bigint NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    PRIMARY KEY (album_id, disc_number, track_number)
+);
+
+CREATE INDEX album_tracks_song_id_idx ON album_tracks (song_id);
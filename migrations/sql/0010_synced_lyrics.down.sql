@@ -0,0 +1 @@
+ALTER TABLE song_details DROP COLUMN synced_lyrics;
@@ -0,0 +1 @@
+ALTER TABLE song_details ADD COLUMN synced_lyrics jsonb;
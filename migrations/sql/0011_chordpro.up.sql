@@ -0,0 +1 @@
+ALTER TABLE song_details ADD COLUMN chordpro text NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+DROP TABLE lyric_versions;
@@ -0,0 +1,10 @@
+CREATE TABLE lyric_versions (
+    id bigserial PRIMARY KEY,
+    song_id bigint NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    language text NOT NULL,
+    kind text NOT NULL CONSTRAINT lyric_versions_kind_check CHECK (kind IN ('original', 'translation', 'transliteration')),
+    text text NOT NULL,
+    sections jsonb NOT NULL DEFAULT '[]',
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT lyric_versions_song_language_kind_key UNIQUE (song_id, language, kind)
+);
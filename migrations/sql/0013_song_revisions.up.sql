@@ -0,0 +1,27 @@
+CREATE TABLE song_revisions (
+    id bigserial PRIMARY KEY,
+    song_id bigint NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    revision integer NOT NULL,
+    author text NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    changes jsonb NOT NULL DEFAULT '[]',
+    snapshot jsonb NOT NULL,
+    restored_from integer,
+    CONSTRAINT song_revisions_song_revision_key UNIQUE (song_id, revision)
+);
+
+-- текущее состояние уже сохраненных песен становится их первой правкой
+INSERT INTO song_revisions (song_id, revision, author, snapshot)
+SELECT songs.id, 1, 'migration', jsonb_build_object(
+    'group', coalesce(artists.name, ''),
+    'song', songs.song,
+    'text', coalesce(song_details.text, ''),
+    'link', coalesce(song_details.link, ''),
+    'releaseDate', CASE song_details.release_precision
+        WHEN 'year' THEN to_char(song_details.released_on, 'YYYY')
+        WHEN 'month' THEN to_char(song_details.released_on, 'MM.YYYY')
+        WHEN 'day' THEN to_char(song_details.released_on, 'DD.MM.YYYY')
+        ELSE '' END)
+FROM songs
+LEFT JOIN artists ON artists.id = songs.artist_id
+LEFT JOIN song_details ON song_details.song_id = songs.id;
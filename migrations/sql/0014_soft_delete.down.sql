@@ -0,0 +1,5 @@
+DELETE FROM songs WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE songs
+    DROP COLUMN deleted_by,
+    DROP COLUMN deleted_at;
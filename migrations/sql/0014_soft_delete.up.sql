@@ -0,0 +1,5 @@
+ALTER TABLE songs
+    ADD COLUMN deleted_at timestamptz,
+    ADD COLUMN deleted_by text NOT NULL DEFAULT '';
+
+CREATE INDEX songs_deleted_at_idx ON songs (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,2 @@
+-- Версия песни увеличивается при каждом изменении и используется для ETag и If-Match.
+ALTER TABLE songs ADD COLUMN version integer NOT NULL DEFAULT 1;
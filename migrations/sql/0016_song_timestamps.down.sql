@@ -0,0 +1,3 @@
+ALTER TABLE songs
+    DROP COLUMN created_at,
+    DROP COLUMN updated_at;
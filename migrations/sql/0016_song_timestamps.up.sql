@@ -0,0 +1,11 @@
+ALTER TABLE songs
+    ADD COLUMN created_at timestamptz NOT NULL DEFAULT now(),
+    ADD COLUMN updated_at timestamptz NOT NULL DEFAULT now();
+
+-- время добавления и последнего изменения существующих песен берется из истории правок
+UPDATE songs SET created_at = r.first, updated_at = r.last
+FROM (SELECT song_id, min(created_at) AS first, max(created_at) AS last FROM song_revisions GROUP BY song_id) r
+WHERE r.song_id = songs.id;
+
+CREATE INDEX songs_created_at_idx ON songs (created_at, id);
+CREATE INDEX songs_updated_at_idx ON songs (updated_at, id);
@@ -0,0 +1,24 @@
+CREATE TABLE playlists (
+    id bigserial PRIMARY KEY,
+    name text NOT NULL,
+    description text NOT NULL DEFAULT '',
+    owner text NOT NULL,
+    visibility text NOT NULL DEFAULT 'private' CONSTRAINT playlists_visibility_check CHECK (visibility IN ('private', 'unlisted', 'public')),
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX playlists_owner_idx ON playlists (owner);
+CREATE INDEX playlists_public_idx ON playlists (id) WHERE visibility = 'public';
+
+-- позиции записей идут подряд с 1, уникальность проверяется в конце транзакции,
+-- чтобы записи можно было переставлять по одной
+CREATE TABLE playlist_entries (
+    id bigserial PRIMARY KEY,
+    playlist_id bigint NOT NULL REFERENCES playlists (id) ON DELETE CASCADE,
+    song_id bigint NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    position integer NOT NULL CHECK (position > 0),
+    CONSTRAINT playlist_entries_position_key UNIQUE (playlist_id, position) DEFERRABLE INITIALLY DEFERRED
+);
+
+CREATE INDEX playlist_entries_song_id_idx ON playlist_entries (song_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS setlist_items;
+DROP TABLE IF EXISTS setlist_sets;
+DROP TABLE IF EXISTS setlists;
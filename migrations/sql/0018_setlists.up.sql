@@ -0,0 +1,36 @@
+CREATE TABLE setlists (
+    id bigserial PRIMARY KEY,
+    name text NOT NULL,
+    venue text NOT NULL DEFAULT '',
+    notes text NOT NULL DEFAULT '',
+    owner text NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX setlists_owner_idx ON setlists (owner);
+
+-- сеты и их песни заменяются целиком при каждом изменении сет-листа
+CREATE TABLE setlist_sets (
+    id bigserial PRIMARY KEY,
+    setlist_id bigint NOT NULL REFERENCES setlists (id) ON DELETE CASCADE,
+    position integer NOT NULL CHECK (position > 0),
+    name text NOT NULL DEFAULT '',
+    encore boolean NOT NULL DEFAULT false,
+    CONSTRAINT setlist_sets_position_key UNIQUE (setlist_id, position)
+);
+
+CREATE TABLE setlist_items (
+    id bigserial PRIMARY KEY,
+    set_id bigint NOT NULL REFERENCES setlist_sets (id) ON DELETE CASCADE,
+    position integer NOT NULL CHECK (position > 0),
+    song_id bigint NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    duration integer NOT NULL DEFAULT 0 CHECK (duration >= 0),
+    key text NOT NULL DEFAULT '',
+    tempo integer NOT NULL DEFAULT 0 CHECK (tempo >= 0),
+    notes text NOT NULL DEFAULT '',
+    segue boolean NOT NULL DEFAULT false,
+    CONSTRAINT setlist_items_position_key UNIQUE (set_id, position)
+);
+
+CREATE INDEX setlist_items_song_id_idx ON setlist_items (song_id);
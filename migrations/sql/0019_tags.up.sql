@@ -0,0 +1,15 @@
+CREATE TABLE tags (
+    id bigserial PRIMARY KEY,
+    namespace text NOT NULL,
+    name text NOT NULL,
+    CONSTRAINT tags_namespace_name_key UNIQUE (namespace, name)
+);
+
+CREATE TABLE song_tags (
+    song_id bigint NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    tag_id bigint NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    PRIMARY KEY (song_id, tag_id)
+);
+
+-- фильтр и количество песен по тегу
+CREATE INDEX song_tags_tag_id_idx ON song_tags (tag_id, song_id);
@@ -0,0 +1,42 @@
+package models
+
+// AlbumType — вид релиза.
+type AlbumType string
+
+const (
+	AlbumLP          AlbumType = "lp"
+	AlbumEP          AlbumType = "ep"
+	AlbumSingle      AlbumType = "single"
+	AlbumCompilation AlbumType = "compilation"
+)
+
+// Valid сообщает, что вид релиза известен.
+func (t AlbumType) Valid() bool {
+	switch t {
+	case AlbumLP, AlbumEP, AlbumSingle, AlbumCompilation:
+		return true
+	}
+	return false
+}
+
+// Album представляет собой модель альбома.
+// @Description Модель альбома
+type Album struct {
+	Id          int         `json:"id" swaggerignore:"true" gorm:"primaryKey"`
+	Title       string      `json:"title" example:"Black Holes and Revelations"`                           //Название
+	ArtistId    *int        `json:"artistId,omitempty" example:"1"`                                        //ID исполнителя, у сборников может отсутствовать
+	Artist      string      `json:"artist" swaggerignore:"true" gorm:"->"`                                 //Название исполнителя
+	ReleaseDate ReleaseDate `json:"releaseDate" gorm:"embedded" swaggertype:"string" example:"03.07.2006"` //Дата выхода: DD.MM.YYYY, MM.YYYY или YYYY
+	Type        AlbumType   `json:"type" example:"lp" enums:"lp,ep,single,compilation"`                    //Вид релиза
+}
+
+// AlbumTrack представляет собой позицию песни в альбоме.
+// @Description Позиция песни в альбоме
+type AlbumTrack struct {
+	AlbumId     int    `json:"albumId" swaggerignore:"true" gorm:"primaryKey;autoIncrement:false"`
+	DiscNumber  int    `json:"disc" gorm:"primaryKey;autoIncrement:false" example:"1"`  //Номер диска, по умолчанию 1
+	TrackNumber int    `json:"track" gorm:"primaryKey;autoIncrement:false" example:"3"` //Номер трека на диске
+	SongId      int    `json:"songId" example:"1"`
+	Song        *Song  `json:"song,omitempty" swaggerignore:"true" gorm:"-"`
+	Album       *Album `json:"album,omitempty" swaggerignore:"true" gorm:"-"`
+}
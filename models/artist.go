@@ -0,0 +1,54 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Artist представляет собой модель исполнителя (группы).
+// @Description Модель исполнителя
+type Artist struct {
+	Id         int        `json:"id" swaggerignore:"true" gorm:"primaryKey"`
+	Name       string     `json:"name" example:"Muse"`                                  //Название
+	Aliases    StringList `json:"aliases" gorm:"type:jsonb" swaggertype:"array,string"` //Другие написания названия
+	Country    string     `json:"country" example:"GB"`                                 //Страна
+	FormedYear *int       `json:"formedYear,omitempty" example:"1994"`                  //Год основания
+	Members    StringList `json:"members" gorm:"type:jsonb" swaggertype:"array,string"` //Участники
+}
+
+// StringList — список строк, который хранится в базе данных как JSON массив.
+type StringList []string
+
+// Scan читает список из JSON массива.
+func (l *StringList) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*l = StringList{}
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("неподдерживаемый тип списка строк %T", value)
+	}
+	return json.Unmarshal(data, l)
+}
+
+// Value записывает список как JSON массив, пустой список записывается как [].
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal([]string(l))
+	return string(data), err
+}
+
+func (l StringList) MarshalJSON() ([]byte, error) {
+	if l == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]string(l))
+}
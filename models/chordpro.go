@@ -0,0 +1,464 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// ChordLineType — вид строки листа аккордов.
+type ChordLineType string
+
+const (
+	ChordLineLyrics       ChordLineType = "lyrics"        //строка текста с аккордами
+	ChordLineComment      ChordLineType = "comment"       //комментарий {comment}
+	ChordLineSectionStart ChordLineType = "section_start" //начало части {start_of_chorus}
+	ChordLineSectionEnd   ChordLineType = "section_end"   //конец части {end_of_chorus}
+	ChordLineChorus       ChordLineType = "chorus"        //повтор припева {chorus}
+	ChordLineTab          ChordLineType = "tab"           //строка табулатуры, выводится как есть
+	ChordLineEmpty        ChordLineType = "empty"
+)
+
+// PlacedChord — аккорд над позицией в строке текста.
+// @Description Аккорд над позицией в строке текста
+type PlacedChord struct {
+	Column int    `json:"column" example:"4"` //Позиция символа в строке текста, начиная с 0
+	Chord  string `json:"chord" example:"Am7"`
+}
+
+// ChordLine представляет собой строку листа аккордов.
+// @Description Строка листа аккордов
+type ChordLine struct {
+	Type    ChordLineType `json:"type" example:"lyrics" enums:"lyrics,comment,section_start,section_end,chorus,tab,empty"`
+	Section string        `json:"section,omitempty" example:"chorus"` //Вид части для section_start и section_end
+	Text    string        `json:"text,omitempty"`                     //Текст строки, комментария или название части
+	Chords  []PlacedChord `json:"chords,omitempty"`
+}
+
+// ChordDirective — директива метаданных ChordPro, например {title: Hysteria}.
+type ChordDirective struct {
+	Name  string `json:"name" example:"key"`
+	Value string `json:"value" example:"Am"`
+}
+
+// ChordSheet представляет собой лист аккордов, разобранный из ChordPro.
+// @Description Лист аккордов
+type ChordSheet struct {
+	Meta  []ChordDirective `json:"meta"`
+	Lines []ChordLine      `json:"lines"`
+}
+
+// ChordProError возвращается, если документ ChordPro не удалось разобрать.
+type ChordProError struct {
+	Line   int //номер строки, начиная с 1
+	Column int //номер символа в строке, начиная с 1
+	Msg    string
+}
+
+func (e *ChordProError) Error() string {
+	return fmt.Sprintf("строка %d, символ %d: %s", e.Line, e.Column, e.Msg)
+}
+
+// chordProAliases сопоставляет короткие названия директив полным.
+var chordProAliases = map[string]string{
+	"t": "title", "st": "subtitle", "c": "comment", "ci": "comment_italic", "cb": "comment_box",
+	"soc": "start_of_chorus", "eoc": "end_of_chorus", "sov": "start_of_verse", "eov": "end_of_verse",
+	"sob": "start_of_bridge", "eob": "end_of_bridge", "sot": "start_of_tab", "eot": "end_of_tab",
+	"np": "new_page",
+}
+
+// chordProMeta — директивы метаданных, у которых обязательно значение.
+var chordProMeta = map[string]bool{
+	"title": true, "subtitle": true, "artist": true, "composer": true, "lyricist": true, "album": true,
+	"year": true, "key": true, "capo": true, "tempo": true, "time": true, "duration": true, "meta": true,
+}
+
+// chordProComments — директивы комментариев.
+var chordProComments = map[string]bool{"comment": true, "comment_italic": true, "comment_box": true, "highlight": true}
+
+// chordProSections — части, у которых есть директивы start_of_ и end_of_.
+var chordProSections = map[string]bool{"chorus": true, "verse": true, "bridge": true, "tab": true}
+
+// ParseChordPro разбирает документ ChordPro. Аккорды записываются в квадратных скобках внутри текста,
+// директивы — в фигурных скобках на отдельной строке. Ошибки содержат строку и символ.
+func ParseChordPro(doc string) (ChordSheet, error) {
+	sheet := ChordSheet{Meta: []ChordDirective{}, Lines: []ChordLine{}}
+	section, sectionLine := "", 0
+
+	for i, raw := range strings.Split(strings.ReplaceAll(doc, "\r\n", "\n"), "\n") {
+		lineNo := i + 1
+		raw = strings.TrimRight(raw, " \t")
+		if i == 0 {
+			raw = strings.TrimPrefix(raw, "\ufeff")
+		}
+		trimmed := strings.TrimLeft(raw, " \t")
+		indent := utf8.RuneCountInString(raw) - utf8.RuneCountInString(trimmed)
+
+		if strings.HasPrefix(trimmed, "#") { //комментарий самого файла
+			continue
+		}
+		if strings.HasPrefix(trimmed, "{") {
+			name, value, err := parseDirective(trimmed)
+			if err != nil {
+				return ChordSheet{}, &ChordProError{Line: lineNo, Column: indent + err.Column, Msg: err.Msg}
+			}
+			column := indent + 2
+
+			switch {
+			case chordProMeta[name]:
+				if value == "" {
+					return ChordSheet{}, &ChordProError{Line: lineNo, Column: column, Msg: fmt.Sprintf("у директивы %s нет значения", name)}
+				}
+				if name == "key" {
+					if _, ok := ParseChord(value); !ok {
+						return ChordSheet{}, &ChordProError{Line: lineNo, Column: column, Msg: fmt.Sprintf("некоректная тональность %q", value)}
+					}
+				}
+				sheet.Meta = append(sheet.Meta, ChordDirective{Name: name, Value: value})
+			case chordProComments[name]:
+				sheet.Lines = append(sheet.Lines, ChordLine{Type: ChordLineComment, Text: value})
+			case name == "chorus":
+				sheet.Lines = append(sheet.Lines, ChordLine{Type: ChordLineChorus, Text: value})
+			case name == "new_page" || strings.HasPrefix(name, "x_"):
+				//оформление и пользовательские директивы не влияют на лист аккордов
+			case strings.HasPrefix(name, "start_of_") && chordProSections[strings.TrimPrefix(name, "start_of_")]:
+				if section != "" {
+					return ChordSheet{}, &ChordProError{Line: lineNo, Column: column, Msg: fmt.Sprintf("часть %s, начатая в строке %d, не закончена", section, sectionLine)}
+				}
+				section, sectionLine = strings.TrimPrefix(name, "start_of_"), lineNo
+				sheet.Lines = append(sheet.Lines, ChordLine{Type: ChordLineSectionStart, Section: section, Text: value})
+			case strings.HasPrefix(name, "end_of_") && chordProSections[strings.TrimPrefix(name, "end_of_")]:
+				if section != strings.TrimPrefix(name, "end_of_") {
+					return ChordSheet{}, &ChordProError{Line: lineNo, Column: column, Msg: fmt.Sprintf("директива %s без начала части", name)}
+				}
+				sheet.Lines = append(sheet.Lines, ChordLine{Type: ChordLineSectionEnd, Section: section})
+				section = ""
+			default:
+				return ChordSheet{}, &ChordProError{Line: lineNo, Column: column, Msg: fmt.Sprintf("неизвестная директива %s", name)}
+			}
+			continue
+		}
+
+		switch {
+		case section == "tab":
+			sheet.Lines = append(sheet.Lines, ChordLine{Type: ChordLineTab, Text: raw})
+		case trimmed == "":
+			sheet.Lines = append(sheet.Lines, ChordLine{Type: ChordLineEmpty})
+		default:
+			line, err := parseChordLine(raw)
+			if err != nil {
+				err.Line = lineNo
+				return ChordSheet{}, err
+			}
+			sheet.Lines = append(sheet.Lines, line)
+		}
+	}
+
+	if section != "" {
+		return ChordSheet{}, &ChordProError{Line: sectionLine, Column: 1, Msg: fmt.Sprintf("часть %s не закончена", section)}
+	}
+	return sheet, nil
+}
+
+// parseDirective разбирает директиву {name: value} или {name value}. Column ошибки отсчитывается от её начала.
+func parseDirective(s string) (string, string, *ChordProError) {
+	end := strings.Index(s, "}")
+	if end < 0 {
+		return "", "", &ChordProError{Column: utf8.RuneCountInString(s) + 1, Msg: "директива не закрыта символом }"}
+	}
+	if rest := strings.TrimSpace(s[end+1:]); rest != "" {
+		return "", "", &ChordProError{Column: utf8.RuneCountInString(s[:end+1]) + 1, Msg: "текст после директивы"}
+	}
+
+	body := s[1:end]
+	name, value, found := strings.Cut(body, ":")
+	if !found {
+		name, value, _ = strings.Cut(body, " ")
+	}
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return "", "", &ChordProError{Column: 2, Msg: "у директивы нет названия"}
+	}
+	if full, ok := chordProAliases[name]; ok {
+		name = full
+	}
+	return name, strings.TrimSpace(value), nil
+}
+
+// parseChordLine выделяет аккорды в квадратных скобках из строки текста.
+func parseChordLine(raw string) (ChordLine, *ChordProError) {
+	line := ChordLine{Type: ChordLineLyrics}
+	var text strings.Builder
+	column, textColumn := 0, 0
+
+	for rest := raw; rest != ""; {
+		r, size := utf8.DecodeRuneInString(rest)
+		column++
+		switch r {
+		case '[':
+			end := strings.IndexAny(rest[1:], "[]")
+			if end < 0 || rest[1+end] != ']' {
+				return ChordLine{}, &ChordProError{Column: column, Msg: "аккорд не закрыт символом ]"}
+			}
+			chord := rest[1 : 1+end]
+			if !validChordToken(chord) {
+				return ChordLine{}, &ChordProError{Column: column + 1, Msg: fmt.Sprintf("некоректный аккорд %q", chord)}
+			}
+			line.Chords = append(line.Chords, PlacedChord{Column: textColumn, Chord: chord})
+			column += utf8.RuneCountInString(chord) + 1
+			rest = rest[end+2:]
+			continue
+		case ']':
+			return ChordLine{}, &ChordProError{Column: column, Msg: "лишний символ ]"}
+		}
+		text.WriteRune(r)
+		textColumn++
+		rest = rest[size:]
+	}
+
+	line.Text = strings.TrimRight(text.String(), " \t")
+	return line, nil
+}
+
+// validChordToken допускает аккорды, пометки вида [*Riff] и обозначение паузы N.C.
+func validChordToken(s string) bool {
+	if strings.HasPrefix(s, "*") || s == "N.C." || s == "NC" || s == "x" {
+		return s != "*"
+	}
+	_, ok := ParseChord(s)
+	return ok
+}
+
+// Notation — запись аккордов после транспонирования.
+type Notation string
+
+const (
+	NotationSharp     Notation = "sharp"     //C#, F#
+	NotationFlat      Notation = "flat"      //Db, Gb
+	NotationNashville Notation = "nashville" //ступени относительно тональности: 1, 4, 5, 6m
+)
+
+var (
+	sharpNames = []string{"C", "C#", "D", "D#", "E", "F", "F#", "G", "G#", "A", "A#", "B"}
+	flatNames  = []string{"C", "Db", "D", "Eb", "E", "F", "Gb", "G", "Ab", "A", "Bb", "B"}
+	// nashvilleDegrees — ступени по числу полутонов от тоники.
+	nashvilleDegrees = []string{"1", "b2", "2", "b3", "3", "4", "#4", "5", "b6", "6", "b7", "7"}
+	noteIndex        = map[string]int{
+		"C": 0, "B#": 0, "C#": 1, "Db": 1, "D": 2, "D#": 3, "Eb": 3, "E": 4, "Fb": 4, "E#": 5, "F": 5,
+		"F#": 6, "Gb": 6, "G": 7, "G#": 8, "Ab": 8, "A": 9, "A#": 10, "Bb": 10, "B": 11, "Cb": 11,
+	}
+	chordPattern  = regexp.MustCompile(`^([A-G][#b]?)([^/]*)(?:/([A-G][#b]?))?$`)
+	chordSuffixes = regexp.MustCompile(`^[a-zA-Z0-9()+#°ø\-]*$`)
+)
+
+// Chord — аккорд: основной тон, обозначение вида и бас.
+type Chord struct {
+	Root   string
+	Suffix string
+	Bass   string
+}
+
+// ParseChord разбирает аккорд вида Am7, F#sus4 или G/B.
+func ParseChord(s string) (Chord, bool) {
+	match := chordPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if match == nil || !chordSuffixes.MatchString(match[2]) {
+		return Chord{}, false
+	}
+	return Chord{Root: match[1], Suffix: match[2], Bass: match[3]}, true
+}
+
+// Minor сообщает, что аккорд минорный.
+func (c Chord) Minor() bool {
+	return strings.HasPrefix(c.Suffix, "m") && !strings.HasPrefix(c.Suffix, "maj")
+}
+
+func (c Chord) String() string {
+	if c.Bass != "" {
+		return c.Root + c.Suffix + "/" + c.Bass
+	}
+	return c.Root + c.Suffix
+}
+
+// transposeNote сдвигает ноту на semitones полутонов. Без явной записи сохраняется запись исходной ноты.
+func transposeNote(note string, semitones int, notation Notation) string {
+	index := (noteIndex[note] + semitones%12 + 12) % 12
+	if notation == NotationFlat || notation == "" && strings.HasSuffix(note, "b") {
+		return flatNames[index]
+	}
+	return sharpNames[index]
+}
+
+// Transpose сдвигает аккорд на semitones полутонов.
+func (c Chord) Transpose(semitones int, notation Notation) Chord {
+	c.Root = transposeNote(c.Root, semitones, notation)
+	if c.Bass != "" {
+		c.Bass = transposeNote(c.Bass, semitones, notation)
+	}
+	return c
+}
+
+// Nashville записывает аккорд ступенями относительно тоники тональности.
+func (c Chord) Nashville(key Chord) string {
+	degree := func(note string) string {
+		return nashvilleDegrees[(noteIndex[note]-noteIndex[key.Root]+12)%12]
+	}
+	result := degree(c.Root) + c.Suffix
+	if c.Suffix != "" && c.Suffix[0] >= '0' && c.Suffix[0] <= '9' { //5(7sus4), а не 57sus4
+		result = degree(c.Root) + "(" + c.Suffix + ")"
+	}
+	if c.Bass != "" {
+		result += "/" + degree(c.Bass)
+	}
+	return result
+}
+
+// Key возвращает тональность из директивы {key}.
+func (s ChordSheet) Key() (Chord, bool) {
+	for _, meta := range s.Meta {
+		if meta.Name == "key" {
+			return ParseChord(meta.Value)
+		}
+	}
+	return Chord{}, false
+}
+
+// ErrNoKey возвращается при записи nashville для листа без тональности.
+var ErrNoKey = errors.New("для записи nashville нужна директива {key}")
+
+// Transpose возвращает лист, в котором аккорды и тональность сдвинуты на semitones полутонов
+// и записаны в notation. Пустая notation сохраняет запись каждого аккорда. Для nashville
+// аккорды записываются ступенями, сдвиг на них не влияет.
+func (s ChordSheet) Transpose(semitones int, notation Notation) (ChordSheet, error) {
+	convert := func(chord Chord) string { return chord.Transpose(semitones, notation).String() }
+	if notation == NotationNashville {
+		key, ok := s.Key()
+		if !ok {
+			return ChordSheet{}, ErrNoKey
+		}
+		convert = func(chord Chord) string { return chord.Nashville(key) }
+	}
+
+	result := ChordSheet{Meta: make([]ChordDirective, len(s.Meta)), Lines: make([]ChordLine, len(s.Lines))}
+	for i, meta := range s.Meta {
+		if key, ok := ParseChord(meta.Value); ok && meta.Name == "key" && notation != NotationNashville {
+			meta.Value = key.Transpose(semitones, notation).String()
+		}
+		result.Meta[i] = meta
+	}
+	for i, line := range s.Lines {
+		if line.Chords != nil {
+			chords := make([]PlacedChord, len(line.Chords))
+			for j, placed := range line.Chords {
+				if chord, ok := ParseChord(placed.Chord); ok {
+					placed.Chord = convert(chord)
+				}
+				chords[j] = placed
+			}
+			line.Chords = chords
+		}
+		result.Lines[i] = line
+	}
+	return result, nil
+}
+
+// ChordPro записывает лист в формате ChordPro.
+func (s ChordSheet) ChordPro() string {
+	var b strings.Builder
+	for _, meta := range s.Meta {
+		fmt.Fprintf(&b, "{%s: %s}\n", meta.Name, meta.Value)
+	}
+	for _, line := range s.Lines {
+		switch line.Type {
+		case ChordLineLyrics:
+			text := []rune(line.Text)
+			last := 0
+			for _, placed := range line.Chords {
+				column := min(placed.Column, len(text))
+				b.WriteString(string(text[last:column]))
+				b.WriteString("[" + placed.Chord + "]")
+				last = column
+			}
+			b.WriteString(string(text[last:]))
+		case ChordLineComment:
+			fmt.Fprintf(&b, "{comment: %s}", line.Text)
+		case ChordLineChorus:
+			b.WriteString(directive("chorus", line.Text))
+		case ChordLineSectionStart:
+			b.WriteString(directive("start_of_"+line.Section, line.Text))
+		case ChordLineSectionEnd:
+			b.WriteString(directive("end_of_"+line.Section, ""))
+		case ChordLineTab:
+			b.WriteString(line.Text)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func directive(name, value string) string {
+	if value == "" {
+		return "{" + name + "}"
+	}
+	return "{" + name + ": " + value + "}"
+}
+
+// PlainText записывает лист обычным текстом: строка аккордов над строкой текста.
+func (s ChordSheet) PlainText() string {
+	var b strings.Builder
+	for _, meta := range s.Meta {
+		switch meta.Name {
+		case "title", "artist":
+			b.WriteString(meta.Value + "\n")
+		case "key", "capo", "tempo":
+			fmt.Fprintf(&b, "%s: %s\n", meta.Name, meta.Value)
+		}
+	}
+	if len(s.Meta) > 0 {
+		b.WriteString("\n")
+	}
+
+	for _, line := range s.Lines {
+		switch line.Type {
+		case ChordLineLyrics:
+			if len(line.Chords) > 0 {
+				b.WriteString(chordRow(line.Chords) + "\n")
+			}
+			if line.Text != "" || len(line.Chords) == 0 {
+				b.WriteString(line.Text + "\n")
+			}
+		case ChordLineComment:
+			b.WriteString("(" + line.Text + ")\n")
+		case ChordLineChorus:
+			b.WriteString("[Chorus]\n")
+		case ChordLineSectionStart:
+			label := line.Text
+			if label == "" {
+				label = strings.ToUpper(line.Section[:1]) + line.Section[1:]
+			}
+			b.WriteString("[" + label + "]\n")
+		case ChordLineTab:
+			b.WriteString(line.Text + "\n")
+		case ChordLineEmpty:
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// chordRow расставляет аккорды по позициям, сдвигая вправо те, что налезают на предыдущий.
+func chordRow(chords []PlacedChord) string {
+	var row []rune
+	for _, placed := range chords {
+		column := placed.Column
+		if len(row) > 0 && column <= len(row) {
+			column = len(row) + 1
+		}
+		for len(row) < column {
+			row = append(row, ' ')
+		}
+		row = append(row, []rune(placed.Chord)...)
+	}
+	return string(row)
+}
@@ -0,0 +1,198 @@
+package models
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseChordPro(t *testing.T) {
+	doc := "{title: Hysteria}\r\n{key: Am}\n# комментарий файла\n{soc: Chorus}\n[Am]It's bugging me [G/B]grating me\n{eoc}\n\n" +
+		"{c: Solo}\n{sot}\ne|--0--|\n{eot}\n{chorus}\n{x_custom: y}\n{np}"
+
+	want := ChordSheet{
+		Meta: []ChordDirective{{Name: "title", Value: "Hysteria"}, {Name: "key", Value: "Am"}},
+		Lines: []ChordLine{
+			{Type: ChordLineSectionStart, Section: "chorus", Text: "Chorus"},
+			{Type: ChordLineLyrics, Text: "It's bugging me grating me", Chords: []PlacedChord{{Column: 0, Chord: "Am"}, {Column: 16, Chord: "G/B"}}},
+			{Type: ChordLineSectionEnd, Section: "chorus"},
+			{Type: ChordLineEmpty},
+			{Type: ChordLineComment, Text: "Solo"},
+			{Type: ChordLineSectionStart, Section: "tab"},
+			{Type: ChordLineTab, Text: "e|--0--|"},
+			{Type: ChordLineSectionEnd, Section: "tab"},
+			{Type: ChordLineChorus},
+		},
+	}
+
+	got, err := ParseChordPro(doc)
+	if err != nil {
+		t.Fatalf("ParseChordPro() ошибка = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseChordPro() = %+v, ожидалось %+v", got, want)
+	}
+}
+
+func TestParseChordProErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want ChordProError
+	}{
+		{name: "директива без значения", doc: "{title}", want: ChordProError{Line: 1, Column: 2, Msg: "у директивы title нет значения"}},
+		{name: "некоректная тональность", doc: "{key: H}", want: ChordProError{Line: 1, Column: 2, Msg: `некоректная тональность "H"`}},
+		{name: "неизвестная директива", doc: "la\n{foo: x}", want: ChordProError{Line: 2, Column: 2, Msg: "неизвестная директива foo"}},
+		{name: "директива не закрыта", doc: "  {title: x", want: ChordProError{Line: 1, Column: 12, Msg: "директива не закрыта символом }"}},
+		{name: "текст после директивы", doc: "{t: a} b", want: ChordProError{Line: 1, Column: 7, Msg: "текст после директивы"}},
+		{name: "директива без названия", doc: "{: a}", want: ChordProError{Line: 1, Column: 2, Msg: "у директивы нет названия"}},
+		{name: "вложенная часть", doc: "{soc}\n{sov}", want: ChordProError{Line: 2, Column: 2, Msg: "часть chorus, начатая в строке 1, не закончена"}},
+		{name: "конец без начала", doc: "{sov}\n{eoc}", want: ChordProError{Line: 2, Column: 2, Msg: "директива end_of_chorus без начала части"}},
+		{name: "незаконченная часть", doc: "{soc}\nla", want: ChordProError{Line: 1, Column: 1, Msg: "часть chorus не закончена"}},
+		{name: "аккорд не закрыт", doc: "Hello [Am", want: ChordProError{Line: 1, Column: 7, Msg: "аккорд не закрыт символом ]"}},
+		{name: "некоректный аккорд", doc: "Hello [H]", want: ChordProError{Line: 1, Column: 8, Msg: `некоректный аккорд "H"`}},
+		{name: "лишняя скобка", doc: "Hello ]", want: ChordProError{Line: 1, Column: 7, Msg: "лишний символ ]"}},
+		{name: "символы после аккорда", doc: "Привет [Am] [X", want: ChordProError{Line: 1, Column: 13, Msg: "аккорд не закрыт символом ]"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseChordPro(tt.doc)
+			var chordErr *ChordProError
+			if !errors.As(err, &chordErr) || *chordErr != tt.want {
+				t.Errorf("ParseChordPro() ошибка = %v, ожидалась %v", err, &tt.want)
+			}
+		})
+	}
+}
+
+func TestParseChord(t *testing.T) {
+	tests := []struct {
+		s      string
+		want   Chord
+		wantOk bool
+	}{
+		{s: "Am7", want: Chord{Root: "A", Suffix: "m7"}, wantOk: true},
+		{s: "F#sus4", want: Chord{Root: "F#", Suffix: "sus4"}, wantOk: true},
+		{s: "Bb/D", want: Chord{Root: "Bb", Bass: "D"}, wantOk: true},
+		{s: "C7(b9)", want: Chord{Root: "C", Suffix: "7(b9)"}, wantOk: true},
+		{s: "H"},
+		{s: "Am/X"},
+		{s: "C$"},
+		{s: ""},
+	}
+	for _, tt := range tests {
+		got, ok := ParseChord(tt.s)
+		if ok != tt.wantOk || got != tt.want {
+			t.Errorf("ParseChord(%q) = %+v, %v, ожидалось %+v, %v", tt.s, got, ok, tt.want, tt.wantOk)
+		}
+	}
+}
+
+func TestChordTranspose(t *testing.T) {
+	tests := []struct {
+		chord     string
+		semitones int
+		notation  Notation
+		want      string
+	}{
+		{chord: "Am", semitones: 2, want: "Bm"},
+		{chord: "C", semitones: 1, want: "C#"},
+		{chord: "C", semitones: 1, notation: NotationFlat, want: "Db"},
+		{chord: "Db", semitones: 2, want: "Eb"},
+		{chord: "Bb", semitones: 0, notation: NotationSharp, want: "A#"},
+		{chord: "G/B", semitones: -2, want: "F/A"},
+		{chord: "F#m7", semitones: 13, want: "Gm7"},
+		{chord: "A", semitones: -14, want: "G"},
+		{chord: "Cb", semitones: 1, notation: NotationSharp, want: "C"},
+	}
+	for _, tt := range tests {
+		chord, _ := ParseChord(tt.chord)
+		if got := chord.Transpose(tt.semitones, tt.notation).String(); got != tt.want {
+			t.Errorf("Transpose(%s, %d, %q) = %s, ожидалось %s", tt.chord, tt.semitones, tt.notation, got, tt.want)
+		}
+	}
+}
+
+func TestChordNashville(t *testing.T) {
+	tests := []struct {
+		key, chord, want string
+	}{
+		{key: "C", chord: "C", want: "1"},
+		{key: "C", chord: "Am", want: "6m"},
+		{key: "C", chord: "G7", want: "5(7)"},
+		{key: "C", chord: "F/A", want: "4/6"},
+		{key: "C", chord: "Bb", want: "b7"},
+		{key: "C", chord: "F#dim", want: "#4dim"},
+		{key: "Am", chord: "C", want: "b3"},
+		{key: "Eb", chord: "D#", want: "1"},
+	}
+	for _, tt := range tests {
+		key, _ := ParseChord(tt.key)
+		chord, _ := ParseChord(tt.chord)
+		if got := chord.Nashville(key); got != tt.want {
+			t.Errorf("Nashville(%s в %s) = %s, ожидалось %s", tt.chord, tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestChordSheetTranspose(t *testing.T) {
+	sheet, err := ParseChordPro("{key: Am}\n[Am]La [G/B]la [*Riff]\n{c: [Am]}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		semitones int
+		notation  Notation
+		wantKey   string
+		want      []string
+	}{
+		{name: "сдвиг", semitones: 2, wantKey: "Bm", want: []string{"Bm", "A/C#", "*Riff"}},
+		{name: "бемоли", semitones: 1, notation: NotationFlat, wantKey: "Bbm", want: []string{"Bbm", "Ab/C", "*Riff"}},
+		{name: "ступени", semitones: 5, notation: NotationNashville, wantKey: "Am", want: []string{"1m", "b7/2", "*Riff"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sheet.Transpose(tt.semitones, tt.notation)
+			if err != nil {
+				t.Fatalf("Transpose() ошибка = %v", err)
+			}
+			if got.Meta[0].Value != tt.wantKey {
+				t.Errorf("тональность = %s, ожидалось %s", got.Meta[0].Value, tt.wantKey)
+			}
+			var chords []string
+			for _, placed := range got.Lines[0].Chords {
+				chords = append(chords, placed.Chord)
+			}
+			if !reflect.DeepEqual(chords, tt.want) {
+				t.Errorf("аккорды = %v, ожидалось %v", chords, tt.want)
+			}
+		})
+	}
+
+	if sheet.Lines[0].Chords[0].Chord != "Am" {
+		t.Errorf("Transpose() изменил исходный лист: %v", sheet.Lines[0].Chords)
+	}
+	if _, err := (ChordSheet{}).Transpose(0, NotationNashville); !errors.Is(err, ErrNoKey) {
+		t.Errorf("Transpose() без тональности ошибка = %v, ожидалась %v", err, ErrNoKey)
+	}
+}
+
+func TestChordSheetFormats(t *testing.T) {
+	doc := "{title: Hysteria}\n{key: Am}\n{start_of_verse: Verse 1}\n[Am]It's [G]bug[F]ging me\n[C]\n{end_of_verse}\n\n{comment: Solo}\n{chorus}"
+	sheet, err := ParseChordPro(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := sheet.ChordPro(); got != doc+"\n" {
+		t.Errorf("ChordPro() = %q, ожидалось %q", got, doc+"\n")
+	}
+
+	wantPlain := "Hysteria\nkey: Am\n\n[Verse 1]\nAm   G  F\nIt's bugging me\nC\n\n(Solo)\n[Chorus]\n"
+	if got := sheet.PlainText(); got != wantPlain {
+		t.Errorf("PlainText() = %q, ожидалось %q", got, wantPlain)
+	}
+}
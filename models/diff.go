@@ -0,0 +1,85 @@
+package models
+
+import "strings"
+
+// DiffOp — вид строки построчного сравнения.
+type DiffOp string
+
+const (
+	DiffEqual  DiffOp = "equal"
+	DiffInsert DiffOp = "insert"
+	DiffDelete DiffOp = "delete"
+)
+
+// DiffLine — строка построчного сравнения двух текстов.
+// @Description Строка построчного сравнения текстов
+type DiffLine struct {
+	Op      DiffOp `json:"op" example:"insert" enums:"equal,insert,delete"`
+	Text    string `json:"text"`
+	OldLine int    `json:"oldLine,omitempty" example:"3"` //Номер строки в старом тексте, начиная с 1
+	NewLine int    `json:"newLine,omitempty" example:"4"` //Номер строки в новом тексте, начиная с 1
+}
+
+// DiffLines сравнивает два текста построчно по наибольшей общей подпоследовательности строк.
+// Удаленные строки идут перед добавленными на том же месте.
+func DiffLines(old, new string) []DiffLine {
+	a, b := splitLines(old), splitLines(new)
+
+	// совпадающие начало и конец не участвуют в поиске подпоследовательности
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	// lcs[i][j] — длина наибольшей общей подпоследовательности midA[i:] и midB[j:]
+	lcs := make([][]int32, len(midA)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(midB)+1)
+	}
+	for i := len(midA) - 1; i >= 0; i-- {
+		for j := len(midB) - 1; j >= 0; j-- {
+			if midA[i] == midB[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	diff := make([]DiffLine, 0, len(a)+len(b))
+	for i := 0; i < prefix; i++ {
+		diff = append(diff, DiffLine{Op: DiffEqual, Text: a[i], OldLine: i + 1, NewLine: i + 1})
+	}
+	i, j := 0, 0
+	for i < len(midA) || j < len(midB) {
+		switch {
+		case i < len(midA) && j < len(midB) && midA[i] == midB[j]:
+			diff = append(diff, DiffLine{Op: DiffEqual, Text: midA[i], OldLine: prefix + i + 1, NewLine: prefix + j + 1})
+			i++
+			j++
+		case j == len(midB) || i < len(midA) && lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, DiffLine{Op: DiffDelete, Text: midA[i], OldLine: prefix + i + 1})
+			i++
+		default:
+			diff = append(diff, DiffLine{Op: DiffInsert, Text: midB[j], NewLine: prefix + j + 1})
+			j++
+		}
+	}
+	for k := 0; k < suffix; k++ {
+		oldIndex, newIndex := len(a)-suffix+k, len(b)-suffix+k
+		diff = append(diff, DiffLine{Op: DiffEqual, Text: a[oldIndex], OldLine: oldIndex + 1, NewLine: newIndex + 1})
+	}
+	return diff
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		want     []DiffLine
+	}{
+		{name: "оба пустые", want: []DiffLine{}},
+		{
+			name: "одинаковые тексты",
+			old:  "a\nb",
+			new:  "a\r\nb",
+			want: []DiffLine{
+				{Op: DiffEqual, Text: "a", OldLine: 1, NewLine: 1},
+				{Op: DiffEqual, Text: "b", OldLine: 2, NewLine: 2},
+			},
+		},
+		{
+			name: "новый текст",
+			new:  "a\nb",
+			want: []DiffLine{
+				{Op: DiffInsert, Text: "a", NewLine: 1},
+				{Op: DiffInsert, Text: "b", NewLine: 2},
+			},
+		},
+		{
+			name: "удаленный текст",
+			old:  "a",
+			want: []DiffLine{{Op: DiffDelete, Text: "a", OldLine: 1}},
+		},
+		{
+			name: "замена строки в середине",
+			old:  "a\nb\nc",
+			new:  "a\nB\nc",
+			want: []DiffLine{
+				{Op: DiffEqual, Text: "a", OldLine: 1, NewLine: 1},
+				{Op: DiffDelete, Text: "b", OldLine: 2},
+				{Op: DiffInsert, Text: "B", NewLine: 2},
+				{Op: DiffEqual, Text: "c", OldLine: 3, NewLine: 3},
+			},
+		},
+		{
+			name: "вставка и удаление в разных местах",
+			old:  "x\na\nb\nc\nd",
+			new:  "a\nb\nnew\nc\nd\ny",
+			want: []DiffLine{
+				{Op: DiffDelete, Text: "x", OldLine: 1},
+				{Op: DiffEqual, Text: "a", OldLine: 2, NewLine: 1},
+				{Op: DiffEqual, Text: "b", OldLine: 3, NewLine: 2},
+				{Op: DiffInsert, Text: "new", NewLine: 3},
+				{Op: DiffEqual, Text: "c", OldLine: 4, NewLine: 4},
+				{Op: DiffEqual, Text: "d", OldLine: 5, NewLine: 5},
+				{Op: DiffInsert, Text: "y", NewLine: 6},
+			},
+		},
+		{
+			name: "переставленные строки",
+			old:  "a\nb",
+			new:  "b\na",
+			want: []DiffLine{
+				{Op: DiffDelete, Text: "a", OldLine: 1},
+				{Op: DiffEqual, Text: "b", OldLine: 2, NewLine: 1},
+				{Op: DiffInsert, Text: "a", NewLine: 2},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DiffLines(tt.old, tt.new); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffLines() = %+v, ожидалось %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// EnrichmentStatus — состояние получения дополнительных данных песни от внешнего API.
+type EnrichmentStatus string
+
+const (
+	EnrichmentPending   EnrichmentStatus = "pending"
+	EnrichmentSucceeded EnrichmentStatus = "succeeded"
+	EnrichmentFailed    EnrichmentStatus = "failed"
+)
+
+// JobStatus — состояние задания в очереди.
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "queued"
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed"
+)
+
+// EnrichmentJob представляет собой задание на получение дополнительных данных песни.
+// @Description Задание на получение дополнительных данных песни
+type EnrichmentJob struct {
+	Id            int       `json:"id" gorm:"primaryKey"`
+	SongId        int       `json:"songId"`
+	Status        JobStatus `json:"status"`
+	Attempts      int       `json:"attempts"`                //количество сделанных попыток
+	LastError     string    `json:"lastError,omitempty"`     //текст последней ошибки
+	LastErrorKind string    `json:"lastErrorKind,omitempty"` //вид последней ошибки внешнего API
+	RunAt         time.Time `json:"runAt"`                   //время, не раньше которого задание будет выполнено
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
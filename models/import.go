@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// DuplicatePolicy — что делать при импорте с песней, которая уже есть в библиотеке.
+type DuplicatePolicy string
+
+const (
+	DuplicateSkip   DuplicatePolicy = "skip"   //оставить песню без изменений
+	DuplicateUpdate DuplicatePolicy = "update" //заменить дополнительные данные песни значениями из файла
+	DuplicateFail   DuplicatePolicy = "fail"   //считать строку ошибкой
+)
+
+// ImportJob представляет собой задание на импорт песен из файла.
+// @Description Задание на импорт песен из файла
+type ImportJob struct {
+	Id              string          `json:"id"`
+	Status          JobStatus       `json:"status"`
+	Format          string          `json:"format"`      //csv, jsonl или xlsx
+	DryRun          bool            `json:"dryRun"`      //только проверить файл, ничего не записывая
+	OnDuplicate     DuplicatePolicy `json:"onDuplicate"` //что делать с уже добавленными песнями
+	Progress        float64         `json:"progress"`    //доля прочитанного файла от 0 до 1
+	Rows            int             `json:"rows"`        //обработано строк
+	Created         int             `json:"created"`     //добавлено песен (для dryRun — было бы добавлено)
+	Updated         int             `json:"updated"`     //изменено песен
+	Skipped         int             `json:"skipped"`     //пропущено дубликатов и песен без изменений
+	Failed          int             `json:"failed"`      //строк с ошибками, см. отчет об ошибках
+	ErrorsTruncated bool            `json:"errorsTruncated,omitempty"`
+	Error           string          `json:"error,omitempty"` //ошибка, из-за которой импорт прерван
+	CreatedBy       string          `json:"createdBy"`
+	CreatedAt       time.Time       `json:"createdAt"`
+	FinishedAt      *time.Time      `json:"finishedAt,omitempty"`
+}
+
+// ImportRowError описывает ошибку в строке импортируемого файла.
+// @Description Ошибка в строке импортируемого файла
+type ImportRowError struct {
+	Row     int    `json:"row"`             //номер строки файла, начиная с 1
+	Group   string `json:"group,omitempty"` //группа и название песни из строки, если удалось прочитать
+	Song    string `json:"song,omitempty"`
+	Field   string `json:"field,omitempty"` //поле с ошибкой, пустое для ошибки всей строки
+	Message string `json:"message"`
+}
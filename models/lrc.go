@@ -0,0 +1,289 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TimedWord представляет собой слово строки с меткой времени из расширенного LRC.
+// @Description Слово с меткой времени
+type TimedWord struct {
+	TimeMs int    `json:"timeMs" example:"12500"` //Время начала слова в миллисекундах
+	Text   string `json:"text" example:"baby"`
+}
+
+// TimedLine представляет собой строку текста с меткой времени.
+// @Description Строка текста с меткой времени
+type TimedLine struct {
+	TimeMs int         `json:"timeMs" example:"12000"` //Время начала строки в миллисекундах
+	Text   string      `json:"text" example:"Ooh baby"`
+	Words  []TimedWord `json:"words,omitempty"` //Метки слов, только для расширенного LRC
+}
+
+// LRCTag — тег метаданных LRC, например [ar:Muse].
+type LRCTag struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// SyncedLyrics — текст песни с метками времени строк. В базе данных хранится как JSON,
+// текст без меток хранится как NULL.
+type SyncedLyrics struct {
+	Tags     []LRCTag    `json:"tags,omitempty"`
+	OffsetMs int         `json:"offsetMs,omitempty"` //Сдвиг из тега offset: положительный показывает строки раньше
+	Lines    []TimedLine `json:"lines"`              //Строки, отсортированные по времени
+}
+
+// LRCError возвращается, если файл LRC не удалось разобрать.
+type LRCError struct {
+	Line int //номер строки файла, начиная с 1, 0 — ошибка файла целиком
+	Msg  string
+}
+
+func (e *LRCError) Error() string {
+	if e.Line == 0 {
+		return e.Msg
+	}
+	return fmt.Sprintf("строка %d: %s", e.Line, e.Msg)
+}
+
+var (
+	lrcTimestamp = regexp.MustCompile(`^(\d+):(\d{1,2})(?:[.:](\d{1,3}))?$`)
+	lrcBracket   = regexp.MustCompile(`^\[([^\[\]]*)\]`)
+	lrcWord      = regexp.MustCompile(`<([^<>]*)>`)
+)
+
+// ParseTimestamp разбирает метку времени вида mm:ss, mm:ss.x, mm:ss.xx или mm:ss.xxx в миллисекунды.
+func ParseTimestamp(value string) (int, error) {
+	match := lrcTimestamp.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return 0, fmt.Errorf("некоректная метка времени %q, ожидается mm:ss.xx", value)
+	}
+	minutes, _ := strconv.Atoi(match[1])
+	seconds, _ := strconv.Atoi(match[2])
+	if seconds >= 60 {
+		return 0, fmt.Errorf("в метке времени %q секунд больше 59", value)
+	}
+	ms := 0
+	if match[3] != "" {
+		fraction := match[3] + strings.Repeat("0", 3-len(match[3]))
+		ms, _ = strconv.Atoi(fraction)
+	}
+	return (minutes*60+seconds)*1000 + ms, nil
+}
+
+// FormatTimestamp записывает миллисекунды в формате LRC mm:ss.xx, а если нужны тысячные — mm:ss.xxx.
+func FormatTimestamp(ms int) string {
+	if ms < 0 {
+		ms = 0
+	}
+	if ms%10 != 0 {
+		return fmt.Sprintf("%02d:%02d.%03d", ms/60000, ms/1000%60, ms%1000)
+	}
+	return fmt.Sprintf("%02d:%02d.%02d", ms/60000, ms/1000%60, ms%1000/10)
+}
+
+// ParseLRC разбирает текст в формате LRC или расширенного LRC с метками слов <mm:ss.xx>.
+// Строка может иметь несколько меток времени, тогда она повторяется в каждый из моментов.
+// Строки сортируются по времени, метки слов должны идти по возрастанию и не раньше метки строки.
+func ParseLRC(data string) (SyncedLyrics, error) {
+	var lyrics SyncedLyrics
+	for i, raw := range strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n") {
+		lineNo := i + 1
+		line := strings.TrimSpace(raw)
+		if i == 0 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "[") {
+			return SyncedLyrics{}, &LRCError{Line: lineNo, Msg: "строка без метки времени"}
+		}
+
+		var times []int
+		rest := line
+		for {
+			match := lrcBracket.FindStringSubmatch(rest)
+			if match == nil {
+				break
+			}
+			if !lrcTimestamp.MatchString(match[1]) {
+				if len(times) > 0 {
+					break //квадратные скобки в самом тексте строки
+				}
+				tag, err := parseLRCTag(match[1])
+				if err != nil {
+					return SyncedLyrics{}, &LRCError{Line: lineNo, Msg: err.Error()}
+				}
+				if tag.Key == "offset" {
+					offset, err := strconv.Atoi(strings.TrimPrefix(tag.Value, "+"))
+					if err != nil {
+						return SyncedLyrics{}, &LRCError{Line: lineNo, Msg: fmt.Sprintf("некоректное значение offset %q", tag.Value)}
+					}
+					lyrics.OffsetMs = offset
+				}
+				lyrics.Tags = append(lyrics.Tags, tag)
+				rest = rest[len(match[0]):]
+				break
+			}
+			ms, err := ParseTimestamp(match[1])
+			if err != nil {
+				return SyncedLyrics{}, &LRCError{Line: lineNo, Msg: err.Error()}
+			}
+			times = append(times, ms)
+			rest = rest[len(match[0]):]
+		}
+		if len(times) == 0 {
+			if strings.TrimSpace(rest) != "" {
+				return SyncedLyrics{}, &LRCError{Line: lineNo, Msg: "текст после тега метаданных"}
+			}
+			continue
+		}
+
+		text, words, err := parseLRCWords(rest)
+		if err != nil {
+			return SyncedLyrics{}, &LRCError{Line: lineNo, Msg: err.Error()}
+		}
+		if len(words) > 0 {
+			if len(times) > 1 {
+				return SyncedLyrics{}, &LRCError{Line: lineNo, Msg: "у строки с метками слов может быть только одна метка времени"}
+			}
+			if words[0].TimeMs < times[0] {
+				return SyncedLyrics{}, &LRCError{Line: lineNo, Msg: "метка слова раньше метки строки"}
+			}
+		}
+		for _, ms := range times {
+			lyrics.Lines = append(lyrics.Lines, TimedLine{TimeMs: ms, Text: text, Words: words})
+		}
+	}
+
+	if len(lyrics.Lines) == 0 {
+		return SyncedLyrics{}, &LRCError{Msg: "в файле нет строк с метками времени"}
+	}
+	sort.SliceStable(lyrics.Lines, func(i, j int) bool { return lyrics.Lines[i].TimeMs < lyrics.Lines[j].TimeMs })
+	return lyrics, nil
+}
+
+func parseLRCTag(value string) (LRCTag, error) {
+	key, tagValue, ok := strings.Cut(value, ":")
+	key = strings.ToLower(strings.TrimSpace(key))
+	if !ok || key == "" || strings.ContainsAny(key, " \t") {
+		return LRCTag{}, fmt.Errorf("некоректная метка времени или тег [%s]", value)
+	}
+	return LRCTag{Key: key, Value: strings.TrimSpace(tagValue)}, nil
+}
+
+// parseLRCWords выделяет из текста строки метки слов расширенного LRC.
+func parseLRCWords(rest string) (string, []TimedWord, error) {
+	markers := lrcWord.FindAllStringSubmatchIndex(rest, -1)
+	if markers == nil {
+		return strings.TrimSpace(rest), nil, nil
+	}
+	if strings.TrimSpace(rest[:markers[0][0]]) != "" {
+		return "", nil, fmt.Errorf("текст перед первой меткой слова")
+	}
+
+	var words []TimedWord
+	var text strings.Builder
+	for i, marker := range markers {
+		ms, err := ParseTimestamp(rest[marker[2]:marker[3]])
+		if err != nil {
+			return "", nil, err
+		}
+		if len(words) > 0 && ms < words[len(words)-1].TimeMs {
+			return "", nil, fmt.Errorf("метки слов идут не по возрастанию")
+		}
+		end := len(rest)
+		if i+1 < len(markers) {
+			end = markers[i+1][0]
+		}
+		word := rest[marker[1]:end]
+		text.WriteString(word)
+		words = append(words, TimedWord{TimeMs: ms, Text: word})
+	}
+	return strings.TrimSpace(text.String()), words, nil
+}
+
+// IsZero сообщает, что у песни нет текста с метками времени.
+func (s SyncedLyrics) IsZero() bool {
+	return len(s.Lines) == 0
+}
+
+// String записывает текст в формате LRC, метки слов сохраняются.
+func (s SyncedLyrics) String() string {
+	var b strings.Builder
+	for _, tag := range s.Tags {
+		fmt.Fprintf(&b, "[%s:%s]\n", tag.Key, tag.Value)
+	}
+	for _, line := range s.Lines {
+		fmt.Fprintf(&b, "[%s]", FormatTimestamp(line.TimeMs))
+		if len(line.Words) == 0 {
+			b.WriteString(line.Text)
+		}
+		for _, word := range line.Words {
+			fmt.Fprintf(&b, "<%s>%s", FormatTimestamp(word.TimeMs), word.Text)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// At возвращает строку, которая звучит в момент positionMs, и следующую за ней строку.
+// Время строк в результате указано с учетом сдвига offset. Если строка еще не началась или
+// уже закончилась последняя, соответствующее значение равно nil.
+func (s SyncedLyrics) At(positionMs int) (current, next *TimedLine) {
+	shifted := func(i int) *TimedLine {
+		line := s.Lines[i]
+		line.TimeMs -= s.OffsetMs
+		if line.Words != nil {
+			words := make([]TimedWord, len(line.Words))
+			for j, word := range line.Words {
+				word.TimeMs -= s.OffsetMs
+				words[j] = word
+			}
+			line.Words = words
+		}
+		return &line
+	}
+
+	i := sort.Search(len(s.Lines), func(i int) bool { return s.Lines[i].TimeMs-s.OffsetMs > positionMs })
+	if i > 0 {
+		current = shifted(i - 1)
+	}
+	if i < len(s.Lines) {
+		next = shifted(i)
+	}
+	return current, next
+}
+
+// Scan читает текст с метками времени из JSON, NULL соответствует отсутствию меток.
+func (s *SyncedLyrics) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*s = SyncedLyrics{}
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("неподдерживаемый тип текста с метками времени %T", value)
+	}
+	return json.Unmarshal(data, s)
+}
+
+// Value записывает текст с метками времени как JSON, отсутствие меток записывается как NULL.
+func (s SyncedLyrics) Value() (driver.Value, error) {
+	if s.IsZero() {
+		return nil, nil
+	}
+	data, err := json.Marshal(s)
+	return string(data), err
+}
@@ -0,0 +1,170 @@
+package models
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{value: "00:00", want: 0},
+		{value: "01:02", want: 62000},
+		{value: "01:02.5", want: 62500},
+		{value: "01:02.50", want: 62500},
+		{value: "01:02.505", want: 62505},
+		{value: "01:02:50", want: 62500},
+		{value: "125:00.00", want: 7500000},
+		{value: " 00:01.00 ", want: 1000},
+		{value: "00:60.00", wantErr: true},
+		{value: "1:2:3:4", wantErr: true},
+		{value: "00:01.0000", wantErr: true},
+		{value: "ar:Muse", wantErr: true},
+		{value: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseTimestamp(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseTimestamp(%q) ошибка = %v, ожидалась ошибка %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseTimestamp(%q) = %d, ожидалось %d", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestFormatTimestamp(t *testing.T) {
+	tests := []struct {
+		ms   int
+		want string
+	}{
+		{ms: 0, want: "00:00.00"},
+		{ms: 62500, want: "01:02.50"},
+		{ms: 62505, want: "01:02.505"},
+		{ms: 7500000, want: "125:00.00"},
+		{ms: -100, want: "00:00.00"},
+	}
+	for _, tt := range tests {
+		if got := FormatTimestamp(tt.ms); got != tt.want {
+			t.Errorf("FormatTimestamp(%d) = %q, ожидалось %q", tt.ms, got, tt.want)
+		}
+	}
+}
+
+func TestParseLRC(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    SyncedLyrics
+		wantErr *LRCError
+	}{
+		{
+			name: "теги, несколько меток и сортировка",
+			data: "\ufeff[ar: Muse]\r\n[offset:+500]\n\n[00:12.00][00:30.00]Ooh baby\n[00:20.00]Second [live]\n",
+			want: SyncedLyrics{
+				Tags:     []LRCTag{{Key: "ar", Value: "Muse"}, {Key: "offset", Value: "+500"}},
+				OffsetMs: 500,
+				Lines: []TimedLine{
+					{TimeMs: 12000, Text: "Ooh baby"},
+					{TimeMs: 20000, Text: "Second [live]"},
+					{TimeMs: 30000, Text: "Ooh baby"},
+				},
+			},
+		},
+		{
+			name: "метки слов",
+			data: "[00:12.00]<00:12.00>Ooh <00:12.50>baby",
+			want: SyncedLyrics{
+				Lines: []TimedLine{{TimeMs: 12000, Text: "Ooh baby", Words: []TimedWord{
+					{TimeMs: 12000, Text: "Ooh "},
+					{TimeMs: 12500, Text: "baby"},
+				}}},
+			},
+		},
+		{name: "строка без метки", data: "[00:01.00]a\nplain text", wantErr: &LRCError{Line: 2, Msg: "строка без метки времени"}},
+		{name: "нет строк", data: "[ar:Muse]\n", wantErr: &LRCError{Msg: "в файле нет строк с метками времени"}},
+		{name: "секунд больше 59", data: "[00:61.00]a", wantErr: &LRCError{Line: 1, Msg: `в метке времени "00:61.00" секунд больше 59`}},
+		{name: "некоректный тег", data: "[intro]\n[00:01.00]a", wantErr: &LRCError{Line: 1, Msg: "некоректная метка времени или тег [intro]"}},
+		{name: "некоректный offset", data: "[offset:soon]\n[00:01.00]a", wantErr: &LRCError{Line: 1, Msg: `некоректное значение offset "soon"`}},
+		{name: "текст после тега", data: "[ar:Muse] text", wantErr: &LRCError{Line: 1, Msg: "текст после тега метаданных"}},
+		{name: "метки слов не по порядку", data: "[00:01.00]<00:02.00>a <00:01.50>b", wantErr: &LRCError{Line: 1, Msg: "метки слов идут не по возрастанию"}},
+		{name: "метка слова раньше строки", data: "[00:02.00]<00:01.00>a", wantErr: &LRCError{Line: 1, Msg: "метка слова раньше метки строки"}},
+		{name: "метки слов и несколько меток строки", data: "[00:01.00][00:02.00]<00:02.00>a", wantErr: &LRCError{Line: 1, Msg: "у строки с метками слов может быть только одна метка времени"}},
+		{name: "текст перед меткой слова", data: "[00:01.00]a <00:02.00>b", wantErr: &LRCError{Line: 1, Msg: "текст перед первой меткой слова"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLRC(tt.data)
+			if tt.wantErr != nil {
+				var lrcErr *LRCError
+				if !errors.As(err, &lrcErr) || *lrcErr != *tt.wantErr {
+					t.Fatalf("ParseLRC() ошибка = %v, ожидалась %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseLRC() ошибка = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseLRC() = %+v, ожидалось %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSyncedLyricsString(t *testing.T) {
+	data := "[ar:Muse]\n[00:01.00]First\n[00:02.505]<00:02.505>Second <00:03.00>line\n"
+	lyrics, err := ParseLRC(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := lyrics.String(); got != data {
+		t.Errorf("String() = %q, ожидалось %q", got, data)
+	}
+}
+
+func TestSyncedLyricsAt(t *testing.T) {
+	lyrics, err := ParseLRC("[offset:1000]\n[00:02.00]a\n[00:04.00]<00:04.00>b\n[00:06.00]c")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		position              int
+		wantCurrent, wantNext string
+		wantCurrentMs         int
+	}{
+		{position: 0, wantNext: "a"},
+		{position: 999, wantNext: "a"},
+		{position: 1000, wantCurrent: "a", wantCurrentMs: 1000, wantNext: "b"},
+		{position: 3500, wantCurrent: "b", wantCurrentMs: 3000, wantNext: "c"},
+		{position: 60000, wantCurrent: "c", wantCurrentMs: 5000},
+	}
+	for _, tt := range tests {
+		current, next := lyrics.At(tt.position)
+		if text(current) != tt.wantCurrent || text(next) != tt.wantNext {
+			t.Errorf("At(%d) = %q, %q, ожидалось %q, %q", tt.position, text(current), text(next), tt.wantCurrent, tt.wantNext)
+		}
+		if current != nil && current.TimeMs != tt.wantCurrentMs {
+			t.Errorf("At(%d) время строки = %d, ожидалось %d", tt.position, current.TimeMs, tt.wantCurrentMs)
+		}
+	}
+
+	current, _ := lyrics.At(3500)
+	if current.Words[0].TimeMs != 3000 || lyrics.Lines[1].Words[0].TimeMs != 4000 {
+		t.Errorf("сдвиг меток слов = %d, исходная метка = %d", current.Words[0].TimeMs, lyrics.Lines[1].Words[0].TimeMs)
+	}
+}
+
+func text(line *TimedLine) string {
+	if line == nil {
+		return ""
+	}
+	return line.Text
+}
@@ -0,0 +1,142 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// LyricKind — вид версии текста песни.
+type LyricKind string
+
+const (
+	LyricOriginal        LyricKind = "original"
+	LyricTranslation     LyricKind = "translation"
+	LyricTransliteration LyricKind = "transliteration"
+)
+
+// lyricKindOrder — порядок выбора версии, если вид не указан.
+var lyricKindOrder = []LyricKind{LyricOriginal, LyricTranslation, LyricTransliteration}
+
+// Valid сообщает, что вид версии известен.
+func (k LyricKind) Valid() bool {
+	for _, kind := range lyricKindOrder {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// LyricVersion представляет собой версию текста песни на одном языке.
+// @Description Версия текста песни на одном языке
+type LyricVersion struct {
+	Id        int       `json:"id" swaggerignore:"true" gorm:"primaryKey"`
+	SongId    int       `json:"songId" swaggerignore:"true"`
+	Language  string    `json:"language" example:"en"`                                                   //Языковой тег BCP 47, например en, ru или ru-Latn
+	Kind      LyricKind `json:"kind" example:"translation" enums:"original,translation,transliteration"` //Вид версии
+	Text      string    `json:"text" example:"Ooh baby, don't you know I suffer?"`
+	Sections  Lyrics    `json:"-" gorm:"type:jsonb"` //Текст, разбитый на части, обновляется вместе с Text
+	UpdatedAt time.Time `json:"updatedAt" swaggerignore:"true"`
+}
+
+var languageTag = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{1,8})*$`)
+
+// NormalizeLanguageTag проверяет языковой тег и приводит его к принятому написанию:
+// язык строчными буквами, письменность с заглавной буквы, регион заглавными (sr-Latn-RS).
+func NormalizeLanguageTag(tag string) (string, bool) {
+	tag = strings.ReplaceAll(strings.TrimSpace(tag), "_", "-")
+	if !languageTag.MatchString(tag) {
+		return "", false
+	}
+	parts := strings.Split(tag, "-")
+	parts[0] = strings.ToLower(parts[0])
+	for i := 1; i < len(parts); i++ {
+		switch {
+		case len(parts[i]) == 4:
+			parts[i] = strings.ToUpper(parts[i][:1]) + strings.ToLower(parts[i][1:])
+		case len(parts[i]) == 2:
+			parts[i] = strings.ToUpper(parts[i])
+		default:
+			parts[i] = strings.ToLower(parts[i])
+		}
+	}
+	return strings.Join(parts, "-"), true
+}
+
+// PickLyricVersion выбирает версию текста на языке lang. Сначала ищется точное совпадение тега,
+// затем совпадение основного языка (en для en-US), пустой lang подходит к любому языку. Если kind пустой, предпочитается оригинал,
+// затем перевод, затем транслитерация.
+func PickLyricVersion(versions []LyricVersion, lang string, kind LyricKind) (LyricVersion, bool) {
+	primary, _, _ := strings.Cut(lang, "-")
+	kinds := lyricKindOrder
+	if kind != "" {
+		kinds = []LyricKind{kind}
+	}
+	for _, exact := range []bool{true, false} {
+		for _, k := range kinds {
+			for _, version := range versions {
+				if version.Kind != k {
+					continue
+				}
+				versionPrimary, _, _ := strings.Cut(version.Language, "-")
+				if lang == "" || exact && version.Language == lang || !exact && versionPrimary == primary {
+					return version, true
+				}
+			}
+		}
+	}
+	return LyricVersion{}, false
+}
+
+// AlignedSection — строка двухколоночного вида: часть оригинала и соответствующая ей часть перевода.
+// @Description Часть оригинала и соответствующая ей часть перевода
+type AlignedSection struct {
+	Original    *LyricSection `json:"original"`    //null, если у части перевода нет пары
+	Translation *LyricSection `json:"translation"` //null, если часть оригинала не переведена
+}
+
+// AlignLyrics сопоставляет части оригинала и перевода. Части сопоставляются по порядку
+// с учетом их вида: если в одной из версий пропущена часть, остальные не сдвигаются.
+// У повторов в результате раскрыты строки.
+func AlignLyrics(original, translation Lyrics) []AlignedSection {
+	n, m := len(original), len(translation)
+	// lcs[i][j] — длина наибольшей общей подпоследовательности видов частей original[i:] и translation[j:]
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if original[i].Kind == translation[j].Kind {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	expand := func(lyrics Lyrics, i int) *LyricSection {
+		section := lyrics[i]
+		section.Lines = lyrics.Resolve(section)
+		return &section
+	}
+
+	var rows []AlignedSection
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && original[i].Kind == translation[j].Kind:
+			rows = append(rows, AlignedSection{Original: expand(original, i), Translation: expand(translation, j)})
+			i++
+			j++
+		case j == m || i < n && lcs[i+1][j] >= lcs[i][j+1]:
+			rows = append(rows, AlignedSection{Original: expand(original, i)})
+			i++
+		default:
+			rows = append(rows, AlignedSection{Translation: expand(translation, j)})
+			j++
+		}
+	}
+	return rows
+}
@@ -0,0 +1,199 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// SectionKind — вид части текста песни.
+type SectionKind string
+
+const (
+	SectionVerse     SectionKind = "verse"
+	SectionChorus    SectionKind = "chorus"
+	SectionPreChorus SectionKind = "pre-chorus"
+	SectionBridge    SectionKind = "bridge"
+	SectionOutro     SectionKind = "outro"
+)
+
+// LyricSection представляет собой часть текста песни.
+// @Description Часть текста песни
+type LyricSection struct {
+	Position int         `json:"position" example:"2"`                                               //Номер части, начиная с 1
+	Kind     SectionKind `json:"kind" example:"chorus" enums:"verse,chorus,pre-chorus,bridge,outro"` //Вид части
+	Label    string      `json:"label,omitempty" example:"Chorus"`                                   //Заголовок из текста, например Verse 2
+	Lines    []string    `json:"lines,omitempty"`                                                    //Строки, у повтора отсутствуют
+	RepeatOf int         `json:"repeatOf,omitempty" example:"2"`                                     //Номер части, которая повторяется
+}
+
+// Lyrics — текст песни, разбитый на части. В базе данных хранится как JSON массив.
+type Lyrics []LyricSection
+
+// sectionHeader — заголовок части вида [Chorus] или [Куплет 2].
+var sectionHeader = regexp.MustCompile(`^\[([^\[\]]+)\]$`)
+
+// sectionKeywords сопоставляет начало заголовка виду части. Предприпев проверяется раньше припева.
+var sectionKeywords = []struct {
+	prefix string
+	kind   SectionKind
+}{
+	{"pre-chorus", SectionPreChorus},
+	{"pre chorus", SectionPreChorus},
+	{"prechorus", SectionPreChorus},
+	{"предприпев", SectionPreChorus},
+	{"пред-припев", SectionPreChorus},
+	{"chorus", SectionChorus},
+	{"hook", SectionChorus},
+	{"refrain", SectionChorus},
+	{"припев", SectionChorus},
+	{"bridge", SectionBridge},
+	{"бридж", SectionBridge},
+	{"outro", SectionOutro},
+	{"аутро", SectionOutro},
+	{"кода", SectionOutro},
+	{"verse", SectionVerse},
+	{"куплет", SectionVerse},
+}
+
+// sectionKind определяет вид части по заголовку, неизвестные заголовки считаются куплетом.
+func sectionKind(label string) SectionKind {
+	label = strings.ToLower(strings.TrimSpace(label))
+	for _, k := range sectionKeywords {
+		if strings.HasPrefix(label, k.prefix) {
+			return k.kind
+		}
+	}
+	return SectionVerse
+}
+
+// ParseLyrics разбивает текст песни на части. Части разделяются пустыми строками
+// или заголовками вида [Chorus]. Вид части определяется по заголовку, часть без заголовка
+// считается куплетом. Часть, которая повторяет строки одной из предыдущих частей или состоит
+// только из заголовка уже встречавшейся части, сохраняется как ссылка на неё без строк.
+func ParseLyrics(text string) Lyrics {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	var lyrics Lyrics
+	var current *LyricSection
+	flush := func() {
+		if current == nil {
+			return
+		}
+		if len(current.Lines) > 0 || current.Label != "" {
+			current.Position = len(lyrics) + 1
+			lyrics = append(lyrics, lyrics.dedupe(*current))
+		}
+		current = nil
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, " \t")
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+		if match := sectionHeader.FindStringSubmatch(strings.TrimSpace(line)); match != nil {
+			flush()
+			label := strings.TrimSpace(match[1])
+			current = &LyricSection{Kind: sectionKind(label), Label: label}
+			continue
+		}
+		if current == nil {
+			current = &LyricSection{Kind: SectionVerse}
+		}
+		current.Lines = append(current.Lines, line)
+	}
+	flush()
+	return lyrics
+}
+
+// dedupe превращает часть в ссылку на предыдущую часть, если она её повторяет.
+// Заголовок без строк ссылается на часть с тем же заголовком, а если такой нет — на первую часть того же вида.
+func (l Lyrics) dedupe(section LyricSection) LyricSection {
+	original, sameKind := 0, 0
+	for _, prev := range l {
+		if prev.RepeatOf != 0 {
+			continue
+		}
+		if len(section.Lines) > 0 && equalLines(prev.Lines, section.Lines) ||
+			len(section.Lines) == 0 && strings.EqualFold(prev.Label, section.Label) {
+			original = prev.Position
+			break
+		}
+		if sameKind == 0 && prev.Kind == section.Kind {
+			sameKind = prev.Position
+		}
+	}
+	if original == 0 && len(section.Lines) == 0 {
+		original = sameKind
+	}
+	if original == 0 {
+		return section
+	}
+
+	if section.Label == "" { //повтор без заголовка получает вид и заголовок оригинала
+		section.Kind = l[original-1].Kind
+		section.Label = l[original-1].Label
+	}
+	section.Lines = nil
+	section.RepeatOf = original
+	return section
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if strings.TrimSpace(a[i]) != strings.TrimSpace(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Resolve возвращает строки части, для повтора — строки повторяемой части.
+func (l Lyrics) Resolve(section LyricSection) []string {
+	if section.RepeatOf > 0 && section.RepeatOf <= len(l) {
+		return l[section.RepeatOf-1].Lines
+	}
+	return section.Lines
+}
+
+// SectionText возвращает текст части вместе с заголовком, повторы раскрываются.
+func (l Lyrics) SectionText(section LyricSection) string {
+	lines := l.Resolve(section)
+	if section.Label != "" {
+		lines = append([]string{"[" + section.Label + "]"}, lines...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Scan читает части текста из JSON массива.
+func (l *Lyrics) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("неподдерживаемый тип частей текста %T", value)
+	}
+	return json.Unmarshal(data, l)
+}
+
+// Value записывает части текста как JSON массив, пустой текст записывается как [].
+func (l Lyrics) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal([]LyricSection(l))
+	return string(data), err
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLyrics(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want Lyrics
+	}{
+		{
+			name: "пустой текст",
+			text: "\n\n",
+			want: nil,
+		},
+		{
+			name: "части без заголовков",
+			text: "Line one\r\nLine two\n\n\nLine three  ",
+			want: Lyrics{
+				{Position: 1, Kind: SectionVerse, Lines: []string{"Line one", "Line two"}},
+				{Position: 2, Kind: SectionVerse, Lines: []string{"Line three"}},
+			},
+		},
+		{
+			name: "виды частей по заголовкам",
+			text: "[Pre-Chorus]\na\n[Припев]\nb\n[Bridge 2]\nc\n[Кода]\nd\n[Intro]\ne",
+			want: Lyrics{
+				{Position: 1, Kind: SectionPreChorus, Label: "Pre-Chorus", Lines: []string{"a"}},
+				{Position: 2, Kind: SectionChorus, Label: "Припев", Lines: []string{"b"}},
+				{Position: 3, Kind: SectionBridge, Label: "Bridge 2", Lines: []string{"c"}},
+				{Position: 4, Kind: SectionOutro, Label: "Кода", Lines: []string{"d"}},
+				{Position: 5, Kind: SectionVerse, Label: "Intro", Lines: []string{"e"}},
+			},
+		},
+		{
+			name: "повтор строк без заголовка",
+			text: "[Chorus]\nLa la\nLa la la\n\nVerse\n\nLa la\n  La la la",
+			want: Lyrics{
+				{Position: 1, Kind: SectionChorus, Label: "Chorus", Lines: []string{"La la", "La la la"}},
+				{Position: 2, Kind: SectionVerse, Lines: []string{"Verse"}},
+				{Position: 3, Kind: SectionChorus, Label: "Chorus", RepeatOf: 1},
+			},
+		},
+		{
+			name: "заголовок без строк",
+			text: "[Verse 1]\na\n[Chorus]\nb\n[Verse 2]\nc\n[chorus]\n[Chorus 2]",
+			want: Lyrics{
+				{Position: 1, Kind: SectionVerse, Label: "Verse 1", Lines: []string{"a"}},
+				{Position: 2, Kind: SectionChorus, Label: "Chorus", Lines: []string{"b"}},
+				{Position: 3, Kind: SectionVerse, Label: "Verse 2", Lines: []string{"c"}},
+				{Position: 4, Kind: SectionChorus, Label: "chorus", RepeatOf: 2},
+				{Position: 5, Kind: SectionChorus, Label: "Chorus 2", RepeatOf: 2},
+			},
+		},
+		{
+			name: "заголовок без строк и без оригинала",
+			text: "[Bridge]",
+			want: Lyrics{
+				{Position: 1, Kind: SectionBridge, Label: "Bridge"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseLyrics(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseLyrics() = %+v, ожидалось %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLyricsSectionText(t *testing.T) {
+	lyrics := ParseLyrics("[Chorus]\nLa la\n\nVerse\n\n[Chorus]")
+
+	tests := []struct {
+		section int
+		want    string
+	}{
+		{section: 0, want: "[Chorus]\nLa la"},
+		{section: 1, want: "Verse"},
+		{section: 2, want: "[Chorus]\nLa la"},
+	}
+	for _, tt := range tests {
+		if got := lyrics.SectionText(lyrics[tt.section]); got != tt.want {
+			t.Errorf("SectionText(%d) = %q, ожидалось %q", tt.section, got, tt.want)
+		}
+	}
+}
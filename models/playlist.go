@@ -0,0 +1,113 @@
+package models
+
+import (
+	"encoding/xml"
+	"strings"
+	"time"
+)
+
+// PlaylistVisibility — кому виден плейлист.
+type PlaylistVisibility string
+
+const (
+	PlaylistPrivate  PlaylistVisibility = "private"  //только владельцу
+	PlaylistUnlisted PlaylistVisibility = "unlisted" //всем, кто знает ID, но не в общем списке
+	PlaylistPublic   PlaylistVisibility = "public"   //всем
+)
+
+// Valid сообщает, что видимость известна.
+func (v PlaylistVisibility) Valid() bool {
+	switch v {
+	case PlaylistPrivate, PlaylistUnlisted, PlaylistPublic:
+		return true
+	}
+	return false
+}
+
+// Playlist представляет собой модель плейлиста.
+// @Description Модель плейлиста
+type Playlist struct {
+	Id          int                `json:"id" swaggerignore:"true" gorm:"primaryKey"`
+	Name        string             `json:"name" example:"В дорогу"`                                      //Название
+	Description string             `json:"description" example:""`                                       //Описание
+	Owner       string             `json:"owner" swaggerignore:"true"`                                   //Владелец, автор из заголовка X-User
+	Visibility  PlaylistVisibility `json:"visibility" example:"private" enums:"private,unlisted,public"` //Кому виден плейлист
+	EntryCount  int                `json:"entryCount" swaggerignore:"true" gorm:"->;-:migration"`        //Количество записей
+	CreatedAt   time.Time          `json:"createdAt" swaggerignore:"true"`                               //Когда плейлист создан
+	UpdatedAt   time.Time          `json:"updatedAt" swaggerignore:"true"`                               //Когда плейлист или его записи последний раз изменены
+}
+
+// PlaylistEntry представляет собой запись плейлиста. Одна песня может встречаться в плейлисте несколько раз.
+// @Description Запись плейлиста
+type PlaylistEntry struct {
+	Id         int   `json:"id" gorm:"primaryKey"`
+	PlaylistId int   `json:"playlistId"`
+	SongId     int   `json:"songId" example:"1"`
+	Position   int   `json:"position" example:"1"`    //Позиция в плейлисте, начиная с 1
+	Song       *Song `json:"song,omitempty" gorm:"-"` //Песня, отсутствует, если песня в корзине
+}
+
+// PlaylistM3U8 возвращает плейлист в формате M3U8 (расширенный M3U в UTF-8).
+// Записи без ссылки на песню пропускаются.
+func PlaylistM3U8(playlist Playlist, entries []PlaylistEntry) string {
+	oneLine := strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ")
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n")
+	b.WriteString("#PLAYLIST:" + oneLine.Replace(playlist.Name) + "\n")
+	for _, entry := range entries {
+		if entry.Song == nil || entry.Song.SongDetails.Link == "" {
+			continue
+		}
+		b.WriteString("#EXTINF:-1," + oneLine.Replace(entry.Song.Group+" - "+entry.Song.Song) + "\n")
+		b.WriteString(oneLine.Replace(entry.Song.SongDetails.Link) + "\n")
+	}
+	return b.String()
+}
+
+type xspfPlaylist struct {
+	XMLName    xml.Name    `xml:"http://xspf.org/ns/0/ playlist"`
+	Version    string      `xml:"version,attr"`
+	Title      string      `xml:"title"`
+	Creator    string      `xml:"creator,omitempty"`
+	Annotation string      `xml:"annotation,omitempty"`
+	Date       string      `xml:"date,omitempty"`
+	Tracks     []xspfTrack `xml:"trackList>track"`
+}
+
+type xspfTrack struct {
+	Location string `xml:"location"`
+	Creator  string `xml:"creator"`
+	Title    string `xml:"title"`
+	TrackNum int    `xml:"trackNum"`
+}
+
+// PlaylistXSPF возвращает плейлист в формате XSPF. Записи без ссылки на песню пропускаются,
+// trackNum — позиция записи в плейлисте.
+func PlaylistXSPF(playlist Playlist, entries []PlaylistEntry) ([]byte, error) {
+	doc := xspfPlaylist{
+		Version:    "1",
+		Title:      playlist.Name,
+		Creator:    playlist.Owner,
+		Annotation: playlist.Description,
+		Tracks:     []xspfTrack{},
+	}
+	if !playlist.CreatedAt.IsZero() {
+		doc.Date = playlist.CreatedAt.UTC().Format(time.RFC3339)
+	}
+	for _, entry := range entries {
+		if entry.Song == nil || entry.Song.SongDetails.Link == "" {
+			continue
+		}
+		doc.Tracks = append(doc.Tracks, xspfTrack{
+			Location: entry.Song.SongDetails.Link,
+			Creator:  entry.Song.Group,
+			Title:    entry.Song.Song,
+			TrackNum: entry.Position,
+		})
+	}
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
@@ -0,0 +1,144 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DatePrecision — точность, с которой известна дата выхода песни.
+type DatePrecision string
+
+const (
+	PrecisionYear  DatePrecision = "year"
+	PrecisionMonth DatePrecision = "month"
+	PrecisionDay   DatePrecision = "day"
+)
+
+// Scan читает точность из базы данных, NULL соответствует пустой точности.
+func (p *DatePrecision) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*p = ""
+	case string:
+		*p = DatePrecision(v)
+	case []byte:
+		*p = DatePrecision(v)
+	default:
+		return fmt.Errorf("неподдерживаемый тип точности даты %T", value)
+	}
+	return nil
+}
+
+// Value записывает пустую точность в базу данных как NULL.
+func (p DatePrecision) Value() (driver.Value, error) {
+	if p == "" {
+		return nil, nil
+	}
+	return string(p), nil
+}
+
+// releaseDateLayouts — поддерживаемые форматы даты выхода от более точных к менее точным.
+var releaseDateLayouts = []struct {
+	layout    string
+	precision DatePrecision
+}{
+	{"02.01.2006", PrecisionDay},
+	{"2.1.2006", PrecisionDay},
+	{"2006-01-02", PrecisionDay},
+	{"01.2006", PrecisionMonth},
+	{"1.2006", PrecisionMonth},
+	{"2006-01", PrecisionMonth},
+	{"2006", PrecisionYear},
+}
+
+// DateFormatError возвращается, если дату выхода не удалось разобрать.
+type DateFormatError struct {
+	Value string
+}
+
+func (e *DateFormatError) Error() string {
+	return fmt.Sprintf("неверный формат даты %q, ожидается DD.MM.YYYY, MM.YYYY, YYYY, YYYY-MM-DD или YYYY-MM", e.Value)
+}
+
+// ReleaseDate — дата выхода песни, известная с точностью до года, месяца или дня.
+// Дата хранится как первый день известного периода. В JSON передается строкой
+// DD.MM.YYYY, MM.YYYY или YYYY в зависимости от точности.
+type ReleaseDate struct {
+	Date      *time.Time    `gorm:"column:released_on;type:date"`
+	Precision DatePrecision `gorm:"column:release_precision"`
+}
+
+// ParseReleaseDate разбирает дату выхода в одном из поддерживаемых форматов. Пустая строка дает пустую дату.
+func ParseReleaseDate(value string) (ReleaseDate, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return ReleaseDate{}, nil
+	}
+	for _, f := range releaseDateLayouts {
+		if t, err := time.Parse(f.layout, value); err == nil {
+			return ReleaseDate{Date: &t, Precision: f.precision}, nil
+		}
+	}
+	return ReleaseDate{}, &DateFormatError{Value: value}
+}
+
+// IsZero сообщает, что дата выхода неизвестна.
+func (d ReleaseDate) IsZero() bool {
+	return d.Date == nil
+}
+
+// Start возвращает первый день периода, в который вышла песня.
+func (d ReleaseDate) Start() time.Time {
+	if d.Date == nil {
+		return time.Time{}
+	}
+	return *d.Date
+}
+
+// End возвращает первый день после периода, в который вышла песня.
+func (d ReleaseDate) End() time.Time {
+	switch d.Precision {
+	case PrecisionYear:
+		return d.Start().AddDate(1, 0, 0)
+	case PrecisionMonth:
+		return d.Start().AddDate(0, 1, 0)
+	}
+	return d.Start().AddDate(0, 0, 1)
+}
+
+func (d ReleaseDate) String() string {
+	if d.Date == nil {
+		return ""
+	}
+	switch d.Precision {
+	case PrecisionYear:
+		return d.Date.Format("2006")
+	case PrecisionMonth:
+		return d.Date.Format("01.2006")
+	}
+	return d.Date.Format("02.01.2006")
+}
+
+func (d ReleaseDate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *ReleaseDate) UnmarshalJSON(data []byte) error {
+	var value *string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if value == nil {
+		*d = ReleaseDate{}
+		return nil
+	}
+	parsed, err := ParseReleaseDate(*value)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
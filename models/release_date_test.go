@@ -0,0 +1,67 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseReleaseDate(t *testing.T) {
+	tests := []struct {
+		value     string
+		want      string //String() разобранной даты
+		precision DatePrecision
+		end       string //End() в формате 2006-01-02
+	}{
+		{value: "01.12.2003", want: "01.12.2003", precision: PrecisionDay, end: "2003-12-02"},
+		{value: "1.2.2003", want: "01.02.2003", precision: PrecisionDay, end: "2003-02-02"},
+		{value: "2003-12-31", want: "31.12.2003", precision: PrecisionDay, end: "2004-01-01"},
+		{value: "12.2003", want: "12.2003", precision: PrecisionMonth, end: "2004-01-01"},
+		{value: "2003-02", want: "02.2003", precision: PrecisionMonth, end: "2003-03-01"},
+		{value: " 2003 ", want: "2003", precision: PrecisionYear, end: "2004-01-01"},
+		{value: "", want: ""},
+	}
+	for _, tt := range tests {
+		got, err := ParseReleaseDate(tt.value)
+		if err != nil {
+			t.Errorf("ParseReleaseDate(%q): %v", tt.value, err)
+			continue
+		}
+		if got.String() != tt.want || got.Precision != tt.precision {
+			t.Errorf("ParseReleaseDate(%q) = %s с точностью %q, ожидалось %s с точностью %q", tt.value, got, got.Precision, tt.want, tt.precision)
+		}
+		if tt.end != "" && got.End().Format(time.DateOnly) != tt.end {
+			t.Errorf("ParseReleaseDate(%q).End() = %s, ожидалось %s", tt.value, got.End().Format(time.DateOnly), tt.end)
+		}
+	}
+
+	for _, value := range []string{"31.02.2003", "2003-13", "13.2003", "December 2003", "03"} {
+		var dateErr *DateFormatError
+		if _, err := ParseReleaseDate(value); !errors.As(err, &dateErr) {
+			t.Errorf("ParseReleaseDate(%q) = %v, ожидалась DateFormatError", value, err)
+		}
+	}
+}
+
+func TestReleaseDateJSON(t *testing.T) {
+	var details SongDetails
+	if err := json.Unmarshal([]byte(`{"releaseDate":"2003-12"}`), &details); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(details)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"SongId":0,"text":"","releaseDate":"12.2003","link":""}`; string(data) != want {
+		t.Errorf("json.Marshal() = %s, ожидалось %s", data, want)
+	}
+
+	if err := json.Unmarshal([]byte(`{"releaseDate":null}`), &details); err != nil || !details.ReleaseDate.IsZero() {
+		t.Errorf("null: дата %v, ошибка %v", details.ReleaseDate, err)
+	}
+	var dateErr *DateFormatError
+	if err := json.Unmarshal([]byte(`{"releaseDate":"2003-13"}`), &details); !errors.As(err, &dateErr) {
+		t.Errorf("некоректная дата: ошибка %v, ожидалась DateFormatError", err)
+	}
+}
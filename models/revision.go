@@ -0,0 +1,131 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// SongSnapshot — состояние редактируемых полей песни после изменения.
+// @Description Состояние полей песни
+type SongSnapshot struct {
+	ArtistId    *int   `json:"artistId,omitempty" example:"1"` //Исполнитель, по нему группа восстанавливается после переименования
+	Group       string `json:"group" example:"Muse"`
+	Song        string `json:"song" example:"Supermassive Black Hole"`
+	Text        string `json:"text"`
+	Link        string `json:"link"`
+	ReleaseDate string `json:"releaseDate" example:"16.07.2006"`
+}
+
+// SnapshotOf возвращает состояние редактируемых полей песни.
+func SnapshotOf(song Song) SongSnapshot {
+	return SongSnapshot{
+		ArtistId:    song.ArtistId,
+		Group:       song.Group,
+		Song:        song.Song,
+		Text:        song.SongDetails.Text,
+		Link:        song.SongDetails.Link,
+		ReleaseDate: song.SongDetails.ReleaseDate.String(),
+	}
+}
+
+// Update возвращает изменение, которое приводит песню к этому состоянию. Песня привязывается
+// к исполнителю по ArtistId, а по названию группы — только если исполнителя уже нет.
+func (s SongSnapshot) Update() (SongUpdate, error) {
+	releaseDate, err := ParseReleaseDate(s.ReleaseDate)
+	if err != nil {
+		return SongUpdate{}, err
+	}
+	return SongUpdate{
+		ArtistId:    s.ArtistId,
+		Group:       &s.Group,
+		Song:        &s.Song,
+		Text:        &s.Text,
+		Link:        &s.Link,
+		ReleaseDate: &releaseDate,
+	}, nil
+}
+
+// Scan читает состояние песни из JSON.
+func (s *SongSnapshot) Scan(value interface{}) error {
+	return scanJSON(value, s)
+}
+
+// Value записывает состояние песни как JSON.
+func (s SongSnapshot) Value() (driver.Value, error) {
+	data, err := json.Marshal(s)
+	return string(data), err
+}
+
+// FieldChange — изменение одного поля песни.
+// @Description Изменение одного поля песни
+type FieldChange struct {
+	Field string `json:"field" example:"link"`
+	Old   string `json:"old" example:""`
+	New   string `json:"new" example:"https://www.youtube.com/watch?v=Xsp3_a-PMTw"`
+}
+
+// FieldChanges — список изменений полей, в базе данных хранится как JSON массив.
+type FieldChanges []FieldChange
+
+// DiffSnapshots возвращает поля, которые отличаются в двух состояниях песни.
+func DiffSnapshots(old, new SongSnapshot) FieldChanges {
+	fields := []struct {
+		name     string
+		old, new string
+	}{
+		{"group", old.Group, new.Group},
+		{"song", old.Song, new.Song},
+		{"text", old.Text, new.Text},
+		{"link", old.Link, new.Link},
+		{"releaseDate", old.ReleaseDate, new.ReleaseDate},
+	}
+	changes := FieldChanges{}
+	for _, f := range fields {
+		if f.old != f.new {
+			changes = append(changes, FieldChange{Field: f.name, Old: f.old, New: f.new})
+		}
+	}
+	return changes
+}
+
+// Scan читает изменения полей из JSON массива.
+func (c *FieldChanges) Scan(value interface{}) error {
+	return scanJSON(value, c)
+}
+
+// Value записывает изменения полей как JSON массив.
+func (c FieldChanges) Value() (driver.Value, error) {
+	if c == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal([]FieldChange(c))
+	return string(data), err
+}
+
+// SongRevision представляет собой запись истории изменений песни.
+// @Description Запись истории изменений песни
+type SongRevision struct {
+	Id           int          `json:"-" gorm:"primaryKey"`
+	SongId       int          `json:"songId"`
+	Revision     int          `json:"revision" example:"3"`               //Номер правки песни, начиная с 1
+	Author       string       `json:"author" example:"anonymous"`         //Кто внес изменение
+	CreatedAt    time.Time    `json:"createdAt"`                          //Когда внесено изменение
+	Changes      FieldChanges `json:"changes" gorm:"type:jsonb"`          //Измененные поля со старыми и новыми значениями
+	Snapshot     SongSnapshot `json:"snapshot" gorm:"type:jsonb"`         //Состояние полей песни после изменения
+	RestoredFrom *int         `json:"restoredFrom,omitempty" example:"1"` //Номер правки, к которой вернули песню
+}
+
+// scanJSON читает значение JSON колонки в dest, NULL оставляет dest без изменений.
+func scanJSON(value interface{}, dest interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return json.Unmarshal([]byte(v), dest)
+	case []byte:
+		return json.Unmarshal(v, dest)
+	}
+	return fmt.Errorf("неподдерживаемый тип JSON колонки %T", value)
+}
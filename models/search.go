@@ -0,0 +1,9 @@
+package models
+
+// SearchResult представляет собой песню, найденную полнотекстовым поиском по тексту.
+// @Description Результат поиска по тексту песни
+type SearchResult struct {
+	Song    Song    `json:"song"`
+	Rank    float64 `json:"rank"`    //релевантность, чем больше, тем выше в выдаче
+	Snippet string  `json:"snippet"` //фрагмент текста в HTML: текст экранирован, совпадения выделены тегами <b></b>
+}
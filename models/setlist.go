@@ -0,0 +1,359 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TrackDuration — длительность в секундах. В JSON передается строкой M:SS или H:MM:SS,
+// при разборе принимается также число секунд.
+type TrackDuration int
+
+// ParseTrackDuration разбирает длительность вида 225, 3:45 или 1:02:03.
+func ParseTrackDuration(value string) (TrackDuration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	parts := strings.Split(value, ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("неверный формат длительности %q, ожидается M:SS, H:MM:SS или число секунд", value)
+	}
+	total := 0
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || i > 0 && (n >= 60 || len(part) != 2) {
+			return 0, fmt.Errorf("неверный формат длительности %q, ожидается M:SS, H:MM:SS или число секунд", value)
+		}
+		total = total*60 + n
+	}
+	return TrackDuration(total), nil
+}
+
+func (d TrackDuration) String() string {
+	seconds := int(d)
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+func (d TrackDuration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *TrackDuration) UnmarshalJSON(data []byte) error {
+	var seconds int
+	if err := json.Unmarshal(data, &seconds); err == nil {
+		if seconds < 0 {
+			return fmt.Errorf("отрицательная длительность %d", seconds)
+		}
+		*d = TrackDuration(seconds)
+		return nil
+	}
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	parsed, err := ParseTrackDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Setlist представляет собой сет-лист концерта или репетиции: сеты и бисы с песнями из библиотеки.
+// @Description Сет-лист
+type Setlist struct {
+	Id        int          `json:"id" swaggerignore:"true" gorm:"primaryKey"`
+	Name      string       `json:"name" example:"Концерт в клубе"`             //Название
+	Venue     string       `json:"venue" example:"Москва, клуб"`               //Место
+	Notes     string       `json:"notes" example:""`                           //Заметки для всей группы
+	Owner     string       `json:"owner" swaggerignore:"true"`                 //Владелец, автор из заголовка X-User
+	Sets      []SetlistSet `json:"sets,omitempty" gorm:"foreignKey:SetlistId"` //Сеты по порядку, бисы после основных сетов, в списке сет-листов отсутствуют
+	CreatedAt time.Time    `json:"createdAt" swaggerignore:"true"`             //Когда сет-лист создан
+	UpdatedAt time.Time    `json:"updatedAt" swaggerignore:"true"`             //Когда сет-лист последний раз изменен
+}
+
+// SetlistSet представляет собой сет или бис сет-листа.
+// @Description Сет или бис
+type SetlistSet struct {
+	Id        int           `json:"id" swaggerignore:"true" gorm:"primaryKey"`
+	SetlistId int           `json:"-"`
+	Position  int           `json:"position" swaggerignore:"true"` //Номер сета, начиная с 1
+	Name      string        `json:"name" example:"Первый сет"`     //Название, по умолчанию «Сет N» или «Бис»
+	Encore    bool          `json:"encore" example:"false"`        //Бис
+	Items     []SetlistItem `json:"items" gorm:"foreignKey:SetId"` //Песни сета по порядку
+}
+
+// SetlistItem представляет собой песню в сете. Незаданные длительность, тональность и темп
+// берутся из директив {duration}, {key} и {tempo} листа аккордов песни.
+// @Description Песня в сете
+type SetlistItem struct {
+	Id       int           `json:"id" swaggerignore:"true" gorm:"primaryKey"`
+	SetId    int           `json:"-"`
+	Position int           `json:"position" swaggerignore:"true"`                //Позиция в сете, начиная с 1
+	SongId   int           `json:"songId" example:"1"`                           //ID песни из библиотеки
+	Duration TrackDuration `json:"duration" swaggertype:"string" example:"3:45"` //Длительность M:SS или число секунд
+	Key      string        `json:"key" example:"Am"`                             //Тональность
+	Tempo    int           `json:"tempo" example:"120"`                          //Темп, ударов в минуту
+	Notes    string        `json:"notes" example:"Вступление — только гитара"`   //Заметки к песне
+	Segue    bool          `json:"segue" example:"false"`                        //Следующая песня начинается без паузы
+	Song     *Song         `json:"song,omitempty" swaggerignore:"true" gorm:"-"` //Песня, отсутствует, если песня в корзине
+}
+
+// SetlistPlan — расчет сет-листа: время каждого сета и песни, тональности и конфликты тональностей.
+// @Description Расчет сет-листа
+type SetlistPlan struct {
+	Name     string        `json:"name"`
+	Venue    string        `json:"venue"`
+	Notes    string        `json:"notes"`
+	Duration TrackDuration `json:"duration" swaggertype:"string" example:"1:25:30"` //Общее время всех сетов
+	Unknown  int           `json:"unknownDurations"`                                //Песни без длительности, в общее время не входят
+	Sets     []SetPlan     `json:"sets"`
+	Clashes  []KeyClash    `json:"keyClashes"`
+}
+
+// SetPlan — расчет сета.
+type SetPlan struct {
+	Name     string        `json:"name"`
+	Encore   bool          `json:"encore"`
+	Duration TrackDuration `json:"duration" swaggertype:"string" example:"45:10"` //Время сета
+	Items    []ItemPlan    `json:"items"`
+}
+
+// ItemPlan — песня сета с учетом данных листа аккордов.
+type ItemPlan struct {
+	ItemId   int           `json:"itemId"`
+	Number   int           `json:"number"` //Номер песни в сет-листе, сквозной для всех сетов
+	SongId   int           `json:"songId"`
+	Title    string        `json:"title"`                                      //Исполнитель и название песни
+	Start    TrackDuration `json:"start" swaggertype:"string" example:"12:30"` //Начало от начала сета
+	Duration TrackDuration `json:"duration" swaggertype:"string" example:"3:45"`
+	Key      string        `json:"key,omitempty"`
+	Tempo    int           `json:"tempo,omitempty"`
+	Notes    string        `json:"notes,omitempty"`
+	Segue    bool          `json:"segue"`
+	Clash    bool          `json:"keyClash"` //Тональность конфликтует с предыдущей песней сета
+}
+
+// KeyClash — соседние песни сета в далеких тональностях.
+type KeyClash struct {
+	Set      int    `json:"set"` //Номер сета, начиная с 1
+	FromItem int    `json:"fromItem"`
+	ToItem   int    `json:"toItem"`
+	FromKey  string `json:"fromKey"`
+	ToKey    string `json:"toKey"`
+	Distance int    `json:"distance"` //Шагов по квинтовому кругу между тональностями
+	Segue    bool   `json:"segue"`    //Песни играются без паузы
+}
+
+// KeyDistance возвращает количество шагов по квинтовому кругу между тональностями,
+// от 0 до 6. Минорная тональность сравнивается как параллельная мажорная: Am и C совпадают.
+func KeyDistance(a, b Chord) int {
+	position := func(key Chord) int {
+		pitch := noteIndex[key.Root]
+		if key.Minor() {
+			pitch = (pitch + 3) % 12
+		}
+		return pitch * 7 % 12 //номер на квинтовом круге
+	}
+	distance := (position(a) - position(b) + 12) % 12
+	return min(distance, 12-distance)
+}
+
+// maxKeyDistance — наибольшее расстояние по квинтовому кругу между тональностями соседних песен без конфликта.
+const maxKeyDistance = 1
+
+// PlanSetlist рассчитывает время сетов и песен и находит конфликты тональностей соседних песен сета.
+// Песни из корзины пропускаются.
+func PlanSetlist(setlist Setlist) SetlistPlan {
+	plan := SetlistPlan{Name: setlist.Name, Venue: setlist.Venue, Notes: setlist.Notes, Sets: []SetPlan{}, Clashes: []KeyClash{}}
+	number, regular := 0, 0
+	for i, set := range setlist.Sets {
+		setPlan := SetPlan{Name: set.Name, Encore: set.Encore, Items: []ItemPlan{}}
+		if !set.Encore {
+			regular++
+		}
+		if setPlan.Name == "" && set.Encore {
+			setPlan.Name = "Бис"
+		} else if setPlan.Name == "" {
+			setPlan.Name = "Сет " + strconv.Itoa(regular)
+		}
+
+		var previous ItemPlan
+		var previousKey Chord
+		previousKnown := false //тональность предыдущей песни сета известна
+		for _, item := range set.Items {
+			if item.Song == nil {
+				continue
+			}
+			number++
+			itemPlan := planItem(item)
+			itemPlan.Number = number
+			itemPlan.Start = setPlan.Duration
+			setPlan.Duration += itemPlan.Duration
+			if itemPlan.Duration == 0 {
+				plan.Unknown++
+			}
+
+			key, known := ParseChord(itemPlan.Key)
+			if known && previousKnown {
+				if distance := KeyDistance(previousKey, key); distance > maxKeyDistance {
+					itemPlan.Clash = true
+					plan.Clashes = append(plan.Clashes, KeyClash{
+						Set: i + 1, FromItem: previous.ItemId, ToItem: itemPlan.ItemId,
+						FromKey: previous.Key, ToKey: itemPlan.Key, Distance: distance, Segue: previous.Segue,
+					})
+				}
+			}
+			setPlan.Items = append(setPlan.Items, itemPlan)
+			previous, previousKey, previousKnown = itemPlan, key, known
+		}
+		plan.Duration += setPlan.Duration
+		plan.Sets = append(plan.Sets, setPlan)
+	}
+	return plan
+}
+
+// planItem дополняет песню сета длительностью, тональностью и темпом из листа аккордов песни.
+func planItem(item SetlistItem) ItemPlan {
+	itemPlan := ItemPlan{
+		ItemId:   item.Id,
+		SongId:   item.SongId,
+		Title:    item.Song.Group + " - " + item.Song.Song,
+		Duration: item.Duration,
+		Key:      item.Key,
+		Tempo:    item.Tempo,
+		Notes:    item.Notes,
+		Segue:    item.Segue,
+	}
+	if item.Song.SongDetails.ChordPro == "" || item.Duration != 0 && item.Key != "" && item.Tempo != 0 {
+		return itemPlan
+	}
+	sheet, err := ParseChordPro(item.Song.SongDetails.ChordPro)
+	if err != nil {
+		return itemPlan
+	}
+	for _, meta := range sheet.Meta {
+		switch {
+		case meta.Name == "duration" && itemPlan.Duration == 0:
+			if duration, err := ParseTrackDuration(meta.Value); err == nil {
+				itemPlan.Duration = duration
+			}
+		case meta.Name == "key" && itemPlan.Key == "":
+			itemPlan.Key = meta.Value
+		case meta.Name == "tempo" && itemPlan.Tempo == 0:
+			if tempo, err := strconv.Atoi(meta.Value); err == nil && tempo > 0 {
+				itemPlan.Tempo = tempo
+			}
+		}
+	}
+	return itemPlan
+}
+
+// Text записывает сет-лист для печати обычным текстом: песни с номерами, началом от начала сета,
+// длительностью, тональностью и темпом. Переход без паузы отмечен «→», конфликт тональностей — «!».
+func (p SetlistPlan) Text() string {
+	var b strings.Builder
+	b.WriteString(p.Name + "\n")
+	if p.Venue != "" {
+		b.WriteString(p.Venue + "\n")
+	}
+	if p.Notes != "" {
+		b.WriteString(p.Notes + "\n")
+	}
+	for _, set := range p.Sets {
+		fmt.Fprintf(&b, "\n%s — %s\n", set.Name, set.Duration)
+		for _, item := range set.Items {
+			clash := " "
+			if item.Clash {
+				clash = "!"
+			}
+			fmt.Fprintf(&b, "%s%3d. %7s  %s", clash, item.Number, item.Start, item.Title)
+			if details := item.Details(); details != "" {
+				b.WriteString("  [" + details + "]")
+			}
+			if item.Segue {
+				b.WriteString(" →")
+			}
+			b.WriteString("\n")
+			if item.Notes != "" {
+				b.WriteString("               " + item.Notes + "\n")
+			}
+		}
+	}
+	fmt.Fprintf(&b, "\nВсего: %s\n", p.Duration)
+	if p.Unknown > 0 {
+		fmt.Fprintf(&b, "Без длительности: %d\n", p.Unknown)
+	}
+	return b.String()
+}
+
+// Details возвращает длительность, тональность и темп песни через запятую.
+func (i ItemPlan) Details() string {
+	var parts []string
+	if i.Duration != 0 {
+		parts = append(parts, i.Duration.String())
+	}
+	if i.Key != "" {
+		parts = append(parts, i.Key)
+	}
+	if i.Tempo != 0 {
+		parts = append(parts, strconv.Itoa(i.Tempo)+" BPM")
+	}
+	return strings.Join(parts, ", ")
+}
+
+var setlistHTML = template.Must(template.New("setlist").Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head>
+<meta charset="utf-8">
+<title>{{.Name}}</title>
+<style>
+body { font-family: sans-serif; font-size: 14pt; margin: 1cm; }
+h1 { margin-bottom: 0; }
+h2 { page-break-before: always; border-bottom: 2px solid #000; }
+h2:first-of-type { page-break-before: auto; }
+table { width: 100%; border-collapse: collapse; }
+td { padding: 4px 8px; border-bottom: 1px solid #ccc; vertical-align: top; }
+td.number, td.time { width: 1%; white-space: nowrap; text-align: right; color: #555; }
+tr.clash td.number { color: #c00; font-weight: bold; }
+.title { font-weight: bold; font-size: 16pt; }
+.notes { font-style: italic; font-size: 11pt; }
+</style>
+</head>
+<body>
+<h1>{{.Name}}</h1>
+{{if .Venue}}<p>{{.Venue}}</p>{{end}}
+{{if .Notes}}<p class="notes">{{.Notes}}</p>{{end}}
+{{range .Sets}}
+<h2>{{.Name}} — {{.Duration}}</h2>
+<table>
+{{range .Items}}<tr{{if .Clash}} class="clash"{{end}}>
+<td class="number">{{if .Clash}}! {{end}}{{.Number}}</td>
+<td class="time">{{.Start}}</td>
+<td><span class="title">{{.Title}}</span>{{if .Segue}} →{{end}}{{with .Notes}}<br><span class="notes">{{.}}</span>{{end}}</td>
+<td class="time">{{.Details}}</td>
+</tr>
+{{end}}</table>
+{{end}}
+<p>Всего: {{.Duration}}{{if .Unknown}}, без длительности: {{.Unknown}}{{end}}</p>
+</body>
+</html>
+`))
+
+// HTML записывает сет-лист страницей HTML для печати, каждый сет на отдельной странице.
+func (p SetlistPlan) HTML() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := setlistHTML.Execute(&buf, p); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
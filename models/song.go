@@ -1,21 +1,57 @@
 package models
 
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
 // Song представляет собой модель песни.
 // @Description Модель песни
 type Song struct {
-	Id          int         `swaggerignore:"true" gorm:"primaryKey"`
-	Group       string      `json:"group"`                                                    //Название группы
-	Song        string      `json:"song"`                                                     //Название песни
-	SongDetails SongDetails `json:"SongDetail" swaggerignore:"true" gorm:"foreignKey:SongId"` //связь один к одному
+	Id               int              `swaggerignore:"true" gorm:"primaryKey"`
+	ArtistId         *int             `json:"artistId,omitempty"`                                             //ID исполнителя, если не задан, исполнитель ищется по group
+	Group            string           `json:"group" gorm:"->"`                                                //Название группы, берется из исполнителя
+	Song             string           `json:"song"`                                                           //Название песни
+	EnrichmentStatus EnrichmentStatus `json:"enrichmentStatus" swaggerignore:"true" gorm:"default:succeeded"` //Состояние получения доп данных
+	Version          int              `json:"version" swaggerignore:"true" gorm:"default:1"`                  //Версия, увеличивается при каждом изменении
+	ETag             string           `json:"etag,omitempty" swaggerignore:"true" gorm:"-"`                   //ETag песни в списках
+	CreatedAt        time.Time        `json:"createdAt" swaggerignore:"true"`                                 //Когда песня добавлена
+	UpdatedAt        time.Time        `json:"updatedAt" swaggerignore:"true"`                                 //Когда песня последний раз изменена
+	DeletedAt        *time.Time       `json:"deletedAt,omitempty" swaggerignore:"true"`                       //Когда песня перемещена в корзину
+	DeletedBy        string           `json:"deletedBy,omitempty" swaggerignore:"true"`                       //Кто переместил песню в корзину
+	SongDetails      SongDetails      `json:"SongDetail" swaggerignore:"true" gorm:"foreignKey:SongId"`       //связь один к одному
+	Score            float64          `json:"score,omitempty" swaggerignore:"true" gorm:"->"`                 //Сходство при нечетком поиске
+}
+
+// VersionETag возвращает ETag песни с версией version.
+func VersionETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// ValidateLink проверяет, что непустая ссылка на песню — абсолютный адрес http или https.
+func ValidateLink(link string) error {
+	if link == "" {
+		return nil
+	}
+	u, err := url.Parse(link)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("ожидается адрес http или https")
+	}
+	return nil
 }
 
 // SongDetails представляет собой модель дополнительных данных песни.
 // @Description Модель дополнительных данных песни
 type SongDetails struct {
-	SongId      int    `swaggerignore:"true"`          //Внешний ключ
-	Text        string `json:"text" example:""`        //Текст песни
-	ReleaseDate string `json:"releaseDate" example:""` //Дата выхода песни
-	Link        string `json:"link" example:""`        //Ссылка на песню
+	SongId      int          `swaggerignore:"true" gorm:"primaryKey;autoIncrement:false"`                   //Внешний ключ
+	Text        string       `json:"text" example:""`                                                       //Текст песни
+	ReleaseDate ReleaseDate  `json:"releaseDate" gorm:"embedded" swaggertype:"string" example:"16.07.2006"` //Дата выхода песни: DD.MM.YYYY, MM.YYYY или YYYY
+	Link        string       `json:"link" example:""`                                                       //Ссылка на песню
+	Sections    Lyrics       `json:"-" gorm:"type:jsonb"`                                                   //Текст, разбитый на части, обновляется вместе с Text
+	Synced      SyncedLyrics `json:"-" gorm:"column:synced_lyrics;type:jsonb"`                              //Строки текста с метками времени из LRC
+	ChordPro    string       `json:"-" gorm:"column:chordpro"`                                              //Лист аккордов в формате ChordPro
 }
 
 // SongWithDetails представляет собой модель песни с дополнительными данными песни.
@@ -25,3 +61,16 @@ type SongWithDetails struct {
 	Song        string      `json:"song" example:""`
 	SongDetails SongDetails `json:"SongDetails"`
 }
+
+// SongUpdate описывает изменения песни и её дополнительных данных.
+// Поля со значением nil остаются без изменений.
+type SongUpdate struct {
+	ArtistId    *int //если исполнитель есть, песня привязывается к нему, а Group не учитывается
+	Group       *string
+	Song        *string
+	Text        *string
+	ReleaseDate *ReleaseDate
+	Link        *string
+
+	IfVersion int //если не 0, песня изменяется только при совпадении версии
+}
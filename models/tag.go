@@ -0,0 +1,69 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Пространства имен тегов с особым смыслом. Кроме них можно использовать любые свои.
+const (
+	TagGenre    = "genre"    //жанр: genre:rock
+	TagMood     = "mood"     //настроение: mood:calm
+	TagLanguage = "language" //язык песни, языковой тег: language:ru
+	TagDefault  = "tag"      //пространство тегов, записанных без пространства имен
+)
+
+// maxTagName — наибольшая длина названия тега в символах.
+const maxTagName = 64
+
+var tagNamespacePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,31}$`)
+
+// Tag представляет собой тег песни в пространстве имен, например genre:rock.
+// @Description Тег песни
+type Tag struct {
+	Id        int    `json:"-" gorm:"primaryKey"`
+	Namespace string `json:"namespace" example:"genre"`                          //Пространство имен: genre, mood, language или свое
+	Name      string `json:"name" example:"rock"`                                //Название тега в пространстве имен
+	Songs     int    `json:"songs,omitempty" gorm:"->;-:migration" example:"12"` //Количество песен с тегом
+}
+
+// ParseTag разбирает тег вида namespace:name. Без пространства имен тег попадает в TagDefault.
+// Пространство имен и название приводятся к нижнему регистру, у языка — к принятому написанию тега.
+func ParseTag(value string) (Tag, error) {
+	namespace, name, ok := strings.Cut(strings.TrimSpace(value), ":")
+	if !ok {
+		namespace, name = TagDefault, namespace
+	}
+	namespace = strings.ToLower(strings.TrimSpace(namespace))
+	name = strings.Join(strings.Fields(strings.ToLower(name)), " ")
+
+	if !tagNamespacePattern.MatchString(namespace) {
+		return Tag{}, fmt.Errorf("некоректное пространство имен тега %q: латинские буквы, цифры, _ и -, до 32 символов", value)
+	}
+	if name == "" || utf8.RuneCountInString(name) > maxTagName {
+		return Tag{}, fmt.Errorf("некоректное название тега %q: от 1 до %d символов", value, maxTagName)
+	}
+	if namespace == TagLanguage {
+		normalized, ok := NormalizeLanguageTag(name)
+		if !ok {
+			return Tag{}, fmt.Errorf("некоректный язык %q, ожидается языковой тег, например en или ru-Latn", value)
+		}
+		name = normalized
+	}
+	return Tag{Namespace: namespace, Name: name}, nil
+}
+
+// String записывает тег в виде namespace:name.
+func (t Tag) String() string {
+	return t.Namespace + ":" + t.Name
+}
+
+// TagFacet — количество песен с тегом среди песен, подходящих к фильтрам списка.
+// @Description Количество песен с тегом
+type TagFacet struct {
+	Tag   string `json:"tag" example:"genre:rock"` //Тег для параметра tag
+	Name  string `json:"name" example:"rock"`
+	Count int    `json:"count" example:"12"`
+}
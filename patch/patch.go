@@ -0,0 +1,311 @@
+// Package patch применяет к JSON документам изменения в форматах JSON Merge Patch (RFC 7396)
+// и JSON Patch (RFC 6902). Документы представлены значениями, которые возвращает encoding/json
+// при разборе в interface{}: map[string]interface{}, []interface{}, string, float64, bool и nil.
+package patch
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Типы содержимого запросов с изменениями.
+const (
+	MergePatchType = "application/merge-patch+json"
+	JSONPatchType  = "application/json-patch+json"
+)
+
+// Error возвращается, если изменение составлено некоректно или не может быть применено к документу.
+type Error struct {
+	Op  int //номер операции JSON Patch, начиная с 0, -1 для merge patch
+	Msg string
+}
+
+func (e *Error) Error() string {
+	if e.Op < 0 {
+		return e.Msg
+	}
+	return fmt.Sprintf("операция %d: %s", e.Op, e.Msg)
+}
+
+// TestFailedError возвращается, если не выполнилась проверка операции test.
+type TestFailedError struct {
+	Op   int
+	Path string
+}
+
+func (e *TestFailedError) Error() string {
+	return fmt.Sprintf("операция %d: значение %s не совпадает с ожидаемым", e.Op, e.Path)
+}
+
+// Merge применяет merge patch к документу по RFC 7396: объекты объединяются рекурсивно,
+// null удаляет поле, любое другое значение заменяет поле целиком. Исходный документ не изменяется.
+func Merge(doc interface{}, mergePatch interface{}) interface{} {
+	patchObject, ok := mergePatch.(map[string]interface{})
+	if !ok {
+		return mergePatch
+	}
+	target, ok := doc.(map[string]interface{})
+	if !ok {
+		target = map[string]interface{}{}
+	}
+
+	result := make(map[string]interface{}, len(target))
+	for key, value := range target {
+		result[key] = value
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(result, key)
+			continue
+		}
+		result[key] = Merge(result[key], value)
+	}
+	return result
+}
+
+// Operation — операция JSON Patch. Value равно nil, только если value отсутствует:
+// value: null разбирается в RawMessage со значением null.
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// ParseOperations разбирает массив операций JSON Patch.
+func ParseOperations(data []byte) ([]Operation, error) {
+	var ops []Operation
+	if err := json.Unmarshal(data, &ops); err != nil {
+		return nil, &Error{Op: -1, Msg: "ожидается массив операций JSON Patch"}
+	}
+	return ops, nil
+}
+
+// Apply применяет операции JSON Patch к документу по порядку. Если одна из операций
+// не выполнилась, возвращается ошибка, а исходный документ не изменяется.
+func Apply(doc interface{}, ops []Operation) (interface{}, error) {
+	doc = deepCopy(doc)
+	for i, op := range ops {
+		path, err := parsePointer(op.Path)
+		if err != nil {
+			return nil, &Error{Op: i, Msg: err.Error()}
+		}
+
+		var value interface{}
+		switch op.Op {
+		case "add", "replace", "test":
+			if op.Value == nil {
+				return nil, &Error{Op: i, Msg: fmt.Sprintf("у операции %s нет value", op.Op)}
+			}
+			if err := json.Unmarshal(op.Value, &value); err != nil {
+				return nil, &Error{Op: i, Msg: "некоректное значение value"}
+			}
+		case "move", "copy":
+			from, err := parsePointer(op.From)
+			if err != nil {
+				return nil, &Error{Op: i, Msg: "from: " + err.Error()}
+			}
+			if op.Op == "move" && isPrefix(from, path) && len(from) < len(path) {
+				return nil, &Error{Op: i, Msg: "нельзя переместить значение внутрь самого себя"}
+			}
+			if value, err = get(doc, from); err != nil {
+				return nil, &Error{Op: i, Msg: "from: " + err.Error()}
+			}
+			value = deepCopy(value)
+			if op.Op == "move" {
+				if doc, err = remove(doc, from); err != nil {
+					return nil, &Error{Op: i, Msg: err.Error()}
+				}
+			}
+		case "remove":
+		default:
+			return nil, &Error{Op: i, Msg: fmt.Sprintf("неизвестная операция %q", op.Op)}
+		}
+
+		switch op.Op {
+		case "add", "move", "copy":
+			doc, err = add(doc, path, value)
+		case "remove":
+			doc, err = remove(doc, path)
+		case "replace":
+			if len(path) == 0 {
+				doc = value
+			} else if doc, err = remove(doc, path); err == nil {
+				doc, err = add(doc, path, value)
+			}
+		case "test":
+			var current interface{}
+			if current, err = get(doc, path); err == nil && !reflect.DeepEqual(current, value) {
+				return nil, &TestFailedError{Op: i, Path: op.Path}
+			}
+		}
+		if err != nil {
+			return nil, &Error{Op: i, Msg: err.Error()}
+		}
+	}
+	return doc, nil
+}
+
+// parsePointer разбирает JSON Pointer (RFC 6901) на части пути.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("путь %q должен начинаться с /", pointer)
+	}
+	parts := strings.Split(pointer[1:], "/")
+	for i, part := range parts {
+		parts[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(part)
+	}
+	return parts, nil
+}
+
+func isPrefix(prefix, path []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func get(doc interface{}, path []string) (interface{}, error) {
+	for _, key := range path {
+		switch node := doc.(type) {
+		case map[string]interface{}:
+			value, ok := node[key]
+			if !ok {
+				return nil, fmt.Errorf("поле %q не найдено", key)
+			}
+			doc = value
+		case []interface{}:
+			i, err := arrayIndex(key, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			doc = node[i]
+		default:
+			return nil, fmt.Errorf("нельзя получить %q у значения, которое не является объектом или массивом", key)
+		}
+	}
+	return doc, nil
+}
+
+// add добавляет значение по пути и возвращает измененный документ.
+func add(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	parent, err := get(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	key := path[len(path)-1]
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		node[key] = value
+		return doc, nil
+	case []interface{}:
+		i := len(node)
+		if key != "-" {
+			if i, err = arrayIndex(key, len(node)); err != nil {
+				return nil, err
+			}
+		}
+		node = append(node, nil)
+		copy(node[i+1:], node[i:])
+		node[i] = value
+		return set(doc, path[:len(path)-1], node)
+	default:
+		return nil, fmt.Errorf("нельзя добавить %q к значению, которое не является объектом или массивом", key)
+	}
+}
+
+// remove удаляет значение по пути и возвращает измененный документ.
+func remove(doc interface{}, path []string) (interface{}, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("нельзя удалить документ целиком")
+	}
+	parent, err := get(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	key := path[len(path)-1]
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		if _, ok := node[key]; !ok {
+			return nil, fmt.Errorf("поле %q не найдено", key)
+		}
+		delete(node, key)
+		return doc, nil
+	case []interface{}:
+		i, err := arrayIndex(key, len(node)-1)
+		if err != nil {
+			return nil, err
+		}
+		return set(doc, path[:len(path)-1], append(node[:i:i], node[i+1:]...))
+	default:
+		return nil, fmt.Errorf("нельзя удалить %q у значения, которое не является объектом или массивом", key)
+	}
+}
+
+// set заменяет существующее значение по пути, нужен после изменения длины массива.
+func set(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	parent, err := get(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		node[path[len(path)-1]] = value
+	case []interface{}:
+		i, err := arrayIndex(path[len(path)-1], len(node)-1)
+		if err != nil {
+			return nil, err
+		}
+		node[i] = value
+	}
+	return doc, nil
+}
+
+// arrayIndex разбирает индекс массива не больше max. По RFC 6901 индекс состоит только из цифр
+// без ведущих нулей, поэтому +1 и -1 не допускаются.
+func arrayIndex(key string, max int) (int, error) {
+	i, err := strconv.Atoi(key)
+	if err != nil || key[0] < '0' || key[0] > '9' || (len(key) > 1 && key[0] == '0') {
+		return 0, fmt.Errorf("некоректный индекс массива %q", key)
+	}
+	if i > max {
+		return 0, fmt.Errorf("индекс массива %d за его пределами", i)
+	}
+	return i, nil
+}
+
+func deepCopy(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[key] = deepCopy(item)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = deepCopy(item)
+		}
+		return result
+	default:
+		return value
+	}
+}
@@ -0,0 +1,166 @@
+package patch
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func decode(t *testing.T, data string) interface{} {
+	t.Helper()
+	var value interface{}
+	if err := json.Unmarshal([]byte(data), &value); err != nil {
+		t.Fatalf("некоректный JSON %s: %v", data, err)
+	}
+	return value
+}
+
+// Примеры из приложения A RFC 7396 и несколько своих.
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		doc, patch, want string
+	}{
+		{doc: `{"a":"b"}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{doc: `{"a":"b"}`, patch: `{"b":"c"}`, want: `{"a":"b","b":"c"}`},
+		{doc: `{"a":"b"}`, patch: `{"a":null}`, want: `{}`},
+		{doc: `{"a":"b","b":"c"}`, patch: `{"a":null}`, want: `{"b":"c"}`},
+		{doc: `{"a":["b"]}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{doc: `{"a":"c"}`, patch: `{"a":["b"]}`, want: `{"a":["b"]}`},
+		{doc: `{"a":{"b":"c"}}`, patch: `{"a":{"b":"d","c":null}}`, want: `{"a":{"b":"d"}}`},
+		{doc: `{"a":[{"b":"c"}]}`, patch: `{"a":[1]}`, want: `{"a":[1]}`},
+		{doc: `["a","b"]`, patch: `["c","d"]`, want: `["c","d"]`},
+		{doc: `{"a":"b"}`, patch: `["c"]`, want: `["c"]`},
+		{doc: `{"a":"foo"}`, patch: `null`, want: `null`},
+		{doc: `{"a":"foo"}`, patch: `"bar"`, want: `"bar"`},
+		{doc: `{"e":null}`, patch: `{"a":1}`, want: `{"e":null,"a":1}`},
+		{doc: `[1,2]`, patch: `{"a":"b","c":null}`, want: `{"a":"b"}`},
+		{doc: `{}`, patch: `{"a":{"bb":{"ccc":null}}}`, want: `{"a":{"bb":{}}}`},
+	}
+	for _, tt := range tests {
+		doc := decode(t, tt.doc)
+		got := Merge(doc, decode(t, tt.patch))
+		if want := decode(t, tt.want); !reflect.DeepEqual(got, want) {
+			t.Errorf("Merge(%s, %s) = %v, ожидалось %v", tt.doc, tt.patch, got, want)
+		}
+		if !reflect.DeepEqual(doc, decode(t, tt.doc)) {
+			t.Errorf("Merge(%s, %s) изменил исходный документ: %v", tt.doc, tt.patch, doc)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name, doc, ops, want string
+	}{
+		{name: "add в объект", doc: `{"foo":"bar"}`, ops: `[{"op":"add","path":"/baz","value":"qux"}]`, want: `{"foo":"bar","baz":"qux"}`},
+		{name: "add в массив", doc: `{"foo":["bar","baz"]}`, ops: `[{"op":"add","path":"/foo/1","value":"qux"}]`, want: `{"foo":["bar","qux","baz"]}`},
+		{name: "add в конец массива", doc: `{"foo":[1]}`, ops: `[{"op":"add","path":"/foo/-","value":2},{"op":"add","path":"/foo/2","value":3}]`, want: `{"foo":[1,2,3]}`},
+		{name: "add null", doc: `{"foo":"bar"}`, ops: `[{"op":"add","path":"/foo","value":null}]`, want: `{"foo":null}`},
+		{name: "add документа целиком", doc: `{"foo":"bar"}`, ops: `[{"op":"add","path":"","value":[1]}]`, want: `[1]`},
+		{name: "remove", doc: `{"baz":"qux","foo":"bar"}`, ops: `[{"op":"remove","path":"/baz"}]`, want: `{"foo":"bar"}`},
+		{name: "remove из массива", doc: `{"foo":["bar","qux","baz"]}`, ops: `[{"op":"remove","path":"/foo/1"}]`, want: `{"foo":["bar","baz"]}`},
+		{name: "replace", doc: `{"baz":"qux","foo":"bar"}`, ops: `[{"op":"replace","path":"/baz","value":"boo"}]`, want: `{"baz":"boo","foo":"bar"}`},
+		{name: "replace документа целиком", doc: `{"foo":"bar"}`, ops: `[{"op":"replace","path":"","value":{"baz":1}}]`, want: `{"baz":1}`},
+		{name: "move", doc: `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`, ops: `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`, want: `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{name: "move в массиве", doc: `{"foo":["all","grass","cows","eat"]}`, ops: `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, want: `{"foo":["all","cows","eat","grass"]}`},
+		{name: "move на то же место", doc: `{"foo":{"a":1}}`, ops: `[{"op":"move","from":"/foo","path":"/foo"}]`, want: `{"foo":{"a":1}}`},
+		{name: "copy не связывает значения", doc: `{"a":{"b":1}}`, ops: `[{"op":"copy","from":"/a","path":"/c"},{"op":"replace","path":"/c/b","value":2}]`, want: `{"a":{"b":1},"c":{"b":2}}`},
+		{name: "test", doc: `{"baz":"qux","foo":["a",2,"c"]}`, ops: `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`, want: `{"baz":"qux","foo":["a",2,"c"]}`},
+		{name: "test null", doc: `{"foo":null}`, ops: `[{"op":"test","path":"/foo","value":null}]`, want: `{"foo":null}`},
+		{name: "экранирование в пути", doc: `{"a/b":1,"m~n":2}`, ops: `[{"op":"replace","path":"/a~1b","value":3},{"op":"remove","path":"/m~0n"}]`, want: `{"a/b":3}`},
+		{name: "пустой ключ", doc: `{"":1}`, ops: `[{"op":"replace","path":"/","value":2}]`, want: `{"":2}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ops, err := ParseOperations([]byte(tt.ops))
+			if err != nil {
+				t.Fatalf("ParseOperations() ошибка = %v", err)
+			}
+			doc := decode(t, tt.doc)
+			got, err := Apply(doc, ops)
+			if err != nil {
+				t.Fatalf("Apply() ошибка = %v", err)
+			}
+			if want := decode(t, tt.want); !reflect.DeepEqual(got, want) {
+				t.Errorf("Apply() = %v, ожидалось %v", got, want)
+			}
+			if !reflect.DeepEqual(doc, decode(t, tt.doc)) {
+				t.Errorf("Apply() изменил исходный документ: %v", doc)
+			}
+		})
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	tests := []struct {
+		name, doc, ops string
+		wantOp         int
+	}{
+		{name: "неизвестная операция", doc: `{}`, ops: `[{"op":"merge","path":"/a"}]`},
+		{name: "нет value", doc: `{}`, ops: `[{"op":"add","path":"/a"}]`},
+		{name: "путь без /", doc: `{}`, ops: `[{"op":"add","path":"a","value":1}]`},
+		{name: "нет родителя", doc: `{}`, ops: `[{"op":"add","path":"/a/b","value":1}]`},
+		{name: "remove отсутствующего поля", doc: `{"a":1}`, ops: `[{"op":"remove","path":"/a"},{"op":"remove","path":"/a"}]`, wantOp: 1},
+		{name: "remove документа", doc: `{}`, ops: `[{"op":"remove","path":""}]`},
+		{name: "replace отсутствующего поля", doc: `{}`, ops: `[{"op":"replace","path":"/a","value":1}]`},
+		{name: "индекс за пределами", doc: `[1]`, ops: `[{"op":"add","path":"/2","value":1}]`},
+		{name: "индекс с ведущим нулем", doc: `[1,2]`, ops: `[{"op":"remove","path":"/01"}]`},
+		{name: "индекс со знаком", doc: `[1,2]`, ops: `[{"op":"add","path":"/+1","value":0}]`},
+		{name: "отрицательный индекс", doc: `[1,2]`, ops: `[{"op":"remove","path":"/-1"}]`},
+		{name: "- вне add", doc: `[1,2]`, ops: `[{"op":"remove","path":"/-"}]`},
+		{name: "путь внутрь строки", doc: `{"a":"b"}`, ops: `[{"op":"add","path":"/a/b","value":1}]`},
+		{name: "move внутрь себя", doc: `{"a":{"b":{}}}`, ops: `[{"op":"move","from":"/a","path":"/a/b/c"}]`},
+		{name: "move из отсутствующего поля", doc: `{}`, ops: `[{"op":"move","from":"/a","path":"/b"}]`},
+		{name: "copy без from", doc: `{"a":1}`, ops: `[{"op":"copy","from":"a","path":"/b"}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ops, err := ParseOperations([]byte(tt.ops))
+			if err != nil {
+				t.Fatalf("ParseOperations() ошибка = %v", err)
+			}
+			doc := decode(t, tt.doc)
+			_, err = Apply(doc, ops)
+			var patchErr *Error
+			if !errors.As(err, &patchErr) || patchErr.Op != tt.wantOp {
+				t.Fatalf("Apply() ошибка = %v, ожидалась ошибка операции %d", err, tt.wantOp)
+			}
+			if !reflect.DeepEqual(doc, decode(t, tt.doc)) {
+				t.Errorf("Apply() изменил исходный документ: %v", doc)
+			}
+		})
+	}
+}
+
+func TestApplyTestFailed(t *testing.T) {
+	ops, err := ParseOperations([]byte(`[{"op":"replace","path":"/a","value":2},{"op":"test","path":"/a","value":"2"}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Apply(decode(t, `{"a":1}`), ops)
+
+	var testErr *TestFailedError
+	if !errors.As(err, &testErr) || *testErr != (TestFailedError{Op: 1, Path: "/a"}) {
+		t.Errorf("Apply() ошибка = %v, ожидалась ошибка проверки операции 1", err)
+	}
+}
+
+func TestParseOperations(t *testing.T) {
+	ops, err := ParseOperations([]byte(`[{"op":"add","path":"/a","value":null},{"op":"remove","path":"/b"}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(ops[0].Value) != "null" || ops[1].Value != nil {
+		t.Errorf("value = %q и %q, ожидалось null и отсутствие значения", ops[0].Value, ops[1].Value)
+	}
+
+	for _, data := range []string{`{"op":"add"}`, `[{"op":1}]`, `not json`} {
+		var patchErr *Error
+		if _, err := ParseOperations([]byte(data)); !errors.As(err, &patchErr) || patchErr.Op != -1 {
+			t.Errorf("ParseOperations(%s) ошибка = %v, ожидалась ошибка разбора", data, err)
+		}
+	}
+}
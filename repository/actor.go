@@ -0,0 +1,24 @@
+package repository
+
+import "context"
+
+// Anonymous — автор изменений, если пользователь не указан.
+const Anonymous = "anonymous"
+
+// EnrichmentActor — автор изменений, внесенных при получении данных песни из внешнего API.
+const EnrichmentActor = "system:enrichment"
+
+type actorKey struct{}
+
+// WithActor возвращает контекст, изменения в котором записываются в историю от имени actor.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom возвращает автора изменений из контекста или Anonymous.
+func ActorFrom(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return Anonymous
+}
@@ -0,0 +1,284 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"song-library/models"
+)
+
+var (
+	// ErrInvalidCursor возвращается, если курсор не подходит к сортировке списка.
+	ErrInvalidCursor = errors.New("курсор не подходит к сортировке списка")
+	// ErrFuzzyCursor возвращается, если курсор передан вместе с нечетким поиском.
+	ErrFuzzyCursor = errors.New("курсор не поддерживается для нечеткого поиска")
+)
+
+// SortKey — поле сортировки списка песен.
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// Cursor — позиция в списке песен: значения полей сортировки и ID песни, рядом с которой продолжается список.
+// Песни без значения поля (NULL) идут в конце списка при любом направлении сортировки.
+type Cursor struct {
+	Values   []*string `json:"v"`           //значения полей сортировки по порядку, nil — NULL
+	Id       int       `json:"id"`          //ID песни, при равных значениях песни упорядочены по ID
+	Backward bool      `json:"b,omitempty"` //список продолжается перед позицией
+}
+
+// sortColumn описывает поле, по которому можно сортировать песни.
+type sortColumn struct {
+	sql   string                    //колонка в запросе к PostgreSQL
+	value func(models.Song) *string //значение поля песни, строки сравниваются в том же порядке, что значения
+	param func(string) (interface{}, error)
+}
+
+// timestampLayout записывает время с постоянной длиной, чтобы строки сравнивались в порядке времени.
+const timestampLayout = "2006-01-02T15:04:05.000000Z"
+
+// sortColumns — поля сортировки песен по названию.
+var sortColumns = map[string]sortColumn{
+	"group": {
+		sql: "artists.name",
+		value: func(song models.Song) *string {
+			if song.ArtistId == nil {
+				return nil
+			}
+			return &song.Group
+		},
+		param: textParam,
+	},
+	"song": {
+		sql:   "songs.song",
+		value: func(song models.Song) *string { return &song.Song },
+		param: textParam,
+	},
+	"release_date": {
+		sql: "song_details.released_on",
+		value: func(song models.Song) *string {
+			if song.SongDetails.ReleaseDate.IsZero() {
+				return nil
+			}
+			value := song.SongDetails.ReleaseDate.Start().Format("2006-01-02")
+			return &value
+		},
+		param: func(value string) (interface{}, error) {
+			return time.Parse("2006-01-02", value)
+		},
+	},
+	"created_at": {
+		sql:   "songs.created_at",
+		value: func(song models.Song) *string { return timestampValue(song.CreatedAt) },
+		param: timestampParam,
+	},
+	"updated_at": {
+		sql:   "songs.updated_at",
+		value: func(song models.Song) *string { return timestampValue(song.UpdatedAt) },
+		param: timestampParam,
+	},
+	"id": {
+		sql: "songs.id",
+		value: func(song models.Song) *string {
+			value := fmt.Sprintf("%020d", song.Id)
+			return &value
+		},
+		param: func(value string) (interface{}, error) {
+			return strconv.Atoi(value)
+		},
+	},
+}
+
+func textParam(value string) (interface{}, error) {
+	return value, nil
+}
+
+func timestampValue(t time.Time) *string {
+	value := t.UTC().Format(timestampLayout)
+	return &value
+}
+
+func timestampParam(value string) (interface{}, error) {
+	return time.Parse(timestampLayout, value)
+}
+
+// SortFields возвращает названия полей, по которым можно сортировать песни.
+func SortFields() []string {
+	fields := make([]string, 0, len(sortColumns))
+	for name := range sortColumns {
+		fields = append(fields, name)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// ParseSort разбирает сортировку вида group,-release_date,song: поля через запятую,
+// минус перед полем — сортировка по убыванию. Для совместимости asc и desc означают
+// сортировку по дате выхода. Неизвестные и повторяющиеся поля — ошибка.
+func ParseSort(value string) ([]SortKey, error) {
+	switch value {
+	case "asc":
+		return []SortKey{{Field: "release_date"}}, nil
+	case "desc":
+		return []SortKey{{Field: "release_date", Desc: true}}, nil
+	}
+
+	var keys []SortKey
+	seen := map[string]bool{}
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key := SortKey{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		if _, ok := sortColumns[key.Field]; !ok {
+			return nil, fmt.Errorf("неизвестное поле сортировки %q, допустимы %s", key.Field, strings.Join(SortFields(), ", "))
+		}
+		if seen[key.Field] {
+			return nil, fmt.Errorf("поле сортировки %q указано несколько раз", key.Field)
+		}
+		seen[key.Field] = true
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// sortKeys возвращает поля сортировки списка без завершающего ID, по умолчанию — по дате выхода.
+func (f SongFilter) sortKeys() []SortKey {
+	if len(f.Sort) == 0 {
+		return []SortKey{{Field: "release_date"}}
+	}
+	return f.Sort
+}
+
+// SongCursor возвращает позицию песни в списке, отсортированном по фильтру.
+func SongCursor(song models.Song, filter SongFilter) Cursor {
+	keys := filter.sortKeys()
+	cursor := Cursor{Values: make([]*string, len(keys)), Id: song.Id}
+	for i, key := range keys {
+		cursor.Values[i] = sortColumns[key.Field].value(song)
+	}
+	return cursor
+}
+
+// checkCursor проверяет, что курсор подходит к полям сортировки.
+func checkCursor(cursor *Cursor, keys []SortKey) error {
+	if len(cursor.Values) != len(keys) {
+		return ErrInvalidCursor
+	}
+	for i, key := range keys {
+		if cursor.Values[i] == nil {
+			continue
+		}
+		if _, err := sortColumns[key.Field].param(*cursor.Values[i]); err != nil {
+			return ErrInvalidCursor
+		}
+	}
+	return nil
+}
+
+// orderSQL возвращает порядок сортировки для ORDER BY. Для страницы перед курсором порядок обратный.
+func orderSQL(keys []SortKey, backward bool) string {
+	var parts []string
+	for _, key := range keys {
+		desc := key.Desc != backward
+		nulls := "NULLS LAST"
+		if backward {
+			nulls = "NULLS FIRST"
+		}
+		direction := "ASC"
+		if desc {
+			direction = "DESC"
+		}
+		parts = append(parts, sortColumns[key.Field].sql+" "+direction+" "+nulls)
+	}
+	if backward {
+		parts = append(parts, "songs.id DESC")
+	} else {
+		parts = append(parts, "songs.id ASC")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// keysetSQL возвращает условие, которому удовлетворяют песни после позиции курсора,
+// а для cursor.Backward — перед ней.
+func keysetSQL(keys []SortKey, cursor Cursor) (string, []interface{}, error) {
+	var alternatives []string
+	var args []interface{}
+	var equal []string //условия равенства предыдущих полей
+	var equalArgs []interface{}
+
+	for i, key := range keys {
+		column := sortColumns[key.Field]
+		value := cursor.Values[i]
+
+		var beyond string
+		var beyondArgs []interface{}
+		if value == nil {
+			if cursor.Backward { //перед NULL все песни со значением
+				beyond = column.sql + " IS NOT NULL"
+			}
+		} else {
+			param, err := column.param(*value)
+			if err != nil {
+				return "", nil, ErrInvalidCursor
+			}
+			op := ">"
+			if key.Desc != cursor.Backward {
+				op = "<"
+			}
+			beyond = fmt.Sprintf("%s %s ?", column.sql, op)
+			if !cursor.Backward { //после значения все песни без значения
+				beyond = fmt.Sprintf("(%s OR %s IS NULL)", beyond, column.sql)
+			}
+			beyondArgs = []interface{}{param}
+		}
+		if beyond != "" {
+			alternatives = append(alternatives, "("+strings.Join(append(append([]string{}, equal...), beyond), " AND ")+")")
+			args = append(append(args, equalArgs...), beyondArgs...)
+		}
+
+		if value == nil {
+			equal = append(equal, column.sql+" IS NULL")
+		} else {
+			param, _ := column.param(*value)
+			equal = append(equal, column.sql+" = ?")
+			equalArgs = append(equalArgs, param)
+		}
+	}
+
+	op := ">"
+	if cursor.Backward {
+		op = "<"
+	}
+	alternatives = append(alternatives, "("+strings.Join(append(equal, "songs.id "+op+" ?"), " AND ")+")")
+	args = append(append(args, equalArgs...), cursor.Id)
+	return "(" + strings.Join(alternatives, " OR ") + ")", args, nil
+}
+
+// compareSongs сравнивает позиции песен в списке, отсортированном по keys: отрицательное значение — a раньше b.
+func compareSongs(a, b Cursor, keys []SortKey) int {
+	for i, key := range keys {
+		x, y := a.Values[i], b.Values[i]
+		switch {
+		case x == nil && y == nil:
+			continue
+		case x == nil:
+			return 1
+		case y == nil:
+			return -1
+		}
+		if c := strings.Compare(*x, *y); c != 0 {
+			if key.Desc {
+				return -c
+			}
+			return c
+		}
+	}
+	return a.Id - b.Id
+}
@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"song-library/models"
+)
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    []SortKey
+		wantErr bool
+	}{
+		{value: "", want: nil},
+		{value: "asc", want: []SortKey{{Field: "release_date"}}},
+		{value: "desc", want: []SortKey{{Field: "release_date", Desc: true}}},
+		{value: "group,-release_date,song", want: []SortKey{{Field: "group"}, {Field: "release_date", Desc: true}, {Field: "song"}}},
+		{value: " -id , ,created_at ", want: []SortKey{{Field: "id", Desc: true}, {Field: "created_at"}}},
+		{value: "title", wantErr: true},
+		{value: "song,-song", wantErr: true},
+		{value: "--song", wantErr: true},
+		{value: "Song", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSort(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSort(%q) ошибка = %v, ожидалась ошибка %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseSort(%q) = %v, ожидалось %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestCompareSongs(t *testing.T) {
+	str := func(s string) *string { return &s }
+	asc := []SortKey{{Field: "song"}}
+	desc := []SortKey{{Field: "song", Desc: true}}
+	two := []SortKey{{Field: "group"}, {Field: "song", Desc: true}}
+
+	tests := []struct {
+		name string
+		a, b Cursor
+		keys []SortKey
+		want int //знак результата
+	}{
+		{name: "по возрастанию", a: Cursor{Values: []*string{str("a")}, Id: 2}, b: Cursor{Values: []*string{str("b")}, Id: 1}, keys: asc, want: -1},
+		{name: "по убыванию", a: Cursor{Values: []*string{str("a")}, Id: 2}, b: Cursor{Values: []*string{str("b")}, Id: 1}, keys: desc, want: 1},
+		{name: "равные значения по ID", a: Cursor{Values: []*string{str("a")}, Id: 2}, b: Cursor{Values: []*string{str("a")}, Id: 1}, keys: desc, want: 1},
+		{name: "NULL в конце по возрастанию", a: Cursor{Values: []*string{nil}, Id: 1}, b: Cursor{Values: []*string{str("a")}, Id: 2}, keys: asc, want: 1},
+		{name: "NULL в конце по убыванию", a: Cursor{Values: []*string{str("a")}, Id: 2}, b: Cursor{Values: []*string{nil}, Id: 1}, keys: desc, want: -1},
+		{name: "оба NULL", a: Cursor{Values: []*string{nil}, Id: 1}, b: Cursor{Values: []*string{nil}, Id: 2}, keys: asc, want: -1},
+		{name: "второе поле", a: Cursor{Values: []*string{str("g"), str("a")}, Id: 1}, b: Cursor{Values: []*string{str("g"), str("b")}, Id: 2}, keys: two, want: 1},
+		{name: "первое поле важнее", a: Cursor{Values: []*string{str("a"), str("a")}, Id: 1}, b: Cursor{Values: []*string{str("b"), str("b")}, Id: 2}, keys: two, want: -1},
+	}
+	for _, tt := range tests {
+		got := compareSongs(tt.a, tt.b, tt.keys)
+		if sign(got) != tt.want {
+			t.Errorf("%s: compareSongs() = %d, ожидался знак %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+func TestKeysetSQL(t *testing.T) {
+	value := "Hysteria"
+	keys := []SortKey{{Field: "release_date", Desc: true}, {Field: "song"}}
+
+	tests := []struct {
+		name     string
+		cursor   Cursor
+		wantSQL  string
+		wantArgs int
+	}{
+		{
+			name:     "после курсора с NULL",
+			cursor:   Cursor{Values: []*string{nil, &value}, Id: 7},
+			wantSQL:  "((song_details.released_on IS NULL AND (songs.song > ? OR songs.song IS NULL)) OR (song_details.released_on IS NULL AND songs.song = ? AND songs.id > ?))",
+			wantArgs: 3,
+		},
+		{
+			name:     "перед курсором с NULL",
+			cursor:   Cursor{Values: []*string{nil, &value}, Id: 7, Backward: true},
+			wantSQL:  "((song_details.released_on IS NOT NULL) OR (song_details.released_on IS NULL AND songs.song < ?) OR (song_details.released_on IS NULL AND songs.song = ? AND songs.id < ?))",
+			wantArgs: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := keysetSQL(keys, tt.cursor)
+			if err != nil {
+				t.Fatalf("keysetSQL() ошибка = %v", err)
+			}
+			if sql != tt.wantSQL || len(args) != tt.wantArgs {
+				t.Errorf("keysetSQL() = %s, %v, ожидалось %s и %d аргумента", sql, args, tt.wantSQL, tt.wantArgs)
+			}
+		})
+	}
+
+	bad := "16.07.2006"
+	if _, _, err := keysetSQL(keys, Cursor{Values: []*string{&bad, &value}}); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("keysetSQL() с некоректной датой ошибка = %v, ожидалась %v", err, ErrInvalidCursor)
+	}
+}
+
+// TestMemoryListSongsCursor проходит список по курсорам вперед и назад и сверяет его с пагинацией по смещению.
+func TestMemoryListSongsCursor(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemory()
+	for _, s := range []struct{ song, date string }{
+		{"b", "2006"}, {"a", "2006"}, {"c", ""}, {"d", "01.2001"}, {"e", ""}, {"f", "2010"}, {"g", "2006"},
+	} {
+		song := models.Song{Group: "Muse", Song: s.song}
+		if s.date != "" {
+			date, err := models.ParseReleaseDate(s.date)
+			if err != nil {
+				t.Fatal(err)
+			}
+			song.SongDetails.ReleaseDate = date
+		}
+		if err := repo.CreateSong(ctx, &song); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sortKeys, err := ParseSort("-release_date,song")
+	if err != nil {
+		t.Fatal(err)
+	}
+	filter := SongFilter{Sort: sortKeys, Limit: 100}
+	all, err := repo.ListSongs(ctx, filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := titles(all), []string{"f", "a", "b", "g", "d", "c", "e"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("ListSongs() = %v, ожидалось %v", got, want)
+	}
+
+	filter.Limit = 3
+	var forward []models.Song
+	for page := (*Cursor)(nil); ; {
+		filter.Cursor = page
+		songs, err := repo.ListSongs(ctx, filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(songs) == 0 {
+			break
+		}
+		forward = append(forward, songs...)
+		cursor := SongCursor(songs[len(songs)-1], filter)
+		page = &cursor
+	}
+	if !reflect.DeepEqual(titles(forward), titles(all)) {
+		t.Errorf("список по курсорам вперед = %v, ожидалось %v", titles(forward), titles(all))
+	}
+
+	cursor := SongCursor(all[len(all)-1], filter)
+	cursor.Backward = true
+	var backward []models.Song
+	for {
+		filter.Cursor = &cursor
+		songs, err := repo.ListSongs(ctx, filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(songs) == 0 {
+			break
+		}
+		backward = append(append([]models.Song{}, songs...), backward...)
+		cursor = SongCursor(songs[0], filter)
+		cursor.Backward = true
+	}
+	if got, want := titles(backward), titles(all[:len(all)-1]); !reflect.DeepEqual(got, want) {
+		t.Errorf("список по курсорам назад = %v, ожидалось %v", got, want)
+	}
+
+	filter.Cursor = &Cursor{Values: []*string{nil}, Id: 1}
+	if _, err := repo.ListSongs(ctx, filter); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("ListSongs() с курсором другой сортировки ошибка = %v, ожидалась %v", err, ErrInvalidCursor)
+	}
+	filter.Cursor, filter.Fuzzy = &Cursor{Values: []*string{nil, nil}, Id: 1}, true
+	if _, err := repo.ListSongs(ctx, filter); !errors.Is(err, ErrFuzzyCursor) {
+		t.Errorf("ListSongs() с курсором и нечетким поиском ошибка = %v, ожидалась %v", err, ErrFuzzyCursor)
+	}
+}
+
+func titles(songs []models.Song) []string {
+	result := []string{}
+	for _, song := range songs {
+		result = append(result, song.Song)
+	}
+	return result
+}
@@ -0,0 +1,493 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"song-library/models"
+)
+
+// Memory хранит данные библиотеки в памяти процесса.
+// Подходит для тестов и локальной демонстрации, данные теряются при перезапуске.
+type Memory struct {
+	mu     sync.RWMutex
+	songs  map[int]models.Song
+	nextID int
+
+	jobs      map[int]models.EnrichmentJob //задания по ID песни
+	nextJobID int
+
+	artists      map[int]models.Artist
+	nextArtistID int
+
+	albums      map[int]models.Album
+	nextAlbumID int
+	tracks      []models.AlbumTrack
+
+	playlists      map[int]models.Playlist
+	nextPlaylistID int
+	entries        map[int][]models.PlaylistEntry //записи по ID плейлиста в порядке позиций
+	nextEntryID    int
+
+	setlists      map[int]models.Setlist //сет-листы вместе с сетами и песнями
+	nextSetlistID int
+	nextSetID     int
+	nextItemID    int
+
+	tags      map[int]models.Tag
+	nextTagID int
+	songTags  map[int]map[int]bool //ID тегов по ID песни
+
+	revisions      map[int][]models.SongRevision //история изменений по ID песни, от старых правок к новым
+	nextRevisionID int
+
+	lyrics       map[int][]models.LyricVersion //версии текста по ID песни
+	nextLyricsID int
+}
+
+// NewMemory создает пустое хранилище в памяти.
+func NewMemory() *Memory {
+	return &Memory{
+		songs:  make(map[int]models.Song),
+		nextID: 1,
+
+		jobs:      make(map[int]models.EnrichmentJob),
+		nextJobID: 1,
+
+		artists:      make(map[int]models.Artist),
+		nextArtistID: 1,
+
+		albums:      make(map[int]models.Album),
+		nextAlbumID: 1,
+
+		playlists:      make(map[int]models.Playlist),
+		nextPlaylistID: 1,
+		entries:        make(map[int][]models.PlaylistEntry),
+		nextEntryID:    1,
+
+		setlists:      make(map[int]models.Setlist),
+		nextSetlistID: 1,
+		nextSetID:     1,
+		nextItemID:    1,
+
+		tags:      make(map[int]models.Tag),
+		nextTagID: 1,
+		songTags:  make(map[int]map[int]bool),
+
+		revisions:      make(map[int][]models.SongRevision),
+		nextRevisionID: 1,
+
+		lyrics:       make(map[int][]models.LyricVersion),
+		nextLyricsID: 1,
+	}
+}
+
+func (m *Memory) ListSongs(ctx context.Context, filter SongFilter) ([]models.Song, error) {
+	keys := filter.sortKeys()
+	if filter.Cursor != nil {
+		if filter.Fuzzy {
+			return nil, ErrFuzzyCursor
+		}
+		if err := checkCursor(filter.Cursor, keys); err != nil {
+			return nil, err
+		}
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	songs := m.sortedSongs(filter)
+	if filter.Cursor == nil {
+		return paginate(songs, filter.Offset, filter.Limit), nil
+	}
+	var page []models.Song
+	for _, song := range songs {
+		c := compareSongs(SongCursor(song, filter), *filter.Cursor, keys)
+		if c > 0 && !filter.Cursor.Backward || c < 0 && filter.Cursor.Backward {
+			page = append(page, song)
+		}
+	}
+	if filter.Cursor.Backward && len(page) > filter.Limit { //ближайшие к курсору песни в конце
+		page = page[len(page)-filter.Limit:]
+	}
+	return paginate(page, 0, filter.Limit), nil
+}
+
+func (m *Memory) ExportSongs(ctx context.Context, filter SongFilter, fn func(song models.Song) error) error {
+	m.mu.RLock()
+	songs := m.sortedSongs(filter)
+	m.mu.RUnlock()
+
+	for _, song := range songs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(song); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortedSongs возвращает песни, удовлетворяющие фильтру, в порядке сортировки без пагинации.
+func (m *Memory) sortedSongs(filter SongFilter) []models.Song {
+	keys := filter.sortKeys()
+	songs := m.filterSongs(filter)
+	sort.SliceStable(songs, func(i, j int) bool {
+		if songs[i].Score != songs[j].Score {
+			return songs[i].Score > songs[j].Score
+		}
+		return compareSongs(SongCursor(songs[i], filter), SongCursor(songs[j], filter), keys) < 0
+	})
+	return songs
+}
+
+func (m *Memory) CountSongs(ctx context.Context, filter SongFilter) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return len(m.filterSongs(filter)), nil
+}
+
+// filterSongs возвращает песни, удовлетворяющие фильтру, в произвольном порядке, вызывается под блокировкой.
+func (m *Memory) filterSongs(filter SongFilter) []models.Song {
+	text := strings.ReplaceAll(filter.Text, `\n`, "\n")
+
+	songs := make([]models.Song, 0, len(m.songs))
+	for _, song := range m.songs {
+		if song.DeletedAt != nil && !filter.IncludeDeleted {
+			continue
+		}
+		song = m.view(song)
+		if filter.ArtistID != 0 && (song.ArtistId == nil || *song.ArtistId != filter.ArtistID) {
+			continue
+		}
+		if filter.Fuzzy {
+			score, ok := fuzzyScore(song, filter)
+			if !ok {
+				continue
+			}
+			song.Score = score
+		} else {
+			if filter.Group != "" && song.Group != filter.Group && !m.hasAlias(song.ArtistId, filter.Group) {
+				continue
+			}
+			if filter.Song != "" && song.Song != filter.Song {
+				continue
+			}
+		}
+		if filter.Link != "" && song.SongDetails.Link != filter.Link {
+			continue
+		}
+		if filter.Album != "" && !m.onAlbum(song.Id, filter.Album) {
+			continue
+		}
+		if text != "" && !strings.Contains(song.SongDetails.Text, text) {
+			continue
+		}
+		if !releasedWithin(song.SongDetails.ReleaseDate, filter) {
+			continue
+		}
+		if len(filter.Tags) > 0 && !m.hasTags(song.Id, filter.Tags, filter.AnyTag) {
+			continue
+		}
+		songs = append(songs, song)
+	}
+	return songs
+}
+
+func (m *Memory) GetSong(ctx context.Context, id int) (*models.Song, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	song, ok := m.liveSong(id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	song = m.view(song)
+	return &song, nil
+}
+
+func (m *Memory) FindSong(ctx context.Context, group, name string) (*models.Song, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	artist, ok := m.findArtist(group)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	for _, song := range m.songs {
+		if song.DeletedAt == nil && song.ArtistId != nil && *song.ArtistId == artist.Id && song.Song == name {
+			song = m.view(song)
+			return &song, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (m *Memory) CreateSong(ctx context.Context, song *models.Song) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if song.ArtistId == nil {
+		artist, err := m.resolveArtist(song.Group)
+		if err != nil {
+			return err
+		}
+		song.ArtistId = &artist.Id
+	} else if _, ok := m.artists[*song.ArtistId]; !ok {
+		return ErrConflict
+	}
+	*song = m.view(*song)
+
+	song.Id = m.nextID
+	song.SongDetails.SongId = song.Id
+	song.SongDetails.Sections = models.ParseLyrics(song.SongDetails.Text)
+	m.nextID++
+	song.Version = 1
+	song.CreatedAt = time.Now()
+	song.UpdatedAt = song.CreatedAt
+	if song.EnrichmentStatus == "" {
+		song.EnrichmentStatus = models.EnrichmentSucceeded
+	}
+
+	m.songs[song.Id] = *song
+	m.recordRevision(song.Id, nil, ActorFrom(ctx), nil)
+	if song.EnrichmentStatus == models.EnrichmentPending {
+		m.newJob(song.Id)
+	}
+	return nil
+}
+
+func (m *Memory) UpdateSong(ctx context.Context, id int, update models.SongUpdate) (*models.Song, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.updateSong(id, update, ActorFrom(ctx), nil)
+}
+
+// updateSong изменяет песню и записывает правку в историю, вызывается под блокировкой.
+func (m *Memory) updateSong(id int, update models.SongUpdate, author string, restoredFrom *int) (*models.Song, error) {
+	song, ok := m.liveSong(id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if update.IfVersion != 0 && update.IfVersion != song.Version {
+		return nil, ErrVersionMismatch
+	}
+	touch(&song)
+	before := models.SnapshotOf(m.view(song))
+
+	keepArtist := false
+	if update.ArtistId != nil {
+		_, keepArtist = m.artists[*update.ArtistId]
+	}
+	if keepArtist { //исполнитель мог быть переименован, группа берется из него
+		artistID := *update.ArtistId
+		song.ArtistId = &artistID
+	} else if update.Group != nil {
+		artist, err := m.resolveArtist(*update.Group)
+		if err != nil {
+			return nil, err
+		}
+		song.ArtistId = &artist.Id
+	}
+	if update.Song != nil {
+		song.Song = *update.Song
+	}
+	if update.Text != nil {
+		song.SongDetails.Text = *update.Text
+		song.SongDetails.Sections = models.ParseLyrics(*update.Text)
+	}
+	if update.Link != nil {
+		song.SongDetails.Link = *update.Link
+	}
+	if update.ReleaseDate != nil {
+		song.SongDetails.ReleaseDate = *update.ReleaseDate
+	}
+
+	m.songs[id] = song
+	song = m.view(song)
+	m.recordRevision(id, &before, author, restoredFrom)
+	return &song, nil
+}
+
+func (m *Memory) SetSyncedLyrics(ctx context.Context, id int, synced models.SyncedLyrics) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	song, ok := m.liveSong(id)
+	if !ok {
+		return ErrNotFound
+	}
+	song.SongDetails.Synced = synced
+	m.songs[id] = song
+	return nil
+}
+
+func (m *Memory) SetChordPro(ctx context.Context, id int, doc string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	song, ok := m.liveSong(id)
+	if !ok {
+		return ErrNotFound
+	}
+	song.SongDetails.ChordPro = doc
+	m.songs[id] = song
+	return nil
+}
+
+func (m *Memory) DeleteSong(ctx context.Context, id, ifVersion int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	song, ok := m.liveSong(id)
+	if !ok {
+		return ErrNotFound
+	}
+	if ifVersion != 0 && ifVersion != song.Version {
+		return ErrVersionMismatch
+	}
+	touch(&song)
+	now := time.Now()
+	song.DeletedAt = &now
+	song.DeletedBy = ActorFrom(ctx)
+	m.songs[id] = song
+	return nil
+}
+
+func (m *Memory) RestoreSong(ctx context.Context, id int) (*models.Song, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	song, ok := m.songs[id]
+	if !ok || song.DeletedAt == nil {
+		return nil, ErrNotFound
+	}
+	for _, other := range m.songs { //пока песня была в корзине, такую же могли добавить заново
+		if other.DeletedAt == nil && other.ArtistId != nil && song.ArtistId != nil && *other.ArtistId == *song.ArtistId && other.Song == song.Song {
+			return nil, ErrConflict
+		}
+	}
+	song.DeletedAt = nil
+	song.DeletedBy = ""
+	touch(&song)
+	m.songs[id] = song
+	song = m.view(song)
+	return &song, nil
+}
+
+func (m *Memory) ListDeletedSongs(ctx context.Context, offset, limit int) ([]models.Song, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	songs := []models.Song{}
+	for _, song := range m.songs {
+		if song.DeletedAt != nil {
+			songs = append(songs, m.view(song))
+		}
+	}
+	sort.Slice(songs, func(i, j int) bool {
+		if !songs[i].DeletedAt.Equal(*songs[j].DeletedAt) {
+			return songs[i].DeletedAt.After(*songs[j].DeletedAt)
+		}
+		return songs[i].Id < songs[j].Id
+	})
+	return paginate(songs, offset, limit), nil
+}
+
+func (m *Memory) PurgeDeletedSongs(ctx context.Context, before time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	purged := 0
+	for id, song := range m.songs {
+		if song.DeletedAt == nil || !song.DeletedAt.Before(before) {
+			continue
+		}
+		delete(m.songs, id)
+		delete(m.jobs, id)
+		delete(m.lyrics, id)
+		delete(m.revisions, id)
+		m.removeTracks(func(track models.AlbumTrack) bool { return track.SongId == id })
+		m.removeEntries(id)
+		m.removeSetlistItems(id)
+		delete(m.songTags, id)
+		purged++
+	}
+	return purged, nil
+}
+
+// touch отмечает изменение песни: увеличивает версию и время изменения.
+func touch(song *models.Song) {
+	song.Version++
+	song.UpdatedAt = time.Now()
+}
+
+// liveSong возвращает песню, если она есть и не находится в корзине.
+func (m *Memory) liveSong(id int) (models.Song, bool) {
+	song, ok := m.songs[id]
+	if !ok || song.DeletedAt != nil {
+		return models.Song{}, false
+	}
+	return song, true
+}
+
+// fuzzyScore возвращает среднее сходство песни с заданными фильтрами Group и Song
+// и признак того, что каждое сходство не ниже порога.
+func fuzzyScore(song models.Song, filter SongFilter) (float64, bool) {
+	var total float64
+	var count int
+	for _, pair := range [][2]string{{song.Group, filter.Group}, {song.Song, filter.Song}} {
+		if pair[1] == "" {
+			continue
+		}
+		score := similarity(normalizeName(pair[0]), normalizeName(pair[1]))
+		if score < filter.Threshold {
+			return 0, false
+		}
+		total += score
+		count++
+	}
+	if count == 0 {
+		return 0, true
+	}
+	return total / float64(count), true
+}
+
+// releasedWithin проверяет, что период выхода песни пересекается с периодом из фильтра.
+func releasedWithin(date models.ReleaseDate, filter SongFilter) bool {
+	if filter.ReleasedFrom == nil && filter.ReleasedBefore == nil {
+		return true
+	}
+	if date.IsZero() {
+		return false
+	}
+	if filter.ReleasedFrom != nil && !date.End().After(*filter.ReleasedFrom) {
+		return false
+	}
+	if filter.ReleasedBefore != nil && !date.Start().Before(*filter.ReleasedBefore) {
+		return false
+	}
+	return true
+}
+
+// paginate возвращает срез с учетом смещения и лимита. Пустой результат — пустой срез, а не nil,
+// чтобы списки выводились в JSON как [], как у Postgres.
+func paginate[T any](items []T, offset, limit int) []T {
+	if items == nil {
+		return []T{}
+	}
+	if offset > len(items) {
+		offset = len(items)
+	}
+	end := offset + limit
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[offset:end]
+}
@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"song-library/models"
+)
+
+func (m *Memory) ListAlbums(ctx context.Context, filter AlbumFilter) ([]models.Album, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	title := normalizeName(filter.Title)
+	albums := make([]models.Album, 0, len(m.albums))
+	for _, album := range m.albums {
+		if filter.ArtistID != 0 && (album.ArtistId == nil || *album.ArtistId != filter.ArtistID) {
+			continue
+		}
+		if title != "" && !strings.Contains(normalizeName(album.Title), title) {
+			continue
+		}
+		albums = append(albums, m.albumView(album))
+	}
+
+	sort.Slice(albums, func(i, j int) bool {
+		a, b := albums[i].ReleaseDate, albums[j].ReleaseDate
+		if a.IsZero() != b.IsZero() {
+			return !a.IsZero()
+		}
+		if !a.Start().Equal(b.Start()) {
+			return a.Start().Before(b.Start())
+		}
+		return albums[i].Id < albums[j].Id
+	})
+	return paginate(albums, filter.Offset, filter.Limit), nil
+}
+
+func (m *Memory) GetAlbum(ctx context.Context, id int) (*models.Album, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	album, ok := m.albums[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	album = m.albumView(album)
+	return &album, nil
+}
+
+func (m *Memory) CreateAlbum(ctx context.Context, album *models.Album) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if album.ArtistId != nil {
+		if _, ok := m.artists[*album.ArtistId]; !ok {
+			return ErrConflict
+		}
+	}
+	album.Id = m.nextAlbumID
+	m.nextAlbumID++
+	m.albums[album.Id] = *album
+	*album = m.albumView(*album)
+	return nil
+}
+
+func (m *Memory) UpdateAlbum(ctx context.Context, id int, album models.Album) (*models.Album, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.albums[id]; !ok {
+		return nil, ErrNotFound
+	}
+	if album.ArtistId != nil {
+		if _, ok := m.artists[*album.ArtistId]; !ok {
+			return nil, ErrConflict
+		}
+	}
+	album.Id = id
+	m.albums[id] = album
+	album = m.albumView(album)
+	return &album, nil
+}
+
+func (m *Memory) DeleteAlbum(ctx context.Context, id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.albums[id]; !ok {
+		return ErrNotFound
+	}
+	delete(m.albums, id)
+	m.removeTracks(func(track models.AlbumTrack) bool { return track.AlbumId == id })
+	return nil
+}
+
+func (m *Memory) ListAlbumTracks(ctx context.Context, albumID int) ([]models.AlbumTrack, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if _, ok := m.albums[albumID]; !ok {
+		return nil, ErrNotFound
+	}
+	tracks := []models.AlbumTrack{}
+	for _, track := range m.tracks {
+		if track.AlbumId != albumID {
+			continue
+		}
+		song, ok := m.liveSong(track.SongId)
+		if !ok { //песни из корзины не показываются
+			continue
+		}
+		song = m.view(song)
+		track.Song = &song
+		tracks = append(tracks, track)
+	}
+
+	sort.Slice(tracks, func(i, j int) bool {
+		if tracks[i].DiscNumber != tracks[j].DiscNumber {
+			return tracks[i].DiscNumber < tracks[j].DiscNumber
+		}
+		return tracks[i].TrackNumber < tracks[j].TrackNumber
+	})
+	return tracks, nil
+}
+
+func (m *Memory) ListSongTracks(ctx context.Context, songID int) ([]models.AlbumTrack, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	tracks := []models.AlbumTrack{}
+	for _, track := range m.tracks {
+		if track.SongId != songID {
+			continue
+		}
+		album := m.albumView(m.albums[track.AlbumId])
+		track.Album = &album
+		tracks = append(tracks, track)
+	}
+
+	sort.SliceStable(tracks, func(i, j int) bool {
+		a, b := tracks[i].Album.ReleaseDate, tracks[j].Album.ReleaseDate
+		if a.IsZero() != b.IsZero() { //альбомы без даты выхода в конце
+			return b.IsZero()
+		}
+		if !a.Start().Equal(b.Start()) {
+			return a.Start().Before(b.Start())
+		}
+		return tracks[i].AlbumId < tracks[j].AlbumId
+	})
+	return tracks, nil
+}
+
+func (m *Memory) SetAlbumTrack(ctx context.Context, track models.AlbumTrack) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.albums[track.AlbumId]; !ok {
+		return ErrNotFound
+	}
+	if _, ok := m.liveSong(track.SongId); !ok {
+		return ErrConflict
+	}
+	track.Song = nil
+	for i, existing := range m.tracks {
+		if samePosition(existing, track) {
+			m.tracks[i] = track
+			return nil
+		}
+	}
+	m.tracks = append(m.tracks, track)
+	return nil
+}
+
+func (m *Memory) RemoveAlbumTrack(ctx context.Context, albumID, disc, track int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	position := models.AlbumTrack{AlbumId: albumID, DiscNumber: disc, TrackNumber: track}
+	before := len(m.tracks)
+	m.removeTracks(func(existing models.AlbumTrack) bool { return samePosition(existing, position) })
+	if len(m.tracks) == before {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// albumView дополняет альбом названием исполнителя, вызывается под блокировкой.
+func (m *Memory) albumView(album models.Album) models.Album {
+	album.Artist = ""
+	if album.ArtistId != nil {
+		album.Artist = m.artists[*album.ArtistId].Name
+	}
+	return album
+}
+
+// onAlbum проверяет, что песня входит в альбом с таким названием, вызывается под блокировкой.
+func (m *Memory) onAlbum(songID int, title string) bool {
+	for _, track := range m.tracks {
+		if track.SongId == songID && normalizeName(m.albums[track.AlbumId].Title) == normalizeName(title) {
+			return true
+		}
+	}
+	return false
+}
+
+// removeTracks удаляет треки, для которых drop возвращает true, вызывается под блокировкой.
+func (m *Memory) removeTracks(drop func(track models.AlbumTrack) bool) {
+	kept := m.tracks[:0]
+	for _, track := range m.tracks {
+		if !drop(track) {
+			kept = append(kept, track)
+		}
+	}
+	m.tracks = kept
+}
+
+func samePosition(a, b models.AlbumTrack) bool {
+	return a.AlbumId == b.AlbumId && a.DiscNumber == b.DiscNumber && a.TrackNumber == b.TrackNumber
+}
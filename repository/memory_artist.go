@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"song-library/models"
+)
+
+func (m *Memory) ListArtists(ctx context.Context, filter ArtistFilter) ([]models.Artist, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	name := normalizeName(filter.Name)
+	artists := make([]models.Artist, 0, len(m.artists))
+	for _, artist := range m.artists {
+		if name != "" && !strings.Contains(normalizeName(artist.Name), name) {
+			continue
+		}
+		artists = append(artists, artist)
+	}
+
+	sort.Slice(artists, func(i, j int) bool {
+		a, b := normalizeName(artists[i].Name), normalizeName(artists[j].Name)
+		if a != b {
+			return a < b
+		}
+		return artists[i].Id < artists[j].Id
+	})
+	return paginate(artists, filter.Offset, filter.Limit), nil
+}
+
+func (m *Memory) GetArtist(ctx context.Context, id int) (*models.Artist, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	artist, ok := m.artists[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &artist, nil
+}
+
+func (m *Memory) CreateArtist(ctx context.Context, artist *models.Artist) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.nameTaken(artist.Name, 0) {
+		return ErrConflict
+	}
+	artist.Id = m.nextArtistID
+	m.nextArtistID++
+	m.artists[artist.Id] = *artist
+	return nil
+}
+
+func (m *Memory) UpdateArtist(ctx context.Context, id int, artist models.Artist) (*models.Artist, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.artists[id]; !ok {
+		return nil, ErrNotFound
+	}
+	if m.nameTaken(artist.Name, id) {
+		return nil, ErrConflict
+	}
+	artist.Id = id
+	m.artists[id] = artist
+	return &artist, nil
+}
+
+func (m *Memory) DeleteArtist(ctx context.Context, id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.artists[id]; !ok {
+		return ErrNotFound
+	}
+	for _, song := range m.songs {
+		if song.ArtistId != nil && *song.ArtistId == id {
+			return ErrConflict
+		}
+	}
+	for _, album := range m.albums {
+		if album.ArtistId != nil && *album.ArtistId == id {
+			return ErrConflict
+		}
+	}
+	delete(m.artists, id)
+	return nil
+}
+
+// view дополняет песню названием исполнителя, вызывается под блокировкой.
+func (m *Memory) view(song models.Song) models.Song {
+	song.Group = ""
+	if song.ArtistId != nil {
+		song.Group = m.artists[*song.ArtistId].Name
+	}
+	return song
+}
+
+// findArtist ищет исполнителя по названию, а если не нашел — по другому написанию.
+func (m *Memory) findArtist(name string) (models.Artist, bool) {
+	var byAlias *models.Artist
+	for _, artist := range m.artists {
+		if normalizeName(artist.Name) == normalizeName(name) {
+			return artist, true
+		}
+		if byAlias == nil && m.hasAlias(&artist.Id, name) {
+			artist := artist
+			byAlias = &artist
+		}
+	}
+	if byAlias != nil {
+		return *byAlias, true
+	}
+	return models.Artist{}, false
+}
+
+// resolveArtist находит исполнителя по названию или создает нового, вызывается под блокировкой.
+// Для пустого названия возвращает ErrEmptyArtist.
+func (m *Memory) resolveArtist(name string) (models.Artist, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" {
+		return models.Artist{}, ErrEmptyArtist
+	}
+	if artist, ok := m.findArtist(name); ok {
+		return artist, nil
+	}
+	artist := models.Artist{Id: m.nextArtistID, Name: name}
+	m.nextArtistID++
+	m.artists[artist.Id] = artist
+	return artist, nil
+}
+
+func (m *Memory) hasAlias(artistID *int, name string) bool {
+	if artistID == nil {
+		return false
+	}
+	for _, alias := range m.artists[*artistID].Aliases {
+		if normalizeName(alias) == normalizeName(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// nameTaken проверяет, занято ли название другим исполнителем.
+func (m *Memory) nameTaken(name string, exceptID int) bool {
+	for _, artist := range m.artists {
+		if artist.Id != exceptID && normalizeName(artist.Name) == normalizeName(name) {
+			return true
+		}
+	}
+	return false
+}
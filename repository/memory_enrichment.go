@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"song-library/models"
+)
+
+func (m *Memory) GetEnrichmentJob(ctx context.Context, songID int) (*models.EnrichmentJob, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	job, ok := m.jobs[songID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &job, nil
+}
+
+func (m *Memory) EnqueueEnrichment(ctx context.Context, songID int) (*models.EnrichmentJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	song, ok := m.liveSong(songID)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	song.EnrichmentStatus = models.EnrichmentPending
+	touch(&song)
+	m.songs[songID] = song
+
+	job := m.newJob(songID)
+	return &job, nil
+}
+
+func (m *Memory) ClaimEnrichmentJob(ctx context.Context) (*models.EnrichmentJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	var claimed *models.EnrichmentJob
+	for _, job := range m.jobs {
+		if job.Status != models.JobQueued || job.RunAt.After(now) {
+			continue
+		}
+		if claimed == nil || job.RunAt.Before(claimed.RunAt) {
+			job := job
+			claimed = &job
+		}
+	}
+	if claimed == nil {
+		return nil, ErrNotFound
+	}
+
+	claimed.Status = models.JobRunning
+	claimed.Attempts++
+	claimed.UpdatedAt = now
+	m.jobs[claimed.SongId] = *claimed
+	return claimed, nil
+}
+
+func (m *Memory) CompleteEnrichmentJob(ctx context.Context, jobID int, details models.SongDetails) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobByID(jobID)
+	if !ok {
+		return ErrNotFound
+	}
+
+	song, ok := m.liveSong(job.SongId)
+	if !ok {
+		m.finishJob(job, models.JobFailed, models.EnrichmentFailed, errSongTrashed, "")
+		return nil
+	}
+	before := models.SnapshotOf(m.view(song))
+	song.SongDetails = enrichedDetails(song.SongDetails, details)
+	m.songs[job.SongId] = song
+	m.recordRevision(job.SongId, &before, EnrichmentActor, nil)
+	m.finishJob(job, models.JobSucceeded, models.EnrichmentSucceeded, "", "")
+	return nil
+}
+
+func (m *Memory) RetryEnrichmentJob(ctx context.Context, jobID int, errMsg, errKind string, runAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobByID(jobID)
+	if !ok {
+		return ErrNotFound
+	}
+	job.Status = models.JobQueued
+	job.LastError = errMsg
+	job.LastErrorKind = errKind
+	job.RunAt = runAt
+	job.UpdatedAt = time.Now()
+	m.jobs[job.SongId] = job
+	return nil
+}
+
+func (m *Memory) FailEnrichmentJob(ctx context.Context, jobID int, errMsg, errKind string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobByID(jobID)
+	if !ok {
+		return ErrNotFound
+	}
+	m.finishJob(job, models.JobFailed, models.EnrichmentFailed, errMsg, errKind)
+	return nil
+}
+
+func (m *Memory) RequeueRunningEnrichmentJobs(ctx context.Context) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	count := 0
+	for songID, job := range m.jobs {
+		if job.Status == models.JobRunning {
+			job.Status = models.JobQueued
+			m.jobs[songID] = job
+			count++
+		}
+	}
+	return count, nil
+}
+
+// newJob создает или перезапускает задание песни, вызывается под блокировкой.
+func (m *Memory) newJob(songID int) models.EnrichmentJob {
+	now := time.Now()
+	job, ok := m.jobs[songID]
+	if !ok {
+		job = models.EnrichmentJob{Id: m.nextJobID, SongId: songID, CreatedAt: now}
+		m.nextJobID++
+	}
+	job.Status = models.JobQueued
+	job.Attempts = 0
+	job.LastError = ""
+	job.LastErrorKind = ""
+	job.RunAt = now
+	job.UpdatedAt = now
+	m.jobs[songID] = job
+	return job
+}
+
+func (m *Memory) jobByID(jobID int) (models.EnrichmentJob, bool) {
+	for _, job := range m.jobs {
+		if job.Id == jobID {
+			return job, true
+		}
+	}
+	return models.EnrichmentJob{}, false
+}
+
+func (m *Memory) finishJob(job models.EnrichmentJob, status models.JobStatus, songStatus models.EnrichmentStatus, errMsg, errKind string) {
+	job.Status = status
+	job.LastError = errMsg
+	job.LastErrorKind = errKind
+	job.UpdatedAt = time.Now()
+	m.jobs[job.SongId] = job
+
+	if song, ok := m.songs[job.SongId]; ok {
+		song.EnrichmentStatus = songStatus
+		if song.DeletedAt == nil { //версия песни в корзине не меняется
+			touch(&song)
+		}
+		m.songs[job.SongId] = song
+	}
+}
+
+// errSongTrashed — ошибка задания песни, перемещенной в корзину, пока задание было в очереди.
+const errSongTrashed = "песня перемещена в корзину"
+
+// enrichedDetails заполняет пустые поля данных песни полученными от внешнего API. Заполненные поля
+// не изменяются: пользователь мог отредактировать песню, пока задание было в очереди.
+func enrichedDetails(current, found models.SongDetails) models.SongDetails {
+	if current.Text == "" {
+		current.Text = found.Text
+		current.Sections = models.ParseLyrics(found.Text)
+	}
+	if current.Link == "" {
+		current.Link = found.Link
+	}
+	if current.ReleaseDate.IsZero() {
+		current.ReleaseDate = found.ReleaseDate
+	}
+	return current
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"song-library/models"
+)
+
+func (m *Memory) ListLyricVersions(ctx context.Context, songID int) ([]models.LyricVersion, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if _, ok := m.liveSong(songID); !ok {
+		return nil, ErrNotFound
+	}
+	versions := append([]models.LyricVersion{}, m.lyrics[songID]...)
+	sort.Slice(versions, func(i, j int) bool {
+		if versions[i].Language != versions[j].Language {
+			return versions[i].Language < versions[j].Language
+		}
+		return versions[i].Kind < versions[j].Kind
+	})
+	return versions, nil
+}
+
+func (m *Memory) SaveLyricVersion(ctx context.Context, version *models.LyricVersion) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.liveSong(version.SongId); !ok {
+		return ErrNotFound
+	}
+	version.Sections = models.ParseLyrics(version.Text)
+	version.UpdatedAt = time.Now()
+
+	versions := m.lyrics[version.SongId]
+	for i, existing := range versions {
+		if existing.Language == version.Language && existing.Kind == version.Kind {
+			version.Id = existing.Id
+			versions[i] = *version
+			return nil
+		}
+	}
+	version.Id = m.nextLyricsID
+	m.nextLyricsID++
+	m.lyrics[version.SongId] = append(versions, *version)
+	return nil
+}
+
+func (m *Memory) DeleteLyricVersion(ctx context.Context, songID int, language string, kind models.LyricKind) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	versions := m.lyrics[songID]
+	for i, existing := range versions {
+		if existing.Language == language && existing.Kind == kind {
+			m.lyrics[songID] = append(versions[:i:i], versions[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"song-library/models"
+)
+
+func (m *Memory) ListPlaylists(ctx context.Context, filter PlaylistFilter) ([]models.Playlist, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	playlists := make([]models.Playlist, 0, len(m.playlists))
+	for _, playlist := range m.playlists {
+		if filter.Owner != "" && playlist.Owner != filter.Owner {
+			continue
+		}
+		if !filter.All && playlist.Visibility != models.PlaylistPublic && playlist.Owner != filter.Viewer {
+			continue
+		}
+		playlists = append(playlists, m.playlistView(playlist))
+	}
+
+	sort.Slice(playlists, func(i, j int) bool {
+		if !playlists[i].UpdatedAt.Equal(playlists[j].UpdatedAt) {
+			return playlists[i].UpdatedAt.After(playlists[j].UpdatedAt)
+		}
+		return playlists[i].Id > playlists[j].Id
+	})
+	return paginate(playlists, filter.Offset, filter.Limit), nil
+}
+
+func (m *Memory) GetPlaylist(ctx context.Context, id int) (*models.Playlist, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	playlist, ok := m.playlists[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	playlist = m.playlistView(playlist)
+	return &playlist, nil
+}
+
+func (m *Memory) CreatePlaylist(ctx context.Context, playlist *models.Playlist) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	playlist.Id = m.nextPlaylistID
+	m.nextPlaylistID++
+	playlist.CreatedAt = now
+	playlist.UpdatedAt = now
+	m.playlists[playlist.Id] = *playlist
+	*playlist = m.playlistView(*playlist)
+	return nil
+}
+
+func (m *Memory) UpdatePlaylist(ctx context.Context, id int, playlist models.Playlist) (*models.Playlist, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	existing, ok := m.playlists[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	existing.Name = playlist.Name
+	existing.Description = playlist.Description
+	existing.Visibility = playlist.Visibility
+	existing.UpdatedAt = time.Now()
+	m.playlists[id] = existing
+	existing = m.playlistView(existing)
+	return &existing, nil
+}
+
+func (m *Memory) DeletePlaylist(ctx context.Context, id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.playlists[id]; !ok {
+		return ErrNotFound
+	}
+	delete(m.playlists, id)
+	delete(m.entries, id)
+	return nil
+}
+
+func (m *Memory) ListPlaylistEntries(ctx context.Context, playlistID int) ([]models.PlaylistEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if _, ok := m.playlists[playlistID]; !ok {
+		return nil, ErrNotFound
+	}
+	return m.entriesView(playlistID), nil
+}
+
+func (m *Memory) AddPlaylistEntry(ctx context.Context, entry *models.PlaylistEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.playlists[entry.PlaylistId]; !ok {
+		return ErrNotFound
+	}
+	if _, ok := m.liveSong(entry.SongId); !ok {
+		return ErrConflict
+	}
+
+	entries := m.entries[entry.PlaylistId]
+	index := len(entries)
+	if entry.Position > 0 && entry.Position <= len(entries) {
+		index = entry.Position - 1
+	}
+	entry.Id = m.nextEntryID
+	m.nextEntryID++
+	entry.Song = nil
+	entries = append(entries, models.PlaylistEntry{})
+	copy(entries[index+1:], entries[index:])
+	entries[index] = *entry
+	m.setEntries(entry.PlaylistId, entries)
+
+	*entry = m.entries[entry.PlaylistId][index]
+	if song, ok := m.liveSong(entry.SongId); ok {
+		song = m.view(song)
+		entry.Song = &song
+	}
+	return nil
+}
+
+func (m *Memory) RemovePlaylistEntry(ctx context.Context, playlistID, entryID int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entries := m.entries[playlistID]
+	index := entryIndex(entries, entryID)
+	if index < 0 {
+		return ErrNotFound
+	}
+	m.setEntries(playlistID, append(entries[:index:index], entries[index+1:]...))
+	return nil
+}
+
+func (m *Memory) MovePlaylistEntry(ctx context.Context, playlistID, entryID, position int) ([]models.PlaylistEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entries := m.entries[playlistID]
+	from := entryIndex(entries, entryID)
+	if from < 0 {
+		return nil, ErrNotFound
+	}
+	to := min(max(position, 1), len(entries)) - 1
+	moved := entries[from]
+	reordered := append(entries[:from:from], entries[from+1:]...)
+	reordered = append(reordered[:to:to], append([]models.PlaylistEntry{moved}, reordered[to:]...)...)
+	m.setEntries(playlistID, reordered)
+	return m.entriesView(playlistID), nil
+}
+
+func (m *Memory) ReorderPlaylist(ctx context.Context, playlistID int, entryIDs []int) ([]models.PlaylistEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.playlists[playlistID]; !ok {
+		return nil, ErrNotFound
+	}
+	entries := m.entries[playlistID]
+	if len(entryIDs) != len(entries) {
+		return nil, ErrConflict
+	}
+	reordered := make([]models.PlaylistEntry, 0, len(entries))
+	seen := make(map[int]bool, len(entryIDs))
+	for _, id := range entryIDs {
+		index := entryIndex(entries, id)
+		if index < 0 || seen[id] {
+			return nil, ErrConflict
+		}
+		seen[id] = true
+		reordered = append(reordered, entries[index])
+	}
+	m.setEntries(playlistID, reordered)
+	return m.entriesView(playlistID), nil
+}
+
+// playlistView дополняет плейлист количеством записей, вызывается под блокировкой.
+func (m *Memory) playlistView(playlist models.Playlist) models.Playlist {
+	playlist.EntryCount = len(m.entries[playlist.Id])
+	return playlist
+}
+
+// entriesView возвращает копию записей плейлиста с песнями, вызывается под блокировкой.
+func (m *Memory) entriesView(playlistID int) []models.PlaylistEntry {
+	entries := make([]models.PlaylistEntry, len(m.entries[playlistID]))
+	for i, entry := range m.entries[playlistID] {
+		if song, ok := m.liveSong(entry.SongId); ok { //у песен из корзины запись остается без песни
+			song = m.view(song)
+			entry.Song = &song
+		}
+		entries[i] = entry
+	}
+	return entries
+}
+
+// setEntries сохраняет записи плейлиста, нумеруя позиции подряд, и отмечает изменение плейлиста.
+// Вызывается под блокировкой.
+func (m *Memory) setEntries(playlistID int, entries []models.PlaylistEntry) {
+	for i := range entries {
+		entries[i].Position = i + 1
+	}
+	m.entries[playlistID] = entries
+	playlist := m.playlists[playlistID]
+	playlist.UpdatedAt = time.Now()
+	m.playlists[playlistID] = playlist
+}
+
+// removeEntries удаляет записи песни из всех плейлистов, вызывается под блокировкой.
+// Время изменения плейлистов не меняется: песня уже была в корзине.
+func (m *Memory) removeEntries(songID int) {
+	for playlistID, entries := range m.entries {
+		kept := entries[:0]
+		for _, entry := range entries {
+			if entry.SongId != songID {
+				entry.Position = len(kept) + 1
+				kept = append(kept, entry)
+			}
+		}
+		m.entries[playlistID] = kept
+	}
+}
+
+// entryIndex возвращает индекс записи с ID id или -1.
+func entryIndex(entries []models.PlaylistEntry, id int) int {
+	for i, entry := range entries {
+		if entry.Id == id {
+			return i
+		}
+	}
+	return -1
+}
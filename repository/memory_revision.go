@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"song-library/models"
+)
+
+func (m *Memory) ListRevisions(ctx context.Context, songID, offset, limit int) ([]models.SongRevision, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if _, ok := m.liveSong(songID); !ok {
+		return nil, ErrNotFound
+	}
+	stored := m.revisions[songID]
+	revisions := make([]models.SongRevision, len(stored))
+	for i, rev := range stored {
+		revisions[len(stored)-1-i] = rev
+	}
+	return paginate(revisions, offset, limit), nil
+}
+
+func (m *Memory) GetRevision(ctx context.Context, songID, revision int) (*models.SongRevision, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if _, ok := m.liveSong(songID); !ok {
+		return nil, ErrNotFound
+	}
+	rev, ok := m.revision(songID, revision)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &rev, nil
+}
+
+func (m *Memory) RestoreRevision(ctx context.Context, songID, revision int) (*models.Song, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rev, ok := m.revision(songID, revision)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	update, err := rev.Snapshot.Update()
+	if err != nil {
+		return nil, err
+	}
+	return m.updateSong(songID, update, ActorFrom(ctx), &revision)
+}
+
+func (m *Memory) revision(songID, revision int) (models.SongRevision, bool) {
+	for _, rev := range m.revisions[songID] {
+		if rev.Revision == revision {
+			return rev, true
+		}
+	}
+	return models.SongRevision{}, false
+}
+
+// recordRevision записывает в историю правку с изменениями полей песни относительно before,
+// вызывается под блокировкой. Если before равен nil, песня только что создана.
+func (m *Memory) recordRevision(songID int, before *models.SongSnapshot, author string, restoredFrom *int) {
+	after := models.SnapshotOf(m.view(m.songs[songID]))
+	var changes models.FieldChanges
+	if before == nil {
+		changes = models.DiffSnapshots(models.SongSnapshot{}, after)
+	} else if changes = models.DiffSnapshots(*before, after); len(changes) == 0 {
+		return
+	}
+
+	revisions := m.revisions[songID]
+	m.revisions[songID] = append(revisions, models.SongRevision{
+		Id:           m.nextRevisionID,
+		SongId:       songID,
+		Revision:     len(revisions) + 1,
+		Author:       author,
+		CreatedAt:    time.Now(),
+		Changes:      changes,
+		Snapshot:     after,
+		RestoredFrom: restoredFrom,
+	})
+	m.nextRevisionID++
+}
@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"song-library/models"
+)
+
+// SearchSongs приближенно повторяет полнотекстовый поиск PostgreSQL: слова сравниваются
+// без учета регистра по упрощенной основе, поддерживаются -исключение и or. Запрос только
+// из исключений, как и websearch_to_tsquery, находит все песни без исключенных слов.
+func (m *Memory) SearchSongs(ctx context.Context, query SearchQuery) ([]models.SearchResult, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	groups, excluded := parseWebSearch(query.Query)
+
+	results := []models.SearchResult{}
+	for _, song := range m.songs {
+		if song.DeletedAt != nil {
+			continue
+		}
+		song = m.view(song)
+		words := splitWords(song.SongDetails.Text)
+		stems := make([]string, len(words))
+		for i, word := range words {
+			stems[i] = stem(word.text)
+		}
+
+		if containsAny(stems, excluded) {
+			continue
+		}
+
+		var matched map[int]bool
+		if len(groups) == 0 && len(excluded) > 0 {
+			matched = map[int]bool{}
+		}
+		for _, group := range groups {
+			if positions := matchAll(stems, group); positions != nil {
+				if matched == nil {
+					matched = map[int]bool{}
+				}
+				for _, pos := range positions {
+					matched[pos] = true
+				}
+			}
+		}
+		if matched == nil {
+			continue
+		}
+
+		rank := 0.0
+		if len(words) > 0 {
+			rank = float64(len(matched)) / (1 + math.Log(float64(len(words))))
+		}
+		results = append(results, models.SearchResult{
+			Song:    song,
+			Rank:    rank,
+			Snippet: snippet(song.SongDetails.Text, words, matched),
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Rank != results[j].Rank {
+			return results[i].Rank > results[j].Rank
+		}
+		return results[i].Song.Id < results[j].Song.Id
+	})
+	return paginate(results, query.Offset, query.Limit), nil
+}
+
+type lyricWord struct {
+	text       string
+	start, end int //границы слова в исходном тексте в байтах
+}
+
+func splitWords(text string) []lyricWord {
+	var words []lyricWord
+	start := -1
+	for i, r := range text {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if isWord && start < 0 {
+			start = i
+		}
+		if !isWord && start >= 0 {
+			words = append(words, lyricWord{text: strings.ToLower(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		words = append(words, lyricWord{text: strings.ToLower(text[start:]), start: start, end: len(text)})
+	}
+	return words
+}
+
+// stem отбрасывает окончание слова, чтобы формы одного слова совпадали.
+func stem(word string) string {
+	runes := []rune(word)
+	switch {
+	case len(runes) >= 6:
+		return string(runes[:len(runes)-2])
+	case len(runes) >= 4:
+		return string(runes[:len(runes)-1])
+	}
+	return word
+}
+
+// parseWebSearch разбирает запрос на группы слов, объединенные через or, и исключенные слова.
+func parseWebSearch(query string) (groups [][]string, excluded []string) {
+	var group []string
+	for _, field := range strings.Fields(strings.ReplaceAll(query, `"`, " ")) {
+		if strings.EqualFold(field, "or") {
+			if len(group) > 0 {
+				groups = append(groups, group)
+			}
+			group = nil
+			continue
+		}
+		negate := strings.HasPrefix(field, "-")
+		for _, w := range splitWords(field) {
+			if negate {
+				excluded = append(excluded, stem(w.text))
+			} else {
+				group = append(group, stem(w.text))
+			}
+		}
+	}
+	if len(group) > 0 {
+		groups = append(groups, group)
+	}
+	return groups, excluded
+}
+
+func containsAny(stems []string, terms []string) bool {
+	for _, s := range stems {
+		for _, term := range terms {
+			if s == term {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchAll возвращает позиции совпавших слов, если в тексте есть все слова группы, иначе nil.
+func matchAll(stems []string, group []string) []int {
+	var positions []int
+	for _, term := range group {
+		found := false
+		for i, s := range stems {
+			if s == term {
+				positions = append(positions, i)
+				found = true
+			}
+		}
+		if !found {
+			return nil
+		}
+	}
+	return positions
+}
+
+// htmlEscaper экранирует текст песни в фрагменте так же, как поиск PostgreSQL.
+var htmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// snippet возвращает фрагмент текста в HTML вокруг первого совпадения, выделяя совпавшие слова тегами <b></b>.
+// Без совпадений возвращает начало текста.
+func snippet(text string, words []lyricWord, matched map[int]bool) string {
+	const maxWords = 20
+
+	if len(words) == 0 {
+		return ""
+	}
+	first := 0
+	if len(matched) > 0 {
+		first = len(words)
+	}
+	for pos := range matched {
+		if pos < first {
+			first = pos
+		}
+	}
+	from := first - maxWords/4
+	if from < 0 {
+		from = 0
+	}
+	to := from + maxWords
+	if to > len(words) {
+		to = len(words)
+	}
+
+	var b strings.Builder
+	cursor := words[from].start
+	for i := from; i < to; i++ {
+		b.WriteString(htmlEscaper.Replace(text[cursor:words[i].start]))
+		if matched[i] {
+			b.WriteString("<b>" + htmlEscaper.Replace(text[words[i].start:words[i].end]) + "</b>")
+		} else {
+			b.WriteString(htmlEscaper.Replace(text[words[i].start:words[i].end]))
+		}
+		cursor = words[i].end
+	}
+	return b.String()
+}
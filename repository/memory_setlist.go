@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"song-library/models"
+)
+
+func (m *Memory) ListSetlists(ctx context.Context, filter SetlistFilter) ([]models.Setlist, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	setlists := make([]models.Setlist, 0, len(m.setlists))
+	for _, setlist := range m.setlists {
+		if filter.Owner != "" && setlist.Owner != filter.Owner {
+			continue
+		}
+		setlist.Sets = nil
+		setlists = append(setlists, setlist)
+	}
+
+	sort.Slice(setlists, func(i, j int) bool {
+		if !setlists[i].UpdatedAt.Equal(setlists[j].UpdatedAt) {
+			return setlists[i].UpdatedAt.After(setlists[j].UpdatedAt)
+		}
+		return setlists[i].Id > setlists[j].Id
+	})
+	return paginate(setlists, filter.Offset, filter.Limit), nil
+}
+
+func (m *Memory) GetSetlist(ctx context.Context, id int) (*models.Setlist, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	setlist, ok := m.setlists[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	setlist = m.setlistView(setlist)
+	return &setlist, nil
+}
+
+func (m *Memory) CreateSetlist(ctx context.Context, setlist *models.Setlist) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.setlistSongsExist(*setlist, nil) {
+		return ErrConflict
+	}
+	now := time.Now()
+	setlist.Id = m.nextSetlistID
+	m.nextSetlistID++
+	setlist.CreatedAt = now
+	setlist.UpdatedAt = now
+	m.setlists[setlist.Id] = m.storeSets(*setlist)
+	*setlist = m.setlistView(m.setlists[setlist.Id])
+	return nil
+}
+
+func (m *Memory) UpdateSetlist(ctx context.Context, id int, setlist models.Setlist) (*models.Setlist, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	existing, ok := m.setlists[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if !m.setlistSongsExist(setlist, &existing) {
+		return nil, ErrConflict
+	}
+	setlist.Id = id
+	setlist.Owner = existing.Owner
+	setlist.CreatedAt = existing.CreatedAt
+	setlist.UpdatedAt = time.Now()
+	m.setlists[id] = m.storeSets(setlist)
+	setlist = m.setlistView(m.setlists[id])
+	return &setlist, nil
+}
+
+func (m *Memory) DeleteSetlist(ctx context.Context, id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.setlists[id]; !ok {
+		return ErrNotFound
+	}
+	delete(m.setlists, id)
+	return nil
+}
+
+// storeSets копирует сеты и песни сет-листа, присваивая им новые ID и позиции, вызывается под блокировкой.
+func (m *Memory) storeSets(setlist models.Setlist) models.Setlist {
+	sets := make([]models.SetlistSet, len(setlist.Sets))
+	for i, set := range setlist.Sets {
+		set.Id = m.nextSetID
+		m.nextSetID++
+		set.SetlistId = setlist.Id
+		set.Position = i + 1
+		items := make([]models.SetlistItem, len(set.Items))
+		for j, item := range set.Items {
+			item.Id = m.nextItemID
+			m.nextItemID++
+			item.SetId = set.Id
+			item.Position = j + 1
+			item.Song = nil
+			items[j] = item
+		}
+		set.Items = items
+		sets[i] = set
+	}
+	setlist.Sets = sets
+	return setlist
+}
+
+// setlistView возвращает копию сет-листа с песнями, вызывается под блокировкой.
+func (m *Memory) setlistView(setlist models.Setlist) models.Setlist {
+	sets := make([]models.SetlistSet, len(setlist.Sets))
+	for i, set := range setlist.Sets {
+		items := make([]models.SetlistItem, len(set.Items))
+		for j, item := range set.Items {
+			if song, ok := m.liveSong(item.SongId); ok { //у песен из корзины Song не заполнено
+				song = m.view(song)
+				item.Song = &song
+			}
+			items[j] = item
+		}
+		set.Items = items
+		sets[i] = set
+	}
+	setlist.Sets = sets
+	return setlist
+}
+
+// setlistSongsExist проверяет, что все песни сет-листа есть и не в корзине. Песни из корзины,
+// которые уже есть в existing, допускаются. Вызывается под блокировкой.
+func (m *Memory) setlistSongsExist(setlist models.Setlist, existing *models.Setlist) bool {
+	kept := make(map[int]bool)
+	if existing != nil {
+		for _, set := range existing.Sets {
+			for _, item := range set.Items {
+				kept[item.SongId] = true
+			}
+		}
+	}
+	for _, set := range setlist.Sets {
+		for _, item := range set.Items {
+			if _, ok := m.liveSong(item.SongId); !ok && !kept[item.SongId] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// removeSetlistItems удаляет песню из всех сет-листов, вызывается под блокировкой.
+func (m *Memory) removeSetlistItems(songID int) {
+	for id, setlist := range m.setlists {
+		for i, set := range setlist.Sets {
+			kept := set.Items[:0]
+			for _, item := range set.Items {
+				if item.SongId != songID {
+					kept = append(kept, item)
+				}
+			}
+			setlist.Sets[i].Items = kept
+		}
+		m.setlists[id] = setlist
+	}
+}
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"sort"
+
+	"song-library/models"
+)
+
+func (m *Memory) ListTags(ctx context.Context, filter TagFilter) ([]models.Tag, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	counts := make(map[int]int)
+	for songID, tagIDs := range m.songTags {
+		if _, ok := m.liveSong(songID); !ok {
+			continue
+		}
+		for tagID := range tagIDs {
+			counts[tagID]++
+		}
+	}
+	tags := make([]models.Tag, 0, len(counts))
+	for tagID, count := range counts {
+		tag := m.tags[tagID]
+		if filter.Namespace != "" && tag.Namespace != filter.Namespace {
+			continue
+		}
+		tag.Songs = count
+		tags = append(tags, tag)
+	}
+
+	sortTags(tags)
+	return paginate(tags, filter.Offset, filter.Limit), nil
+}
+
+func (m *Memory) ListSongTags(ctx context.Context, songID int) ([]models.Tag, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if _, ok := m.liveSong(songID); !ok {
+		return nil, ErrNotFound
+	}
+	tags := []models.Tag{}
+	for tagID := range m.songTags[songID] {
+		tags = append(tags, m.tags[tagID])
+	}
+	sortTags(tags)
+	return tags, nil
+}
+
+func (m *Memory) TagSongs(ctx context.Context, songIDs []int, tags []models.Tag) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, id := range songIDs {
+		if _, ok := m.liveSong(id); !ok {
+			return 0, ErrConflict
+		}
+	}
+	tagIDs := make([]int, len(tags))
+	for i, tag := range tags {
+		id, ok := m.findTag(tag)
+		if !ok {
+			id = m.nextTagID
+			m.nextTagID++
+			m.tags[id] = models.Tag{Id: id, Namespace: tag.Namespace, Name: tag.Name}
+		}
+		tagIDs[i] = id
+	}
+
+	added := 0
+	for _, songID := range songIDs {
+		if m.songTags[songID] == nil {
+			m.songTags[songID] = make(map[int]bool)
+		}
+		for _, tagID := range tagIDs {
+			if !m.songTags[songID][tagID] {
+				m.songTags[songID][tagID] = true
+				added++
+			}
+		}
+	}
+	return added, nil
+}
+
+func (m *Memory) UntagSongs(ctx context.Context, songIDs []int, tags []models.Tag) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	removed := 0
+	for _, tag := range tags {
+		tagID, ok := m.findTag(tag)
+		if !ok {
+			continue
+		}
+		for _, songID := range songIDs {
+			if m.songTags[songID][tagID] {
+				delete(m.songTags[songID], tagID)
+				removed++
+			}
+		}
+	}
+	return removed, nil
+}
+
+func (m *Memory) TagFacets(ctx context.Context, filter SongFilter) ([]models.Tag, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	counts := make(map[int]int)
+	for _, song := range m.filterSongs(filter) {
+		for tagID := range m.songTags[song.Id] {
+			counts[tagID]++
+		}
+	}
+	tags := make([]models.Tag, 0, len(counts))
+	for tagID, count := range counts {
+		tag := m.tags[tagID]
+		tag.Songs = count
+		tags = append(tags, tag)
+	}
+
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Songs != tags[j].Songs {
+			return tags[i].Songs > tags[j].Songs
+		}
+		return tagLess(tags[i], tags[j])
+	})
+	return tags, nil
+}
+
+// hasTags проверяет, что у песни есть все теги или, если anyTag, хотя бы один, вызывается под блокировкой.
+func (m *Memory) hasTags(songID int, tags []models.Tag, anyTag bool) bool {
+	for _, tag := range tags {
+		tagID, ok := m.findTag(tag)
+		has := ok && m.songTags[songID][tagID]
+		if has && anyTag {
+			return true
+		}
+		if !has && !anyTag {
+			return false
+		}
+	}
+	return !anyTag
+}
+
+// findTag возвращает ID тега, вызывается под блокировкой.
+func (m *Memory) findTag(tag models.Tag) (int, bool) {
+	for id, existing := range m.tags {
+		if existing.Namespace == tag.Namespace && existing.Name == tag.Name {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+func sortTags(tags []models.Tag) {
+	sort.Slice(tags, func(i, j int) bool { return tagLess(tags[i], tags[j]) })
+}
+
+func tagLess(a, b models.Tag) bool {
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	return a.Name < b.Name
+}
@@ -0,0 +1,496 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"song-library/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// releaseEndSQL — первый день после периода выхода песни с учетом точности даты.
+const releaseEndSQL = `(released_on + CASE release_precision
+	WHEN 'year' THEN interval '1 year'
+	WHEN 'month' THEN interval '1 month'
+	ELSE interval '1 day' END)`
+
+// songColumns — колонки песни вместе с названием исполнителя.
+const songColumns = `songs.*, artists.name AS "group"`
+
+// aliasMatchSQL проверяет, что параметр совпадает с одним из других написаний исполнителя.
+const aliasMatchSQL = `EXISTS (SELECT 1 FROM jsonb_array_elements_text(artists.aliases) alias
+	WHERE normalize_name(alias) = normalize_name(?))`
+
+// Postgres хранит данные библиотеки в PostgreSQL через GORM.
+type Postgres struct {
+	db *gorm.DB
+}
+
+// NewPostgres создает хранилище поверх подключения к PostgreSQL.
+func NewPostgres(db *gorm.DB) *Postgres {
+	return &Postgres{db: db}
+}
+
+func (p *Postgres) ListSongs(ctx context.Context, filter SongFilter) ([]models.Song, error) {
+	keys := filter.sortKeys()
+	if filter.Cursor != nil {
+		if filter.Fuzzy {
+			return nil, ErrFuzzyCursor
+		}
+		if err := checkCursor(filter.Cursor, keys); err != nil {
+			return nil, err
+		}
+	}
+
+	var songs []models.Song
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := songsQuery(tx).
+			Joins("JOIN song_details ON song_details.song_id = songs.id")
+		query, score, err := filterSongs(tx, query, filter)
+		if err != nil {
+			return err
+		}
+		if score != nil {
+			query = query.Select(songColumns+", "+score.SQL+" AS score", score.Vars...).Order("score DESC")
+		}
+
+		backward := false
+		if filter.Cursor != nil {
+			condition, args, err := keysetSQL(keys, *filter.Cursor)
+			if err != nil {
+				return err
+			}
+			query = query.Where(condition, args...)
+			backward = filter.Cursor.Backward
+		} else {
+			query = query.Offset(filter.Offset)
+		}
+
+		if err := query.Order(orderSQL(keys, backward)).Limit(filter.Limit).Find(&songs).Error; err != nil {
+			return err
+		}
+		if backward { //страница перед курсором выбирается в обратном порядке
+			for i, j := 0, len(songs)-1; i < j; i, j = i+1, j-1 {
+				songs[i], songs[j] = songs[j], songs[i]
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return songs, nil
+}
+
+func (p *Postgres) CountSongs(ctx context.Context, filter SongFilter) (int, error) {
+	var count int64
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&models.Song{}).
+			Joins("LEFT JOIN artists ON artists.id = songs.artist_id").
+			Joins("JOIN song_details ON song_details.song_id = songs.id")
+		query, _, err := filterSongs(tx, query, filter)
+		if err != nil {
+			return err
+		}
+		return query.Count(&count).Error
+	})
+	return int(count), err
+}
+
+// exportRow — песня вместе с дополнительными данными одной строкой результата запроса.
+type exportRow struct {
+	models.Song
+	DetailText             string
+	DetailLink             string
+	DetailReleasedOn       *time.Time
+	DetailReleasePrecision models.DatePrecision
+}
+
+// exportColumns — колонки дополнительных данных для exportRow.
+const exportColumns = `song_details.text AS detail_text, song_details.link AS detail_link,
+	song_details.released_on AS detail_released_on, song_details.release_precision AS detail_release_precision`
+
+func (p *Postgres) ExportSongs(ctx context.Context, filter SongFilter, fn func(song models.Song) error) error {
+	//песни читаются курсором в одном снимке базы данных, без загрузки всего списка в память
+	opts := &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+	return p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&models.Song{}).
+			Select(songColumns + ", " + exportColumns).
+			Joins("LEFT JOIN artists ON artists.id = songs.artist_id").
+			Joins("JOIN song_details ON song_details.song_id = songs.id")
+		query, score, err := filterSongs(tx, query, filter)
+		if err != nil {
+			return err
+		}
+		if score != nil {
+			query = query.Select(songColumns+", "+exportColumns+", "+score.SQL+" AS score", score.Vars...).Order("score DESC")
+		}
+
+		rows, err := query.Order(orderSQL(filter.sortKeys(), false)).Rows()
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var row exportRow
+			if err := tx.ScanRows(rows, &row); err != nil {
+				return err
+			}
+			song := row.Song
+			song.SongDetails = models.SongDetails{
+				SongId:      song.Id,
+				Text:        row.DetailText,
+				Link:        row.DetailLink,
+				ReleaseDate: models.ReleaseDate{Date: row.DetailReleasedOn, Precision: row.DetailReleasePrecision},
+			}
+			if err := fn(song); err != nil {
+				return err
+			}
+		}
+		return rows.Err()
+	}, opts)
+}
+
+// filterSongs добавляет к запросу условия фильтра. Для нечеткого поиска возвращает выражение сходства песни.
+func filterSongs(tx *gorm.DB, query *gorm.DB, filter SongFilter) (*gorm.DB, *clause.Expr, error) {
+	text := strings.ReplaceAll(filter.Text, `\n`, "\n") // чтобы коректно находились записи в бд
+	var score *clause.Expr
+
+	if !filter.IncludeDeleted {
+		query = query.Where("songs.deleted_at IS NULL")
+	}
+
+	if filter.ArtistID != 0 {
+		query = query.Where("songs.artist_id = ?", filter.ArtistID)
+	}
+
+	if filter.Fuzzy {
+		// оператор % использует триграммные индексы и порог из pg_trgm.similarity_threshold
+		if err := tx.Exec("SELECT set_config('pg_trgm.similarity_threshold', ?, true)", strconv.FormatFloat(filter.Threshold, 'f', -1, 64)).Error; err != nil {
+			return nil, nil, err
+		}
+
+		var scores []string
+		var args []interface{}
+		if filter.Group != "" {
+			query = query.Where("normalize_name(artists.name) % normalize_name(?)", filter.Group)
+			scores = append(scores, "similarity(normalize_name(artists.name), normalize_name(?))")
+			args = append(args, filter.Group)
+		}
+		if filter.Song != "" {
+			query = query.Where("normalize_name(song) % normalize_name(?)", filter.Song)
+			scores = append(scores, "similarity(normalize_name(song), normalize_name(?))")
+			args = append(args, filter.Song)
+		}
+		if len(scores) > 0 { //сходство песни — среднее сходство по заданным фильтрам
+			score = &clause.Expr{SQL: "(" + strings.Join(scores, " + ") + ") / " + strconv.Itoa(len(scores)), Vars: args}
+		}
+	} else {
+		if filter.Group != "" {
+			query = query.Where("(artists.name = ? OR "+aliasMatchSQL+")", filter.Group, filter.Group)
+		}
+		if filter.Song != "" {
+			query = query.Where("song = ?", filter.Song)
+		}
+	}
+	if filter.Link != "" {
+		query = query.Where("link = ?", filter.Link)
+	}
+	if filter.Album != "" {
+		query = query.Where(`EXISTS (SELECT 1 FROM album_tracks JOIN albums ON albums.id = album_tracks.album_id
+			WHERE album_tracks.song_id = songs.id AND normalize_name(albums.title) = normalize_name(?))`, filter.Album)
+	}
+	if text != "" {
+		query = query.Where("text LIKE ?", "%"+text+"%") //LIKE для частичного совподения чтобы искать не по всему тексту а по фрагменту
+	}
+
+	if filter.ReleasedFrom != nil { //период выхода песни должен закончиться после начала периода фильтра
+		query = query.Where(releaseEndSQL+" > ?", *filter.ReleasedFrom)
+	}
+	if filter.ReleasedBefore != nil {
+		query = query.Where("released_on < ?", *filter.ReleasedBefore)
+	}
+	if len(filter.Tags) > 0 {
+		if filter.AnyTag {
+			query = query.Where(songTagSQL+" AND (tags.namespace, tags.name) IN ?)", tagPairs(filter.Tags))
+		} else {
+			for _, tag := range filter.Tags {
+				query = query.Where(songTagSQL+" AND tags.namespace = ? AND tags.name = ?)", tag.Namespace, tag.Name)
+			}
+		}
+	}
+	return query, score, nil
+}
+
+func (p *Postgres) GetSong(ctx context.Context, id int) (*models.Song, error) {
+	var song models.Song
+	err := songsQuery(p.db.WithContext(ctx)).Where("songs.id = ? AND songs.deleted_at IS NULL", id).First(&song).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &song, nil
+}
+
+func (p *Postgres) FindSong(ctx context.Context, group, name string) (*models.Song, error) {
+	var song models.Song
+	err := songsQuery(p.db.WithContext(ctx)).
+		Where("(artists.name = ? OR "+aliasMatchSQL+") AND song = ? AND songs.deleted_at IS NULL", group, group, name).
+		First(&song).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &song, nil
+}
+
+func (p *Postgres) CreateSong(ctx context.Context, song *models.Song) error {
+	return p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error { //данные добавляются в обе таблицы атомарно
+		artist := &models.Artist{}
+		if song.ArtistId == nil {
+			var err error
+			if artist, err = resolveArtist(tx, song.Group); err != nil {
+				return err
+			}
+		} else if err := tx.First(artist, *song.ArtistId).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrConflict
+		} else if err != nil {
+			return err
+		}
+		song.ArtistId = &artist.Id
+		song.Group = artist.Name
+
+		if err := tx.Omit(clause.Associations).Create(song).Error; err != nil {
+			return err
+		}
+		song.SongDetails.SongId = song.Id
+		song.SongDetails.Sections = models.ParseLyrics(song.SongDetails.Text)
+		if err := tx.Create(&song.SongDetails).Error; err != nil {
+			return err
+		}
+		if err := recordRevision(tx, song.Id, nil, ActorFrom(ctx), nil); err != nil {
+			return err
+		}
+		if song.EnrichmentStatus == models.EnrichmentPending {
+			return tx.Create(&models.EnrichmentJob{SongId: song.Id, Status: models.JobQueued, RunAt: time.Now()}).Error
+		}
+		return nil
+	})
+}
+
+func (p *Postgres) UpdateSong(ctx context.Context, id int, update models.SongUpdate) (*models.Song, error) {
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return updateSong(tx, id, update, ActorFrom(ctx), nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return p.GetSong(ctx, id) //для вывода обновленных данных
+}
+
+// updateSong изменяет песню и записывает правку в историю, вызывается в транзакции.
+func updateSong(tx *gorm.DB, id int, update models.SongUpdate, author string, restoredFrom *int) error {
+	songFields := map[string]interface{}{"version": nextVersion}
+	if update.Song != nil {
+		songFields["song"] = *update.Song
+	}
+
+	detailsFields := map[string]interface{}{}
+	if update.Text != nil {
+		detailsFields["text"] = *update.Text
+		detailsFields["sections"] = models.ParseLyrics(*update.Text)
+	}
+	if update.Link != nil {
+		detailsFields["link"] = *update.Link
+	}
+	if update.ReleaseDate != nil {
+		detailsFields["released_on"] = update.ReleaseDate.Date
+		detailsFields["release_precision"] = update.ReleaseDate.Precision
+	}
+
+	before, err := lockSong(tx, id)
+	if err != nil {
+		return err
+	}
+	if err := songExists(tx, id); err != nil { //песню в корзине нельзя изменить
+		return err
+	}
+	if err := checkVersion(tx, id, update.IfVersion); err != nil {
+		return err
+	}
+
+	keepArtist := false
+	if update.ArtistId != nil {
+		var count int64
+		if err := tx.Model(&models.Artist{}).Where("id = ?", *update.ArtistId).Count(&count).Error; err != nil {
+			return err
+		}
+		keepArtist = count > 0
+	}
+	if keepArtist { //исполнитель мог быть переименован, группа берется из него
+		songFields["artist_id"] = *update.ArtistId
+	} else if update.Group != nil {
+		artist, err := resolveArtist(tx, *update.Group)
+		if err != nil {
+			return err
+		}
+		songFields["artist_id"] = artist.Id
+	}
+
+	if err := tx.Model(&models.Song{}).Where("id = ?", id).Updates(songFields).Error; err != nil {
+		return err
+	}
+	if len(detailsFields) > 0 {
+		if err := tx.Model(&models.SongDetails{}).Where("song_id = ?", id).Updates(detailsFields).Error; err != nil {
+			return err
+		}
+	}
+	return recordRevision(tx, id, &before, author, restoredFrom)
+}
+
+func (p *Postgres) SetSyncedLyrics(ctx context.Context, id int, synced models.SyncedLyrics) error {
+	result := p.db.WithContext(ctx).Model(&models.SongDetails{}).Where("song_id = ?", id).Where(liveSongs).Update("synced_lyrics", synced)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (p *Postgres) SetChordPro(ctx context.Context, id int, doc string) error {
+	result := p.db.WithContext(ctx).Model(&models.SongDetails{}).Where("song_id = ?", id).Where(liveSongs).Update("chordpro", doc)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// liveSongs оставляет дополнительные данные только тех песен, которых нет в корзине.
+const liveSongs = "song_id IN (SELECT id FROM songs WHERE deleted_at IS NULL)"
+
+func (p *Postgres) DeleteSong(ctx context.Context, id, ifVersion int) error {
+	return p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := lockSong(tx, id); err != nil {
+			return err
+		}
+		if err := songExists(tx, id); err != nil {
+			return err
+		}
+		if err := checkVersion(tx, id, ifVersion); err != nil {
+			return err
+		}
+		return tx.Model(&models.Song{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"deleted_at": time.Now(),
+			"deleted_by": ActorFrom(ctx),
+			"version":    nextVersion,
+		}).Error
+	})
+}
+
+// nextVersion увеличивает версию песни при обновлении строки.
+var nextVersion = gorm.Expr("version + 1")
+
+// checkVersion возвращает ErrVersionMismatch, если version не 0 и не совпадает с версией песни.
+func checkVersion(tx *gorm.DB, id, version int) error {
+	if version == 0 {
+		return nil
+	}
+	var current int
+	if err := tx.Model(&models.Song{}).Where("id = ?", id).Pluck("version", &current).Error; err != nil {
+		return err
+	}
+	if current != version {
+		return ErrVersionMismatch
+	}
+	return nil
+}
+
+func (p *Postgres) RestoreSong(ctx context.Context, id int) (*models.Song, error) {
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var song models.Song
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id, artist_id, song").
+			Where("id = ? AND deleted_at IS NOT NULL", id).Take(&song).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		var count int64 //пока песня была в корзине, такую же могли добавить заново
+		err = tx.Model(&models.Song{}).
+			Where("artist_id IS NOT DISTINCT FROM ? AND song = ? AND deleted_at IS NULL", song.ArtistId, song.Song).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrConflict
+		}
+
+		return tx.Model(&models.Song{}).Where("id = ?", id).
+			Updates(map[string]interface{}{"deleted_at": nil, "deleted_by": "", "version": nextVersion}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return p.GetSong(ctx, id)
+}
+
+func (p *Postgres) ListDeletedSongs(ctx context.Context, offset, limit int) ([]models.Song, error) {
+	var songs []models.Song
+	err := songsQuery(p.db.WithContext(ctx)).
+		Where("songs.deleted_at IS NOT NULL").
+		Order("songs.deleted_at DESC, songs.id").
+		Offset(offset).Limit(limit).Find(&songs).Error
+	if err != nil {
+		return nil, err
+	}
+	return songs, nil
+}
+
+func (p *Postgres) PurgeDeletedSongs(ctx context.Context, before time.Time) (int, error) {
+	var purged int64
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		expired := tx.Model(&models.Song{}).Select("id").Where("deleted_at < ?", before)
+		if err := tx.Where("song_id IN (?)", expired).Delete(&models.SongDetails{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("deleted_at < ?", before).Delete(&models.Song{})
+		if result.Error != nil {
+			return result.Error
+		}
+		purged = result.RowsAffected
+		if purged == 0 {
+			return nil
+		}
+		//задания, версии текста, правки, треки альбомов, теги и записи плейлистов удаляются каскадно,
+		//оставшиеся записи плейлистов нумеруются заново
+		return compactPlaylists(tx)
+	})
+	return int(purged), err
+}
+
+// songsQuery выбирает песни вместе с исполнителем и дополнительными данными.
+func songsQuery(db *gorm.DB) *gorm.DB {
+	return db.Model(&models.Song{}).
+		Select(songColumns).
+		Joins("LEFT JOIN artists ON artists.id = songs.artist_id").
+		Preload("SongDetails")
+}
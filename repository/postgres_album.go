@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"song-library/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// albumColumns — колонки альбома вместе с названием исполнителя.
+const albumColumns = `albums.*, artists.name AS artist`
+
+func (p *Postgres) ListAlbums(ctx context.Context, filter AlbumFilter) ([]models.Album, error) {
+	query := albumsQuery(p.db.WithContext(ctx))
+	if filter.ArtistID != 0 {
+		query = query.Where("albums.artist_id = ?", filter.ArtistID)
+	}
+	if filter.Title != "" {
+		query = query.Where("normalize_name(albums.title) LIKE ?", "%"+escapeLike(normalizeName(filter.Title))+"%")
+	}
+
+	var albums []models.Album
+	err := query.Order("albums.released_on ASC NULLS LAST, albums.id").
+		Offset(filter.Offset).Limit(filter.Limit).Find(&albums).Error
+	if err != nil {
+		return nil, err
+	}
+	return albums, nil
+}
+
+func (p *Postgres) GetAlbum(ctx context.Context, id int) (*models.Album, error) {
+	var album models.Album
+	err := albumsQuery(p.db.WithContext(ctx)).Where("albums.id = ?", id).First(&album).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &album, nil
+}
+
+func (p *Postgres) CreateAlbum(ctx context.Context, album *models.Album) error {
+	if err := translateError(p.db.WithContext(ctx).Create(album).Error); err != nil {
+		return err //исполнитель не существует
+	}
+	created, err := p.GetAlbum(ctx, album.Id)
+	if err != nil {
+		return err
+	}
+	*album = *created
+	return nil
+}
+
+func (p *Postgres) UpdateAlbum(ctx context.Context, id int, album models.Album) (*models.Album, error) {
+	album.Id = id
+	result := p.db.WithContext(ctx).Model(&album).Select("*").Omit("id", "artist").Updates(&album)
+	if err := translateError(result.Error); err != nil {
+		return nil, err
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrNotFound
+	}
+	return p.GetAlbum(ctx, id)
+}
+
+func (p *Postgres) DeleteAlbum(ctx context.Context, id int) error {
+	result := p.db.WithContext(ctx).Delete(&models.Album{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (p *Postgres) ListAlbumTracks(ctx context.Context, albumID int) ([]models.AlbumTrack, error) {
+	var tracks []models.AlbumTrack
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := albumExists(tx, albumID); err != nil {
+			return err
+		}
+		if err := tx.Where("album_id = ?", albumID).Order("disc_number, track_number").Find(&tracks).Error; err != nil {
+			return err
+		}
+
+		ids := make([]int, len(tracks))
+		for i, track := range tracks {
+			ids[i] = track.SongId
+		}
+		var songs []models.Song
+		if err := songsQuery(tx).Where("songs.id IN ? AND songs.deleted_at IS NULL", ids).Find(&songs).Error; err != nil {
+			return err
+		}
+		byID := make(map[int]*models.Song, len(songs))
+		for i := range songs {
+			byID[songs[i].Id] = &songs[i]
+		}
+		visible := tracks[:0]
+		for _, track := range tracks {
+			if track.Song = byID[track.SongId]; track.Song != nil { //песни из корзины не показываются
+				visible = append(visible, track)
+			}
+		}
+		tracks = visible
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tracks, nil
+}
+
+func (p *Postgres) ListSongTracks(ctx context.Context, songID int) ([]models.AlbumTrack, error) {
+	var tracks []models.AlbumTrack
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("song_id = ?", songID).Find(&tracks).Error; err != nil {
+			return err
+		}
+
+		ids := make([]int, len(tracks))
+		for i, track := range tracks {
+			ids[i] = track.AlbumId
+		}
+		var albums []models.Album
+		err := albumsQuery(tx).Where("albums.id IN ?", ids).
+			Order("albums.released_on NULLS LAST, albums.id").Find(&albums).Error
+		if err != nil {
+			return err
+		}
+
+		ordered := make([]models.AlbumTrack, 0, len(tracks))
+		for i := range albums {
+			for _, track := range tracks {
+				if track.AlbumId == albums[i].Id {
+					track.Album = &albums[i]
+					ordered = append(ordered, track)
+				}
+			}
+		}
+		tracks = ordered
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tracks, nil
+}
+
+func (p *Postgres) SetAlbumTrack(ctx context.Context, track models.AlbumTrack) error {
+	return p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := albumExists(tx, track.AlbumId); err != nil {
+			return err
+		}
+		if err := songExists(tx, track.SongId); errors.Is(err, ErrNotFound) { //песни нет или она в корзине
+			return ErrConflict
+		} else if err != nil {
+			return err
+		}
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "album_id"}, {Name: "disc_number"}, {Name: "track_number"}},
+			DoUpdates: clause.AssignmentColumns([]string{"song_id"}),
+		}).Create(&track).Error
+		return translateError(err) //песня не существует
+	})
+}
+
+func (p *Postgres) RemoveAlbumTrack(ctx context.Context, albumID, disc, track int) error {
+	result := p.db.WithContext(ctx).
+		Where("album_id = ? AND disc_number = ? AND track_number = ?", albumID, disc, track).
+		Delete(&models.AlbumTrack{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// albumExists возвращает ErrNotFound, если альбома нет.
+func albumExists(tx *gorm.DB, id int) error {
+	var count int64
+	if err := tx.Model(&models.Album{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func albumsQuery(db *gorm.DB) *gorm.DB {
+	return db.Model(&models.Album{}).
+		Select(albumColumns).
+		Joins("LEFT JOIN artists ON artists.id = albums.artist_id")
+}
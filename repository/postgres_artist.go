@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"song-library/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (p *Postgres) ListArtists(ctx context.Context, filter ArtistFilter) ([]models.Artist, error) {
+	query := p.db.WithContext(ctx).Model(&models.Artist{})
+	if filter.Name != "" {
+		query = query.Where("normalize_name(name) LIKE ?", "%"+escapeLike(normalizeName(filter.Name))+"%")
+	}
+
+	var artists []models.Artist
+	err := query.Order("normalize_name(name), id").Offset(filter.Offset).Limit(filter.Limit).Find(&artists).Error
+	if err != nil {
+		return nil, err
+	}
+	return artists, nil
+}
+
+func (p *Postgres) GetArtist(ctx context.Context, id int) (*models.Artist, error) {
+	var artist models.Artist
+	err := p.db.WithContext(ctx).First(&artist, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &artist, nil
+}
+
+func (p *Postgres) CreateArtist(ctx context.Context, artist *models.Artist) error {
+	return translateError(p.db.WithContext(ctx).Create(artist).Error)
+}
+
+func (p *Postgres) UpdateArtist(ctx context.Context, id int, artist models.Artist) (*models.Artist, error) {
+	artist.Id = id
+	result := p.db.WithContext(ctx).Model(&artist).Select("*").Omit("id").Updates(&artist)
+	if err := translateError(result.Error); err != nil {
+		return nil, err
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrNotFound
+	}
+	return p.GetArtist(ctx, id)
+}
+
+func (p *Postgres) DeleteArtist(ctx context.Context, id int) error {
+	result := p.db.WithContext(ctx).Delete(&models.Artist{}, id)
+	if err := translateError(result.Error); err != nil {
+		return err //у исполнителя остались песни
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// resolveArtist находит исполнителя по названию или другому написанию и создает его, если такого еще нет.
+// Для пустого названия возвращает ErrEmptyArtist.
+func resolveArtist(tx *gorm.DB, name string) (*models.Artist, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, ErrEmptyArtist
+	}
+	find := func() (*models.Artist, error) {
+		var artist models.Artist
+		err := tx.Where("normalize_name(name) = normalize_name(?) OR "+aliasMatchSQL, name, name).
+			Order(clause.OrderBy{Expression: clause.Expr{ //точное название важнее другого написания
+				SQL:  "normalize_name(name) = normalize_name(?) DESC, id",
+				Vars: []interface{}{name},
+			}}).
+			First(&artist).Error
+		if err != nil {
+			return nil, err
+		}
+		return &artist, nil
+	}
+
+	artist, err := find()
+	if err == nil {
+		return artist, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	artist = &models.Artist{Name: strings.Join(strings.Fields(name), " ")}
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(artist)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 { //исполнителя только что добавили в параллельной транзакции
+		return find()
+	}
+	return artist, nil
+}
+
+// translateError переводит ошибки нарушения ограничений базы данных в ErrConflict.
+func translateError(err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) || errors.Is(err, gorm.ErrForeignKeyViolated) {
+		return ErrConflict
+	}
+	return err
+}
+
+// escapeLike экранирует спецсимволы шаблона LIKE.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
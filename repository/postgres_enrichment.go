@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"song-library/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (p *Postgres) GetEnrichmentJob(ctx context.Context, songID int) (*models.EnrichmentJob, error) {
+	var job models.EnrichmentJob
+	err := p.db.WithContext(ctx).Where("song_id = ?", songID).First(&job).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (p *Postgres) EnqueueEnrichment(ctx context.Context, songID int) (*models.EnrichmentJob, error) {
+	job := models.EnrichmentJob{SongId: songID, Status: models.JobQueued, RunAt: time.Now()}
+
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Song{}).Where("id = ? AND deleted_at IS NULL", songID).
+			Updates(map[string]interface{}{"enrichment_status": models.EnrichmentPending, "version": nextVersion})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+
+		return tx.Clauses(clause.OnConflict{ //у песни одно задание, повторная постановка его перезапускает
+			Columns: []clause.Column{{Name: "song_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"status":          models.JobQueued,
+				"attempts":        0,
+				"last_error":      "",
+				"last_error_kind": "",
+				"run_at":          job.RunAt,
+				"updated_at":      job.RunAt,
+			}),
+		}).Create(&job).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return p.GetEnrichmentJob(ctx, songID)
+}
+
+func (p *Postgres) ClaimEnrichmentJob(ctx context.Context) (*models.EnrichmentJob, error) {
+	var jobs []models.EnrichmentJob
+	err := p.db.WithContext(ctx).Raw(`
+		UPDATE enrichment_jobs
+		SET status = ?, attempts = attempts + 1, updated_at = now()
+		WHERE id = (
+			SELECT id FROM enrichment_jobs
+			WHERE status = ? AND run_at <= now()
+			ORDER BY run_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, models.JobRunning, models.JobQueued).Scan(&jobs).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, ErrNotFound
+	}
+	return &jobs[0], nil
+}
+
+func (p *Postgres) CompleteEnrichmentJob(ctx context.Context, jobID int, details models.SongDetails) error {
+	return p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		job, err := lockJob(tx, jobID)
+		if err != nil {
+			return err
+		}
+
+		before, err := lockSong(tx, job.SongId)
+		if err != nil {
+			return err
+		}
+		if err := songExists(tx, job.SongId); errors.Is(err, ErrNotFound) {
+			return finishJob(tx, job, models.JobFailed, models.EnrichmentFailed, errSongTrashed, "")
+		} else if err != nil {
+			return err
+		}
+
+		var current models.SongDetails
+		if err := tx.Where("song_id = ?", job.SongId).Take(&current).Error; err != nil {
+			return err
+		}
+		enriched := enrichedDetails(current, details)
+		err = tx.Model(&models.SongDetails{}).Where("song_id = ?", job.SongId).Updates(map[string]interface{}{
+			"text":              enriched.Text,
+			"sections":          enriched.Sections,
+			"link":              enriched.Link,
+			"released_on":       enriched.ReleaseDate.Date,
+			"release_precision": enriched.ReleaseDate.Precision,
+		}).Error
+		if err != nil {
+			return err
+		}
+		if err := recordRevision(tx, job.SongId, &before, EnrichmentActor, nil); err != nil {
+			return err
+		}
+		return finishJob(tx, job, models.JobSucceeded, models.EnrichmentSucceeded, "", "")
+	})
+}
+
+func (p *Postgres) RetryEnrichmentJob(ctx context.Context, jobID int, errMsg, errKind string, runAt time.Time) error {
+	return p.db.WithContext(ctx).Model(&models.EnrichmentJob{}).Where("id = ?", jobID).Updates(map[string]interface{}{
+		"status":          models.JobQueued,
+		"last_error":      errMsg,
+		"last_error_kind": errKind,
+		"run_at":          runAt,
+		"updated_at":      time.Now(),
+	}).Error
+}
+
+func (p *Postgres) FailEnrichmentJob(ctx context.Context, jobID int, errMsg, errKind string) error {
+	return p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		job, err := lockJob(tx, jobID)
+		if err != nil {
+			return err
+		}
+		return finishJob(tx, job, models.JobFailed, models.EnrichmentFailed, errMsg, errKind)
+	})
+}
+
+func (p *Postgres) RequeueRunningEnrichmentJobs(ctx context.Context) (int, error) {
+	result := p.db.WithContext(ctx).Model(&models.EnrichmentJob{}).
+		Where("status = ?", models.JobRunning).
+		Updates(map[string]interface{}{"status": models.JobQueued, "updated_at": time.Now()})
+	return int(result.RowsAffected), result.Error
+}
+
+func lockJob(tx *gorm.DB, jobID int) (*models.EnrichmentJob, error) {
+	var job models.EnrichmentJob
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&job, jobID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// finishJob завершает задание и переносит итог в состояние песни.
+func finishJob(tx *gorm.DB, job *models.EnrichmentJob, status models.JobStatus, songStatus models.EnrichmentStatus, errMsg, errKind string) error {
+	err := tx.Model(job).Updates(map[string]interface{}{
+		"status":          status,
+		"last_error":      errMsg,
+		"last_error_kind": errKind,
+		"updated_at":      time.Now(),
+	}).Error
+	if err != nil {
+		return err
+	}
+	return tx.Model(&models.Song{}).Where("id = ?", job.SongId).Updates(map[string]interface{}{
+		"enrichment_status": songStatus,
+		"version":           gorm.Expr("CASE WHEN deleted_at IS NULL THEN version + 1 ELSE version END"), //версия песни в корзине не меняется
+	}).Error
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"song-library/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (p *Postgres) ListLyricVersions(ctx context.Context, songID int) ([]models.LyricVersion, error) {
+	var versions []models.LyricVersion
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := songExists(tx, songID); err != nil {
+			return err
+		}
+		return tx.Where("song_id = ?", songID).Order("language, kind").Find(&versions).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+func (p *Postgres) SaveLyricVersion(ctx context.Context, version *models.LyricVersion) error {
+	version.Sections = models.ParseLyrics(version.Text)
+	version.UpdatedAt = time.Now()
+	return p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := songExists(tx, version.SongId); err != nil {
+			return err
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "song_id"}, {Name: "language"}, {Name: "kind"}},
+			DoUpdates: clause.AssignmentColumns([]string{"text", "sections", "updated_at"}),
+		}).Create(version).Error
+	})
+}
+
+func (p *Postgres) DeleteLyricVersion(ctx context.Context, songID int, language string, kind models.LyricKind) error {
+	result := p.db.WithContext(ctx).
+		Where("song_id = ? AND language = ? AND kind = ?", songID, language, kind).
+		Delete(&models.LyricVersion{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// songExists возвращает ErrNotFound, если песни нет или она в корзине.
+func songExists(tx *gorm.DB, id int) error {
+	var count int64
+	if err := tx.Model(&models.Song{}).Where("id = ? AND deleted_at IS NULL", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,304 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"song-library/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// playlistColumns — колонки плейлиста вместе с количеством записей.
+const playlistColumns = `playlists.*,
+	(SELECT count(*) FROM playlist_entries WHERE playlist_entries.playlist_id = playlists.id) AS entry_count`
+
+func (p *Postgres) ListPlaylists(ctx context.Context, filter PlaylistFilter) ([]models.Playlist, error) {
+	query := playlistsQuery(p.db.WithContext(ctx))
+	if filter.Owner != "" {
+		query = query.Where("playlists.owner = ?", filter.Owner)
+	}
+	if !filter.All {
+		query = query.Where("playlists.visibility = ? OR playlists.owner = ?", models.PlaylistPublic, filter.Viewer)
+	}
+
+	var playlists []models.Playlist
+	err := query.Order("playlists.updated_at DESC, playlists.id DESC").
+		Offset(filter.Offset).Limit(filter.Limit).Find(&playlists).Error
+	if err != nil {
+		return nil, err
+	}
+	return playlists, nil
+}
+
+func (p *Postgres) GetPlaylist(ctx context.Context, id int) (*models.Playlist, error) {
+	var playlist models.Playlist
+	err := playlistsQuery(p.db.WithContext(ctx)).Where("playlists.id = ?", id).First(&playlist).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &playlist, nil
+}
+
+func (p *Postgres) CreatePlaylist(ctx context.Context, playlist *models.Playlist) error {
+	return p.db.WithContext(ctx).Create(playlist).Error
+}
+
+func (p *Postgres) UpdatePlaylist(ctx context.Context, id int, playlist models.Playlist) (*models.Playlist, error) {
+	playlist.UpdatedAt = time.Now()
+	result := p.db.WithContext(ctx).Model(&models.Playlist{Id: id}).
+		Select("name", "description", "visibility", "updated_at").Updates(&playlist)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrNotFound
+	}
+	return p.GetPlaylist(ctx, id)
+}
+
+func (p *Postgres) DeletePlaylist(ctx context.Context, id int) error {
+	result := p.db.WithContext(ctx).Delete(&models.Playlist{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil //записи удаляются каскадно
+}
+
+func (p *Postgres) ListPlaylistEntries(ctx context.Context, playlistID int) ([]models.PlaylistEntry, error) {
+	var entries []models.PlaylistEntry
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.Playlist{}).Where("id = ?", playlistID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return ErrNotFound
+		}
+		var err error
+		entries, err = playlistEntries(tx, playlistID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (p *Postgres) AddPlaylistEntry(ctx context.Context, entry *models.PlaylistEntry) error {
+	return p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		length, err := lockPlaylist(tx, entry.PlaylistId)
+		if err != nil {
+			return err
+		}
+		if err := songExists(tx, entry.SongId); errors.Is(err, ErrNotFound) { //песни нет или она в корзине
+			return ErrConflict
+		} else if err != nil {
+			return err
+		}
+
+		if entry.Position <= 0 || entry.Position > length {
+			entry.Position = length + 1
+		}
+		err = tx.Model(&models.PlaylistEntry{}).
+			Where("playlist_id = ? AND position >= ?", entry.PlaylistId, entry.Position).
+			Update("position", gorm.Expr("position + 1")).Error
+		if err != nil {
+			return err
+		}
+		entry.Id = 0
+		entry.Song = nil
+		if err := translateError(tx.Create(entry).Error); err != nil {
+			return err //песня не существует
+		}
+		if err := touchPlaylist(tx, entry.PlaylistId); err != nil {
+			return err
+		}
+
+		var song models.Song
+		if err := songsQuery(tx).Where("songs.id = ?", entry.SongId).First(&song).Error; err != nil {
+			return err
+		}
+		entry.Song = &song
+		return nil
+	})
+}
+
+func (p *Postgres) RemovePlaylistEntry(ctx context.Context, playlistID, entryID int) error {
+	return p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := lockPlaylist(tx, playlistID); err != nil {
+			return err
+		}
+		entry, err := findPlaylistEntry(tx, playlistID, entryID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.PlaylistEntry{}, entry.Id).Error; err != nil {
+			return err
+		}
+		err = tx.Model(&models.PlaylistEntry{}).
+			Where("playlist_id = ? AND position > ?", playlistID, entry.Position).
+			Update("position", gorm.Expr("position - 1")).Error
+		if err != nil {
+			return err
+		}
+		return touchPlaylist(tx, playlistID)
+	})
+}
+
+func (p *Postgres) MovePlaylistEntry(ctx context.Context, playlistID, entryID, position int) ([]models.PlaylistEntry, error) {
+	var entries []models.PlaylistEntry
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		length, err := lockPlaylist(tx, playlistID)
+		if err != nil {
+			return err
+		}
+		entry, err := findPlaylistEntry(tx, playlistID, entryID)
+		if err != nil {
+			return err
+		}
+
+		position = min(max(position, 1), length)
+		shift := tx.Model(&models.PlaylistEntry{}).Where("playlist_id = ?", playlistID)
+		switch {
+		case position > entry.Position:
+			err = shift.Where("position > ? AND position <= ?", entry.Position, position).
+				Update("position", gorm.Expr("position - 1")).Error
+		case position < entry.Position:
+			err = shift.Where("position >= ? AND position < ?", position, entry.Position).
+				Update("position", gorm.Expr("position + 1")).Error
+		}
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(entry).Update("position", position).Error; err != nil {
+			return err
+		}
+		if err := touchPlaylist(tx, playlistID); err != nil {
+			return err
+		}
+		entries, err = playlistEntries(tx, playlistID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (p *Postgres) ReorderPlaylist(ctx context.Context, playlistID int, entryIDs []int) ([]models.PlaylistEntry, error) {
+	var entries []models.PlaylistEntry
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		length, err := lockPlaylist(tx, playlistID)
+		if err != nil {
+			return err
+		}
+		if len(entryIDs) != length {
+			return ErrConflict
+		}
+		var count int64
+		err = tx.Model(&models.PlaylistEntry{}).
+			Where("playlist_id = ? AND id IN ?", playlistID, entryIDs).
+			Distinct("id").Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if int(count) != length { //повторы или чужие записи
+			return ErrConflict
+		}
+
+		for i, id := range entryIDs { //уникальность позиций проверяется в конце транзакции
+			if err := tx.Model(&models.PlaylistEntry{}).Where("id = ?", id).Update("position", i+1).Error; err != nil {
+				return err
+			}
+		}
+		if err := touchPlaylist(tx, playlistID); err != nil {
+			return err
+		}
+		entries, err = playlistEntries(tx, playlistID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// compactPlaylists нумерует записи всех плейлистов подряд после удаления песен.
+func compactPlaylists(tx *gorm.DB) error {
+	return tx.Exec(`UPDATE playlist_entries SET position = ranked.position
+		FROM (SELECT id, row_number() OVER (PARTITION BY playlist_id ORDER BY position) AS position
+			FROM playlist_entries) ranked
+		WHERE playlist_entries.id = ranked.id AND playlist_entries.position <> ranked.position`).Error
+}
+
+// lockPlaylist блокирует плейлист до конца транзакции и возвращает количество его записей.
+// Если плейлиста нет, возвращает ErrNotFound.
+func lockPlaylist(tx *gorm.DB, id int) (int, error) {
+	var playlist models.Playlist
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", id).Take(&playlist).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, ErrNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	var count int64
+	if err := tx.Model(&models.PlaylistEntry{}).Where("playlist_id = ?", id).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+func findPlaylistEntry(tx *gorm.DB, playlistID, entryID int) (*models.PlaylistEntry, error) {
+	var entry models.PlaylistEntry
+	err := tx.Where("id = ? AND playlist_id = ?", entryID, playlistID).Take(&entry).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func touchPlaylist(tx *gorm.DB, id int) error {
+	return tx.Model(&models.Playlist{}).Where("id = ?", id).Update("updated_at", time.Now()).Error
+}
+
+// playlistEntries возвращает записи плейлиста по порядку с песнями не из корзины.
+func playlistEntries(tx *gorm.DB, playlistID int) ([]models.PlaylistEntry, error) {
+	var entries []models.PlaylistEntry
+	if err := tx.Where("playlist_id = ?", playlistID).Order("position").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, len(entries))
+	for i, entry := range entries {
+		ids[i] = entry.SongId
+	}
+	var songs []models.Song
+	if err := songsQuery(tx).Where("songs.id IN ? AND songs.deleted_at IS NULL", ids).Find(&songs).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[int]*models.Song, len(songs))
+	for i := range songs {
+		byID[songs[i].Id] = &songs[i]
+	}
+	for i := range entries {
+		entries[i].Song = byID[entries[i].SongId] //у песен из корзины запись остается без песни
+	}
+	return entries, nil
+}
+
+func playlistsQuery(db *gorm.DB) *gorm.DB {
+	return db.Model(&models.Playlist{}).Select(playlistColumns)
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"song-library/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (p *Postgres) ListRevisions(ctx context.Context, songID, offset, limit int) ([]models.SongRevision, error) {
+	var revisions []models.SongRevision
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := songExists(tx, songID); err != nil {
+			return err
+		}
+		return tx.Where("song_id = ?", songID).Order("revision DESC").Offset(offset).Limit(limit).Find(&revisions).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+func (p *Postgres) GetRevision(ctx context.Context, songID, revision int) (*models.SongRevision, error) {
+	var rev models.SongRevision
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := songExists(tx, songID); err != nil {
+			return err
+		}
+		err := tx.Where("song_id = ? AND revision = ?", songID, revision).First(&rev).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotFound
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+func (p *Postgres) RestoreRevision(ctx context.Context, songID, revision int) (*models.Song, error) {
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var rev models.SongRevision
+		err := tx.Where("song_id = ? AND revision = ?", songID, revision).First(&rev).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		update, err := rev.Snapshot.Update()
+		if err != nil {
+			return err
+		}
+		return updateSong(tx, songID, update, ActorFrom(ctx), &revision)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return p.GetSong(ctx, songID)
+}
+
+// lockSong блокирует строку песни до конца транзакции, чтобы правки нумеровались последовательно,
+// и возвращает текущее состояние её полей.
+func lockSong(tx *gorm.DB, id int) (models.SongSnapshot, error) {
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").
+		Where("id = ?", id).Take(&models.Song{}).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.SongSnapshot{}, ErrNotFound
+	}
+	if err != nil {
+		return models.SongSnapshot{}, err
+	}
+	return loadSnapshot(tx, id)
+}
+
+func loadSnapshot(tx *gorm.DB, id int) (models.SongSnapshot, error) {
+	var song models.Song
+	if err := songsQuery(tx).Where("songs.id = ?", id).First(&song).Error; err != nil {
+		return models.SongSnapshot{}, err
+	}
+	return models.SnapshotOf(song), nil
+}
+
+// recordRevision записывает в историю правку с изменениями полей песни относительно before.
+// Если before равен nil, песня только что создана. Правка без изменений не записывается.
+func recordRevision(tx *gorm.DB, songID int, before *models.SongSnapshot, author string, restoredFrom *int) error {
+	after, err := loadSnapshot(tx, songID)
+	if err != nil {
+		return err
+	}
+	var changes models.FieldChanges
+	if before == nil {
+		changes = models.DiffSnapshots(models.SongSnapshot{}, after)
+	} else if changes = models.DiffSnapshots(*before, after); len(changes) == 0 {
+		return nil
+	}
+
+	var last int
+	err = tx.Model(&models.SongRevision{}).Where("song_id = ?", songID).Select("coalesce(max(revision), 0)").Scan(&last).Error
+	if err != nil {
+		return err
+	}
+	return translateError(tx.Create(&models.SongRevision{
+		SongId:       songID,
+		Revision:     last + 1,
+		Author:       author,
+		Changes:      changes,
+		Snapshot:     after,
+		RestoredFrom: restoredFrom,
+	}).Error)
+}
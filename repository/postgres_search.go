@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+
+	"song-library/models"
+)
+
+// headlineOptions настраивает фрагменты текста с выделенными совпадениями.
+const headlineOptions = "StartSel=<b>, StopSel=</b>, MaxWords=20, MinWords=8, MaxFragments=2, FragmentDelimiter=\" … \""
+
+// escapedTextSQL — текст песни с экранированными для HTML &, < и >, чтобы в фрагменте разметкой были только
+// выделения совпадений. Парсер считает сущности вроде &lt; отдельными лексемами, а не словами.
+const escapedTextSQL = "replace(replace(replace(song_details.text, '&', '&amp;'), '<', '&lt;'), '>', '&gt;')"
+
+func (p *Postgres) SearchSongs(ctx context.Context, query SearchQuery) ([]models.SearchResult, error) {
+	var hits []struct {
+		SongId  int
+		Rank    float64
+		Snippet string
+	}
+	err := p.db.WithContext(ctx).Raw(`
+		SELECT song_details.song_id,
+			ts_rank_cd(song_details.search_vector, q.query) AS rank,
+			ts_headline(q.config, `+escapedTextSQL+`, q.query, ?) AS snippet
+		FROM song_details
+			JOIN songs ON songs.id = song_details.song_id,
+			(SELECT ?::regconfig AS config, websearch_to_tsquery(?::regconfig, ?) AS query) q
+		WHERE song_details.search_vector @@ q.query AND songs.deleted_at IS NULL
+		ORDER BY rank DESC, song_details.song_id
+		OFFSET ? LIMIT ?`,
+		headlineOptions, query.Language, query.Language, query.Query, query.Offset, query.Limit,
+	).Scan(&hits).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(hits) == 0 {
+		return []models.SearchResult{}, nil
+	}
+
+	ids := make([]int, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.SongId
+	}
+	var songs []models.Song
+	if err := songsQuery(p.db.WithContext(ctx)).Where("songs.id IN ?", ids).Find(&songs).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[int]models.Song, len(songs))
+	for _, song := range songs {
+		byID[song.Id] = song
+	}
+
+	results := make([]models.SearchResult, 0, len(hits))
+	for _, hit := range hits {
+		song, ok := byID[hit.SongId]
+		if !ok {
+			continue //песню удалили между запросами
+		}
+		results = append(results, models.SearchResult{Song: song, Rank: hit.Rank, Snippet: hit.Snippet})
+	}
+	return results, nil
+}
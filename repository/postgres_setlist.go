@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"song-library/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (p *Postgres) ListSetlists(ctx context.Context, filter SetlistFilter) ([]models.Setlist, error) {
+	query := p.db.WithContext(ctx).Model(&models.Setlist{})
+	if filter.Owner != "" {
+		query = query.Where("owner = ?", filter.Owner)
+	}
+
+	var setlists []models.Setlist
+	err := query.Order("updated_at DESC, id DESC").Offset(filter.Offset).Limit(filter.Limit).Find(&setlists).Error
+	if err != nil {
+		return nil, err
+	}
+	return setlists, nil
+}
+
+func (p *Postgres) GetSetlist(ctx context.Context, id int) (*models.Setlist, error) {
+	var setlist *models.Setlist
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		setlist, err = loadSetlist(tx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return setlist, nil
+}
+
+func (p *Postgres) CreateSetlist(ctx context.Context, setlist *models.Setlist) error {
+	return p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := setlistSongsExist(tx, *setlist, 0); err != nil {
+			return err
+		}
+		numberSets(setlist.Sets)
+		if err := translateError(tx.Create(setlist).Error); err != nil {
+			return err //песня не существует
+		}
+		created, err := loadSetlist(tx, setlist.Id)
+		if err != nil {
+			return err
+		}
+		*setlist = *created
+		return nil
+	})
+}
+
+func (p *Postgres) UpdateSetlist(ctx context.Context, id int, setlist models.Setlist) (*models.Setlist, error) {
+	var updated *models.Setlist
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing models.Setlist
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", id).Take(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if err := setlistSongsExist(tx, setlist, id); err != nil {
+			return err
+		}
+
+		setlist.UpdatedAt = time.Now()
+		err = tx.Model(&models.Setlist{Id: id}).Select("name", "venue", "notes", "updated_at").Updates(&setlist).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("setlist_id = ?", id).Delete(&models.SetlistSet{}).Error; err != nil {
+			return err //песни сетов удаляются каскадно
+		}
+		if len(setlist.Sets) > 0 {
+			numberSets(setlist.Sets)
+			for i := range setlist.Sets {
+				setlist.Sets[i].SetlistId = id
+			}
+			if err := translateError(tx.Create(&setlist.Sets).Error); err != nil {
+				return err
+			}
+		}
+		updated, err = loadSetlist(tx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (p *Postgres) DeleteSetlist(ctx context.Context, id int) error {
+	result := p.db.WithContext(ctx).Delete(&models.Setlist{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil //сеты и их песни удаляются каскадно
+}
+
+// loadSetlist возвращает сет-лист с сетами и песнями не из корзины.
+func loadSetlist(tx *gorm.DB, id int) (*models.Setlist, error) {
+	var setlist models.Setlist
+	err := tx.Preload("Sets", func(db *gorm.DB) *gorm.DB { return db.Order("position") }).
+		Preload("Sets.Items", func(db *gorm.DB) *gorm.DB { return db.Order("position") }).
+		Where("id = ?", id).First(&setlist).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int
+	for _, set := range setlist.Sets {
+		for _, item := range set.Items {
+			ids = append(ids, item.SongId)
+		}
+	}
+	var songs []models.Song
+	if err := songsQuery(tx).Where("songs.id IN ? AND songs.deleted_at IS NULL", ids).Find(&songs).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[int]*models.Song, len(songs))
+	for i := range songs {
+		byID[songs[i].Id] = &songs[i]
+	}
+	for i := range setlist.Sets {
+		if setlist.Sets[i].Items == nil {
+			setlist.Sets[i].Items = []models.SetlistItem{}
+		}
+		for j := range setlist.Sets[i].Items {
+			item := &setlist.Sets[i].Items[j]
+			item.Song = byID[item.SongId] //у песен из корзины Song не заполнено
+		}
+	}
+	if setlist.Sets == nil {
+		setlist.Sets = []models.SetlistSet{}
+	}
+	return &setlist, nil
+}
+
+// setlistSongsExist возвращает ErrConflict, если какой-то песни сет-листа нет или она в корзине.
+// Песни из корзины, которые уже есть в сет-листе keptFrom, допускаются.
+func setlistSongsExist(tx *gorm.DB, setlist models.Setlist, keptFrom int) error {
+	wanted := make(map[int]bool)
+	for _, set := range setlist.Sets {
+		for _, item := range set.Items {
+			wanted[item.SongId] = true
+		}
+	}
+	if len(wanted) == 0 {
+		return nil
+	}
+	ids := make([]int, 0, len(wanted))
+	for id := range wanted {
+		ids = append(ids, id)
+	}
+
+	query := tx.Model(&models.Song{}).Where("id IN ?", ids)
+	if keptFrom != 0 {
+		kept := tx.Model(&models.SetlistItem{}).Select("setlist_items.song_id").
+			Joins("JOIN setlist_sets ON setlist_sets.id = setlist_items.set_id").
+			Where("setlist_sets.setlist_id = ?", keptFrom)
+		query = query.Where("deleted_at IS NULL OR id IN (?)", kept)
+	} else {
+		query = query.Where("deleted_at IS NULL")
+	}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return err
+	}
+	if int(count) != len(ids) {
+		return ErrConflict
+	}
+	return nil
+}
+
+// numberSets нумерует сеты и их песни по порядку в списках.
+func numberSets(sets []models.SetlistSet) {
+	for i := range sets {
+		sets[i].Id = 0
+		sets[i].Position = i + 1
+		for j := range sets[i].Items {
+			sets[i].Items[j].Id = 0
+			sets[i].Items[j].Position = j + 1
+			sets[i].Items[j].Song = nil
+		}
+	}
+}
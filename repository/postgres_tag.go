@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+
+	"song-library/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// songTagSQL — начало условия на теги песни, условие на сами теги и закрывающая скобка добавляются к нему.
+const songTagSQL = `EXISTS (SELECT 1 FROM song_tags JOIN tags ON tags.id = song_tags.tag_id
+	WHERE song_tags.song_id = songs.id`
+
+// tagCountColumns — колонки тега с количеством песен для запросов с группировкой по тегу.
+const tagCountColumns = "tags.id, tags.namespace, tags.name, count(*) AS songs"
+
+func (p *Postgres) ListTags(ctx context.Context, filter TagFilter) ([]models.Tag, error) {
+	query := p.db.WithContext(ctx).Table("tags").
+		Select(tagCountColumns).
+		Joins("JOIN song_tags ON song_tags.tag_id = tags.id").
+		Joins("JOIN songs ON songs.id = song_tags.song_id AND songs.deleted_at IS NULL").
+		Group("tags.id")
+	if filter.Namespace != "" {
+		query = query.Where("tags.namespace = ?", filter.Namespace)
+	}
+
+	var tags []models.Tag
+	err := query.Order("tags.namespace, tags.name").Offset(filter.Offset).Limit(filter.Limit).Find(&tags).Error
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (p *Postgres) ListSongTags(ctx context.Context, songID int) ([]models.Tag, error) {
+	var tags []models.Tag
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := songExists(tx, songID); err != nil {
+			return err
+		}
+		return tx.Model(&models.Tag{}).Select("tags.id, tags.namespace, tags.name").
+			Joins("JOIN song_tags ON song_tags.tag_id = tags.id").
+			Where("song_tags.song_id = ?", songID).
+			Order("tags.namespace, tags.name").Find(&tags).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (p *Postgres) TagSongs(ctx context.Context, songIDs []int, tags []models.Tag) (int, error) {
+	var added int64
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ids := uniqueIDs(songIDs)
+		var found []int
+		//песни блокируются, чтобы их не переместили в корзину до привязки тегов
+		err := tx.Model(&models.Song{}).Clauses(clause.Locking{Strength: "SHARE"}).
+			Where("id IN ? AND deleted_at IS NULL", ids).Pluck("id", &found).Error
+		if err != nil {
+			return err
+		}
+		if len(found) != len(ids) {
+			return ErrConflict
+		}
+
+		rows := make([]models.Tag, len(tags))
+		for i, tag := range tags {
+			rows[i] = models.Tag{Namespace: tag.Namespace, Name: tag.Name}
+		}
+		err = tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "namespace"}, {Name: "name"}},
+			DoNothing: true,
+		}).Create(&rows).Error
+		if err != nil {
+			return err
+		}
+
+		result := tx.Exec(`INSERT INTO song_tags (song_id, tag_id)
+			SELECT songs.id, tags.id FROM songs CROSS JOIN tags
+			WHERE songs.id IN ? AND (tags.namespace, tags.name) IN ?
+			ON CONFLICT DO NOTHING`, ids, tagPairs(tags))
+		added = result.RowsAffected
+		return result.Error
+	})
+	return int(added), err
+}
+
+func (p *Postgres) UntagSongs(ctx context.Context, songIDs []int, tags []models.Tag) (int, error) {
+	result := p.db.WithContext(ctx).Exec(`DELETE FROM song_tags USING tags
+		WHERE tags.id = song_tags.tag_id AND song_tags.song_id IN ? AND (tags.namespace, tags.name) IN ?`,
+		uniqueIDs(songIDs), tagPairs(tags))
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return int(result.RowsAffected), nil
+}
+
+func (p *Postgres) TagFacets(ctx context.Context, filter SongFilter) ([]models.Tag, error) {
+	var tags []models.Tag
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		songs := tx.Model(&models.Song{}).Select("songs.id").
+			Joins("LEFT JOIN artists ON artists.id = songs.artist_id").
+			Joins("JOIN song_details ON song_details.song_id = songs.id")
+		songs, _, err := filterSongs(tx, songs, filter)
+		if err != nil {
+			return err
+		}
+		return tx.Table("tags").
+			Select(tagCountColumns).
+			Joins("JOIN song_tags ON song_tags.tag_id = tags.id").
+			Where("song_tags.song_id IN (?)", songs).
+			Group("tags.id").
+			Order("songs DESC, tags.namespace, tags.name").
+			Find(&tags).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// tagPairs возвращает пары пространства имен и названия тегов для условия (namespace, name) IN ?.
+func tagPairs(tags []models.Tag) [][]interface{} {
+	pairs := make([][]interface{}, len(tags))
+	for i, tag := range tags {
+		pairs[i] = []interface{}{tag.Namespace, tag.Name}
+	}
+	return pairs
+}
+
+// uniqueIDs возвращает ID без повторов в исходном порядке.
+func uniqueIDs(ids []int) []int {
+	seen := make(map[int]bool, len(ids))
+	unique := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
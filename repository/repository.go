@@ -0,0 +1,309 @@
+// Package repository содержит хранилища данных библиотеки песен.
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"song-library/models"
+)
+
+var (
+	// ErrNotFound возвращается, когда запрошенная запись отсутствует в хранилище.
+	ErrNotFound = errors.New("запись не найдена")
+	// ErrConflict возвращается, когда изменение нарушает уникальность или связи записей.
+	ErrConflict = errors.New("запись конфликтует с существующими данными")
+	// ErrVersionMismatch возвращается, когда версия записи не совпадает с ожидаемой.
+	ErrVersionMismatch = errors.New("версия записи не совпадает с ожидаемой")
+	// ErrEmptyArtist возвращается, когда исполнитель песни задан пустым названием.
+	ErrEmptyArtist = errors.New("пустое название исполнителя")
+)
+
+// SongFilter описывает фильтры, сортировку и пагинацию списка песен.
+type SongFilter struct {
+	ArtistID int       //фильтр по ID исполнителя
+	Group    string    //фильтр по названию или другому написанию исполнителя
+	Song     string    //фильтр по названию песни
+	Link     string    //фильтр по ссылке
+	Text     string    //фильтр по фрагменту текста
+	Album    string    //фильтр по названию альбома без учета регистра
+	Sort     []SortKey //поля сортировки, см. ParseSort, при равенстве песни упорядочены по ID
+	Offset   int
+	Limit    int
+
+	// Cursor, если задан, заменяет Offset: список продолжается после позиции курсора или перед ней.
+	// Песни всегда возвращаются в порядке сортировки. Не поддерживается вместе с Fuzzy.
+	Cursor *Cursor
+
+	// Фильтры по дате выхода. Песня подходит, если период её выхода (год, месяц или день
+	// в зависимости от точности даты) пересекается с [ReleasedFrom, ReleasedBefore).
+	ReleasedFrom   *time.Time
+	ReleasedBefore *time.Time
+
+	// Fuzzy включает нечеткое сравнение Group и Song по триграммам без учета регистра и пробелов.
+	// Песни сортируются по убыванию сходства, сходство возвращается в поле Score.
+	Fuzzy     bool
+	Threshold float64 //минимальное сходство от 0 до 1
+
+	IncludeDeleted bool //включать песни из корзины
+
+	// Tags оставляет песни с тегами: со всеми тегами или, если AnyTag, хотя бы с одним.
+	Tags   []models.Tag
+	AnyTag bool
+}
+
+// SearchQuery описывает полнотекстовый поиск по текстам песен.
+type SearchQuery struct {
+	Query    string //запрос в синтаксисе websearch: слова, "фраза", -исключение, or
+	Language string //russian или english
+	Offset   int
+	Limit    int
+}
+
+// SongRepository описывает хранилище песен вместе с их дополнительными данными.
+// Все изменяющие методы выполняются атомарно. CreateSong и UpdateSong в той же транзакции
+// записывают правку в историю песни от имени автора из контекста (см. WithActor).
+// Песни в корзине возвращают только ListSongs с IncludeDeleted и ListDeletedSongs,
+// для остальных методов их нет.
+type SongRepository interface {
+	// ListSongs возвращает песни, удовлетворяющие фильтру. Если курсор не подходит к сортировке,
+	// возвращает ErrInvalidCursor, если курсор передан для нечеткого поиска — ErrFuzzyCursor.
+	ListSongs(ctx context.Context, filter SongFilter) ([]models.Song, error)
+	// CountSongs возвращает количество песен, удовлетворяющих фильтру, без учета пагинации.
+	CountSongs(ctx context.Context, filter SongFilter) (int, error)
+	// ExportSongs вызывает fn для каждой песни с дополнительными данными, удовлетворяющей фильтру,
+	// в порядке сортировки. Пагинация и курсор фильтра не учитываются. Песни читаются по одной,
+	// весь список не загружается в память. Ошибка fn прерывает выгрузку и возвращается.
+	ExportSongs(ctx context.Context, filter SongFilter, fn func(song models.Song) error) error
+	// SearchSongs ищет песни по тексту и возвращает их в порядке убывания релевантности.
+	SearchSongs(ctx context.Context, query SearchQuery) ([]models.SearchResult, error)
+	// GetSong возвращает песню с дополнительными данными по её ID.
+	GetSong(ctx context.Context, id int) (*models.Song, error)
+	// FindSong ищет песню по названию (или другому написанию) исполнителя и названию песни.
+	FindSong(ctx context.Context, group, song string) (*models.Song, error)
+	// CreateSong сохраняет песню и её дополнительные данные в одной транзакции.
+	// Если ArtistId не задан, исполнитель ищется по Group и создается, если его еще нет.
+	// Если песня ожидает получения данных (EnrichmentPending), в той же транзакции
+	// для неё создается задание в очереди.
+	CreateSong(ctx context.Context, song *models.Song) error
+	// UpdateSong изменяет песню и её дополнительные данные в одной транзакции
+	// и возвращает обновленную песню. Версия песни увеличивается на 1.
+	// Если update.IfVersion не 0 и не совпадает с версией песни, возвращает ErrVersionMismatch.
+	UpdateSong(ctx context.Context, id int, update models.SongUpdate) (*models.Song, error)
+	// SetSyncedLyrics сохраняет метки времени строк текста песни, пустое значение удаляет их.
+	SetSyncedLyrics(ctx context.Context, id int, synced models.SyncedLyrics) error
+	// SetChordPro сохраняет лист аккордов песни в формате ChordPro, пустая строка удаляет его.
+	SetChordPro(ctx context.Context, id int, doc string) error
+	// DeleteSong перемещает песню в корзину от имени автора из контекста.
+	// Если ifVersion не 0 и не совпадает с версией песни, возвращает ErrVersionMismatch.
+	DeleteSong(ctx context.Context, id, ifVersion int) error
+	// RestoreSong возвращает песню из корзины. Если песни нет в корзине, возвращает ErrNotFound,
+	// если такая же песня добавлена заново, пока эта была в корзине, — ErrConflict.
+	RestoreSong(ctx context.Context, id int) (*models.Song, error)
+	// ListDeletedSongs возвращает песни из корзины, начиная с удаленных последними.
+	ListDeletedSongs(ctx context.Context, offset, limit int) ([]models.Song, error)
+	// PurgeDeletedSongs окончательно удаляет песни, перемещенные в корзину раньше before,
+	// и возвращает их количество.
+	PurgeDeletedSongs(ctx context.Context, before time.Time) (int, error)
+}
+
+// EnrichmentRepository хранит задания на получение дополнительных данных песен.
+// У каждой песни не больше одного задания.
+type EnrichmentRepository interface {
+	// GetEnrichmentJob возвращает задание песни.
+	GetEnrichmentJob(ctx context.Context, songID int) (*models.EnrichmentJob, error)
+	// EnqueueEnrichment ставит песню в очередь заново и переводит её в состояние pending.
+	// Если песни нет или она в корзине, возвращает ErrNotFound.
+	EnqueueEnrichment(ctx context.Context, songID int) (*models.EnrichmentJob, error)
+	// ClaimEnrichmentJob забирает самое раннее готовое к выполнению задание и переводит его в running.
+	// Если готовых заданий нет, возвращает ErrNotFound.
+	ClaimEnrichmentJob(ctx context.Context) (*models.EnrichmentJob, error)
+	// CompleteEnrichmentJob заполняет пустые поля песни полученными данными и завершает задание.
+	// Если песня в корзине, данные не сохраняются, а задание завершается с ошибкой.
+	CompleteEnrichmentJob(ctx context.Context, jobID int, details models.SongDetails) error
+	// RetryEnrichmentJob возвращает задание в очередь с выполнением не раньше runAt.
+	RetryEnrichmentJob(ctx context.Context, jobID int, errMsg, errKind string, runAt time.Time) error
+	// FailEnrichmentJob окончательно завершает задание с ошибкой.
+	FailEnrichmentJob(ctx context.Context, jobID int, errMsg, errKind string) error
+	// RequeueRunningEnrichmentJobs возвращает в очередь задания, прерванные остановкой сервера.
+	RequeueRunningEnrichmentJobs(ctx context.Context) (int, error)
+}
+
+// ArtistFilter описывает фильтры и пагинацию списка исполнителей.
+type ArtistFilter struct {
+	Name   string //фильтр по фрагменту названия без учета регистра
+	Offset int
+	Limit  int
+}
+
+// ArtistRepository описывает хранилище исполнителей.
+type ArtistRepository interface {
+	// ListArtists возвращает исполнителей, отсортированных по названию.
+	ListArtists(ctx context.Context, filter ArtistFilter) ([]models.Artist, error)
+	// GetArtist возвращает исполнителя по ID.
+	GetArtist(ctx context.Context, id int) (*models.Artist, error)
+	// CreateArtist сохраняет нового исполнителя. Если исполнитель с таким названием уже есть, возвращает ErrConflict.
+	CreateArtist(ctx context.Context, artist *models.Artist) error
+	// UpdateArtist заменяет данные исполнителя и возвращает обновленного исполнителя.
+	UpdateArtist(ctx context.Context, id int, artist models.Artist) (*models.Artist, error)
+	// DeleteArtist удаляет исполнителя. Если у исполнителя есть песни или альбомы, возвращает ErrConflict.
+	DeleteArtist(ctx context.Context, id int) error
+}
+
+// AlbumFilter описывает фильтры и пагинацию списка альбомов.
+type AlbumFilter struct {
+	ArtistID int    //фильтр по ID исполнителя
+	Title    string //фильтр по фрагменту названия без учета регистра
+	Offset   int
+	Limit    int
+}
+
+// AlbumRepository описывает хранилище альбомов и их списков треков.
+type AlbumRepository interface {
+	// ListAlbums возвращает альбомы, отсортированные по дате выхода.
+	ListAlbums(ctx context.Context, filter AlbumFilter) ([]models.Album, error)
+	// GetAlbum возвращает альбом по ID.
+	GetAlbum(ctx context.Context, id int) (*models.Album, error)
+	// CreateAlbum сохраняет новый альбом. Если исполнитель не существует, возвращает ErrConflict.
+	CreateAlbum(ctx context.Context, album *models.Album) error
+	// UpdateAlbum заменяет данные альбома и возвращает обновленный альбом.
+	UpdateAlbum(ctx context.Context, id int, album models.Album) (*models.Album, error)
+	// DeleteAlbum удаляет альбом вместе со списком треков.
+	DeleteAlbum(ctx context.Context, id int) error
+	// ListAlbumTracks возвращает треки альбома с песнями, упорядоченные по номеру диска и трека.
+	ListAlbumTracks(ctx context.Context, albumID int) ([]models.AlbumTrack, error)
+	// ListSongTracks возвращает позиции песни во всех альбомах вместе с альбомами,
+	// упорядоченные по дате выхода альбома.
+	ListSongTracks(ctx context.Context, songID int) ([]models.AlbumTrack, error)
+	// SetAlbumTrack ставит песню на позицию в альбоме, заменяя прежнюю песню на этой позиции.
+	// Если альбом не найден, возвращает ErrNotFound, если песня не найдена — ErrConflict.
+	SetAlbumTrack(ctx context.Context, track models.AlbumTrack) error
+	// RemoveAlbumTrack убирает трек с позиции в альбоме.
+	RemoveAlbumTrack(ctx context.Context, albumID, disc, track int) error
+}
+
+// PlaylistFilter описывает фильтры и пагинацию списка плейлистов.
+type PlaylistFilter struct {
+	Owner  string //фильтр по владельцу
+	Viewer string //кроме публичных, в список попадают все плейлисты этого пользователя
+	All    bool   //все плейлисты независимо от видимости, для администратора
+	Offset int
+	Limit  int
+}
+
+// PlaylistRepository описывает хранилище плейлистов и их записей.
+// Позиции записей плейлиста идут подряд, начиная с 1. Изменения записей обновляют время изменения плейлиста.
+type PlaylistRepository interface {
+	// ListPlaylists возвращает плейлисты с количеством записей, начиная с измененных последними.
+	ListPlaylists(ctx context.Context, filter PlaylistFilter) ([]models.Playlist, error)
+	// GetPlaylist возвращает плейлист с количеством записей по ID.
+	GetPlaylist(ctx context.Context, id int) (*models.Playlist, error)
+	// CreatePlaylist сохраняет новый пустой плейлист.
+	CreatePlaylist(ctx context.Context, playlist *models.Playlist) error
+	// UpdatePlaylist заменяет название, описание и видимость плейлиста и возвращает обновленный плейлист.
+	UpdatePlaylist(ctx context.Context, id int, playlist models.Playlist) (*models.Playlist, error)
+	// DeletePlaylist удаляет плейлист вместе с записями.
+	DeletePlaylist(ctx context.Context, id int) error
+	// ListPlaylistEntries возвращает записи плейлиста с песнями по порядку. У записей песен из корзины
+	// Song не заполнено. Если плейлист не найден, возвращает ErrNotFound.
+	ListPlaylistEntries(ctx context.Context, playlistID int) ([]models.PlaylistEntry, error)
+	// AddPlaylistEntry добавляет песню на позицию entry.Position, сдвигая следующие записи.
+	// Позиция 0 или больше длины плейлиста добавляет песню в конец. Заполняет ID и итоговую позицию записи.
+	// Если плейлист не найден, возвращает ErrNotFound, если песня не найдена или в корзине — ErrConflict.
+	AddPlaylistEntry(ctx context.Context, entry *models.PlaylistEntry) error
+	// RemovePlaylistEntry удаляет запись плейлиста, следующие записи сдвигаются.
+	RemovePlaylistEntry(ctx context.Context, playlistID, entryID int) error
+	// MovePlaylistEntry переставляет запись на позицию position, позиция больше длины плейлиста
+	// ставит запись в конец. Возвращает записи плейлиста в новом порядке.
+	MovePlaylistEntry(ctx context.Context, playlistID, entryID, position int) ([]models.PlaylistEntry, error)
+	// ReorderPlaylist расставляет записи в порядке entryIDs и возвращает записи в новом порядке.
+	// Если entryIDs не перечисляет каждую запись плейлиста ровно один раз, возвращает ErrConflict.
+	ReorderPlaylist(ctx context.Context, playlistID int, entryIDs []int) ([]models.PlaylistEntry, error)
+}
+
+// SetlistFilter описывает фильтры и пагинацию списка сет-листов.
+type SetlistFilter struct {
+	Owner  string //фильтр по владельцу
+	Offset int
+	Limit  int
+}
+
+// SetlistRepository описывает хранилище сет-листов. Сеты и песни сет-листа сохраняются и заменяются
+// вместе с ним, их позиции нумеруются по порядку в списках.
+type SetlistRepository interface {
+	// ListSetlists возвращает сет-листы без сетов, начиная с измененных последними.
+	ListSetlists(ctx context.Context, filter SetlistFilter) ([]models.Setlist, error)
+	// GetSetlist возвращает сет-лист с сетами и песнями. У песен из корзины Song не заполнено.
+	GetSetlist(ctx context.Context, id int) (*models.Setlist, error)
+	// CreateSetlist сохраняет сет-лист вместе с сетами. Если песня не найдена или в корзине, возвращает ErrConflict.
+	CreateSetlist(ctx context.Context, setlist *models.Setlist) error
+	// UpdateSetlist заменяет данные и сеты сет-листа, сеты и песни получают новые ID.
+	// Возвращает обновленный сет-лист. Если песня не найдена или в корзине, возвращает ErrConflict,
+	// песни из корзины, которые уже были в сет-листе, остаются.
+	UpdateSetlist(ctx context.Context, id int, setlist models.Setlist) (*models.Setlist, error)
+	// DeleteSetlist удаляет сет-лист вместе с сетами.
+	DeleteSetlist(ctx context.Context, id int) error
+}
+
+// TagFilter описывает фильтры и пагинацию списка тегов.
+type TagFilter struct {
+	Namespace string //фильтр по пространству имен
+	Offset    int
+	Limit     int
+}
+
+// TagRepository описывает хранилище тегов песен. Теги создаются при первой привязке к песне.
+// Песни в корзине не учитываются в количестве песен тегов.
+type TagRepository interface {
+	// ListTags возвращает теги, которые есть хотя бы у одной песни, с количеством песен,
+	// отсортированные по пространству имен и названию.
+	ListTags(ctx context.Context, filter TagFilter) ([]models.Tag, error)
+	// ListSongTags возвращает теги песни. Если песня не найдена, возвращает ErrNotFound.
+	ListSongTags(ctx context.Context, songID int) ([]models.Tag, error)
+	// TagSongs добавляет теги всем песням в одной транзакции и возвращает количество новых привязок.
+	// Если песня не найдена или в корзине, возвращает ErrConflict и ничего не изменяет.
+	TagSongs(ctx context.Context, songIDs []int, tags []models.Tag) (int, error)
+	// UntagSongs убирает теги у песен и возвращает количество удаленных привязок.
+	UntagSongs(ctx context.Context, songIDs []int, tags []models.Tag) (int, error)
+	// TagFacets возвращает теги песен, удовлетворяющих фильтру, с количеством таких песен у каждого тега,
+	// по убыванию количества. Пагинация, курсор и сортировка фильтра не учитываются.
+	TagFacets(ctx context.Context, filter SongFilter) ([]models.Tag, error)
+}
+
+// LyricsRepository описывает хранилище версий текста песен на разных языках.
+// У песни не больше одной версии каждого вида на каждом языке.
+type LyricsRepository interface {
+	// ListLyricVersions возвращает версии текста песни, отсортированные по языку и виду.
+	// Если песня не найдена, возвращает ErrNotFound.
+	ListLyricVersions(ctx context.Context, songID int) ([]models.LyricVersion, error)
+	// SaveLyricVersion создает или заменяет версию текста с теми же языком и видом.
+	// Если песня не найдена, возвращает ErrNotFound.
+	SaveLyricVersion(ctx context.Context, version *models.LyricVersion) error
+	// DeleteLyricVersion удаляет версию текста песни.
+	DeleteLyricVersion(ctx context.Context, songID int, language string, kind models.LyricKind) error
+}
+
+// RevisionRepository описывает историю изменений песен.
+type RevisionRepository interface {
+	// ListRevisions возвращает правки песни от новых к старым. Если песня не найдена, возвращает ErrNotFound.
+	ListRevisions(ctx context.Context, songID, offset, limit int) ([]models.SongRevision, error)
+	// GetRevision возвращает правку песни по номеру. Если песня не найдена, возвращает ErrNotFound.
+	GetRevision(ctx context.Context, songID, revision int) (*models.SongRevision, error)
+	// RestoreRevision возвращает поля песни к состоянию после правки revision в одной транзакции
+	// и записывает это как новую правку. Возвращает обновленную песню.
+	RestoreRevision(ctx context.Context, songID, revision int) (*models.Song, error)
+}
+
+// Store объединяет все хранилища библиотеки, его реализуют Postgres и Memory.
+type Store interface {
+	SongRepository
+	EnrichmentRepository
+	ArtistRepository
+	AlbumRepository
+	PlaylistRepository
+	SetlistRepository
+	TagRepository
+	LyricsRepository
+	RevisionRepository
+}
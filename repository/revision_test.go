@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"song-library/models"
+)
+
+func TestMemoryRestoreRevisionArtist(t *testing.T) {
+	missing := 100
+	tests := []struct {
+		name       string
+		snapshotID func(muse int) *int //исполнитель в первой правке
+		wantGroup  string
+		wantNew    bool //песня привязана к новому исполнителю с названием из правки
+	}{
+		{name: "исполнитель переименован", snapshotID: func(muse int) *int { return &muse }, wantGroup: "Muse (band)"},
+		{name: "правка без исполнителя", snapshotID: func(int) *int { return nil }, wantGroup: "Muse", wantNew: true},
+		{name: "исполнителя больше нет", snapshotID: func(int) *int { return &missing }, wantGroup: "Muse", wantNew: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			m := NewMemory()
+			song := &models.Song{Group: "Muse", Song: "Hysteria"}
+			if err := m.CreateSong(ctx, song); err != nil {
+				t.Fatal(err)
+			}
+			muse := *song.ArtistId
+			m.revisions[song.Id][0].Snapshot.ArtistId = tt.snapshotID(muse)
+
+			text := "It's bugging me"
+			if _, err := m.UpdateSong(ctx, song.Id, models.SongUpdate{Text: &text}); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := m.UpdateArtist(ctx, muse, models.Artist{Name: "Muse (band)"}); err != nil {
+				t.Fatal(err)
+			}
+
+			restored, err := m.RestoreRevision(ctx, song.Id, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if restored.Group != tt.wantGroup || restored.SongDetails.Text != "" {
+				t.Errorf("песня после возврата к правке = %+v, ожидалась группа %q", restored, tt.wantGroup)
+			}
+			if isNew := *restored.ArtistId != muse; isNew != tt.wantNew {
+				t.Errorf("исполнитель песни %d, исполнитель правки %d, ожидался новый исполнитель: %v", *restored.ArtistId, muse, tt.wantNew)
+			}
+			artists, err := m.ListArtists(ctx, ArtistFilter{Limit: 10})
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantArtists := 1
+			if tt.wantNew {
+				wantArtists = 2
+			}
+			if len(artists) != wantArtists {
+				t.Errorf("исполнителей %d, ожидалось %d: %+v", len(artists), wantArtists, artists)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"strings"
+	"unicode"
+)
+
+// normalizeName приводит название к нижнему регистру и схлопывает пробелы,
+// так же как функция normalize_name в базе данных.
+func normalizeName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// similarity вычисляет сходство строк по триграммам так же, как similarity из pg_trgm:
+// отношение числа общих триграмм к числу всех различных триграмм обеих строк.
+func similarity(a, b string) float64 {
+	ta, tb := trigrams(a), trigrams(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+
+	common := 0
+	for t := range ta {
+		if tb[t] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(ta)+len(tb)-common)
+}
+
+// trigrams возвращает множество триграмм строки. Как и в pg_trgm, каждое слово
+// дополняется двумя пробелами в начале и одним в конце.
+func trigrams(s string) map[string]bool {
+	set := map[string]bool{}
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		runes := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			set[string(runes[i:i+3])] = true
+		}
+	}
+	return set
+}
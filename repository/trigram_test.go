@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{a: "word", b: "word", want: 1},
+		{a: "word", b: "two words", want: 4.0 / 11}, //пример из документации pg_trgm
+		{a: "Radiohead", b: "RADIOHEAD", want: 1},
+		{a: "Кино", b: "кино!", want: 1},
+		{a: "abc", b: "xyz", want: 0},
+		{a: "", b: "word", want: 0},
+		{a: "!!!", b: "!!!", want: 0},
+	}
+	for _, tt := range tests {
+		if got := similarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("similarity(%q, %q) = %v, ожидалось %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		name, want string
+	}{
+		{name: "  Muse ", want: "muse"},
+		{name: "Guns   N'\tRoses", want: "guns n' roses"},
+		{name: "", want: ""},
+	}
+	for _, tt := range tests {
+		if got := normalizeName(tt.name); got != tt.want {
+			t.Errorf("normalizeName(%q) = %q, ожидалось %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Package trash по расписанию окончательно удаляет песни, которые пролежали в корзине дольше срока хранения.
+package trash
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Store — хранилище, из которого удаляются песни, см. repository.SongRepository.
+type Store interface {
+	PurgeDeletedSongs(ctx context.Context, before time.Time) (int, error)
+}
+
+// Config описывает параметры очистки корзины.
+type Config struct {
+	Retention time.Duration //сколько песня хранится в корзине
+	Interval  time.Duration //как часто проверять корзину
+}
+
+// Purger периодически очищает корзину.
+type Purger struct {
+	store Store
+	cfg   Config
+	wg    sync.WaitGroup
+}
+
+// NewPurger создает очистку корзины, подставляя значения по умолчанию для незаполненных полей Config.
+func NewPurger(store Store, cfg Config) *Purger {
+	if cfg.Retention <= 0 {
+		cfg.Retention = 30 * 24 * time.Hour
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Hour
+	}
+	return &Purger{store: store, cfg: cfg}
+}
+
+// Start сразу очищает корзину и дальше повторяет очистку с интервалом Interval, пока не отменен ctx.
+func (p *Purger) Start(ctx context.Context) {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		ticker := time.NewTicker(p.cfg.Interval)
+		defer ticker.Stop()
+
+		for {
+			p.purge(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Wait дожидается завершения очистки после отмены контекста Start.
+func (p *Purger) Wait() {
+	p.wg.Wait()
+}
+
+func (p *Purger) purge(ctx context.Context) {
+	purged, err := p.store.PurgeDeletedSongs(ctx, time.Now().Add(-p.cfg.Retention))
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("Не удалось очистить корзину, %v\n", err)
+		}
+		return
+	}
+	if purged > 0 {
+		log.Printf("Из корзины окончательно удалено песен: %d\n", purged)
+	}
+}
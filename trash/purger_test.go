@@ -0,0 +1,178 @@
+package trash
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"song-library/models"
+	"song-library/repository"
+)
+
+// recordingStore запоминает границы, с которыми очищалась корзина.
+type recordingStore struct {
+	mu      sync.Mutex
+	cutoffs []time.Time
+	err     error
+}
+
+func (s *recordingStore) PurgeDeletedSongs(ctx context.Context, before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cutoffs = append(s.cutoffs, before)
+	return 0, s.err
+}
+
+func (s *recordingStore) calls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.cutoffs)
+}
+
+func TestNewPurgerDefaults(t *testing.T) {
+	tests := []struct {
+		cfg  Config
+		want Config
+	}{
+		{cfg: Config{}, want: Config{Retention: 30 * 24 * time.Hour, Interval: time.Hour}},
+		{cfg: Config{Retention: -time.Hour, Interval: -time.Minute}, want: Config{Retention: 30 * 24 * time.Hour, Interval: time.Hour}},
+		{cfg: Config{Retention: 48 * time.Hour, Interval: time.Minute}, want: Config{Retention: 48 * time.Hour, Interval: time.Minute}},
+	}
+	for _, tt := range tests {
+		if got := NewPurger(nil, tt.cfg).cfg; got != tt.want {
+			t.Errorf("NewPurger(%+v).cfg = %+v, ожидалось %+v", tt.cfg, got, tt.want)
+		}
+	}
+}
+
+func TestPurgerCutoff(t *testing.T) {
+	store := &recordingStore{}
+	p := NewPurger(store, Config{Retention: 48 * time.Hour})
+
+	before := time.Now()
+	p.purge(context.Background())
+	after := time.Now()
+
+	if store.calls() != 1 {
+		t.Fatalf("очисток %d, ожидалась 1", store.calls())
+	}
+	if cutoff := store.cutoffs[0]; cutoff.Before(before.Add(-48*time.Hour)) || cutoff.After(after.Add(-48*time.Hour)) {
+		t.Errorf("граница очистки %v, ожидалось на 48 часов раньше %v", cutoff, before)
+	}
+}
+
+func TestPurgerRetention(t *testing.T) {
+	tests := []struct {
+		name       string
+		retention  time.Duration
+		wantPurged bool
+	}{
+		{name: "срок хранения не истек", retention: time.Hour, wantPurged: false},
+		{name: "срок хранения истек", retention: time.Nanosecond, wantPurged: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := repository.NewMemory()
+			deleted := &models.Song{Group: "Muse", Song: "Hysteria"}
+			live := &models.Song{Group: "Muse", Song: "Uprising"}
+			for _, song := range []*models.Song{deleted, live} {
+				if err := repo.CreateSong(ctx, song); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := repo.DeleteSong(ctx, deleted.Id, 0); err != nil {
+				t.Fatal(err)
+			}
+			time.Sleep(time.Millisecond)
+
+			NewPurger(repo, Config{Retention: tt.retention}).purge(ctx)
+
+			_, err := repo.RestoreSong(ctx, deleted.Id)
+			if purged := errors.Is(err, repository.ErrNotFound); purged != tt.wantPurged {
+				t.Errorf("песня удалена окончательно: %v, ожидалось %v (ошибка %v)", purged, tt.wantPurged, err)
+			}
+			if _, err := repo.GetSong(ctx, live.Id); err != nil {
+				t.Errorf("песня не из корзины: %v", err)
+			}
+		})
+	}
+}
+
+func TestPurgerPlaylistPositions(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemory()
+	playlist := &models.Playlist{Name: "В дорогу", Visibility: models.PlaylistPrivate}
+	if err := repo.CreatePlaylist(ctx, playlist); err != nil {
+		t.Fatal(err)
+	}
+	var songs []*models.Song
+	for _, title := range []string{"Hysteria", "Uprising", "Starlight"} {
+		song := &models.Song{Group: "Muse", Song: title}
+		if err := repo.CreateSong(ctx, song); err != nil {
+			t.Fatal(err)
+		}
+		songs = append(songs, song)
+	}
+	//удаляемая песня встречается в начале и в середине плейлиста
+	for _, song := range []*models.Song{songs[1], songs[0], songs[1], songs[2]} {
+		if err := repo.AddPlaylistEntry(ctx, &models.PlaylistEntry{PlaylistId: playlist.Id, SongId: song.Id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := repo.DeleteSong(ctx, songs[1].Id, 0); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond)
+
+	NewPurger(repo, Config{Retention: time.Nanosecond}).purge(ctx)
+
+	entries, err := repo.ListPlaylistEntries(ctx, playlist.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantSongs := []int{songs[0].Id, songs[2].Id}
+	if len(entries) != len(wantSongs) {
+		t.Fatalf("записей %d, ожидалось %d: %+v", len(entries), len(wantSongs), entries)
+	}
+	for i, entry := range entries {
+		if entry.SongId != wantSongs[i] || entry.Position != i+1 {
+			t.Errorf("запись %d: песня %d, позиция %d, ожидалось песня %d, позиция %d", i, entry.SongId, entry.Position, wantSongs[i], i+1)
+		}
+	}
+	//новая запись встает сразу за оставшимися
+	entry := &models.PlaylistEntry{PlaylistId: playlist.Id, SongId: songs[0].Id}
+	if err := repo.AddPlaylistEntry(ctx, entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.Position != 3 {
+		t.Errorf("позиция новой записи %d, ожидалось 3", entry.Position)
+	}
+}
+
+func TestPurgerStart(t *testing.T) {
+	store := &recordingStore{err: errors.New("connection refused")}
+	p := NewPurger(store, Config{Interval: 5 * time.Millisecond})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.Start(ctx)
+
+	//ошибка очистки не останавливает повторы
+	deadline := time.Now().Add(5 * time.Second)
+	for store.calls() < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("очисток %d, ожидалось хотя бы 2", store.calls())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	p.Wait()
+
+	calls := store.calls()
+	time.Sleep(20 * time.Millisecond)
+	if store.calls() != calls {
+		t.Errorf("очистка продолжается после отмены контекста")
+	}
+}